@@ -1,6 +1,8 @@
 package server_test
 
 import (
+	"bufio"
+	"encoding/json"
 	"os"
 	"path/filepath"
 	"strconv"
@@ -8,6 +10,8 @@ import (
 
 	"github.com/nspcc-dev/neo-go/internal/testcli"
 	"github.com/nspcc-dev/neo-go/pkg/config"
+	"github.com/nspcc-dev/neo-go/pkg/core/native/nativehashes"
+	"github.com/nspcc-dev/neo-go/pkg/core/state"
 	"github.com/nspcc-dev/neo-go/pkg/core/storage/dbconfig"
 	"github.com/stretchr/testify/require"
 	"gopkg.in/yaml.v3"
@@ -156,3 +160,123 @@ func TestDBDumpRestoreIncremental(t *testing.T) {
 	// Restore second 15 blocks from incremental dump.
 	e.Run(t, append(restoreBaseArgs, "--in", incDump, "-n", "--count", "15")...)
 }
+
+func TestDBExportDiffs(t *testing.T) {
+	tmpDir := t.TempDir()
+	chainPath := filepath.Join(tmpDir, "neogotestchain")
+	diffsPath := filepath.Join(tmpDir, "diffs.json")
+
+	cfg, err := config.LoadFile(filepath.Join("..", "..", "config", "protocol.unit_testnet.yml"))
+	require.NoError(t, err, "could not load config")
+	cfg.ApplicationConfiguration.DBConfiguration.Type = dbconfig.LevelDB
+	cfg.ApplicationConfiguration.DBConfiguration.LevelDBOptions.DataDirectoryPath = chainPath
+	out, err := yaml.Marshal(cfg)
+	require.NoError(t, err)
+
+	cfgPath := filepath.Join(tmpDir, "protocol.unit_testnet.yml")
+	require.NoError(t, os.WriteFile(cfgPath, out, os.ModePerm))
+
+	e := testcli.NewExecutor(t, false)
+	e.Run(t, "neo-go", "db", "restore", "--unittest", "--config-path", tmpDir, "--in", inDump)
+
+	baseArgs := []string{"neo-go", "db", "export-diffs", "--unittest",
+		"--config-path", tmpDir, "--out", diffsPath}
+
+	t.Run("excessive parameters", func(t *testing.T) {
+		e.RunWithError(t, append(baseArgs, "something")...)
+	})
+	t.Run("invalid start/count", func(t *testing.T) {
+		e.RunWithError(t, append(baseArgs, "--start", "45", "--count", "10")...)
+	})
+
+	e.Run(t, append(baseArgs, "--start", "10", "--count", "5")...)
+
+	f, err := os.Open(diffsPath)
+	require.NoError(t, err)
+	defer f.Close()
+	var (
+		diffs   []state.StateDiff
+		scanner = bufio.NewScanner(f)
+	)
+	for scanner.Scan() {
+		var d state.StateDiff
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &d))
+		diffs = append(diffs, d)
+	}
+	require.NoError(t, scanner.Err())
+	require.Equal(t, 5, len(diffs))
+	for i, d := range diffs {
+		require.Equal(t, uint32(10+i), d.Index)
+		require.NotEqual(t, 0, len(d.Contracts))
+		if i > 0 {
+			require.Equal(t, diffs[i-1].Root, d.PrevRoot)
+		}
+	}
+}
+
+func TestDBInspect(t *testing.T) {
+	tmpDir := t.TempDir()
+	chainPath := filepath.Join(tmpDir, "neogotestchain")
+
+	cfg, err := config.LoadFile(filepath.Join("..", "..", "config", "protocol.unit_testnet.yml"))
+	require.NoError(t, err, "could not load config")
+	cfg.ApplicationConfiguration.DBConfiguration.Type = dbconfig.LevelDB
+	cfg.ApplicationConfiguration.DBConfiguration.LevelDBOptions.DataDirectoryPath = chainPath
+	out, err := yaml.Marshal(cfg)
+	require.NoError(t, err)
+
+	cfgPath := filepath.Join(tmpDir, "protocol.unit_testnet.yml")
+	require.NoError(t, os.WriteFile(cfgPath, out, os.ModePerm))
+
+	e := testcli.NewExecutor(t, false)
+	e.Run(t, "neo-go", "db", "restore", "--unittest", "--config-path", tmpDir, "--in", inDump)
+
+	cfgArgs := []string{"--unittest", "--config-path", tmpDir}
+
+	t.Run("stats", func(t *testing.T) {
+		e.RunWithError(t, append([]string{"neo-go", "db", "stats", "something"}, cfgArgs...)...)
+
+		e.Run(t, append([]string{"neo-go", "db", "stats"}, cfgArgs...)...)
+		res := e.Out.String()
+		require.Regexp(t, `0x01\s+DataExecutable\s+\d+`, res)
+		require.Regexp(t, `0x70\s+STStorage\s+\d+`, res)
+		require.Regexp(t, `-5\s+`+nativehashes.NeoToken.StringLE()+`\s+NeoToken\s+\d+`, res)
+		require.Regexp(t, `MPT nodes:\s+[1-9]\d*`, res)
+		require.Regexp(t, `Blocks:\s+51\s`, res)
+		require.Regexp(t, `Transactions:\s+[1-9]\d*`, res)
+		require.Regexp(t, `Application logs:\s+\([1-9]\d* bytes\)`, res)
+	})
+	t.Run("get", func(t *testing.T) {
+		e.RunWithError(t, append([]string{"neo-go", "db", "get"}, cfgArgs...)...)
+		e.RunWithError(t, append([]string{"neo-go", "db", "get", "zz"}, cfgArgs...)...)
+		e.RunWithError(t, append([]string{"neo-go", "db", "get", "ff"}, cfgArgs...)...)
+		e.RunWithError(t, append([]string{"neo-go", "db", "get", "--contract", "unknown", "01"}, cfgArgs...)...)
+
+		e.Run(t, append([]string{"neo-go", "db", "get", "f0"}, cfgArgs...)...)
+		e.CheckNextLine(t, `^[0-9a-f]+$`)
+		e.CheckEOF(t)
+
+		// GAS per block record for the genesis block.
+		e.Run(t, append([]string{"neo-go", "db", "get", "--contract=-5", "1d00000000"}, cfgArgs...)...)
+		gasPerBlock := e.GetNextLine(t)
+		e.CheckLine(t, gasPerBlock, `^[0-9a-f]+$`)
+
+		e.Run(t, append([]string{"neo-go", "db", "get", "--contract", nativehashes.NeoToken.StringLE(), "1d00000000"}, cfgArgs...)...)
+		e.CheckNextLine(t, gasPerBlock)
+	})
+	t.Run("find", func(t *testing.T) {
+		e.RunWithError(t, append([]string{"neo-go", "db", "find", "01", "02"}, cfgArgs...)...)
+
+		e.Run(t, append([]string{"neo-go", "db", "find", "c0"}, cfgArgs...)...)
+		e.CheckNextLine(t, `^c0\t[0-9a-f]+$`)
+		e.CheckEOF(t)
+
+		e.Run(t, append([]string{"neo-go", "db", "find", "--contract=-5", "1d"}, cfgArgs...)...)
+		e.CheckNextLine(t, `^1d00000000\t[0-9a-f]+$`)
+
+		e.Run(t, append([]string{"neo-go", "db", "find", "--contract=-5", "--count", "2"}, cfgArgs...)...)
+		e.GetNextLine(t)
+		e.GetNextLine(t)
+		e.CheckEOF(t)
+	})
+}
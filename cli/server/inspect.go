@@ -0,0 +1,331 @@
+package server
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/nspcc-dev/neo-go/cli/cmdargs"
+	"github.com/nspcc-dev/neo-go/cli/options"
+	"github.com/nspcc-dev/neo-go/pkg/config"
+	"github.com/nspcc-dev/neo-go/pkg/core/block"
+	"github.com/nspcc-dev/neo-go/pkg/core/dao"
+	"github.com/nspcc-dev/neo-go/pkg/core/native"
+	"github.com/nspcc-dev/neo-go/pkg/core/state"
+	"github.com/nspcc-dev/neo-go/pkg/core/storage"
+	"github.com/nspcc-dev/neo-go/pkg/core/transaction"
+	"github.com/nspcc-dev/neo-go/pkg/io"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/nspcc-dev/neo-go/pkg/vm/stackitem"
+	"github.com/urfave/cli"
+)
+
+// prefixNames are used to describe the DB contents.
+var prefixNames = map[storage.KeyPrefix]string{
+	storage.DataExecutable:                 "DataExecutable",
+	storage.DataMPT:                        "DataMPT",
+	storage.DataMPTAux:                     "DataMPTAux",
+	storage.STStorage:                      "STStorage",
+	storage.STTempStorage:                  "STTempStorage",
+	storage.STNEP11Transfers:               "STNEP11Transfers",
+	storage.STNEP17Transfers:               "STNEP17Transfers",
+	storage.STTokenTransferInfo:            "STTokenTransferInfo",
+	storage.IXHeaderHashList:               "IXHeaderHashList",
+	storage.SYSCurrentBlock:                "SYSCurrentBlock",
+	storage.SYSCurrentHeader:               "SYSCurrentHeader",
+	storage.SYSStateSyncCurrentBlockHeight: "SYSStateSyncCurrentBlockHeight",
+	storage.SYSStateSyncPoint:              "SYSStateSyncPoint",
+	storage.SYSStateChangeStage:            "SYSStateChangeStage",
+	storage.SYSFork:                        "SYSFork",
+	storage.SYSVersion:                     "SYSVersion",
+}
+
+// sizeStat is the number of DB entries with their total key and value size.
+type sizeStat struct {
+	Keys      int
+	KeySize   int
+	ValueSize int
+}
+
+func (s *sizeStat) add(k, v []byte) {
+	s.Keys++
+	s.KeySize += len(k)
+	s.ValueSize += len(v)
+}
+
+// openInspectedDB opens the node DB in read-only mode and returns it along
+// with DAO over it (having the stored version).
+func openInspectedDB(ctx *cli.Context) (storage.Store, *dao.Simple, error) {
+	cfg, err := options.GetConfigFromContext(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	return openReadOnlyDB(cfg)
+}
+
+func openReadOnlyDB(cfg config.Config) (storage.Store, *dao.Simple, error) {
+	dbCfg := cfg.ApplicationConfiguration.DBConfiguration
+	dbCfg.LevelDBOptions.ReadOnly = true
+	dbCfg.BoltDBOptions.ReadOnly = true
+	dbCfg.StateDB.LevelDBOptions.ReadOnly = true
+	dbCfg.StateDB.BoltDBOptions.ReadOnly = true
+	store, err := storage.NewStore(dbCfg)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open the DB: %w", err)
+	}
+	d := dao.NewSimple(store, cfg.ProtocolConfiguration.StateRootInHeader)
+	ver, err := d.GetVersion()
+	if err != nil {
+		_ = store.Close()
+		return nil, nil, fmt.Errorf("failed to get DB version: %w", err)
+	}
+	d.Version = ver
+	return store, d, nil
+}
+
+// contractName returns the name of the contract with the given ID using
+// the native Management storage, an empty string is returned if it's unknown.
+func contractName(d *dao.Simple, id int32) (util.Uint160, string) {
+	h, err := native.GetContractScriptHash(d, id)
+	if err != nil {
+		return util.Uint160{}, ""
+	}
+	cs, err := getStoredContract(d, h)
+	if err != nil {
+		return h, ""
+	}
+	return h, cs.Manifest.Name
+}
+
+// getStoredContract reads contract state directly from the native Management
+// storage.
+func getStoredContract(d *dao.Simple, h util.Uint160) (*state.Contract, error) {
+	si := d.GetStorageItem(native.ManagementContractID, native.MakeContractKey(h))
+	if si == nil {
+		return nil, storage.ErrKeyNotFound
+	}
+	var cs = new(state.Contract)
+	if err := stackitem.DeserializeConvertible(si, cs); err != nil {
+		return nil, err
+	}
+	return cs, nil
+}
+
+// contractIDFromArg parses contract ID or hash (LE) and returns the contract ID.
+func contractIDFromArg(d *dao.Simple, s string) (int32, error) {
+	id, err := strconv.ParseInt(s, 10, 32)
+	if err == nil {
+		return int32(id), nil
+	}
+	h, err := util.Uint160DecodeStringLE(strings.TrimPrefix(s, "0x"))
+	if err != nil {
+		return 0, fmt.Errorf("invalid contract ID or hash %q", s)
+	}
+	cs, err := getStoredContract(d, h)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get contract %s: %w", h.StringLE(), err)
+	}
+	return cs.ID, nil
+}
+
+func decodeHexArg(s string) ([]byte, error) {
+	b, err := hex.DecodeString(strings.TrimPrefix(s, "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid hex key %q: %w", s, err)
+	}
+	return b, nil
+}
+
+// storageKeyFromArgs returns a raw DB key for the given key (or prefix) and
+// contract (if specified).
+func storageKeyFromArgs(ctx *cli.Context, d *dao.Simple, key []byte) ([]byte, error) {
+	contract := ctx.String("contract")
+	if contract == "" {
+		return key, nil
+	}
+	id, err := contractIDFromArg(d, contract)
+	if err != nil {
+		return nil, err
+	}
+	var k = make([]byte, 5+len(key))
+	k[0] = byte(d.Version.StoragePrefix)
+	binary.LittleEndian.PutUint32(k[1:], uint32(id))
+	copy(k[5:], key)
+	return k, nil
+}
+
+// dbStats prints statistics of the DB contents.
+func dbStats(ctx *cli.Context) error {
+	if err := cmdargs.EnsureNone(ctx); err != nil {
+		return err
+	}
+	store, d, err := openInspectedDB(ctx)
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	defer store.Close()
+
+	var (
+		prefixes  = make(map[storage.KeyPrefix]*sizeStat)
+		contracts = make(map[int32]*sizeStat)
+		blocks    sizeStat
+		txes      sizeStat
+		conflicts sizeStat
+		aerSize   int
+	)
+	store.Seek(storage.SeekRange{}, func(k, v []byte) bool {
+		p := storage.KeyPrefix(k[0])
+		if prefixes[p] == nil {
+			prefixes[p] = new(sizeStat)
+		}
+		prefixes[p].add(k, v)
+		switch {
+		case p == d.Version.StoragePrefix && len(k) >= 5:
+			id := int32(binary.LittleEndian.Uint32(k[1:]))
+			if contracts[id] == nil {
+				contracts[id] = new(sizeStat)
+			}
+			contracts[id].add(k, v)
+		case p == storage.DataExecutable && len(v) > 0:
+			r := io.NewBinReaderFromBuf(v[1:])
+			switch v[0] {
+			case storage.ExecBlock:
+				blocks.add(k, v)
+				_, _ = block.NewTrimmedFromReader(d.Version.StateRootInHeader, r)
+			case storage.ExecTransaction:
+				if len(k) != 1+util.Uint256Size || len(v) <= 5 {
+					conflicts.add(k, v)
+					return true
+				}
+				txes.add(k, v)
+				_ = r.ReadU32LE()
+				new(transaction.Transaction).DecodeBinary(r)
+			}
+			// Application logs follow the block or transaction.
+			if r.Err == nil {
+				aerSize += r.Len()
+			}
+		}
+		return true
+	})
+
+	var res []byte
+	res = fmt.Appendf(res, "Prefix\tName\tKeys\tKey size\tValue size\n")
+	var keys = make([]int, 0, len(prefixes))
+	for p := range prefixes {
+		keys = append(keys, int(p))
+	}
+	sort.Ints(keys)
+	var total sizeStat
+	for _, p := range keys {
+		s := prefixes[storage.KeyPrefix(p)]
+		res = fmt.Appendf(res, "0x%02x\t%s\t%d\t%d\t%d\n", p, prefixNames[storage.KeyPrefix(p)], s.Keys, s.KeySize, s.ValueSize)
+		total.Keys += s.Keys
+		total.KeySize += s.KeySize
+		total.ValueSize += s.ValueSize
+	}
+	res = fmt.Appendf(res, "\tTotal\t%d\t%d\t%d\n", total.Keys, total.KeySize, total.ValueSize)
+
+	res = fmt.Appendf(res, "\nContract ID\tHash\tName\tKeys\tKey size\tValue size\n")
+	keys = keys[:0]
+	for id := range contracts {
+		keys = append(keys, int(id))
+	}
+	sort.Ints(keys)
+	for _, id := range keys {
+		var (
+			s       = contracts[int32(id)]
+			h, name = contractName(d, int32(id))
+		)
+		res = fmt.Appendf(res, "%d\t%s\t%s\t%d\t%d\t%d\n", id, h.StringLE(), name, s.Keys, s.KeySize, s.ValueSize)
+	}
+
+	mpt := prefixes[storage.DataMPT]
+	if mpt == nil {
+		mpt = new(sizeStat)
+	}
+	res = fmt.Appendf(res, "\nMPT nodes:\t%d\t(%d bytes)\n", mpt.Keys, mpt.KeySize+mpt.ValueSize)
+	res = fmt.Appendf(res, "Blocks:\t%d\t(%d bytes)\n", blocks.Keys, blocks.KeySize+blocks.ValueSize)
+	res = fmt.Appendf(res, "Transactions:\t%d\t(%d bytes)\n", txes.Keys, txes.KeySize+txes.ValueSize)
+	res = fmt.Appendf(res, "Conflict records:\t%d\t(%d bytes)\n", conflicts.Keys, conflicts.KeySize+conflicts.ValueSize)
+	res = fmt.Appendf(res, "Application logs:\t\t(%d bytes)\n", aerSize)
+
+	tw := tabwriter.NewWriter(ctx.App.Writer, 0, 2, 2, ' ', 0)
+	if _, err = tw.Write(res); err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	return tw.Flush()
+}
+
+// dbGet prints the value of the given DB key (or contract storage key).
+func dbGet(ctx *cli.Context) error {
+	args := ctx.Args()
+	if len(args) != 1 {
+		return cli.NewExitError(errors.New("exactly one key is expected"), 1)
+	}
+	store, d, err := openInspectedDB(ctx)
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	defer store.Close()
+
+	key, err := decodeHexArg(args[0])
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	key, err = storageKeyFromArgs(ctx, d, key)
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	v, err := store.Get(key)
+	if err != nil {
+		return cli.NewExitError(fmt.Errorf("failed to get %x: %w", key, err), 1)
+	}
+	fmt.Fprintln(ctx.App.Writer, hex.EncodeToString(v))
+	return nil
+}
+
+// dbFind prints keys and values of DB items (or contract storage items) with
+// the given prefix.
+func dbFind(ctx *cli.Context) error {
+	args := ctx.Args()
+	if len(args) > 1 {
+		return cli.NewExitError(errors.New("only one prefix is expected"), 1)
+	}
+	store, d, err := openInspectedDB(ctx)
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	defer store.Close()
+
+	var prefix []byte
+	if len(args) == 1 {
+		prefix, err = decodeHexArg(args[0])
+		if err != nil {
+			return cli.NewExitError(err, 1)
+		}
+	}
+	var trim = 0
+	if ctx.String("contract") != "" {
+		trim = 5
+	}
+	prefix, err = storageKeyFromArgs(ctx, d, prefix)
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	var (
+		count = ctx.Uint("count")
+		n     uint
+	)
+	store.Seek(storage.SeekRange{Prefix: prefix}, func(k, v []byte) bool {
+		fmt.Fprintf(ctx.App.Writer, "%s\t%s\n", hex.EncodeToString(k[trim:]), hex.EncodeToString(v))
+		n++
+		return count == 0 || n < count
+	})
+	return nil
+}
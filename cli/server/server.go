@@ -1,7 +1,9 @@
 package server
 
 import (
+	"bufio"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"os"
@@ -17,14 +19,19 @@ import (
 	"github.com/nspcc-dev/neo-go/pkg/core"
 	"github.com/nspcc-dev/neo-go/pkg/core/block"
 	"github.com/nspcc-dev/neo-go/pkg/core/chaindump"
+	"github.com/nspcc-dev/neo-go/pkg/core/fork"
 	corestate "github.com/nspcc-dev/neo-go/pkg/core/stateroot"
 	"github.com/nspcc-dev/neo-go/pkg/core/storage"
 	"github.com/nspcc-dev/neo-go/pkg/core/transaction"
 	"github.com/nspcc-dev/neo-go/pkg/io"
 	"github.com/nspcc-dev/neo-go/pkg/network"
+	"github.com/nspcc-dev/neo-go/pkg/rpcclient"
+	"github.com/nspcc-dev/neo-go/pkg/services/ledgersink"
 	"github.com/nspcc-dev/neo-go/pkg/services/metrics"
 	"github.com/nspcc-dev/neo-go/pkg/services/notary"
 	"github.com/nspcc-dev/neo-go/pkg/services/oracle"
+	"github.com/nspcc-dev/neo-go/pkg/services/plugins"
+	"github.com/nspcc-dev/neo-go/pkg/services/replication"
 	"github.com/nspcc-dev/neo-go/pkg/services/rpcsrv"
 	"github.com/nspcc-dev/neo-go/pkg/services/stateroot"
 	"github.com/urfave/cli"
@@ -81,6 +88,18 @@ func NewCommands() []cli.Command {
 		Usage:    "Height of the state to reset DB to",
 		Required: true,
 	}
+	var cfgContractFlags = make([]cli.Flag, len(cfgFlags)+1)
+	copy(cfgContractFlags, cfgFlags)
+	cfgContractFlags[len(cfgContractFlags)-1] = cli.StringFlag{
+		Name:  "contract",
+		Usage: "ID or hash (LE) of the contract to look up the storage of",
+	}
+	var cfgFindFlags = make([]cli.Flag, len(cfgContractFlags)+1)
+	copy(cfgFindFlags, cfgContractFlags)
+	cfgFindFlags[len(cfgFindFlags)-1] = cli.UintFlag{
+		Name:  "count, c",
+		Usage: "maximum number of items to print (default or 0: all)",
+	}
 	return []cli.Command{
 		{
 			Name:      "node",
@@ -107,6 +126,13 @@ func NewCommands() []cli.Command {
 					Action:    restoreDB,
 					Flags:     cfgCountInFlags,
 				},
+				{
+					Name:      "export-diffs",
+					Usage:     "export contract storage changes made by blocks to the file",
+					UsageText: "neo-go db export-diffs -o file [-s start] [-c count] [--config-path path] [-p/-m/-t] [--config-file file]",
+					Action:    exportDiffs,
+					Flags:     cfgCountOutFlags,
+				},
 				{
 					Name:      "reset",
 					Usage:     "reset database to the previous state",
@@ -114,6 +140,27 @@ func NewCommands() []cli.Command {
 					Action:    resetDB,
 					Flags:     cfgHeightFlags,
 				},
+				{
+					Name:      "stats",
+					Usage:     "print DB statistics (key counts and sizes by prefix, contract storage sizes, etc.)",
+					UsageText: "neo-go db stats [--config-path path] [-p/-m/-t] [--config-file file]",
+					Action:    dbStats,
+					Flags:     cfgFlags,
+				},
+				{
+					Name:      "get",
+					Usage:     "print the value of the raw DB key or contract storage key (hex)",
+					UsageText: "neo-go db get [--contract id/hash] key [--config-path path] [-p/-m/-t] [--config-file file]",
+					Action:    dbGet,
+					Flags:     cfgContractFlags,
+				},
+				{
+					Name:      "find",
+					Usage:     "print raw DB items or contract storage items with the given prefix (hex)",
+					UsageText: "neo-go db find [--contract id/hash] [-c count] [prefix] [--config-path path] [-p/-m/-t] [--config-file file]",
+					Action:    dbFind,
+					Flags:     cfgFindFlags,
+				},
 			},
 		},
 	}
@@ -131,10 +178,10 @@ func newGraceContext() context.Context {
 	return ctx
 }
 
-func initBCWithMetrics(cfg config.Config, log *zap.Logger) (*core.Blockchain, *metrics.Service, *metrics.Service, error) {
-	chain, _, err := initBlockChain(cfg, log)
+func initBCWithMetrics(cfg config.Config, log *zap.Logger) (*core.Blockchain, storage.Store, *metrics.Service, *metrics.Service, error) {
+	chain, store, err := initBlockChain(cfg, log)
 	if err != nil {
-		return nil, nil, nil, cli.NewExitError(err, 1)
+		return nil, nil, nil, nil, cli.NewExitError(err, 1)
 	}
 	prometheus := metrics.NewPrometheusService(cfg.ApplicationConfiguration.Prometheus, log)
 	pprof := metrics.NewPprofService(cfg.ApplicationConfiguration.Pprof, log)
@@ -142,14 +189,14 @@ func initBCWithMetrics(cfg config.Config, log *zap.Logger) (*core.Blockchain, *m
 	go chain.Run()
 	err = prometheus.Start()
 	if err != nil {
-		return nil, nil, nil, cli.NewExitError(fmt.Errorf("failed to start Prometheus service: %w", err), 1)
+		return nil, nil, nil, nil, cli.NewExitError(fmt.Errorf("failed to start Prometheus service: %w", err), 1)
 	}
 	err = pprof.Start()
 	if err != nil {
-		return nil, nil, nil, cli.NewExitError(fmt.Errorf("failed to start Pprof service: %w", err), 1)
+		return nil, nil, nil, nil, cli.NewExitError(fmt.Errorf("failed to start Pprof service: %w", err), 1)
 	}
 
-	return chain, prometheus, pprof, nil
+	return chain, store, prometheus, pprof, nil
 }
 
 func dumpDB(ctx *cli.Context) error {
@@ -180,7 +227,7 @@ func dumpDB(ctx *cli.Context) error {
 	defer outStream.Close()
 	writer := io.NewBinWriterFromIO(outStream)
 
-	chain, prometheus, pprof, err := initBCWithMetrics(cfg, log)
+	chain, _, prometheus, pprof, err := initBCWithMetrics(cfg, log)
 	if err != nil {
 		return err
 	}
@@ -208,6 +255,72 @@ func dumpDB(ctx *cli.Context) error {
 	return nil
 }
 
+// exportDiffs writes state diffs of the specified blocks as a sequence of
+// newline-delimited JSON objects.
+func exportDiffs(ctx *cli.Context) error {
+	if err := cmdargs.EnsureNone(ctx); err != nil {
+		return err
+	}
+	cfg, err := options.GetConfigFromContext(ctx)
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	if cfg.ApplicationConfiguration.Ledger.KeepOnlyLatestState {
+		return cli.NewExitError("state diffs can't be exported with KeepOnlyLatestState enabled", 1)
+	}
+	log, _, logCloser, err := options.HandleLoggingParams(ctx.Bool("debug"), cfg.ApplicationConfiguration)
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	if logCloser != nil {
+		defer func() { _ = logCloser() }()
+	}
+	count := uint32(ctx.Uint("count"))
+	start := uint32(ctx.Uint("start"))
+
+	var outStream = os.Stdout
+	if out := ctx.String("out"); out != "" {
+		outStream, err = os.Create(out)
+		if err != nil {
+			return cli.NewExitError(err, 1)
+		}
+	}
+	defer outStream.Close()
+
+	chain, _, prometheus, pprof, err := initBCWithMetrics(cfg, log)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		pprof.ShutDown()
+		prometheus.ShutDown()
+		chain.Close()
+	}()
+
+	chainCount := chain.BlockHeight() + 1
+	if start+count > chainCount {
+		return cli.NewExitError(fmt.Errorf("chain is not that high (%d) to export %d diffs starting from %d", chainCount-1, count, start), 1)
+	}
+	if count == 0 {
+		count = chainCount - start
+	}
+	w := bufio.NewWriter(outStream)
+	enc := json.NewEncoder(w)
+	for i := start; i < start+count; i++ {
+		diff, err := chain.GetStateDiff(i)
+		if err != nil {
+			return cli.NewExitError(fmt.Errorf("failed to get state diff for block %d: %w", i, err), 1)
+		}
+		if err = enc.Encode(diff); err != nil {
+			return cli.NewExitError(fmt.Errorf("failed to write state diff for block %d: %w", i, err), 1)
+		}
+	}
+	if err = w.Flush(); err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	return nil
+}
+
 func restoreDB(ctx *cli.Context) error {
 	if err := cmdargs.EnsureNone(ctx); err != nil {
 		return err
@@ -240,7 +353,7 @@ func restoreDB(ctx *cli.Context) error {
 		cfg.ApplicationConfiguration.SaveStorageBatch = true
 	}
 
-	chain, prometheus, pprof, err := initBCWithMetrics(cfg, log)
+	chain, _, prometheus, pprof, err := initBCWithMetrics(cfg, log)
 	if err != nil {
 		return err
 	}
@@ -385,6 +498,34 @@ func mkOracle(config config.OracleConfiguration, magic netmode.Magic, chain *cor
 	return orc, nil
 }
 
+// pluginService is an interface representing plugin host that handles RPC
+// methods registered by plugins.
+type pluginService interface {
+	rpcsrv.ExtensionHandler
+	Start() error
+	Shutdown()
+}
+
+// mkPlugins creates and starts plugin host if there are any enabled plugins.
+func mkPlugins(cfgs []config.Plugin, chain *core.Blockchain, serv *network.Server, log *zap.Logger) (pluginService, error) {
+	var enabled bool
+	for _, c := range cfgs {
+		enabled = enabled || c.Enabled
+	}
+	if !enabled {
+		return nil, nil
+	}
+	h, err := plugins.NewHost(cfgs, chain, serv, log)
+	if err != nil {
+		return nil, fmt.Errorf("can't initialize plugins: %w", err)
+	}
+	err = h.Start()
+	if err != nil {
+		return nil, fmt.Errorf("failed to start plugins: %w", err)
+	}
+	return h, nil
+}
+
 func mkConsensus(config config.Consensus, tpb time.Duration, chain *core.Blockchain, serv *network.Server, log *zap.Logger) (consensus.Service, error) {
 	if !config.Enabled {
 		return nil, nil
@@ -399,6 +540,9 @@ func mkConsensus(config config.Consensus, tpb time.Duration, chain *core.Blockch
 		StopTxFlow:            serv.StopTxFlow,
 		Wallet:                config.UnlockWallet,
 		TimePerBlock:          tpb,
+		JournalFile:           config.JournalFile,
+		RemoteSigner:          config.RemoteSigner,
+		InstantMining:         config.InstantMining,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("can't initialize Consensus module: %w", err)
@@ -435,6 +579,30 @@ func mkP2PNotary(config config.P2PNotary, chain *core.Blockchain, serv *network.
 	return n, nil
 }
 
+// startLedgerSinks creates and starts services for all enabled ledger sinks.
+func startLedgerSinks(cfgs []config.LedgerSink, chain *core.Blockchain, log *zap.Logger) ([]*ledgersink.Service, error) {
+	var sinks []*ledgersink.Service
+	for i, cfg := range cfgs {
+		if !cfg.Enabled {
+			continue
+		}
+		s, err := ledgersink.New(cfg, chain, log)
+		if err != nil {
+			stopLedgerSinks(sinks)
+			return nil, fmt.Errorf("can't create ledger sink #%d: %w", i, err)
+		}
+		s.Start()
+		sinks = append(sinks, s)
+	}
+	return sinks, nil
+}
+
+func stopLedgerSinks(sinks []*ledgersink.Service) {
+	for _, s := range sinks {
+		s.Shutdown()
+	}
+}
+
 func startServer(ctx *cli.Context) error {
 	if err := cmdargs.EnsureNone(ctx); err != nil {
 		return err
@@ -461,7 +629,14 @@ func startServer(ctx *cli.Context) error {
 		return cli.NewExitError(err, 1)
 	}
 
-	chain, prometheus, pprof, err := initBCWithMetrics(cfg, log)
+	var isReplica = cfg.ApplicationConfiguration.Replica.Enabled
+	if isReplica && (cfg.ApplicationConfiguration.Consensus.Enabled ||
+		cfg.ApplicationConfiguration.Oracle.Enabled ||
+		cfg.ApplicationConfiguration.P2PNotary.Enabled) {
+		return cli.NewExitError(errors.New("consensus, oracle and notary services can't be enabled on replica"), 1)
+	}
+
+	chain, store, prometheus, pprof, err := initBCWithMetrics(cfg, log)
 	if err != nil {
 		return cli.NewExitError(err, 1)
 	}
@@ -471,6 +646,30 @@ func startServer(ctx *cli.Context) error {
 		chain.Close()
 	}()
 
+	replStore, _ := store.(*replication.Store)
+	var replSrv *replication.Service
+	if replStore != nil {
+		replSrv = replication.NewService(cfg.ApplicationConfiguration.Replication, replStore, log)
+		err = replSrv.Start()
+		if err != nil {
+			return cli.NewExitError(fmt.Errorf("failed to start Replication service: %w", err), 1)
+		}
+		defer func() { replSrv.ShutDown() }()
+	}
+	sinks, err := startLedgerSinks(cfg.ApplicationConfiguration.LedgerSinks, chain, log)
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	defer func() { stopLedgerSinks(sinks) }()
+
+	var replica *replication.Replica
+	if isReplica {
+		replica, err = replication.NewReplica(cfg.ApplicationConfiguration.Replica, chain, log)
+		if err != nil {
+			return cli.NewExitError(fmt.Errorf("failed to create replica: %w", err), 1)
+		}
+	}
+
 	serv, err := network.NewServer(serverConfig, chain, chain.GetStateSyncModule(), log)
 	if err != nil {
 		return cli.NewExitError(fmt.Errorf("failed to create network server: %w", err), 1)
@@ -494,12 +693,28 @@ func startServer(ctx *cli.Context) error {
 	if err != nil {
 		return cli.NewExitError(err, 1)
 	}
+	pluginHost, err := mkPlugins(cfg.ApplicationConfiguration.Plugins, chain, serv, log)
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	if pluginHost != nil {
+		defer pluginHost.Shutdown()
+	}
+
 	errChan := make(chan error)
 	rpcServer := rpcsrv.New(chain, cfg.ApplicationConfiguration.RPC, serv, oracleSrv, log, errChan)
+	rpcServer.SetConsensusHandler(dbftSrv)
+	rpcServer.SetExtensionHandler(pluginHost)
 	serv.AddService(&rpcServer)
 
-	serv.Start()
-	if !cfg.ApplicationConfiguration.RPC.StartWhenSynchronized {
+	if isReplica {
+		// Replica doesn't use P2P, it's always in sync with its primary node.
+		replica.Start()
+		go rpcServer.Start()
+	} else {
+		serv.Start()
+	}
+	if !isReplica && !cfg.ApplicationConfiguration.RPC.StartWhenSynchronized {
 		// Run RPC server in a separate routine. This is necessary to avoid a potential
 		// deadlock: Start() can write errors to errChan which is not yet read in the
 		// current execution context (see for-loop below).
@@ -508,8 +723,10 @@ func startServer(ctx *cli.Context) error {
 
 	sigCh := make(chan os.Signal, 1)
 	signal.Notify(sigCh, sighup)
-	signal.Notify(sigCh, sigusr1)
-	signal.Notify(sigCh, sigusr2)
+	if !isReplica {
+		signal.Notify(sigCh, sigusr1)
+		signal.Notify(sigCh, sigusr2)
+	}
 
 	fmt.Fprintln(ctx.App.Writer, Logo())
 	fmt.Fprintln(ctx.App.Writer, serv.UserAgent)
@@ -555,8 +772,10 @@ Main:
 				serv.DelService(&rpcServer)
 				rpcServer.Shutdown()
 				rpcServer = rpcsrv.New(chain, cfgnew.ApplicationConfiguration.RPC, serv, oracleSrv, log, errChan)
+				rpcServer.SetConsensusHandler(dbftSrv)
+				rpcServer.SetExtensionHandler(pluginHost)
 				serv.AddService(&rpcServer)
-				if !cfgnew.ApplicationConfiguration.RPC.StartWhenSynchronized || serv.IsInSync() {
+				if isReplica || !cfgnew.ApplicationConfiguration.RPC.StartWhenSynchronized || serv.IsInSync() {
 					// Here similar to the initial run (see above for-loop), so async.
 					go rpcServer.Start()
 				}
@@ -574,6 +793,21 @@ Main:
 					shutdownErr = fmt.Errorf("failed to start Prometheus service: %w", err)
 					cancel() // Fatal error, like for RPC server.
 				}
+				if replSrv != nil {
+					replSrv.ShutDown()
+					replSrv = replication.NewService(cfgnew.ApplicationConfiguration.Replication, replStore, log)
+					err = replSrv.Start()
+					if err != nil {
+						shutdownErr = fmt.Errorf("failed to start Replication service: %w", err)
+						cancel() // Fatal error, like for RPC server.
+					}
+				}
+				stopLedgerSinks(sinks)
+				sinks, err = startLedgerSinks(cfgnew.ApplicationConfiguration.LedgerSinks, chain, log)
+				if err != nil {
+					shutdownErr = err
+					cancel() // Fatal error, like for RPC server.
+				}
 			case sigusr1:
 				if oracleSrv != nil {
 					serv.DelService(oracleSrv)
@@ -620,6 +854,7 @@ Main:
 			case sigusr2:
 				if dbftSrv != nil {
 					serv.DelConsensusService(dbftSrv)
+					rpcServer.SetConsensusHandler(nil)
 					dbftSrv.Shutdown()
 				}
 				dbftSrv, err = mkConsensus(cfgnew.ApplicationConfiguration.Consensus, serverConfig.TimePerBlock, chain, serv, log)
@@ -627,6 +862,7 @@ Main:
 					log.Error("failed to create consensus service", zap.Error(err))
 					break // Whatever happens, I'll leave it all to chance.
 				}
+				rpcServer.SetConsensusHandler(dbftSrv)
 				if dbftSrv != nil && serv.IsInSync() {
 					dbftSrv.Start()
 				}
@@ -634,7 +870,12 @@ Main:
 			cfg = cfgnew
 		case <-grace.Done():
 			signal.Stop(sigCh)
-			serv.Shutdown()
+			if isReplica {
+				rpcServer.Shutdown()
+				replica.Shutdown()
+			} else {
+				serv.Shutdown()
+			}
 			break Main
 		}
 	}
@@ -652,6 +893,17 @@ func initBlockChain(cfg config.Config, log *zap.Logger) (*core.Blockchain, stora
 	if err != nil {
 		return nil, nil, cli.NewExitError(fmt.Errorf("could not initialize storage: %w", err), 1)
 	}
+	if cfg.ApplicationConfiguration.Fork.Endpoint != "" {
+		fs, err := newForkStore(store, cfg, log)
+		if err != nil {
+			_ = store.Close()
+			return nil, nil, cli.NewExitError(fmt.Errorf("could not fork the network: %w", err), 1)
+		}
+		store = fs
+	}
+	if cfg.ApplicationConfiguration.Replication.Enabled {
+		store = replication.NewStore(store, cfg.ApplicationConfiguration.Replication.BacklogSize)
+	}
 
 	chain, err := core.NewBlockchain(store, cfg.Blockchain(), log)
 	if err != nil {
@@ -668,6 +920,29 @@ func initBlockChain(cfg config.Config, log *zap.Logger) (*core.Blockchain, stora
 	return chain, store, nil
 }
 
+// newForkStore wraps the store into the fork store fetching the remote network
+// state from the configured RPC node.
+func newForkStore(store storage.Store, cfg config.Config, log *zap.Logger) (storage.Store, error) {
+	var forkCfg = cfg.ApplicationConfiguration.Fork
+
+	c, err := rpcclient.New(context.Background(), forkCfg.Endpoint, rpcclient.Options{RequestTimeout: forkCfg.Timeout})
+	if err != nil {
+		return nil, err
+	}
+	v, err := c.GetVersion()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get remote node version: %w", err)
+	}
+	if v.Protocol.Network != cfg.ProtocolConfiguration.Magic {
+		return nil, fmt.Errorf("remote network magic %d doesn't match the configured one %d",
+			v.Protocol.Network, cfg.ProtocolConfiguration.Magic)
+	}
+	if err = c.Init(); err != nil {
+		return nil, err
+	}
+	return fork.NewStore(store, c, cfg.Blockchain(), forkCfg.Height, log)
+}
+
 // Logo returns NeoGo logo.
 func Logo() string {
 	return `
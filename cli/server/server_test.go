@@ -9,6 +9,7 @@ import (
 	"testing"
 
 	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest"
 
 	"github.com/nspcc-dev/neo-go/cli/options"
 	"github.com/nspcc-dev/neo-go/pkg/config"
@@ -185,11 +186,11 @@ func TestInitBCWithMetrics(t *testing.T) {
 	})
 
 	t.Run("bad store", func(t *testing.T) {
-		_, _, _, err = initBCWithMetrics(config.Config{}, logger)
+		_, _, _, _, err = initBCWithMetrics(config.Config{}, logger)
 		require.Error(t, err)
 	})
 
-	chain, prometheus, pprof, err := initBCWithMetrics(cfg, logger)
+	chain, _, prometheus, pprof, err := initBCWithMetrics(cfg, logger)
 	require.NoError(t, err)
 	t.Cleanup(func() {
 		chain.Close()
@@ -390,3 +391,17 @@ func TestResetDB(t *testing.T) {
 	err = resetDB(ctx)
 	require.NoError(t, err)
 }
+
+func TestMkPlugins(t *testing.T) {
+	log := zaptest.NewLogger(t)
+	h, err := mkPlugins(nil, nil, nil, log)
+	require.NoError(t, err)
+	require.Nil(t, h)
+
+	h, err = mkPlugins([]config.Plugin{{Address: "localhost:0"}}, nil, nil, log)
+	require.NoError(t, err)
+	require.Nil(t, h)
+
+	_, err = mkPlugins([]config.Plugin{{Enabled: true}}, nil, nil, log)
+	require.ErrorContains(t, err, "can't initialize plugins")
+}
@@ -17,6 +17,7 @@ import (
 	"github.com/nspcc-dev/neo-go/internal/versionutil"
 	"github.com/nspcc-dev/neo-go/pkg/config"
 	"github.com/nspcc-dev/neo-go/pkg/core/interop/storage"
+	"github.com/nspcc-dev/neo-go/pkg/core/native/nativehashes"
 	"github.com/nspcc-dev/neo-go/pkg/core/state"
 	"github.com/nspcc-dev/neo-go/pkg/core/transaction"
 	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
@@ -1172,3 +1173,53 @@ func TestContractCompile_NEFSizeCheck(t *testing.T) {
 	e.RunWithError(t, "neo-go", "contract", "compile", "--in", in)
 	require.NoFileExists(t, filepath.Join(tmpDir, "main.nef"))
 }
+
+func TestContractDumpStorage(t *testing.T) {
+	e := testcli.NewExecutor(t, true)
+	h := testcli.DeployContract(t, e, "testdata/deploy/main.go", "testdata/deploy/neo-go.yml", testcli.ValidatorWallet, testcli.ValidatorAddr, testcli.ValidatorPass)
+
+	tmpDir := t.TempDir()
+	jsonOut := filepath.Join(tmpDir, "contract.json")
+	binOut := filepath.Join(tmpDir, "contract.dump")
+	cmd := []string{"neo-go", "contract", "dump-storage", "--rpc-endpoint", "http://" + e.RPC.Addresses()[0]}
+
+	t.Run("errors", func(t *testing.T) {
+		e.RunWithError(t, append(cmd, "--out", jsonOut)...)
+		e.RunWithError(t, append(cmd, h.StringLE())...)
+		e.RunWithError(t, append(cmd, "--out", jsonOut, h.StringLE(), "extra")...)
+		e.RunWithError(t, append(cmd, "--out", jsonOut, "not-a-hash")...)
+		e.RunWithError(t, append(cmd, "--out", jsonOut, random.Uint160().StringLE())...)
+		e.RunWithError(t, append(cmd, "--out", jsonOut, "--height", "0", h.StringLE())...)
+		e.RunWithError(t, append(cmd, "--out", jsonOut, nativehashes.GasToken.StringLE())...)
+	})
+
+	height := e.Chain.BlockHeight()
+	e.Run(t, append(cmd, "--out", jsonOut, h.StringLE())...)
+	e.CheckNextLine(t, fmt.Sprintf("^Contract %s with \\d+ storage items at height %d saved to", h.StringLE(), height))
+	e.CheckEOF(t)
+	e.Run(t, append(cmd, "--out", binOut, "--binary", "--height", strconv.Itoa(int(height)), h.StringLE())...)
+
+	data, err := os.ReadFile(jsonOut)
+	require.NoError(t, err)
+	d, err := state.DecodeContractDump(data)
+	require.NoError(t, err)
+	data, err = os.ReadFile(binOut)
+	require.NoError(t, err)
+	require.False(t, json.Valid(data))
+	dBin, err := state.DecodeContractDump(data)
+	require.NoError(t, err)
+	require.Equal(t, d, dBin)
+
+	cs := e.Chain.GetContractState(h)
+	require.Equal(t, *cs, d.Contract)
+	require.Equal(t, height, d.Height)
+	require.Equal(t, e.Chain.GetStateModule().CurrentLocalStateRoot(), d.StateRoot)
+	var expected []state.ContractDumpItem
+	e.Chain.SeekStorage(cs.ID, nil, func(k, v []byte) bool {
+		expected = append(expected, state.ContractDumpItem{Key: bytes.Clone(k), Value: bytes.Clone(v)})
+		return true
+	})
+	require.Equal(t, expected, d.Storage)
+	require.Contains(t, d.Storage, state.ContractDumpItem{Key: []byte("key"), Value: []byte("on create")})
+	require.Contains(t, d.Storage, state.ContractDumpItem{Key: []byte("mgmt"), Value: nativehashes.ContractManagement.BytesBE()})
+}
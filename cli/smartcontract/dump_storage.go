@@ -0,0 +1,145 @@
+package smartcontract
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/nspcc-dev/neo-go/cli/flags"
+	"github.com/nspcc-dev/neo-go/cli/options"
+	"github.com/nspcc-dev/neo-go/pkg/core/native"
+	"github.com/nspcc-dev/neo-go/pkg/core/native/nativehashes"
+	"github.com/nspcc-dev/neo-go/pkg/core/state"
+	"github.com/nspcc-dev/neo-go/pkg/io"
+	"github.com/nspcc-dev/neo-go/pkg/rpcclient"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/nspcc-dev/neo-go/pkg/vm/stackitem"
+	"github.com/urfave/cli"
+)
+
+var dumpStorageCmd = cli.Command{
+	Name:      "dump-storage",
+	Usage:     "dump contract state and storage at the given height",
+	UsageText: "neo-go contract dump-storage -r endpoint [-s timeout] [--height index] [--binary] -o file scripthash",
+	Description: `Fetches NEF, manifest and all storage items of the contract with the given
+   script hash (or address) at the given height (the latest local state height
+   of the RPC node by default) via getstate and findstates RPC calls and saves
+   them to the file (in JSON format by default). Historic states must be
+   available on the RPC node, large contracts may need an increased timeout.
+   The dump can be imported into the private network genesis block (see
+   ContractDumps genesis configuration) or into neotest chains.
+`,
+	Action: dumpStorage,
+	Flags: append([]cli.Flag{
+		cli.UintFlag{
+			Name:  "height",
+			Usage: "height of the state to dump (the latest local state height by default)",
+		},
+		cli.StringFlag{
+			Name:  "out, o",
+			Usage: "file to save the dump to",
+		},
+		cli.BoolFlag{
+			Name:  "binary",
+			Usage: "use binary format instead of JSON",
+		},
+	}, options.RPC...),
+}
+
+func dumpStorage(ctx *cli.Context) error {
+	args := ctx.Args()
+	if !args.Present() {
+		return cli.NewExitError(errNoScriptHash, 1)
+	}
+	if len(args) > 1 {
+		return cli.NewExitError(fmt.Errorf("additional arguments given: %v", args[1:]), 1)
+	}
+	h, err := flags.ParseAddress(args[0])
+	if err != nil {
+		return cli.NewExitError(fmt.Errorf("incorrect script hash: %w", err), 1)
+	}
+	out := ctx.String("out")
+	if out == "" {
+		return cli.NewExitError(errors.New("no output file was provided, specify it with the '--out' or '-o' flag"), 1)
+	}
+
+	gctx, cancel := options.GetTimeoutContext(ctx)
+	defer cancel()
+	c, exitErr := options.GetRPCClient(gctx, ctx)
+	if exitErr != nil {
+		return exitErr
+	}
+	defer c.Close()
+
+	var height = uint32(ctx.Uint("height"))
+	if !ctx.IsSet("height") {
+		sh, err := c.GetStateHeight()
+		if err != nil {
+			return cli.NewExitError(fmt.Errorf("failed to get state height: %w", err), 1)
+		}
+		height = sh.Local
+	}
+	d, err := getContractDump(c, h, height)
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+
+	var data []byte
+	if ctx.Bool("binary") {
+		w := io.NewBufBinWriter()
+		d.EncodeBinary(w.BinWriter)
+		if err = w.Err; err == nil {
+			data = w.Bytes()
+		}
+	} else {
+		data, err = json.Marshal(d)
+	}
+	if err != nil {
+		return cli.NewExitError(fmt.Errorf("failed to encode dump: %w", err), 1)
+	}
+	if err = os.WriteFile(out, data, 0644); err != nil {
+		return cli.NewExitError(fmt.Errorf("failed to write dump: %w", err), 1)
+	}
+	fmt.Fprintf(ctx.App.Writer, "Contract %s with %d storage items at height %d saved to %s\n",
+		h.StringLE(), len(d.Storage), height, out)
+	return nil
+}
+
+// getContractDump fetches the contract state and all of its storage items at
+// the given height.
+func getContractDump(c *rpcclient.Client, h util.Uint160, height uint32) (*state.ContractDump, error) {
+	root, err := c.GetStateRootByHeight(height)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get state root at %d: %w", height, err)
+	}
+	d := &state.ContractDump{
+		Height:    height,
+		StateRoot: root.Root,
+	}
+	cs, err := c.GetState(root.Root, nativehashes.ContractManagement, native.MakeContractKey(h))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get contract state: %w", err)
+	}
+	if err = stackitem.DeserializeConvertible(cs, &d.Contract); err != nil {
+		return nil, fmt.Errorf("failed to decode contract state: %w", err)
+	}
+	if d.Contract.ID < 0 {
+		return nil, errors.New("native contracts can't be dumped")
+	}
+	var start []byte
+	for {
+		res, err := c.FindStates(root.Root, h, nil, start, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get storage items: %w", err)
+		}
+		for _, kv := range res.Results {
+			d.Storage = append(d.Storage, state.ContractDumpItem{Key: kv.Key, Value: kv.Value})
+		}
+		if !res.Truncated || len(res.Results) == 0 {
+			break
+		}
+		start = res.Results[len(res.Results)-1].Key
+	}
+	return d, nil
+}
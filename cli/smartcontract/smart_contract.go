@@ -202,6 +202,7 @@ func NewCommands() []cli.Command {
 			},
 			generateWrapperCmd,
 			generateRPCWrapperCmd,
+			dumpStorageCmd,
 			{
 				Name:      "invokefunction",
 				Usage:     "invoke deployed contract on the blockchain",
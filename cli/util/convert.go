@@ -5,6 +5,7 @@ import (
 	"encoding/hex"
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/nspcc-dev/neo-go/cli/flags"
 	"github.com/nspcc-dev/neo-go/cli/options"
@@ -103,6 +104,91 @@ func NewCommands() []cli.Command {
 						},
 					},
 				},
+				{
+					Name:      "p2p-decode",
+					Usage:     "Decode P2P capture file made by the node",
+					UsageText: "p2p-decode <capture-file>",
+					Description: `Decodes all messages from the given P2P capture file (see P2P.Capture node
+   configuration section) and prints them as a stream of JSON objects (one per
+   line) containing capture timestamp, direction, remote peer address, message
+   command and decoded payload. Messages that can't be decoded are printed with
+   an error description.
+`,
+					Action: p2pDecode,
+				},
+				{
+					Name:      "p2p-replay",
+					Usage:     "Replay inbound messages from P2P capture file to the node",
+					UsageText: "p2p-replay <capture-file> -a <host:port> [--timing] [--timeout <duration>]",
+					Description: `Connects to the node at the given address, performs P2P handshake using
+   the network magic from the capture file and sends all inbound messages
+   (except version and verack) from the capture file to it. Pings sent by the
+   node are answered to keep the connection alive, other messages are ignored.
+   If --timing flag is set, original intervals between messages are preserved,
+   otherwise messages are sent as fast as possible.
+`,
+					Action: p2pReplay,
+					Flags: []cli.Flag{
+						cli.StringFlag{
+							Name:  "address, a",
+							Usage: "P2P address of the node to replay messages to",
+						},
+						cli.BoolFlag{
+							Name:  "timing",
+							Usage: "preserve original intervals between messages",
+						},
+						cli.DurationFlag{
+							Name:  "timeout",
+							Value: 10 * time.Second,
+							Usage: "connection timeout",
+						},
+					},
+				},
+				{
+					Name:      "signer",
+					Usage:     "Run remote signer for consensus and state validator keys",
+					UsageText: "signer -a <address> --db <path> [--token-file <path> --tls-cert <path> --tls-key <path>] --wallet <wallet> | --wallet-config <path> [--config-path path] [-p/-m/-t] [--config-file file] [-d]",
+					Description: `Runs a signer process holding private keys of all wallet accounts that can
+   be unlocked with the password given. Consensus and state root services of
+   the node can use it via RemoteSigner configuration section instead of
+   unlocking the wallet themselves. The signer listens either on Unix socket
+   (unix:/path/to/socket address) or on TCP (https://host:port address), the
+   latter requires the token file with the secret clients must provide and
+   TLS certificate and key files.
+   Network parameters are taken from the node configuration, the signer
+   only signs block headers, state roots and extensible payloads of this
+   network. Block and state root signatures are recorded in the protection
+   database, the signer refuses to sign a different block or state root for
+   the height it has already signed something for.
+`,
+					Action: runSigner,
+					Flags: append(append([]cli.Flag{
+						cli.StringFlag{
+							Name:  "address, a",
+							Usage: "address to listen on (unix:/path/to/socket or https://host:port)",
+						},
+						cli.StringFlag{
+							Name:  "db",
+							Usage: "path to the double-signing protection database",
+						},
+						cli.StringFlag{
+							Name:  "token-file",
+							Usage: "path to the file with the token clients must provide (required for TCP address)",
+						},
+						cli.StringFlag{
+							Name:  "tls-cert",
+							Usage: "path to the TLS certificate file (required for TCP address)",
+						},
+						cli.StringFlag{
+							Name:  "tls-key",
+							Usage: "path to the TLS key file (required for TCP address)",
+						},
+						options.Config,
+						options.ConfigFile,
+						options.RelativePath,
+						options.Debug,
+					}, options.Network...), options.Wallet...),
+				},
 			},
 		},
 	}
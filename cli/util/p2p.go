@@ -0,0 +1,237 @@
+package util
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/nspcc-dev/neo-go/pkg/config/netmode"
+	nio "github.com/nspcc-dev/neo-go/pkg/io"
+	"github.com/nspcc-dev/neo-go/pkg/network"
+	"github.com/nspcc-dev/neo-go/pkg/network/payload"
+	"github.com/urfave/cli"
+)
+
+// replayUserAgent is the user agent used by p2p-replay command.
+const replayUserAgent = "/NEO-GO-replay/"
+
+type (
+	// capturedMessage is a JSON representation of a captured P2P message.
+	capturedMessage struct {
+		Timestamp  time.Time `json:"timestamp"`
+		Direction  string    `json:"direction"`
+		Peer       string    `json:"peer"`
+		Command    string    `json:"command,omitempty"`
+		Compressed bool      `json:"compressed,omitempty"`
+		Payload    any       `json:"payload,omitempty"`
+		Error      string    `json:"error,omitempty"`
+	}
+
+	// versionJSON is a JSON representation of the Version payload with
+	// human-readable user agent.
+	versionJSON struct {
+		*payload.Version
+		UserAgent string `json:"UserAgent"`
+	}
+)
+
+func openCapture(ctx *cli.Context) (*os.File, *network.CaptureReader, error) {
+	args := ctx.Args()
+	if len(args) == 0 {
+		return nil, nil, errors.New("missing input file")
+	} else if len(args) > 1 {
+		return nil, nil, errors.New("only one input file is accepted")
+	}
+	f, err := os.Open(args[0])
+	if err != nil {
+		return nil, nil, err
+	}
+	cr, err := network.NewCaptureReader(bufio.NewReader(f))
+	if err != nil {
+		f.Close()
+		return nil, nil, fmt.Errorf("failed to read capture: %w", err)
+	}
+	return f, cr, nil
+}
+
+func p2pDecode(ctx *cli.Context) error {
+	f, cr, err := openCapture(ctx)
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	defer f.Close()
+
+	enc := json.NewEncoder(ctx.App.Writer)
+	for {
+		rec, err := cr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return cli.NewExitError(fmt.Errorf("failed to read capture record: %w", err), 1)
+		}
+		msgs, err := rec.Messages(cr.StateRootInHeader)
+		for _, msg := range msgs {
+			var p = msg.Payload
+			if v, ok := p.(*payload.Version); ok {
+				p = versionJSON{Version: v, UserAgent: string(v.UserAgent)}
+			} else if _, ok := p.(payload.NullPayload); ok {
+				p = nil
+			}
+			err := enc.Encode(capturedMessage{
+				Timestamp:  rec.Timestamp,
+				Direction:  rec.Direction.String(),
+				Peer:       rec.Peer,
+				Command:    strings.TrimPrefix(msg.Command.String(), "CMD"),
+				Compressed: msg.Flags&network.Compressed != 0,
+				Payload:    p,
+			})
+			if err != nil {
+				return cli.NewExitError(err, 1)
+			}
+		}
+		if err != nil {
+			err = enc.Encode(capturedMessage{
+				Timestamp: rec.Timestamp,
+				Direction: rec.Direction.String(),
+				Peer:      rec.Peer,
+				Error:     err.Error(),
+			})
+			if err != nil {
+				return cli.NewExitError(err, 1)
+			}
+		}
+	}
+}
+
+func p2pReplay(ctx *cli.Context) error {
+	addr := ctx.String("address")
+	if addr == "" {
+		return cli.NewExitError("target node address is not specified", 1)
+	}
+	f, cr, err := openCapture(ctx)
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	defer f.Close()
+
+	conn, err := net.DialTimeout("tcp", addr, ctx.Duration("timeout"))
+	if err != nil {
+		return cli.NewExitError(fmt.Errorf("failed to connect to %s: %w", addr, err), 1)
+	}
+	defer conn.Close()
+
+	if err = replayHandshake(conn, cr.Magic, cr.StateRootInHeader); err != nil {
+		return cli.NewExitError(fmt.Errorf("handshake failed: %w", err), 1)
+	}
+	readErr := make(chan error, 1)
+	go func() { readErr <- replayReadLoop(conn, cr.StateRootInHeader) }()
+
+	var (
+		withTiming = ctx.Bool("timing")
+		prevTime   time.Time
+		sent       int
+	)
+	for {
+		rec, err := cr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return cli.NewExitError(fmt.Errorf("failed to read capture record: %w", err), 1)
+		}
+		if rec.Direction != network.CaptureInbound {
+			continue
+		}
+		msgs, err := rec.Messages(cr.StateRootInHeader)
+		if err != nil || len(msgs) == 0 || msgs[0].Command == network.CMDVersion || msgs[0].Command == network.CMDVerack {
+			continue
+		}
+		if withTiming && !prevTime.IsZero() {
+			time.Sleep(rec.Timestamp.Sub(prevTime))
+		}
+		prevTime = rec.Timestamp
+		select {
+		case err = <-readErr:
+			return cli.NewExitError(fmt.Errorf("connection failed after %d messages: %w", sent, err), 1)
+		default:
+		}
+		if _, err = conn.Write(rec.Data); err != nil {
+			return cli.NewExitError(fmt.Errorf("failed to send message: %w", err), 1)
+		}
+		sent += len(msgs)
+	}
+	fmt.Fprintf(ctx.App.Writer, "%d messages replayed\n", sent)
+	return nil
+}
+
+// replayHandshake performs P2P handshake with the node.
+func replayHandshake(conn net.Conn, magic netmode.Magic, stateRootInHeader bool) error {
+	ver, err := network.NewMessage(network.CMDVersion,
+		payload.NewVersion(magic, uint32(time.Now().UnixNano()), replayUserAgent, nil)).Bytes()
+	if err != nil {
+		return err
+	}
+	if _, err = conn.Write(ver); err != nil {
+		return err
+	}
+	var (
+		r           = nio.NewBinReaderFromIO(conn)
+		gotVersion  bool
+		gotVerack   bool
+		sentVerack  bool
+		verackBytes []byte
+	)
+	verackBytes, err = network.NewMessage(network.CMDVerack, payload.NewNullPayload()).Bytes()
+	if err != nil {
+		return err
+	}
+	for !gotVerack || !sentVerack {
+		msg := &network.Message{StateRootInHeader: stateRootInHeader}
+		if err = msg.Decode(r); err != nil {
+			return err
+		}
+		switch msg.Command {
+		case network.CMDVersion:
+			gotVersion = true
+		case network.CMDVerack:
+			gotVerack = true
+		}
+		if gotVersion && !sentVerack {
+			if _, err = conn.Write(verackBytes); err != nil {
+				return err
+			}
+			sentVerack = true
+		}
+	}
+	return nil
+}
+
+// replayReadLoop reads and drops everything the node sends, except for pings
+// that are answered to keep the connection alive.
+func replayReadLoop(conn net.Conn, stateRootInHeader bool) error {
+	r := nio.NewBinReaderFromIO(conn)
+	for {
+		msg := &network.Message{StateRootInHeader: stateRootInHeader}
+		if err := msg.Decode(r); err != nil {
+			return err
+		}
+		if msg.Command != network.CMDPing {
+			continue
+		}
+		ping := msg.Payload.(*payload.Ping)
+		pong, err := network.NewMessage(network.CMDPong, payload.NewPing(0, ping.Nonce)).Bytes()
+		if err != nil {
+			return err
+		}
+		if _, err = conn.Write(pong); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,176 @@
+package util
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/nspcc-dev/neo-go/cli/cmdargs"
+	"github.com/nspcc-dev/neo-go/cli/input"
+	"github.com/nspcc-dev/neo-go/cli/options"
+	"github.com/nspcc-dev/neo-go/pkg/config"
+	"github.com/nspcc-dev/neo-go/pkg/core/storage"
+	"github.com/nspcc-dev/neo-go/pkg/core/storage/dbconfig"
+	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
+	"github.com/nspcc-dev/neo-go/pkg/services/signer"
+	"github.com/nspcc-dev/neo-go/pkg/wallet"
+	"github.com/urfave/cli"
+	"go.uber.org/zap"
+)
+
+func runSigner(ctx *cli.Context) error {
+	if err := cmdargs.EnsureNone(ctx); err != nil {
+		return err
+	}
+	addr := ctx.String("address")
+	if len(addr) == 0 {
+		return cli.NewExitError("no signer address specified", 1)
+	}
+	dbPath := ctx.String("db")
+	if len(dbPath) == 0 {
+		return cli.NewExitError("no protection database specified", 1)
+	}
+	token, err := readSignerToken(ctx.String("token-file"))
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	var (
+		certFile = ctx.String("tls-cert")
+		keyFile  = ctx.String("tls-key")
+	)
+	if !strings.HasPrefix(addr, "unix:") {
+		if !strings.HasPrefix(addr, "https://") {
+			return cli.NewExitError("TCP signer address must be https://host:port", 1)
+		}
+		if len(token) == 0 {
+			return cli.NewExitError("token file is required for TCP signer address", 1)
+		}
+		if len(certFile) == 0 || len(keyFile) == 0 {
+			return cli.NewExitError("TLS certificate and key are required for TCP signer address", 1)
+		}
+	}
+	cfg, err := options.GetConfigFromContext(ctx)
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	privs, err := readSignerKeys(ctx)
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	log, _, logCloser, err := options.HandleLoggingParams(ctx.Bool("debug"), config.ApplicationConfiguration{})
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	if logCloser != nil {
+		defer func() { _ = logCloser() }()
+	}
+
+	db, err := storage.NewBoltDBStore(dbconfig.BoltDBOptions{FilePath: dbPath})
+	if err != nil {
+		return cli.NewExitError(fmt.Errorf("can't open protection database: %w", err), 1)
+	}
+	defer db.Close()
+
+	l, err := signer.Listen(addr)
+	if err != nil {
+		return cli.NewExitError(fmt.Errorf("can't listen on %s: %w", addr, err), 1)
+	}
+	srv := signer.NewServer(signer.ServerConfig{
+		Network:           cfg.ProtocolConfiguration.Magic,
+		StateRootInHeader: cfg.ProtocolConfiguration.StateRootInHeader,
+		Token:             token,
+	}, privs, db, log)
+	hs := &http.Server{
+		Handler:           srv,
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+	gctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	errCh := make(chan error, 1)
+	go func() {
+		if len(certFile) != 0 {
+			errCh <- hs.ServeTLS(l, certFile, keyFile)
+		} else {
+			errCh <- hs.Serve(l)
+		}
+	}()
+	log.Info("signer started", zap.String("address", addr), zap.Int("keys", len(privs)),
+		zap.Uint32("network", uint32(cfg.ProtocolConfiguration.Magic)))
+	select {
+	case err = <-errCh:
+	case <-gctx.Done():
+		err = hs.Shutdown(context.Background())
+	}
+	log.Info("signer stopped")
+	if err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return cli.NewExitError(err, 1)
+	}
+	return nil
+}
+
+// readSignerToken reads the authentication token from the given file. No
+// token is used if the path is empty.
+func readSignerToken(path string) (string, error) {
+	if len(path) == 0 {
+		return "", nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("can't read token file: %w", err)
+	}
+	token := strings.TrimSpace(string(data))
+	if len(token) == 0 {
+		return "", fmt.Errorf("empty token in %s", path)
+	}
+	return token, nil
+}
+
+// readSignerKeys returns private keys of all wallet accounts that can be
+// decrypted with the password given.
+func readSignerKeys(ctx *cli.Context) ([]*keys.PrivateKey, error) {
+	var (
+		wPath = ctx.String("wallet")
+		cPath = ctx.String("wallet-config")
+		pass  string
+	)
+	switch {
+	case len(wPath) != 0 && len(cPath) != 0:
+		return nil, errors.New("--wallet flag conflicts with --wallet-config flag")
+	case len(cPath) != 0:
+		cfg, err := options.ReadWalletConfig(cPath)
+		if err != nil {
+			return nil, err
+		}
+		wPath, pass = cfg.Path, cfg.Password
+	case len(wPath) != 0:
+		rawPass, err := input.ReadPassword("Enter password > ")
+		if err != nil {
+			return nil, fmt.Errorf("error reading password: %w", err)
+		}
+		pass = strings.TrimRight(rawPass, "\n")
+	default:
+		return nil, errors.New("no wallet specified")
+	}
+	w, err := wallet.NewWalletFromFile(wPath)
+	if err != nil {
+		return nil, err
+	}
+	var privs []*keys.PrivateKey
+	for _, acc := range w.Accounts {
+		if acc.Decrypt(pass, w.Scrypt) != nil {
+			continue
+		}
+		privs = append(privs, acc.PrivateKey())
+	}
+	if len(privs) == 0 {
+		return nil, errors.New("no account could be unlocked")
+	}
+	return privs, nil
+}
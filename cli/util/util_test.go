@@ -1,7 +1,9 @@
 package util_test
 
 import (
+	"encoding/json"
 	"fmt"
+	"net"
 	"os"
 	"path/filepath"
 	"strings"
@@ -9,6 +11,10 @@ import (
 	"time"
 
 	"github.com/nspcc-dev/neo-go/internal/testcli"
+	"github.com/nspcc-dev/neo-go/pkg/config/netmode"
+	"github.com/nspcc-dev/neo-go/pkg/io"
+	"github.com/nspcc-dev/neo-go/pkg/network"
+	"github.com/nspcc-dev/neo-go/pkg/network/payload"
 	"github.com/nspcc-dev/neo-go/pkg/smartcontract/trigger"
 	"github.com/nspcc-dev/neo-go/pkg/util"
 	"github.com/nspcc-dev/neo-go/pkg/vm/vmstate"
@@ -190,3 +196,150 @@ func TestAwaitUtilCancelTx(t *testing.T) {
 		t.Fatal(fmt.Errorf("unexpected error: %w", err))
 	}
 }
+
+func writeTestCapture(t *testing.T, path string, msgs ...*network.Message) {
+	w := io.NewBufBinWriter()
+	h := network.CaptureHeader{Magic: netmode.UnitTestNet}
+	h.EncodeBinary(w.BinWriter)
+	for i, m := range msgs {
+		data, err := m.Bytes()
+		require.NoError(t, err)
+		rec := network.CaptureRecord{
+			Timestamp: time.Unix(int64(i), 0),
+			Direction: network.CaptureInbound,
+			Peer:      "127.0.0.1:20333",
+			Data:      data,
+		}
+		rec.EncodeBinary(w.BinWriter)
+	}
+	require.NoError(t, w.Err)
+	require.NoError(t, os.WriteFile(path, w.Bytes(), 0o644))
+}
+
+func TestUtilP2PDecode(t *testing.T) {
+	e := testcli.NewExecutor(t, false)
+	path := filepath.Join(t.TempDir(), "p2p.cap")
+	writeTestCapture(t, path,
+		network.NewMessage(network.CMDVersion, payload.NewVersion(netmode.UnitTestNet, 1, "/test/", nil)),
+		network.NewMessage(network.CMDPing, payload.NewPing(5, 7)))
+
+	e.RunWithError(t, "neo-go", "util", "p2p-decode")
+	e.RunWithError(t, "neo-go", "util", "p2p-decode", filepath.Join(t.TempDir(), "unknown"))
+	e.Run(t, "neo-go", "util", "p2p-decode", path)
+
+	var res map[string]any
+	require.NoError(t, json.Unmarshal([]byte(e.GetNextLine(t)), &res))
+	require.Equal(t, "in", res["direction"])
+	require.Equal(t, "127.0.0.1:20333", res["peer"])
+	require.Equal(t, "Version", res["command"])
+	require.Equal(t, "/test/", res["payload"].(map[string]any)["UserAgent"])
+
+	res = nil
+	require.NoError(t, json.Unmarshal([]byte(e.GetNextLine(t)), &res))
+	require.Equal(t, "Ping", res["command"])
+	require.Equal(t, float64(7), res["payload"].(map[string]any)["Nonce"])
+	e.CheckEOF(t)
+}
+
+func TestUtilP2PReplay(t *testing.T) {
+	e := testcli.NewExecutor(t, false)
+	path := filepath.Join(t.TempDir(), "p2p.cap")
+	writeTestCapture(t, path,
+		network.NewMessage(network.CMDVersion, payload.NewVersion(netmode.UnitTestNet, 1, "/test/", nil)),
+		network.NewMessage(network.CMDVerack, payload.NewNullPayload()),
+		network.NewMessage(network.CMDPing, payload.NewPing(5, 7)),
+		network.NewMessage(network.CMDPing, payload.NewPing(6, 8)))
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { l.Close() })
+
+	received := make(chan []network.CommandType, 1)
+	go func() {
+		var cmds []network.CommandType
+		defer func() { received <- cmds }()
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		for _, m := range []*network.Message{
+			network.NewMessage(network.CMDVersion, payload.NewVersion(netmode.UnitTestNet, 2, "/node/", nil)),
+			network.NewMessage(network.CMDVerack, payload.NewNullPayload()),
+		} {
+			b, err := m.Bytes()
+			if err != nil {
+				return
+			}
+			if _, err = conn.Write(b); err != nil {
+				return
+			}
+		}
+		r := io.NewBinReaderFromIO(conn)
+		for len(cmds) < 4 {
+			m := &network.Message{}
+			if err := m.Decode(r); err != nil {
+				return
+			}
+			cmds = append(cmds, m.Command)
+		}
+	}()
+
+	e.RunWithError(t, "neo-go", "util", "p2p-replay", path)
+	e.Run(t, "neo-go", "util", "p2p-replay", path, "-a", l.Addr().String())
+	e.CheckNextLine(t, "2 messages replayed")
+	e.CheckEOF(t)
+
+	select {
+	case cmds := <-received:
+		require.Equal(t, []network.CommandType{network.CMDVersion, network.CMDVerack, network.CMDPing, network.CMDPing}, cmds)
+	case <-time.After(5 * time.Second):
+		t.Fatal("no messages received")
+	}
+}
+
+func TestUtilSigner(t *testing.T) {
+	e := testcli.NewExecutor(t, false)
+	tmp := t.TempDir()
+	db := filepath.Join(tmp, "signer.db")
+	addr := "unix:" + filepath.Join(tmp, "signer.sock")
+
+	t.Run("missing address", func(t *testing.T) {
+		e.RunWithError(t, "neo-go", "util", "signer", "--db", db, "--wallet", testcli.ValidatorWallet)
+	})
+	t.Run("missing db", func(t *testing.T) {
+		e.RunWithError(t, "neo-go", "util", "signer", "-a", addr, "--wallet", testcli.ValidatorWallet)
+	})
+	t.Run("missing wallet", func(t *testing.T) {
+		e.RunWithError(t, "neo-go", "util", "signer", "-a", addr, "--db", db)
+	})
+	t.Run("invalid password", func(t *testing.T) {
+		e.In.WriteString("invalid\r")
+		e.RunWithError(t, "neo-go", "util", "signer", "-a", addr, "--db", db, "--wallet", testcli.ValidatorWallet)
+	})
+	t.Run("missing token", func(t *testing.T) {
+		e.RunWithError(t, "neo-go", "util", "signer", "-a", "https://localhost:0", "--db", db, "--wallet", testcli.ValidatorWallet)
+	})
+	tokenFile := filepath.Join(tmp, "token")
+	t.Run("empty token", func(t *testing.T) {
+		require.NoError(t, os.WriteFile(tokenFile, []byte("\n"), 0o600))
+		e.RunWithError(t, "neo-go", "util", "signer", "-a", "https://localhost:0", "--token-file", tokenFile,
+			"--db", db, "--wallet", testcli.ValidatorWallet)
+	})
+	t.Run("plain http", func(t *testing.T) {
+		require.NoError(t, os.WriteFile(tokenFile, []byte("secret\n"), 0o600))
+		e.RunWithError(t, "neo-go", "util", "signer", "-a", "http://localhost:0", "--token-file", tokenFile,
+			"--tls-cert", "cert.pem", "--tls-key", "key.pem", "--db", db, "--wallet", testcli.ValidatorWallet)
+	})
+	t.Run("missing TLS certificate", func(t *testing.T) {
+		require.NoError(t, os.WriteFile(tokenFile, []byte("secret\n"), 0o600))
+		e.RunWithError(t, "neo-go", "util", "signer", "-a", "https://localhost:0", "--token-file", tokenFile,
+			"--tls-key", "key.pem", "--db", db, "--wallet", testcli.ValidatorWallet)
+	})
+	t.Run("invalid address", func(t *testing.T) {
+		require.NoError(t, os.WriteFile(tokenFile, []byte("secret\n"), 0o600))
+		e.In.WriteString(testcli.ValidatorPass + "\r")
+		e.RunWithError(t, "neo-go", "util", "signer", "-a", "localhost:0", "--token-file", tokenFile,
+			"--config-path", "../../config", "--unittest", "--db", db, "--wallet", testcli.ValidatorWallet)
+	})
+}
@@ -34,10 +34,13 @@ func startVMPrompt(ctx *cli.Context) error {
 	}
 	if ctx.NumFlags() == 0 {
 		cfg.ApplicationConfiguration.DBConfiguration.Type = dbconfig.InMemoryDB
+		cfg.ApplicationConfiguration.DBConfiguration.StateDB = dbconfig.StateDBConfiguration{}
 	}
 	if cfg.ApplicationConfiguration.DBConfiguration.Type != dbconfig.InMemoryDB {
 		cfg.ApplicationConfiguration.DBConfiguration.LevelDBOptions.ReadOnly = true
 		cfg.ApplicationConfiguration.DBConfiguration.BoltDBOptions.ReadOnly = true
+		cfg.ApplicationConfiguration.DBConfiguration.StateDB.LevelDBOptions.ReadOnly = true
+		cfg.ApplicationConfiguration.DBConfiguration.StateDB.BoltDBOptions.ReadOnly = true
 	}
 
 	p, err := NewWithConfig(true, os.Exit, &readline.Config{}, cfg)
@@ -0,0 +1,296 @@
+/*
+Package simnet provides an in-process multi-node network simulator for
+integration tests. It runs a number of network.Server instances backed by
+in-memory blockchains and connected via network.MemoryNetwork, consensus nodes
+run real dBFT service. Link latency, message loss, network partitions and
+per-node clock skew can be configured, so consensus liveness, synchronization
+and relay behavior can be tested deterministically without docker privnets.
+*/
+package simnet
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/nspcc-dev/dbft/timer"
+	"github.com/nspcc-dev/neo-go/pkg/config"
+	"github.com/nspcc-dev/neo-go/pkg/config/netmode"
+	"github.com/nspcc-dev/neo-go/pkg/consensus"
+	"github.com/nspcc-dev/neo-go/pkg/core"
+	"github.com/nspcc-dev/neo-go/pkg/core/storage"
+	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
+	"github.com/nspcc-dev/neo-go/pkg/network"
+	"github.com/nspcc-dev/neo-go/pkg/wallet"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest"
+)
+
+const (
+	// DefaultValidators is the default number of consensus nodes.
+	DefaultValidators = 4
+
+	// DefaultTimePerBlock is the default block interval used by simulated
+	// networks. It's much lower than the real one to speed up tests.
+	DefaultTimePerBlock = 200 * time.Millisecond
+
+	// basePort is the port all nodes are listening on.
+	basePort = 20333
+
+	// walletPass is the password used for generated validator wallets.
+	walletPass = "one"
+)
+
+// Options contains parameters of the simulated network.
+type Options struct {
+	// Validators is the number of consensus nodes, DefaultValidators are
+	// used if not set.
+	Validators int
+	// Observers is the number of additional non-consensus nodes.
+	Observers int
+	// Seed is used to make message loss decisions, every node gets its own
+	// random source derived from it.
+	Seed int64
+	// Latency is the default one-way latency of all links.
+	Latency time.Duration
+	// Loss is the probability (0.0-1.0) of any message being dropped.
+	Loss float64
+	// ClockSkew contains clock offsets for consensus nodes (by index), it
+	// affects block timestamps and dBFT timers of the node.
+	ClockSkew []time.Duration
+	// TimePerBlock is the block interval, DefaultTimePerBlock is used if not
+	// set.
+	TimePerBlock time.Duration
+	// BlockchainConfigHook allows to adjust the configuration of all nodes'
+	// chains.
+	BlockchainConfigHook func(*config.Blockchain)
+	// Logger is a logger used by all nodes, zaptest.Logger is used if not
+	// set.
+	Logger *zap.Logger
+}
+
+// Node is a single simulated node.
+type Node struct {
+	// Index is the node index in the network, consensus nodes go first.
+	Index int
+	// Host is an IP address identifying the node in the MemoryNetwork.
+	Host string
+	// Address is the P2P address of the node.
+	Address string
+	// Key is a validator key, it's nil for observers.
+	Key *keys.PrivateKey
+	// Chain is the node's blockchain.
+	Chain *core.Blockchain
+	// Server is the node's P2P server.
+	Server *network.Server
+	// Consensus is the node's consensus service, it's nil for observers.
+	Consensus consensus.Service
+}
+
+// Network is a simulated network of nodes.
+type Network struct {
+	*network.MemoryNetwork
+
+	// Nodes contains all nodes of the network, consensus nodes go first.
+	Nodes []*Node
+
+	started bool
+}
+
+// skewedTimer is a dBFT timer with the clock shifted by some offset.
+type skewedTimer struct {
+	*timer.Timer
+	skew time.Duration
+}
+
+// Now implements dbft.Timer interface.
+func (t skewedTimer) Now() time.Time {
+	return t.Timer.Now().Add(t.skew)
+}
+
+// New creates a new simulated network with the given options. Nodes are not
+// started, use Start for that. All nodes are stopped automatically when the
+// test completes.
+func New(t testing.TB, opts Options) *Network {
+	if opts.Validators <= 0 {
+		opts.Validators = DefaultValidators
+	}
+	if opts.TimePerBlock <= 0 {
+		opts.TimePerBlock = DefaultTimePerBlock
+	}
+	log := opts.Logger
+	if log == nil {
+		log = zaptest.NewLogger(t)
+	}
+
+	privs := make([]*keys.PrivateKey, opts.Validators)
+	committee := make([]string, opts.Validators)
+	for i := range privs {
+		var err error
+		privs[i], err = keys.NewPrivateKey()
+		require.NoError(t, err)
+		committee[i] = privs[i].PublicKey().StringCompressed()
+	}
+	cfg := config.Blockchain{
+		ProtocolConfiguration: config.ProtocolConfiguration{
+			Magic:              netmode.UnitTestNet,
+			MaxTraceableBlocks: 1000,
+			TimePerBlock:       opts.TimePerBlock,
+			StandbyCommittee:   committee,
+			ValidatorsCount:    uint32(opts.Validators),
+			VerifyTransactions: true,
+		},
+	}
+	if opts.BlockchainConfigHook != nil {
+		opts.BlockchainConfigHook(&cfg)
+	}
+
+	n := &Network{
+		MemoryNetwork: network.NewMemoryNetwork(opts.Seed),
+		Nodes:         make([]*Node, opts.Validators+opts.Observers),
+	}
+	n.SetLatency(opts.Latency)
+	n.SetLoss(opts.Loss)
+
+	seeds := make([]string, len(n.Nodes))
+	for i := range n.Nodes {
+		host := fmt.Sprintf("10.0.%d.%d", (i+1)/256, (i+1)%256)
+		seeds[i] = fmt.Sprintf("%s:%d", host, basePort)
+		n.Nodes[i] = &Node{
+			Index:   i,
+			Host:    host,
+			Address: seeds[i],
+		}
+	}
+	dir := t.TempDir()
+	for i, node := range n.Nodes {
+		nlog := log.With(zap.Int("node", i))
+		chain, err := core.NewBlockchain(storage.NewMemoryStore(), cfg, nlog)
+		require.NoError(t, err)
+		node.Chain = chain
+
+		var others = make([]string, 0, len(seeds)-1)
+		others = append(others, seeds[:i]...)
+		others = append(others, seeds[i+1:]...)
+		srvCfg := network.ServerConfig{
+			Addresses:          []config.AnnounceableAddress{{Address: node.Address}},
+			UserAgent:          fmt.Sprintf("/simnet:node%d/", i),
+			Net:                cfg.Magic,
+			Relay:              true,
+			Seeds:              others,
+			DialTimeout:        time.Second,
+			ProtoTickInterval:  opts.TimePerBlock / 2,
+			PingInterval:       opts.TimePerBlock * 5,
+			PingTimeout:        opts.TimePerBlock * 15,
+			MinPeers:           len(n.Nodes) - 1,
+			MaxPeers:           len(n.Nodes) * 2,
+			AttemptConnPeers:   len(n.Nodes),
+			TimePerBlock:       opts.TimePerBlock,
+			ExtensiblePoolSize: 20,
+		}
+		srv, err := network.NewMemoryServer(srvCfg, chain, chain.GetStateSyncModule(), n.MemoryNetwork, nlog)
+		require.NoError(t, err)
+		node.Server = srv
+
+		if i >= opts.Validators {
+			continue
+		}
+		node.Key = privs[i]
+		w, err := wallet.NewWallet(filepath.Join(dir, fmt.Sprintf("wallet%d.json", i)))
+		require.NoError(t, err)
+		w.Scrypt = keys.ScryptParams{N: 2, R: 1, P: 1}
+		acc := wallet.NewAccountFromPrivateKey(privs[i])
+		require.NoError(t, acc.Encrypt(walletPass, w.Scrypt))
+		w.AddAccount(acc)
+		require.NoError(t, w.Save())
+		w.Close()
+
+		tmr := skewedTimer{Timer: timer.New()}
+		if i < len(opts.ClockSkew) {
+			tmr.skew = opts.ClockSkew[i]
+		}
+		cons, err := consensus.NewService(consensus.Config{
+			Logger:                nlog,
+			Broadcast:             srv.BroadcastExtensible,
+			Chain:                 chain,
+			BlockQueue:            srv.GetBlockQueue(),
+			ProtocolConfiguration: chain.GetConfig().ProtocolConfiguration,
+			RequestTx:             srv.RequestTx,
+			StopTxFlow:            srv.StopTxFlow,
+			TimePerBlock:          opts.TimePerBlock,
+			Wallet:                config.Wallet{Path: w.Path(), Password: walletPass},
+			Timer:                 tmr,
+		})
+		require.NoError(t, err)
+		srv.AddConsensusService(cons, cons.OnPayload, cons.OnTransaction)
+		node.Consensus = cons
+	}
+	t.Cleanup(n.Stop)
+	return n
+}
+
+// Start starts all nodes of the network.
+func (n *Network) Start() {
+	if n.started {
+		return
+	}
+	n.started = true
+	for _, node := range n.Nodes {
+		go node.Chain.Run()
+		node.Server.Start()
+	}
+}
+
+// Stop stops all nodes of the network. It's called automatically when the
+// test completes.
+func (n *Network) Stop() {
+	if !n.started {
+		return
+	}
+	n.started = false
+	for _, node := range n.Nodes {
+		node.Server.Shutdown()
+		node.Chain.Close()
+	}
+}
+
+// PartitionNodes splits the network into groups of nodes specified by their
+// indexes. See network.MemoryNetwork.Partition for details.
+func (n *Network) PartitionNodes(groups ...[]int) {
+	var hosts = make([][]string, len(groups))
+	for i, g := range groups {
+		for _, idx := range g {
+			hosts[i] = append(hosts[i], n.Nodes[idx].Host)
+		}
+	}
+	n.Partition(hosts...)
+}
+
+// Heights returns the current block heights of all nodes.
+func (n *Network) Heights() []uint32 {
+	var res = make([]uint32, len(n.Nodes))
+	for i, node := range n.Nodes {
+		res[i] = node.Chain.BlockHeight()
+	}
+	return res
+}
+
+// MinHeight returns the lowest block height among all nodes.
+func (n *Network) MinHeight() uint32 {
+	var res = n.Nodes[0].Chain.BlockHeight()
+	for _, node := range n.Nodes[1:] {
+		if h := node.Chain.BlockHeight(); h < res {
+			res = h
+		}
+	}
+	return res
+}
+
+// WaitHeight waits for all nodes to reach the given height or fails the test
+// after the timeout.
+func (n *Network) WaitHeight(t testing.TB, h uint32, timeout time.Duration) {
+	require.Eventually(t, func() bool { return n.MinHeight() >= h }, timeout, 10*time.Millisecond,
+		"nodes didn't reach height %d: %v", h, n.Heights())
+}
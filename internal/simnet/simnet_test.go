@@ -0,0 +1,67 @@
+package simnet
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNetwork_Liveness(t *testing.T) {
+	n := New(t, Options{Observers: 1})
+	n.Start()
+	n.WaitHeight(t, 3, 20*time.Second)
+
+	h := n.Nodes[4].Chain.GetHeaderHash(3)
+	for _, node := range n.Nodes {
+		require.Equal(t, h, node.Chain.GetHeaderHash(3))
+	}
+}
+
+func TestNetwork_Partition(t *testing.T) {
+	n := New(t, Options{})
+	n.Start()
+	n.WaitHeight(t, 2, 20*time.Second)
+
+	// 2 of 4 validators can't produce blocks, but the block being accepted at
+	// the moment of partition can still reach some nodes.
+	n.PartitionNodes([]int{0, 1}, []int{2, 3})
+	var (
+		tpb  = n.Nodes[0].Chain.GetConfig().TimePerBlock
+		last []uint32
+	)
+	require.Eventually(t, func() bool {
+		hs := n.Heights()
+		stable := assert.ObjectsAreEqual(last, hs)
+		last = hs
+		return stable
+	}, 20*time.Second, tpb)
+	stuck := n.MinHeight()
+	var maxH uint32
+	for _, h := range last {
+		if h > maxH {
+			maxH = h
+		}
+	}
+	require.Never(t, func() bool {
+		for _, h := range n.Heights() {
+			if h > maxH+1 {
+				return true
+			}
+		}
+		return false
+	}, 5*tpb, 10*time.Millisecond)
+
+	n.Heal()
+	n.WaitHeight(t, stuck+3, 60*time.Second)
+}
+
+func TestNetwork_BadConditions(t *testing.T) {
+	n := New(t, Options{
+		Latency:   20 * time.Millisecond,
+		ClockSkew: []time.Duration{0, 50 * time.Millisecond, -50 * time.Millisecond},
+	})
+	n.Start()
+	n.WaitHeight(t, 3, 30*time.Second)
+}
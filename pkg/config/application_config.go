@@ -14,10 +14,14 @@ type ApplicationConfiguration struct {
 	Ledger `yaml:",inline"`
 
 	DBConfiguration dbconfig.DBConfiguration `yaml:"DBConfiguration"`
+	// Fork allows to fork a remote network instead of synchronizing with it.
+	Fork Fork `yaml:"Fork"`
 
 	LogLevel string `yaml:"LogLevel"`
 	LogPath  string `yaml:"LogPath"`
 
+	Mempool Mempool `yaml:"Mempool"`
+
 	P2P P2P `yaml:"P2P"`
 
 	Pprof      BasicService `yaml:"Pprof"`
@@ -29,11 +33,20 @@ type ApplicationConfiguration struct {
 	Oracle    OracleConfiguration `yaml:"Oracle"`
 	P2PNotary P2PNotary           `yaml:"P2PNotary"`
 	StateRoot StateRoot           `yaml:"StateRoot"`
+
+	// Replication streams DB changes to read-only replicas.
+	Replication Replication `yaml:"Replication"`
+	// Replica makes the node a read-only replica of some primary node.
+	Replica Replica `yaml:"Replica"`
+	// LedgerSinks deliver processed blocks to external systems.
+	LedgerSinks []LedgerSink `yaml:"LedgerSinks"`
+	// Plugins are out-of-process services connected via gRPC.
+	Plugins []Plugin `yaml:"Plugins"`
 }
 
 // EqualsButServices returns true when the o is the same as a except for services
-// (Oracle, P2PNotary, Pprof, Prometheus, RPC and StateRoot sections)
-// and LogLevel field.
+// (LedgerSinks, Oracle, P2PNotary, Plugins, Pprof, Prometheus, Replication,
+// RPC and StateRoot sections) and LogLevel field.
 func (a *ApplicationConfiguration) EqualsButServices(o *ApplicationConfiguration) bool {
 	if len(a.P2P.Addresses) != len(o.P2P.Addresses) {
 		return false
@@ -51,16 +64,22 @@ func (a *ApplicationConfiguration) EqualsButServices(o *ApplicationConfiguration
 	}
 	if a.P2P.AttemptConnPeers != o.P2P.AttemptConnPeers ||
 		a.P2P.BroadcastFactor != o.P2P.BroadcastFactor ||
+		a.P2P.Capture != o.P2P.Capture ||
 		a.DBConfiguration != o.DBConfiguration ||
+		a.Fork != o.Fork ||
 		a.P2P.DialTimeout != o.P2P.DialTimeout ||
 		a.P2P.ExtensiblePoolSize != o.P2P.ExtensiblePoolSize ||
 		a.LogPath != o.LogPath ||
+		a.Mempool != o.Mempool ||
 		a.P2P.MaxPeers != o.P2P.MaxPeers ||
 		a.P2P.MinPeers != o.P2P.MinPeers ||
+		!a.P2P.PeerPolicy.equals(&o.P2P.PeerPolicy) ||
 		a.P2P.PingInterval != o.P2P.PingInterval ||
 		a.P2P.PingTimeout != o.P2P.PingTimeout ||
 		a.P2P.ProtoTickInterval != o.P2P.ProtoTickInterval ||
-		a.Relay != o.Relay {
+		a.P2P.Proxy != o.P2P.Proxy ||
+		a.Relay != o.Relay ||
+		a.Replica != o.Replica {
 		return false
 	}
 	return true
@@ -61,6 +61,8 @@ func (c Config) Blockchain() Blockchain {
 	return Blockchain{
 		ProtocolConfiguration: c.ProtocolConfiguration,
 		Ledger:                c.ApplicationConfiguration.Ledger,
+		Mempool:               c.ApplicationConfiguration.Mempool,
+		Replica:               c.ApplicationConfiguration.Replica.Enabled,
 	}
 }
 
@@ -107,6 +109,10 @@ func LoadFile(configPath string, relativePath ...string) (Config, error) {
 	if err != nil {
 		return Config{}, err
 	}
+	err = config.ProtocolConfiguration.Genesis.checkFiles()
+	if err != nil {
+		return Config{}, fmt.Errorf("invalid Genesis: %w", err)
+	}
 
 	return config, nil
 }
@@ -120,10 +126,22 @@ func updateRelativePaths(relativePath string, config *Config) {
 	}
 
 	updatePath(&config.ApplicationConfiguration.LogPath)
+	updatePath(&config.ApplicationConfiguration.Mempool.PersistFile)
+	updatePath(&config.ApplicationConfiguration.P2P.Capture.FilePath)
 	updatePath(&config.ApplicationConfiguration.DBConfiguration.BoltDBOptions.FilePath)
 	updatePath(&config.ApplicationConfiguration.DBConfiguration.LevelDBOptions.DataDirectoryPath)
+	updatePath(&config.ApplicationConfiguration.DBConfiguration.StateDB.BoltDBOptions.FilePath)
+	updatePath(&config.ApplicationConfiguration.DBConfiguration.StateDB.LevelDBOptions.DataDirectoryPath)
 	updatePath(&config.ApplicationConfiguration.Consensus.UnlockWallet.Path)
+	updatePath(&config.ApplicationConfiguration.Consensus.JournalFile)
 	updatePath(&config.ApplicationConfiguration.P2PNotary.UnlockWallet.Path)
 	updatePath(&config.ApplicationConfiguration.Oracle.UnlockWallet.Path)
 	updatePath(&config.ApplicationConfiguration.StateRoot.UnlockWallet.Path)
+	for i := range config.ProtocolConfiguration.Genesis.ContractDumps {
+		updatePath(&config.ProtocolConfiguration.Genesis.ContractDumps[i])
+	}
+	for i := range config.ProtocolConfiguration.Genesis.Contracts {
+		updatePath(&config.ProtocolConfiguration.Genesis.Contracts[i].NEF)
+		updatePath(&config.ProtocolConfiguration.Genesis.Contracts[i].Manifest)
+	}
 }
@@ -32,3 +32,47 @@ func TestUnknownConfigFields(t *testing.T) {
 		require.Contains(t, err.Error(), "field UnknownConfigurationField not found in type config.Config")
 	})
 }
+
+func TestGenesisFiles(t *testing.T) {
+	tmp := t.TempDir()
+	cfg := filepath.Join(tmp, "protocol.testnet.yml")
+	require.NoError(t, os.WriteFile(filepath.Join(tmp, "contract.nef"), []byte{1}, os.ModePerm))
+	require.NoError(t, os.WriteFile(filepath.Join(tmp, "contract.manifest.json"), []byte{1}, os.ModePerm))
+	require.NoError(t, os.WriteFile(filepath.Join(tmp, "contract.dump"), []byte{1}, os.ModePerm))
+
+	check := func(t *testing.T, genesis string, errText string) {
+		require.NoError(t, os.WriteFile(cfg, []byte(`ProtocolConfiguration:
+  StandbyCommittee:
+    - 02b3622bf4017bdfe317c58aed5f4c753f206b7db896046fa7d774bbc4bf7f8dc2
+  ValidatorsCount: 1
+  Genesis:
+`+genesis), os.ModePerm))
+		_, err := LoadFile(cfg, tmp)
+		if errText == "" {
+			require.NoError(t, err)
+			return
+		}
+		require.ErrorContains(t, err, errText)
+	}
+	t.Run("good", func(t *testing.T) {
+		check(t, `    ContractDumps:
+      - contract.dump
+    Contracts:
+      - NEF: contract.nef
+        Manifest: contract.manifest.json`, "")
+	})
+	t.Run("missing NEF", func(t *testing.T) {
+		check(t, `    Contracts:
+      - NEF: missing.nef
+        Manifest: contract.manifest.json`, "genesis contract #0: NEF file: stat "+filepath.Join(tmp, "missing.nef"))
+	})
+	t.Run("missing manifest", func(t *testing.T) {
+		check(t, `    Contracts:
+      - NEF: contract.nef
+        Manifest: missing.manifest.json`, "genesis contract #0: manifest file: stat "+filepath.Join(tmp, "missing.manifest.json"))
+	})
+	t.Run("missing dump", func(t *testing.T) {
+		check(t, `    ContractDumps:
+      - missing.dump`, "genesis contract dump: stat "+filepath.Join(tmp, "missing.dump"))
+	})
+}
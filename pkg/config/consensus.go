@@ -1,4 +1,17 @@
 package config
 
 // Consensus contains consensus service configuration.
-type Consensus InternalService
+type Consensus struct {
+	InternalService `yaml:",inline"`
+	// JournalFile is the path to the file used to keep consensus messages
+	// sent by the node to prevent signing conflicting blocks for the same
+	// height after restart. The journal is disabled if it's empty.
+	JournalFile string `yaml:"JournalFile"`
+	// RemoteSigner is an external signer configuration. When it's set,
+	// validator keys are taken from the signer instead of UnlockWallet.
+	RemoteSigner RemoteSigner `yaml:"RemoteSigner"`
+	// InstantMining enables development mode where dBFT is not used and
+	// blocks are created as soon as transactions enter the memory pool (or
+	// on generateblocks RPC request) by the node holding validator keys.
+	InstantMining bool `yaml:"InstantMining"`
+}
@@ -0,0 +1,14 @@
+package config
+
+import "time"
+
+// Fork contains configuration of the node forking a remote network.
+type Fork struct {
+	// Endpoint is the RPC endpoint of the remote network node used to fetch
+	// the state at the fork point. The node is not forked if it's empty.
+	Endpoint string `yaml:"Endpoint"`
+	// Height is the remote network height the fork starts from.
+	Height uint32 `yaml:"Height"`
+	// Timeout is the timeout for a single RPC request.
+	Timeout time.Duration `yaml:"Timeout"`
+}
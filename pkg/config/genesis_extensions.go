@@ -2,12 +2,22 @@ package config
 
 import (
 	"encoding/base64"
+	"errors"
 	"fmt"
+	"os"
 
 	"github.com/nspcc-dev/neo-go/pkg/core/native/noderoles"
 	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
+	"github.com/nspcc-dev/neo-go/pkg/encoding/address"
+	"github.com/nspcc-dev/neo-go/pkg/encoding/fixedn"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract"
+	"github.com/nspcc-dev/neo-go/pkg/util"
 )
 
+// neoTotalSupply is the total amount of NEO minted during native NEO contract
+// initialization.
+const neoTotalSupply = 100000000
+
 // Genesis represents a set of genesis block settings including the extensions
 // enabled in the genesis block or during native contracts initialization.
 type Genesis struct {
@@ -19,6 +29,21 @@ type Genesis struct {
 	// genesis block. It is NeoGo extension and must be disabled on the public
 	// Neo N3 networks.
 	Transaction *GenesisTransaction
+	// ContractDumps contains paths to contract dumps (see `contract
+	// dump-storage` CLI command) that should be imported in the genesis
+	// block. Contracts are recreated with the same hashes and storage, but
+	// get new IDs. It is NeoGo extension and must be disabled on the public
+	// Neo N3 networks.
+	ContractDumps []string
+	// Allocations contains NEO and GAS amounts that should be transferred from
+	// the standby validators multisignature account to the given addresses in
+	// the genesis block. It is NeoGo extension and must be disabled on the
+	// public Neo N3 networks.
+	Allocations []GenesisAllocation
+	// Contracts contains contracts that should be deployed in the genesis
+	// block by the standby validators multisignature account. It is NeoGo
+	// extension and must be disabled on the public Neo N3 networks.
+	Contracts []GenesisContract
 }
 
 // GenesisTransaction is a placeholder for script that should be included into genesis
@@ -30,11 +55,39 @@ type GenesisTransaction struct {
 	SystemFee int64
 }
 
+// GenesisAllocation is the amount of NEO and GAS that should be transferred
+// to the given account in the genesis block.
+type GenesisAllocation struct {
+	Address util.Uint160
+	NEO     int64
+	GAS     fixedn.Fixed8
+}
+
+// GenesisContract describes a contract that should be deployed in the genesis
+// block. Contract hash is calculated the same way as for any other deployment
+// with the standby validators multisignature account used as a sender, so it
+// only depends on this account, NEF checksum and contract name.
+type GenesisContract struct {
+	// NEF is a path to the contract NEF file.
+	NEF string `yaml:"NEF"`
+	// Manifest is a path to the contract manifest file.
+	Manifest string `yaml:"Manifest"`
+	// Data is an optional parameter passed to the contract _deploy method.
+	// It's either a string in the format used by the CLI for contract
+	// parameters (like "int:42" or "hash160:NbTiM6h8r99kpRtb428XcsUk1TzKed2gTc"),
+	// a plain integer or boolean or a list of such values that is passed as
+	// an array.
+	Data any `yaml:"Data,omitempty"`
+}
+
 type (
 	// genesisAux is an auxiliary structure for Genesis YAML marshalling.
 	genesisAux struct {
-		Roles       map[string]keys.PublicKeys `yaml:"Roles"`
-		Transaction *genesisTransactionAux     `yaml:"Transaction"`
+		Roles         map[string]keys.PublicKeys `yaml:"Roles"`
+		Transaction   *genesisTransactionAux     `yaml:"Transaction"`
+		ContractDumps []string                   `yaml:"ContractDumps"`
+		Allocations   []genesisAllocationAux     `yaml:"Allocations"`
+		Contracts     []GenesisContract          `yaml:"Contracts"`
 	}
 	// genesisTransactionAux is an auxiliary structure for GenesisTransaction YAML
 	// marshalling.
@@ -42,11 +95,27 @@ type (
 		Script    string `yaml:"Script"`
 		SystemFee int64  `yaml:"SystemFee"`
 	}
+	// genesisAllocationAux is an auxiliary structure for GenesisAllocation
+	// YAML marshalling.
+	genesisAllocationAux struct {
+		Address string        `yaml:"Address"`
+		NEO     int64         `yaml:"NEO"`
+		GAS     fixedn.Fixed8 `yaml:"GAS"`
+	}
 )
 
 // MarshalYAML implements the YAML marshaler interface.
 func (e Genesis) MarshalYAML() (any, error) {
 	var aux genesisAux
+	aux.ContractDumps = e.ContractDumps
+	aux.Contracts = e.Contracts
+	for _, a := range e.Allocations {
+		aux.Allocations = append(aux.Allocations, genesisAllocationAux{
+			Address: address.Uint160ToString(a.Address),
+			NEO:     a.NEO,
+			GAS:     a.GAS,
+		})
+	}
 	aux.Roles = make(map[string]keys.PublicKeys, len(e.Roles))
 	for r, ks := range e.Roles {
 		aux.Roles[r.String()] = ks
@@ -86,6 +155,111 @@ func (e *Genesis) UnmarshalYAML(unmarshal func(any) error) error {
 			SystemFee: aux.Transaction.SystemFee,
 		}
 	}
+	e.ContractDumps = aux.ContractDumps
+	e.Contracts = aux.Contracts
+	for _, a := range aux.Allocations {
+		h, err := address.StringToUint160(a.Address)
+		if err != nil {
+			return fmt.Errorf("invalid genesis allocation address %s: %w", a.Address, err)
+		}
+		e.Allocations = append(e.Allocations, GenesisAllocation{
+			Address: h,
+			NEO:     a.NEO,
+			GAS:     a.GAS,
+		})
+	}
+
+	return nil
+}
+
+// validate checks genesis allocations and contracts, initialGAS is the amount
+// of GAS available for allocations. System fee needed to transfer allocations
+// and deploy contracts is only known when the genesis block is created, so
+// it's checked there.
+func (e *Genesis) validate(initialGAS fixedn.Fixed8) error {
+	var (
+		neo   int64
+		gas   fixedn.Fixed8
+		addrs = make(map[util.Uint160]bool, len(e.Allocations))
+	)
+	for _, a := range e.Allocations {
+		if addrs[a.Address] {
+			return fmt.Errorf("duplicate genesis allocation for %s", address.Uint160ToString(a.Address))
+		}
+		addrs[a.Address] = true
+		if a.NEO < 0 || a.GAS < 0 {
+			return fmt.Errorf("negative genesis allocation for %s", address.Uint160ToString(a.Address))
+		}
+		if a.NEO == 0 && a.GAS == 0 {
+			return fmt.Errorf("empty genesis allocation for %s", address.Uint160ToString(a.Address))
+		}
+		neo += a.NEO
+		gas += a.GAS
+	}
+	if neo > neoTotalSupply {
+		return fmt.Errorf("genesis NEO allocations (%d) exceed total supply (%d)", neo, neoTotalSupply)
+	}
+	if gas > initialGAS {
+		return fmt.Errorf("genesis GAS allocations (%s) exceed InitialGASSupply (%s)", gas, initialGAS)
+	}
+	for i, c := range e.Contracts {
+		if c.NEF == "" || c.Manifest == "" {
+			return fmt.Errorf("genesis contract #%d: both NEF and Manifest must be specified", i)
+		}
+		if _, err := c.DeployData(); err != nil {
+			return fmt.Errorf("genesis contract #%d: invalid data: %w", i, err)
+		}
+	}
+	return nil
+}
 
+// checkFiles checks that genesis contract files and contract dumps exist.
+func (e *Genesis) checkFiles() error {
+	for i, c := range e.Contracts {
+		for _, f := range []struct {
+			kind string
+			path string
+		}{{"NEF", c.NEF}, {"manifest", c.Manifest}} {
+			if _, err := os.Stat(f.path); err != nil {
+				return fmt.Errorf("genesis contract #%d: %s file: %w", i, f.kind, err)
+			}
+		}
+	}
+	for _, path := range e.ContractDumps {
+		if _, err := os.Stat(path); err != nil {
+			return fmt.Errorf("genesis contract dump: %w", err)
+		}
+	}
 	return nil
 }
+
+// DeployData converts Data into a value that can be emitted as a contract
+// method parameter, nil is returned if there is no Data.
+func (c *GenesisContract) DeployData() (any, error) {
+	return convertDeployData(c.Data)
+}
+
+func convertDeployData(data any) (any, error) {
+	switch d := data.(type) {
+	case nil, bool, int, int64:
+		return d, nil
+	case string:
+		p, err := smartcontract.NewParameterFromString(d)
+		if err != nil {
+			return nil, err
+		}
+		return smartcontract.ExpandParameterToEmitable(*p)
+	case []any:
+		res := make([]any, len(d))
+		for i := range d {
+			var err error
+			res[i], err = convertDeployData(d[i])
+			if err != nil {
+				return nil, err
+			}
+		}
+		return res, nil
+	default:
+		return nil, errors.New("unsupported value type")
+	}
+}
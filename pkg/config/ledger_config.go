@@ -26,4 +26,10 @@ type Ledger struct {
 type Blockchain struct {
 	ProtocolConfiguration
 	Ledger
+	// Mempool contains memory pool settings.
+	Mempool Mempool
+	// Replica makes Blockchain a read-only replica of some other node, it
+	// doesn't accept blocks and transactions and can only be updated with
+	// DB changes made by that node.
+	Replica bool
 }
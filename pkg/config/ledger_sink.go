@@ -0,0 +1,25 @@
+package config
+
+import "time"
+
+// LedgerSink contains the configuration of the service delivering processed
+// blocks along with their execution results and token transfers to some
+// external system (like indexer).
+type LedgerSink struct {
+	Enabled bool `yaml:"Enabled"`
+	// Type is the sink type, either "file" or "http".
+	Type string `yaml:"Type"`
+	// Path is the NDJSON file events are appended to (for "file" sink).
+	Path string `yaml:"Path"`
+	// URL is the endpoint event batches are POSTed to (for "http" sink).
+	URL string `yaml:"URL"`
+	// Timeout is the timeout for a single HTTP request.
+	Timeout time.Duration `yaml:"Timeout"`
+	// CheckpointFile is the file keeping the height of the last block
+	// delivered to the sink.
+	CheckpointFile string `yaml:"CheckpointFile"`
+	// BatchSize is the maximum number of blocks delivered at once.
+	BatchSize int `yaml:"BatchSize"`
+	// RetryInterval is the interval between delivery attempts after failure.
+	RetryInterval time.Duration `yaml:"RetryInterval"`
+}
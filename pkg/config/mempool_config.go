@@ -0,0 +1,24 @@
+package config
+
+// Mempool contains the node's memory pool settings.
+type Mempool struct {
+	// PersistFile is the path to the file used to keep memory pool contents
+	// (including P2PNotaryRequest payloads) between node restarts. Pools are
+	// saved on node shutdown and restored on start, persistence is disabled
+	// if it's empty.
+	PersistFile string `yaml:"PersistFile"`
+	// MaxSenderTransactions is the maximum number of transactions from the
+	// same sender that can be kept in the memory pool, 0 means no limit.
+	MaxSenderTransactions int `yaml:"MaxSenderTransactions"`
+	// MaxSenderSize is the maximum total size (in bytes) of transactions
+	// from the same sender that can be kept in the memory pool, 0 means no
+	// limit.
+	MaxSenderSize int `yaml:"MaxSenderSize"`
+	// FairEviction changes the policy used when the memory pool is full. By
+	// default the least prioritized transaction is evicted, with this option
+	// enabled the least prioritized transaction of the sender having the
+	// largest number of pooled transactions is evicted instead (if this
+	// sender has more transactions than the sender of the new one and the
+	// new transaction has at least the same priority).
+	FairEviction bool `yaml:"FairEviction"`
+}
@@ -9,11 +9,89 @@ type P2P struct {
 	AttemptConnPeers int      `yaml:"AttemptConnPeers"`
 	// BroadcastFactor is the factor (0-100) controlling gossip fan-out number optimization.
 	BroadcastFactor    int           `yaml:"BroadcastFactor"`
+	Capture            P2PCapture    `yaml:"Capture"`
 	DialTimeout        time.Duration `yaml:"DialTimeout"`
 	ExtensiblePoolSize int           `yaml:"ExtensiblePoolSize"`
 	MaxPeers           int           `yaml:"MaxPeers"`
 	MinPeers           int           `yaml:"MinPeers"`
-	PingInterval       time.Duration `yaml:"PingInterval"`
-	PingTimeout        time.Duration `yaml:"PingTimeout"`
-	ProtoTickInterval  time.Duration `yaml:"ProtoTickInterval"`
+	// PeerPolicy restricts inbound and outbound peer connections.
+	PeerPolicy        P2PPeerPolicy `yaml:"PeerPolicy"`
+	PingInterval      time.Duration `yaml:"PingInterval"`
+	PingTimeout       time.Duration `yaml:"PingTimeout"`
+	ProtoTickInterval time.Duration `yaml:"ProtoTickInterval"`
+	// Proxy is a SOCKS5 proxy used for outbound connections.
+	Proxy P2PProxy `yaml:"Proxy"`
+}
+
+// P2PCapture holds P2P message capture settings.
+type P2PCapture struct {
+	Enabled bool `yaml:"Enabled"`
+	// FilePath is the path to the capture file, rotated files get numeric
+	// suffixes (".1", ".2" and so on).
+	FilePath string `yaml:"FilePath"`
+	// MaxFileSize is the size (in bytes) of a single capture file after
+	// which it's rotated.
+	MaxFileSize int64 `yaml:"MaxFileSize"`
+	// MaxFiles is the number of rotated capture files to keep. 0 disables
+	// rotation, the existing file is appended to and capturing stops when
+	// MaxFileSize is reached.
+	MaxFiles int `yaml:"MaxFiles"`
+}
+
+// P2PPeerPolicy holds restrictions for P2P connections.
+type P2PPeerPolicy struct {
+	// DisableIPv4 prevents outbound connections to IPv4 addresses.
+	DisableIPv4 bool `yaml:"DisableIPv4"`
+	// DisableIPv6 prevents outbound connections to IPv6 addresses.
+	DisableIPv6 bool `yaml:"DisableIPv6"`
+	// PreferIPv6 makes IPv6 addresses to be tried first when peer's host
+	// name resolves to both IPv4 and IPv6 addresses.
+	PreferIPv6 bool `yaml:"PreferIPv6"`
+	// AllowInbound is a list of CIDRs inbound connections are accepted
+	// from, any address is allowed if it's empty.
+	AllowInbound []string `yaml:"AllowInbound"`
+	// DenyInbound is a list of CIDRs inbound connections are rejected
+	// from, it takes precedence over AllowInbound.
+	DenyInbound []string `yaml:"DenyInbound"`
+	// AllowOutbound is a list of CIDRs outbound connections can be made
+	// to, any address is allowed if it's empty.
+	AllowOutbound []string `yaml:"AllowOutbound"`
+	// DenyOutbound is a list of CIDRs outbound connections can't be made
+	// to, it takes precedence over AllowOutbound.
+	DenyOutbound []string `yaml:"DenyOutbound"`
+	// MaxPeersPerSubnet is the maximum number of peers from the same /24
+	// IPv4 (or /64 IPv6) subnet, 0 means no limit.
+	MaxPeersPerSubnet int `yaml:"MaxPeersPerSubnet"`
+}
+
+// equals returns true if both policies are the same.
+func (p *P2PPeerPolicy) equals(o *P2PPeerPolicy) bool {
+	var eq = func(a, b []string) bool {
+		if len(a) != len(b) {
+			return false
+		}
+		for i := range a {
+			if a[i] != b[i] {
+				return false
+			}
+		}
+		return true
+	}
+	return p.DisableIPv4 == o.DisableIPv4 &&
+		p.DisableIPv6 == o.DisableIPv6 &&
+		p.PreferIPv6 == o.PreferIPv6 &&
+		eq(p.AllowInbound, o.AllowInbound) &&
+		eq(p.DenyInbound, o.DenyInbound) &&
+		eq(p.AllowOutbound, o.AllowOutbound) &&
+		eq(p.DenyOutbound, o.DenyOutbound) &&
+		p.MaxPeersPerSubnet == o.MaxPeersPerSubnet
+}
+
+// P2PProxy holds SOCKS5 proxy settings for outbound P2P connections.
+type P2PProxy struct {
+	// Address is the proxy address in the form of "host:port", direct
+	// connections are used if it's empty.
+	Address  string `yaml:"Address"`
+	Username string `yaml:"Username"`
+	Password string `yaml:"Password"`
 }
@@ -0,0 +1,20 @@
+package config
+
+import "time"
+
+// Plugin contains the configuration of an out-of-process service plugin the
+// node connects to via gRPC.
+type Plugin struct {
+	Enabled bool `yaml:"Enabled"`
+	// Address is the plugin gRPC server address in the form of
+	// "address:port".
+	Address string `yaml:"Address"`
+	// Command is the plugin executable with its arguments. If set, the node
+	// launches the plugin process on start and stops it on shutdown, the
+	// process is expected to listen at Address.
+	Command []string `yaml:"Command"`
+	// CallTimeout is the timeout for RPC method calls handled by the plugin.
+	CallTimeout time.Duration `yaml:"CallTimeout"`
+	// RetryInterval is the interval between reconnection attempts.
+	RetryInterval time.Duration `yaml:"RetryInterval"`
+}
@@ -161,6 +161,9 @@ func (p *ProtocolConfiguration) Validate() error {
 			}
 		}
 	}
+	if err := p.Genesis.validate(p.InitialGASSupply); err != nil {
+		return fmt.Errorf("invalid Genesis: %w", err)
+	}
 	return nil
 }
 
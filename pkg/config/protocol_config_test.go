@@ -3,6 +3,7 @@ package config
 import (
 	"encoding/base64"
 	"fmt"
+	"os"
 	"path/filepath"
 	"testing"
 	"time"
@@ -10,6 +11,8 @@ import (
 	"github.com/nspcc-dev/neo-go/internal/testserdes"
 	"github.com/nspcc-dev/neo-go/pkg/core/native/noderoles"
 	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
+	"github.com/nspcc-dev/neo-go/pkg/encoding/fixedn"
+	"github.com/nspcc-dev/neo-go/pkg/util"
 	"github.com/stretchr/testify/require"
 	"gopkg.in/yaml.v3"
 )
@@ -313,6 +316,13 @@ func TestGenesisExtensionsMarshalYAML(t *testing.T) {
 				Script:    []byte{1, 2, 3, 4},
 				SystemFee: 123,
 			},
+			ContractDumps: []string{"contract.dump"},
+			Allocations: []GenesisAllocation{
+				{Address: pub.GetScriptHash(), NEO: 10, GAS: fixedn.Fixed8FromInt64(100)},
+			},
+			Contracts: []GenesisContract{
+				{NEF: "contract.nef", Manifest: "contract.manifest.json", Data: []any{"int:1", 2, true}},
+			},
 		}
 		testserdes.MarshalUnmarshalYAML(t, g, new(Genesis))
 	})
@@ -332,7 +342,17 @@ func TestGenesisExtensionsMarshalYAML(t *testing.T) {
         - %s
       Oracle:
         - %s
-        - %s`, base64.StdEncoding.EncodeToString(script), pubStr, pubStr, pubStr, pubStr)
+        - %s
+    ContractDumps:
+      - contract.json
+    Allocations:
+      - Address: %s
+        NEO: 10
+        GAS: 1.5
+    Contracts:
+      - NEF: contract.nef
+        Manifest: contract.manifest.json
+        Data: [1, "string:some"]`, base64.StdEncoding.EncodeToString(script), pubStr, pubStr, pubStr, pubStr, pub.Address())
 			cfg := new(Config)
 			require.NoError(t, yaml.Unmarshal([]byte(cfgYml), cfg))
 			require.Equal(t, 2, len(cfg.ProtocolConfiguration.Genesis.Roles))
@@ -342,6 +362,20 @@ func TestGenesisExtensionsMarshalYAML(t *testing.T) {
 				Script:    script,
 				SystemFee: 123,
 			}, cfg.ProtocolConfiguration.Genesis.Transaction)
+			require.Equal(t, []string{"contract.json"}, cfg.ProtocolConfiguration.Genesis.ContractDumps)
+			require.Equal(t, []GenesisAllocation{{
+				Address: pub.GetScriptHash(),
+				NEO:     10,
+				GAS:     fixedn.Fixed8(150000000),
+			}}, cfg.ProtocolConfiguration.Genesis.Allocations)
+			require.Equal(t, []GenesisContract{{
+				NEF:      "contract.nef",
+				Manifest: "contract.manifest.json",
+				Data:     []any{1, "string:some"},
+			}}, cfg.ProtocolConfiguration.Genesis.Contracts)
+			data, err := cfg.ProtocolConfiguration.Genesis.Contracts[0].DeployData()
+			require.NoError(t, err)
+			require.Equal(t, []any{1, "some"}, data)
 		})
 
 		t.Run("empty", func(t *testing.T) {
@@ -352,6 +386,18 @@ func TestGenesisExtensionsMarshalYAML(t *testing.T) {
 			require.Empty(t, cfg.ProtocolConfiguration.Genesis.Roles)
 		})
 
+		t.Run("bad allocation address", func(t *testing.T) {
+			cfgYml := `ProtocolConfiguration:
+  Genesis:
+    Allocations:
+      - Address: bad
+        NEO: 1`
+			cfg := new(Config)
+			err := yaml.Unmarshal([]byte(cfgYml), cfg)
+			require.Error(t, err)
+			require.Contains(t, err.Error(), "invalid genesis allocation address bad")
+		})
+
 		t.Run("unknown role", func(t *testing.T) {
 			pubStr := pub.StringCompressed()
 			cfgYml := fmt.Sprintf(`ProtocolConfiguration:
@@ -379,3 +425,51 @@ func TestGenesisExtensionsMarshalYAML(t *testing.T) {
 		})
 	})
 }
+
+func TestGenesisValidation(t *testing.T) {
+	dir := t.TempDir()
+	nefPath := filepath.Join(dir, "contract.nef")
+	manifestPath := filepath.Join(dir, "contract.manifest.json")
+	require.NoError(t, os.WriteFile(nefPath, []byte{1}, 0644))
+	require.NoError(t, os.WriteFile(manifestPath, []byte{1}, 0644))
+
+	newCfg := func(g Genesis) *ProtocolConfiguration {
+		return &ProtocolConfiguration{
+			StandbyCommittee: []string{
+				"02b3622bf4017bdfe317c58aed5f4c753f206b7db896046fa7d774bbc4bf7f8dc2",
+			},
+			ValidatorsCount:  1,
+			InitialGASSupply: fixedn.Fixed8FromInt64(100),
+			Genesis:          g,
+		}
+	}
+	a1, a2 := util.Uint160{1}, util.Uint160{2}
+
+	require.NoError(t, newCfg(Genesis{
+		Allocations: []GenesisAllocation{
+			{Address: a1, NEO: 50000000, GAS: fixedn.Fixed8FromInt64(50)},
+			{Address: a2, NEO: 50000000, GAS: fixedn.Fixed8FromInt64(50)},
+		},
+		Contracts: []GenesisContract{
+			{NEF: nefPath, Manifest: manifestPath},
+			{NEF: nefPath, Manifest: manifestPath, Data: []any{"int:1", []any{true}}},
+		},
+	}).Validate())
+
+	for name, g := range map[string]Genesis{
+		"duplicate address": {Allocations: []GenesisAllocation{{Address: a1, NEO: 1}, {Address: a1, GAS: 1}}},
+		"negative NEO":      {Allocations: []GenesisAllocation{{Address: a1, NEO: -1}}},
+		"negative GAS":      {Allocations: []GenesisAllocation{{Address: a1, NEO: 1, GAS: -1}}},
+		"empty":             {Allocations: []GenesisAllocation{{Address: a1}}},
+		"too much NEO":      {Allocations: []GenesisAllocation{{Address: a1, NEO: 50000000}, {Address: a2, NEO: 50000001}}},
+		"too much GAS":      {Allocations: []GenesisAllocation{{Address: a1, GAS: fixedn.Fixed8FromInt64(101)}}},
+		"no NEF":            {Contracts: []GenesisContract{{Manifest: manifestPath}}},
+		"no manifest":       {Contracts: []GenesisContract{{NEF: nefPath}}},
+		"bad data":          {Contracts: []GenesisContract{{NEF: nefPath, Manifest: manifestPath, Data: "int:bad"}}},
+		"bad data type":     {Contracts: []GenesisContract{{NEF: nefPath, Manifest: manifestPath, Data: map[string]any{"a": 1}}}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			require.Error(t, newCfg(g).Validate())
+		})
+	}
+}
@@ -0,0 +1,21 @@
+package config
+
+import "time"
+
+// RemoteSigner contains configuration of an external signer process holding
+// private keys used by a service.
+type RemoteSigner struct {
+	// Address is the signer endpoint, either "unix:/path/to/socket" or
+	// "http(s)://host:port" URL. Signer is not used if it's empty.
+	Address string `yaml:"Address"`
+	// Timeout is the timeout for a single signer request.
+	Timeout time.Duration `yaml:"Timeout"`
+	// Token is the secret used to authenticate to the signer, it's sent
+	// in the Authorization header of every request, so it can only be used
+	// with Unix socket or https addresses.
+	Token string `yaml:"Token"`
+	// CAFile is the path to the PEM-encoded certificate(s) of the authority
+	// that issued the signer certificate for https address, system roots are
+	// used if it's empty.
+	CAFile string `yaml:"CAFile"`
+}
@@ -0,0 +1,26 @@
+package config
+
+import "time"
+
+// Replication contains the configuration of the service streaming DB changes
+// of the node to its read-only replicas.
+type Replication struct {
+	BasicService `yaml:",inline"`
+	// BacklogSize is the number of the latest DB change sets kept in memory
+	// for replicas to catch up with the node after reconnection.
+	BacklogSize int `yaml:"BacklogSize"`
+}
+
+// Replica contains the configuration of the read-only RPC replica node that
+// follows some primary node by applying DB changes streamed from it instead
+// of synchronizing via P2P.
+type Replica struct {
+	// Enabled turns the node into a replica. The node DB must be a copy of
+	// the primary node DB.
+	Enabled bool `yaml:"Enabled"`
+	// Primary is the address of the primary node Replication service in
+	// the form of "address:port".
+	Primary string `yaml:"Primary"`
+	// RetryInterval is the interval between reconnection attempts.
+	RetryInterval time.Duration `yaml:"RetryInterval"`
+}
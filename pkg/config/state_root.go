@@ -1,4 +1,9 @@
 package config
 
 // StateRoot contains state root service configuration.
-type StateRoot InternalService
+type StateRoot struct {
+	InternalService `yaml:",inline"`
+	// RemoteSigner is an external signer configuration. When it's set, state
+	// validator keys are taken from the signer instead of UnlockWallet.
+	RemoteSigner RemoteSigner `yaml:"RemoteSigner"`
+}
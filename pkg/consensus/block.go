@@ -17,14 +17,25 @@ type neoBlock struct {
 
 	network   netmode.Magic
 	signature []byte
+	// checkSign is called before signing the block, signing is refused if
+	// it returns an error.
+	checkSign func(*neoBlock) error
 }
 
 var _ dbft.Block[util.Uint256] = (*neoBlock)(nil)
 
 // Sign implements the block.Block interface.
 func (n *neoBlock) Sign(key dbft.PrivateKey) error {
+	if n.checkSign != nil {
+		if err := n.checkSign(n); err != nil {
+			return err
+		}
+	}
 	k := key.(*privateKey)
-	sig := k.PrivateKey.SignHashable(uint32(n.network), &n.Block)
+	sig, err := k.signBlock(n.network, &n.Block.Header)
+	if err != nil {
+		return err
+	}
 	n.signature = sig
 	return nil
 }
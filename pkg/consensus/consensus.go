@@ -1,6 +1,7 @@
 package consensus
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
 	"sort"
@@ -20,7 +21,9 @@ import (
 	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
 	"github.com/nspcc-dev/neo-go/pkg/encoding/address"
 	"github.com/nspcc-dev/neo-go/pkg/io"
+	"github.com/nspcc-dev/neo-go/pkg/neorpc/result"
 	npayload "github.com/nspcc-dev/neo-go/pkg/network/payload"
+	"github.com/nspcc-dev/neo-go/pkg/services/signer"
 	"github.com/nspcc-dev/neo-go/pkg/smartcontract"
 	"github.com/nspcc-dev/neo-go/pkg/util"
 	"github.com/nspcc-dev/neo-go/pkg/vm/emit"
@@ -38,6 +41,10 @@ const defaultTimePerBlock = 15 * time.Second
 // Number of nanoseconds in millisecond.
 const nsInMs = 1000000
 
+// errConflictingCommit is returned on attempt to sign a block that differs
+// from the one already committed at the same height.
+var errConflictingCommit = errors.New("conflicting commit for the same height")
+
 // Ledger is the interface to Blockchain sufficient for Service.
 type Ledger interface {
 	ApplyPolicyToTxSet([]*transaction.Transaction) []*transaction.Transaction
@@ -97,6 +104,10 @@ type service struct {
 	blockEvents  chan *coreb.Block
 	lastProposal []util.Uint256
 	wallet       *wallet.Wallet
+	// signer is an external signer holding validator keys, it's used
+	// instead of the wallet if configured.
+	signer     signer.Signer
+	signerKeys keys.PublicKeys
 	// started is a flag set with Start method that runs an event handling
 	// goroutine.
 	started  atomic.Bool
@@ -107,6 +118,13 @@ type service struct {
 	// before the block is accepted. So, in case of change view, it will contain
 	// an updated value.
 	lastTimestamp uint64
+	// journal keeps messages sent by the node, it's nil if not configured.
+	journal *journal
+	// stateRequests is used to pass GetState requests to the event loop.
+	stateRequests chan chan *result.ConsensusState
+	// rounds is the timeline of recent rounds, the last one is the current
+	// round. It's only accessed from the event loop.
+	rounds []result.ConsensusRound
 }
 
 // Config is a configuration for consensus services.
@@ -133,6 +151,22 @@ type Config struct {
 	// Wallet is a local-node wallet configuration. If the path is empty, then
 	// no wallet will be initialized and the service will be in watch-only mode.
 	Wallet config.Wallet
+	// Timer is a dBFT timer implementation, it's optional and the default
+	// one is used if it's not set. It allows to tweak the notion of current
+	// time used by the service (block timestamps, timeouts) in tests.
+	Timer dbft.Timer
+	// JournalFile is the path to the file used to keep PrepareRequest,
+	// PrepareResponse and Commit messages sent by the node, so that it never
+	// signs different blocks for the same height even after restart. The
+	// journal is not used if it's empty.
+	JournalFile string
+	// RemoteSigner is an external signer configuration. If its address is
+	// set, validator keys are taken from the signer and Wallet is ignored.
+	RemoteSigner config.RemoteSigner
+	// InstantMining enables development mode: dBFT is not used and blocks
+	// are created (and signed with all validator keys available) as soon as
+	// transactions enter the memory pool or when requested explicitly.
+	InstantMining bool
 }
 
 // NewService returns a new consensus.Service instance.
@@ -145,6 +179,10 @@ func NewService(cfg Config) (Service, error) {
 		return nil, errors.New("empty logger")
 	}
 
+	if cfg.InstantMining {
+		return newInstantService(cfg)
+	}
+
 	srv := &service{
 		Config: cfg,
 
@@ -152,35 +190,31 @@ func NewService(cfg Config) (Service, error) {
 		txx:      newFIFOCache(cacheMaxCapacity),
 		messages: make(chan Payload, 100),
 
-		transactions: make(chan *transaction.Transaction, 100),
-		blockEvents:  make(chan *coreb.Block, 1),
-		quit:         make(chan struct{}),
-		finished:     make(chan struct{}),
+		transactions:  make(chan *transaction.Transaction, 100),
+		blockEvents:   make(chan *coreb.Block, 1),
+		quit:          make(chan struct{}),
+		finished:      make(chan struct{}),
+		stateRequests: make(chan chan *result.ConsensusState),
 	}
 
 	var err error
 
-	if len(cfg.Wallet.Path) > 0 {
-		if srv.wallet, err = wallet.NewWalletFromFile(cfg.Wallet.Path); err != nil {
-			return nil, err
-		}
+	if srv.Timer == nil {
+		srv.Timer = timer.New()
+	}
 
-		// Check that the wallet password is correct for at least one account.
-		var ok bool
-		for _, acc := range srv.wallet.Accounts {
-			err := acc.Decrypt(srv.Config.Wallet.Password, srv.wallet.Scrypt)
-			if err == nil {
-				ok = true
-				break
-			}
-		}
-		if !ok {
-			return nil, errors.New("no account with provided password was found")
+	if srv.wallet, srv.signer, srv.signerKeys, err = openKeys(cfg); err != nil {
+		return nil, err
+	}
+
+	if len(cfg.JournalFile) > 0 && (srv.wallet != nil || srv.signer != nil) {
+		if srv.journal, err = openJournal(cfg.JournalFile, srv.log); err != nil {
+			return nil, fmt.Errorf("can't open consensus journal: %w", err)
 		}
 	}
 
 	srv.dbft, err = dbft.New[util.Uint256](
-		dbft.WithTimer[util.Uint256](timer.New()),
+		dbft.WithTimer[util.Uint256](srv.Timer),
 		dbft.WithLogger[util.Uint256](srv.log),
 		dbft.WithSecondsPerBlock[util.Uint256](cfg.TimePerBlock),
 		dbft.WithGetKeyPair[util.Uint256](srv.getKeyPair),
@@ -210,12 +244,46 @@ func NewService(cfg Config) (Service, error) {
 	)
 
 	if err != nil {
+		if srv.journal != nil {
+			_ = srv.journal.close()
+		}
 		return nil, fmt.Errorf("can't initialize dBFT: %w", err)
 	}
 
 	return srv, nil
 }
 
+// openKeys opens the wallet or connects to the remote signer depending on the
+// configuration. Nothing is returned if neither of them is configured.
+func openKeys(cfg Config) (*wallet.Wallet, signer.Signer, keys.PublicKeys, error) {
+	if len(cfg.RemoteSigner.Address) > 0 {
+		sgn, err := signer.NewClient(cfg.RemoteSigner)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("can't create remote signer client: %w", err)
+		}
+		pubs, err := sgn.PublicKeys()
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("can't get keys from remote signer: %w", err)
+		}
+		return nil, sgn, pubs, nil
+	}
+	if len(cfg.Wallet.Path) == 0 {
+		return nil, nil, nil, nil
+	}
+	w, err := wallet.NewWalletFromFile(cfg.Wallet.Path)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	// Check that the wallet password is correct for at least one account.
+	for _, acc := range w.Accounts {
+		if acc.Decrypt(cfg.Wallet.Password, w.Scrypt) == nil {
+			return w, nil, nil, nil
+		}
+	}
+	w.Close()
+	return nil, nil, nil, errors.New("no account with provided password was found")
+}
+
 var (
 	_ dbft.Transaction[util.Uint256] = (*transaction.Transaction)(nil)
 	_ dbft.Block[util.Uint256]       = (*neoBlock)(nil)
@@ -313,6 +381,10 @@ func (s *service) Start() {
 		b, _ := s.Chain.GetBlock(s.Chain.CurrentBlockHash()) // Can't fail, we have some current block!
 		s.lastTimestamp = b.Timestamp
 		s.dbft.Start(s.lastTimestamp * nsInMs)
+		if s.journal != nil {
+			s.restoreJournal()
+		}
+		s.trackRound()
 		go s.eventLoop()
 	}
 }
@@ -326,6 +398,11 @@ func (s *service) Shutdown() {
 		if s.wallet != nil {
 			s.wallet.Close()
 		}
+		if s.journal != nil {
+			if err := s.journal.close(); err != nil {
+				s.log.Warn("failed to close consensus journal", zap.Error(err))
+			}
+		}
 	}
 	_ = s.log.Sync()
 }
@@ -382,6 +459,8 @@ events:
 			s.dbft.OnTransaction(tx)
 		case b := <-s.blockEvents:
 			s.handleChainBlock(b)
+		case ch := <-s.stateRequests:
+			ch <- s.getState()
 		}
 		// Always process block event if there is any, we can add one above or external
 		// services can add several blocks during message processing.
@@ -397,6 +476,7 @@ events:
 		if latestBlock != nil {
 			s.handleChainBlock(latestBlock)
 		}
+		s.trackRound()
 	}
 drainLoop:
 	for {
@@ -422,6 +502,11 @@ func (s *service) handleChainBlock(b *coreb.Block) {
 			zap.Uint32("chain index", s.Chain.BlockHeight()))
 		s.postBlock(b)
 		s.dbft.Reset(b.Timestamp * nsInMs)
+		if s.journal != nil {
+			if err := s.journal.prune(s.dbft.BlockIndex); err != nil {
+				s.log.Warn("failed to prune consensus journal", zap.Error(err))
+			}
+		}
 	}
 }
 
@@ -437,6 +522,16 @@ func (s *service) validatePayload(p *Payload) bool {
 }
 
 func (s *service) getKeyPair(pubs []dbft.PublicKey) (int, dbft.PrivateKey, dbft.PublicKey) {
+	if s.signer != nil {
+		for i := range pubs {
+			pub := pubs[i].(*publicKey).PublicKey
+			for _, k := range s.signerKeys {
+				if k.Equal(pub) {
+					return i, &privateKey{signer: s.signer, pub: k}, &publicKey{PublicKey: k}
+				}
+			}
+		}
+	}
 	if s.wallet != nil {
 		for i := range pubs {
 			sh := pubs[i].(*publicKey).GetScriptHash()
@@ -501,10 +596,126 @@ func (s *service) OnTransaction(tx *transaction.Transaction) {
 func (s *service) broadcast(p dbft.ConsensusPayload[util.Uint256]) {
 	if err := p.(*Payload).Sign(s.dbft.Priv.(*privateKey)); err != nil {
 		s.log.Warn("can't sign consensus payload", zap.Error(err))
+		return
+	}
+
+	var msg = p.(*Payload)
+	if s.journal != nil {
+		var ok bool
+		if msg, ok = s.journalPayload(msg); !ok {
+			return
+		}
 	}
+	s.Config.Broadcast(&msg.Extensible)
+}
 
-	ep := &p.(*Payload).Extensible
-	s.Config.Broadcast(ep)
+// journalPayload saves the PrepareRequest, PrepareResponse or Commit payload to
+// the journal before it's broadcasted and returns the payload to be sent. If
+// the journal already contains a different message of the same type for the
+// same height and view (any view for Commit), the journaled one is returned
+// to not send conflicting messages. False is returned if the payload can't be
+// saved, it must not be sent then.
+func (s *service) journalPayload(p *Payload) (*Payload, bool) {
+	var (
+		rec = journalRecord{
+			Type:   p.message.Type,
+			Height: p.BlockIndex,
+			View:   p.message.ViewNumber,
+		}
+		prev *journalRecord
+	)
+	switch p.message.Type {
+	case prepareRequestType, prepareResponseType:
+		prev = s.journal.find(rec.Type, rec.Height, rec.View)
+	case commitType:
+		rec.BlockHash = s.dbft.MakeHeader().(*neoBlock).Hash()
+		// The same block can be committed in different views.
+		if prev = s.journal.commit(rec.Height); prev != nil && prev.BlockHash == rec.BlockHash {
+			prev = s.journal.find(rec.Type, rec.Height, rec.View)
+		}
+	default:
+		return p, true
+	}
+	w := io.NewBufBinWriter()
+	p.Extensible.EncodeBinary(w.BinWriter)
+	if w.Err != nil {
+		s.log.Error("can't serialize consensus payload", zap.Error(w.Err))
+		return nil, false
+	}
+	rec.Payload = w.Bytes()
+	if prev != nil {
+		if bytes.Equal(prev.Payload, rec.Payload) {
+			return p, true
+		}
+		old, err := s.journalRecordPayload(prev)
+		if err != nil {
+			s.log.Error("can't decode journaled consensus payload", zap.Error(err))
+			return nil, false
+		}
+		s.log.Warn("sending journaled message instead of the conflicting one",
+			zap.Stringer("type", old.Type()),
+			zap.Uint32("height", old.BlockIndex),
+			zap.Uint("view", uint(old.ViewNumber())))
+		return old, true
+	}
+	if err := s.journal.append(rec); err != nil {
+		s.log.Error("can't save consensus payload to the journal", zap.Error(err))
+		return nil, false
+	}
+	return p, true
+}
+
+// journalRecordPayload decodes the payload from the journal record.
+func (s *service) journalRecordPayload(r *journalRecord) (*Payload, error) {
+	var (
+		ep npayload.Extensible
+		br = io.NewBinReaderFromBuf(r.Payload)
+	)
+	ep.DecodeBinary(br)
+	if br.Err != nil {
+		return nil, br.Err
+	}
+	p := s.payloadFromExtensible(&ep)
+	if err := p.decodeData(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// restoreJournal removes outdated records from the journal and passes the
+// Commit sent for the current height (if any) to dBFT, so that it doesn't
+// create another one after restart.
+func (s *service) restoreJournal() {
+	if err := s.journal.prune(s.dbft.BlockIndex); err != nil {
+		s.log.Warn("failed to prune consensus journal", zap.Error(err))
+	}
+	r := s.journal.commit(s.dbft.BlockIndex)
+	if r == nil {
+		return
+	}
+	p, err := s.journalRecordPayload(r)
+	if err != nil {
+		s.log.Error("can't decode journaled consensus payload", zap.Error(err))
+		return
+	}
+	s.log.Info("restoring commit from the journal",
+		zap.Uint32("height", r.Height),
+		zap.Uint("view", uint(r.View)),
+		zap.Stringer("block", r.BlockHash))
+	s.dbft.OnReceive(p)
+}
+
+// checkCommit refuses to sign a block if a different one was already
+// committed at the same height according to the journal.
+func (s *service) checkCommit(b *neoBlock) error {
+	if r := s.journal.commit(b.Block.Index); r != nil && r.BlockHash != b.Hash() {
+		s.log.Error("refusing to sign conflicting block",
+			zap.Uint32("height", b.Block.Index),
+			zap.Stringer("committed", r.BlockHash),
+			zap.Stringer("block", b.Hash()))
+		return errConflictingCommit
+	}
+	return nil
 }
 
 func (s *service) getTx(h util.Uint256) dbft.Transaction[util.Uint256] {
@@ -754,6 +965,9 @@ func convertKeys(validators []dbft.PublicKey) (pubs []*keys.PublicKey) {
 
 func (s *service) newBlockFromContext(ctx *dbft.Context[util.Uint256]) dbft.Block[util.Uint256] {
 	block := &neoBlock{network: s.ProtocolConfiguration.Magic}
+	if s.journal != nil {
+		block.checkSign = s.checkCommit
+	}
 
 	block.Block.Timestamp = ctx.Timestamp / nsInMs
 	block.Block.Nonce = ctx.Nonce
@@ -1,6 +1,10 @@
 package consensus
 
 import (
+	"encoding/pem"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -18,7 +22,9 @@ import (
 	"github.com/nspcc-dev/neo-go/pkg/crypto/hash"
 	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
 	"github.com/nspcc-dev/neo-go/pkg/io"
+	"github.com/nspcc-dev/neo-go/pkg/neorpc/result"
 	npayload "github.com/nspcc-dev/neo-go/pkg/network/payload"
+	"github.com/nspcc-dev/neo-go/pkg/services/signer"
 	"github.com/nspcc-dev/neo-go/pkg/smartcontract"
 	"github.com/nspcc-dev/neo-go/pkg/util"
 	"github.com/nspcc-dev/neo-go/pkg/vm/emit"
@@ -61,6 +67,69 @@ func TestNewWatchingService(t *testing.T) {
 	require.NotPanics(t, srv.Shutdown)
 }
 
+func TestService_RemoteSigner(t *testing.T) {
+	w, err := wallet.NewWalletFromFile("./testdata/wallet1.json")
+	require.NoError(t, err)
+	var privs []*keys.PrivateKey
+	for _, acc := range w.Accounts {
+		require.NoError(t, acc.Decrypt("one", w.Scrypt))
+		privs = append(privs, acc.PrivateKey())
+	}
+	bc := newTestChain(t, false)
+	ts := httptest.NewTLSServer(signer.NewServer(signer.ServerConfig{
+		Network:           bc.GetConfig().Magic,
+		StateRootInHeader: bc.GetConfig().StateRootInHeader,
+		Token:             "secret",
+	}, privs, storage.NewMemoryStore(), zaptest.NewLogger(t)))
+	t.Cleanup(ts.Close)
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	require.NoError(t, os.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ts.Certificate().Raw}), 0o600))
+
+	srv, err := NewService(Config{
+		Logger:                zaptest.NewLogger(t),
+		Broadcast:             func(*npayload.Extensible) {},
+		Chain:                 bc,
+		BlockQueue:            testBlockQueuer{bc: bc},
+		ProtocolConfiguration: bc.GetConfig().ProtocolConfiguration,
+		RequestTx:             func(...util.Uint256) {},
+		StopTxFlow:            func() {},
+		TimePerBlock:          bc.GetConfig().TimePerBlock,
+		RemoteSigner:          config.RemoteSigner{Address: ts.URL, Token: "secret", CAFile: caFile},
+	})
+	require.NoError(t, err)
+	s := srv.(*service)
+	s.dbft.Start(0)
+	t.Cleanup(s.dbft.Timer.Stop)
+	require.Nil(t, s.wallet)
+	priv := s.dbft.Priv.(*privateKey)
+	require.NotNil(t, priv.signer)
+	require.Nil(t, priv.PrivateKey)
+	pub := priv.PublicKey()
+
+	var sent []*npayload.Extensible
+	s.Config.Broadcast = func(p *npayload.Extensible) { sent = append(sent, p) }
+	resp := s.newPayload(&s.dbft.Context, dbft.PrepareResponseType, s.newPrepareResponse(random.Uint256()))
+	s.broadcast(resp)
+	require.Equal(t, 1, len(sent))
+	require.Equal(t, pub.GetVerificationScript(), sent[0].Witness.VerificationScript)
+	require.True(t, pub.VerifyHashable(sent[0].Witness.InvocationScript[2:], uint32(s.ProtocolConfiguration.Magic), sent[0]))
+
+	// The same block can be signed again, but not a different one.
+	s.dbft.Timestamp = uint64(s.dbft.Timer.Now().UnixNano())
+	s.dbft.TransactionHashes = []util.Uint256{}
+	b := s.newBlockFromContext(&s.dbft.Context).(*neoBlock)
+	require.NoError(t, b.Sign(priv))
+	require.NoError(t, b.Verify(&publicKey{PublicKey: pub}, b.Signature()))
+	require.NoError(t, b.Sign(priv))
+	other := s.newBlockFromContext(&s.dbft.Context).(*neoBlock)
+	other.Block.Nonce++
+	require.ErrorIs(t, other.Sign(priv), signer.ErrDoubleSign)
+
+	// Arbitrary data can't be signed.
+	_, err = priv.Sign([]byte{1, 2, 3})
+	require.Error(t, err)
+}
+
 func collectBlock(t *testing.T, bc *core.Blockchain, srv *service) {
 	h := bc.BlockHeight()
 	srv.dbft.OnTimeout(srv.dbft.Context.BlockIndex, 0) // Collect and add block to the chain.
@@ -604,3 +673,178 @@ func signTx(t *testing.T, bc Ledger, txs ...*transaction.Transaction) {
 		}}
 	}
 }
+
+func TestService_GetState(t *testing.T) {
+	srv := newTestService(t)
+	_, err := srv.GetState()
+	require.ErrorIs(t, err, errNotRunning)
+
+	srv.dbft.Start(0)
+	t.Cleanup(srv.dbft.Timer.Stop)
+	srv.trackRound()
+	height := srv.dbft.BlockIndex
+
+	st := srv.getState()
+	require.Equal(t, height, st.Height)
+	require.EqualValues(t, 0, st.View)
+	require.Equal(t, srv.dbft.PrimaryIndex, st.Primary)
+	require.Equal(t, srv.dbft.MyIndex, st.Index)
+	require.Equal(t, len(srv.dbft.Validators), len(st.Validators))
+	for i, v := range st.Validators {
+		require.Equal(t, i, v.Index)
+		require.Equal(t, srv.dbft.Validators[i].(*publicKey).PublicKey, v.PublicKey)
+		require.False(t, v.Preparation)
+		require.False(t, v.Commit)
+		require.Nil(t, v.ChangeView)
+	}
+	require.Equal(t, 1, len(st.Rounds))
+	require.Equal(t, height, st.Rounds[0].Height)
+	require.Empty(t, st.Rounds[0].Result)
+
+	// Messages received are reported.
+	p := srv.newPayload(&srv.dbft.Context, dbft.ChangeViewType, srv.newChangeView(1, dbft.CVTxNotFound, 0))
+	srv.dbft.ChangeViewPayloads[0] = p
+	srv.dbft.CommitPayloads[1] = p
+	srv.dbft.PreparationPayloads[2] = p
+	st = srv.getState()
+	require.Equal(t, &result.ConsensusChangeView{NewView: 1, Reason: "TxNotFound"}, st.Validators[0].ChangeView)
+	require.True(t, st.Validators[1].Commit)
+	require.True(t, st.Validators[2].Preparation)
+
+	// View change.
+	srv.dbft.ViewNumber = 1
+	srv.dbft.LastChangeViewPayloads = []dbft.ConsensusPayload[util.Uint256]{p, nil,
+		srv.newPayload(&srv.dbft.Context, dbft.ChangeViewType, srv.newChangeView(1, dbft.CVTimeout, 0)), p}
+	srv.trackRound()
+	// The same round.
+	srv.trackRound()
+	require.Equal(t, 2, len(srv.rounds))
+	require.Equal(t, result.ConsensusRoundChangeView, srv.rounds[0].Result)
+	require.Equal(t, "TxNotFound", srv.rounds[0].Reason)
+	require.EqualValues(t, 1, srv.rounds[1].View)
+
+	// New block.
+	srv.dbft.BlockIndex++
+	srv.dbft.ViewNumber = 0
+	srv.trackRound()
+	require.Equal(t, 3, len(srv.rounds))
+	require.Equal(t, result.ConsensusRoundBlock, srv.rounds[1].Result)
+	require.Empty(t, srv.rounds[1].Reason)
+	require.Equal(t, height+1, srv.rounds[2].Height)
+
+	// The number of rounds is limited.
+	for i := 0; i < maxRounds; i++ {
+		srv.dbft.BlockIndex++
+		srv.trackRound()
+	}
+	require.Equal(t, maxRounds, len(srv.rounds))
+	require.Equal(t, srv.dbft.BlockIndex, srv.rounds[maxRounds-1].Height)
+}
+
+func TestService_GetStateRunning(t *testing.T) {
+	srv := newTestService(t)
+	srv.Start()
+	t.Cleanup(srv.Shutdown)
+
+	st, err := srv.GetState()
+	require.NoError(t, err)
+	require.Equal(t, srv.Chain.BlockHeight()+1, st.Height)
+	require.Equal(t, 1, len(st.Rounds))
+
+	srv.Shutdown()
+	_, err = srv.GetState()
+	require.ErrorIs(t, err, errNotRunning)
+}
+
+func newInstantTestService(t *testing.T, bc *core.Blockchain) *instantService {
+	srv, err := NewService(Config{
+		Logger:                zaptest.NewLogger(t),
+		Broadcast:             func(*npayload.Extensible) {},
+		Chain:                 bc,
+		BlockQueue:            testBlockQueuer{bc: bc},
+		ProtocolConfiguration: bc.GetConfig().ProtocolConfiguration,
+		RequestTx:             func(...util.Uint256) {},
+		StopTxFlow:            func() {},
+		TimePerBlock:          bc.GetConfig().TimePerBlock,
+		Wallet: config.Wallet{
+			Path:     "./testdata/wallet1.json",
+			Password: "one",
+		},
+		InstantMining: true,
+	})
+	require.NoError(t, err)
+	srv.Start()
+	t.Cleanup(srv.Shutdown)
+	return srv.(*instantService)
+}
+
+func TestService_InstantMining(t *testing.T) {
+	acc, err := wallet.NewAccountFromWIF(testchain.WIF(testchain.IDToOrder(0)))
+	require.NoError(t, err)
+	require.NoError(t, acc.ConvertMultisig(1, keys.PublicKeys{acc.PublicKey()}))
+
+	bc := newSingleTestChain(t)
+	srv := newInstantTestService(t, bc)
+
+	h := bc.BlockHeight()
+	hashes, err := srv.GenerateBlocks(2)
+	require.NoError(t, err)
+	require.Equal(t, h+2, bc.BlockHeight())
+	require.Equal(t, []util.Uint256{bc.GetHeaderHash(h + 1), bc.GetHeaderHash(h + 2)}, hashes)
+
+	st, err := srv.GetState()
+	require.NoError(t, err)
+	require.Equal(t, h+3, st.Height)
+	require.Equal(t, 0, st.Index)
+	require.Equal(t, 1, len(st.Validators))
+
+	tx := transaction.New([]byte{byte(opcode.PUSH1)}, 1_000_000)
+	tx.ValidUntilBlock = bc.BlockHeight() + 2
+	tx.NetworkFee = 10_000_000
+	tx.Signers = []transaction.Signer{{Account: acc.Contract.ScriptHash()}}
+	require.NoError(t, acc.SignTx(netmode.UnitTestNet, tx))
+	require.NoError(t, bc.PoolTx(tx))
+	require.Eventually(t, func() bool {
+		_, height, err := bc.GetTransaction(tx.Hash())
+		return err == nil && height == h+3
+	}, 2*time.Second, 10*time.Millisecond)
+	require.Equal(t, h+3, bc.BlockHeight())
+	require.Equal(t, 0, bc.GetMemPool().Count())
+
+	srv.Shutdown()
+	_, err = srv.GenerateBlocks(1)
+	require.ErrorIs(t, err, errNotRunning)
+}
+
+func TestService_InstantMiningNotEnoughKeys(t *testing.T) {
+	bc := newTestChain(t, false)
+	srv := newInstantTestService(t, bc)
+
+	h := bc.BlockHeight()
+	_, err := srv.GenerateBlocks(1)
+	require.ErrorIs(t, err, errNotEnoughKeys)
+	require.Equal(t, h, bc.BlockHeight())
+
+	_, err = NewService(Config{
+		Logger:        zaptest.NewLogger(t),
+		Chain:         bc,
+		InstantMining: true,
+	})
+	require.Error(t, err)
+
+	t.Run("skip block verification", func(t *testing.T) {
+		cfg, err := config.Load("../../config", netmode.UnitTestNet)
+		require.NoError(t, err)
+		cfg.ApplicationConfiguration.SkipBlockVerification = true
+		bc, err := core.NewBlockchain(storage.NewMemoryStore(), cfg.Blockchain(), zaptest.NewLogger(t))
+		require.NoError(t, err)
+		go bc.Run()
+		t.Cleanup(bc.Close)
+		srv := newInstantTestService(t, bc)
+
+		h := bc.BlockHeight()
+		hashes, err := srv.GenerateBlocks(1)
+		require.NoError(t, err)
+		require.Equal(t, []util.Uint256{bc.GetHeaderHash(h + 1)}, hashes)
+	})
+}
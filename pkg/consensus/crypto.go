@@ -5,22 +5,59 @@ import (
 	"errors"
 
 	"github.com/nspcc-dev/dbft"
+	"github.com/nspcc-dev/neo-go/pkg/config/netmode"
+	"github.com/nspcc-dev/neo-go/pkg/core/block"
 	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
+	npayload "github.com/nspcc-dev/neo-go/pkg/network/payload"
+	"github.com/nspcc-dev/neo-go/pkg/services/signer"
 )
 
 // privateKey is a wrapper around keys.PrivateKey
 // which implements the crypto.PrivateKey interface.
+// If signer is set, the key is held by the external
+// signer and PrivateKey is nil.
 type privateKey struct {
 	*keys.PrivateKey
+
+	signer signer.Signer
+	pub    *keys.PublicKey
 }
 
 var _ dbft.PrivateKey = &privateKey{}
 
-// Sign implements the dbft's crypto.PrivateKey interface.
+// Sign implements the dbft's crypto.PrivateKey interface. Arbitrary data
+// can't be signed by the external signer.
 func (p *privateKey) Sign(data []byte) ([]byte, error) {
+	if p.signer != nil {
+		return nil, errors.New("external signer can't sign arbitrary data")
+	}
 	return p.PrivateKey.Sign(data), nil
 }
 
+// PublicKey returns the public key corresponding to the private one.
+func (p *privateKey) PublicKey() *keys.PublicKey {
+	if p.signer != nil {
+		return p.pub
+	}
+	return p.PrivateKey.PublicKey()
+}
+
+// signBlock signs the block header for the given network.
+func (p *privateKey) signBlock(net netmode.Magic, h *block.Header) ([]byte, error) {
+	if p.signer != nil {
+		return p.signer.SignBlock(p.pub, net, h)
+	}
+	return p.PrivateKey.SignHashable(uint32(net), h), nil
+}
+
+// signExtensible signs the extensible payload for the given network.
+func (p *privateKey) signExtensible(net netmode.Magic, e *npayload.Extensible) ([]byte, error) {
+	if p.signer != nil {
+		return p.signer.SignExtensible(p.pub, net, e)
+	}
+	return p.PrivateKey.SignHashable(uint32(net), e), nil
+}
+
 // publicKey is a wrapper around keys.PublicKey
 // which implements the crypto.PublicKey interface.
 type publicKey struct {
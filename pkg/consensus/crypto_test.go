@@ -11,7 +11,7 @@ func TestCrypt(t *testing.T) {
 	key, err := keys.NewPrivateKey()
 	require.NoError(t, err)
 
-	priv := privateKey{key}
+	priv := privateKey{PrivateKey: key}
 
 	key1, err := keys.NewPrivateKey()
 	require.NoError(t, err)
@@ -0,0 +1,421 @@
+package consensus
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"sort"
+	"sync/atomic"
+	"time"
+
+	"github.com/nspcc-dev/dbft/timer"
+	coreb "github.com/nspcc-dev/neo-go/pkg/core/block"
+	"github.com/nspcc-dev/neo-go/pkg/core/mempoolevent"
+	"github.com/nspcc-dev/neo-go/pkg/core/transaction"
+	"github.com/nspcc-dev/neo-go/pkg/crypto/hash"
+	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
+	"github.com/nspcc-dev/neo-go/pkg/io"
+	"github.com/nspcc-dev/neo-go/pkg/neorpc/result"
+	npayload "github.com/nspcc-dev/neo-go/pkg/network/payload"
+	"github.com/nspcc-dev/neo-go/pkg/services/signer"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/nspcc-dev/neo-go/pkg/vm/emit"
+	"github.com/nspcc-dev/neo-go/pkg/wallet"
+	"go.uber.org/zap"
+)
+
+// errNotEnoughKeys is returned when the instant mining service can't collect
+// enough validator signatures for a block.
+var errNotEnoughKeys = errors.New("not enough validator keys to sign a block")
+
+// instantService is a development consensus service that doesn't run dBFT.
+// It creates a new block as soon as a transaction enters the memory pool or
+// when requested via GenerateBlocks and signs it with validator keys it holds.
+type instantService struct {
+	Config
+
+	log        *zap.Logger
+	wallet     *wallet.Wallet
+	signer     signer.Signer
+	signerKeys keys.PublicKeys
+	started    atomic.Bool
+	quit       chan struct{}
+	finished   chan struct{}
+	// txEvents is subscribed to the memory pool, it's handled by a separate
+	// goroutine that never blocks the pool and notifies the event loop about
+	// new transactions via newTx.
+	txEvents    chan mempoolevent.Event
+	txDone      chan struct{}
+	newTx       chan struct{}
+	blockEvents chan *coreb.Block
+	requests    chan generateRequest
+}
+
+// generateRequest is a GenerateBlocks request passed to the event loop.
+type generateRequest struct {
+	count  int
+	result chan generateResult
+}
+
+type generateResult struct {
+	hashes []util.Uint256
+	err    error
+}
+
+// newInstantService returns a new instant mining consensus service.
+func newInstantService(cfg Config) (*instantService, error) {
+	if cfg.TimePerBlock <= 0 {
+		cfg.TimePerBlock = defaultTimePerBlock
+	}
+	if cfg.Timer == nil {
+		cfg.Timer = timer.New()
+	}
+	srv := &instantService{
+		Config: cfg,
+
+		log:         cfg.Logger,
+		quit:        make(chan struct{}),
+		finished:    make(chan struct{}),
+		txEvents:    make(chan mempoolevent.Event, 100),
+		txDone:      make(chan struct{}),
+		newTx:       make(chan struct{}, 1),
+		blockEvents: make(chan *coreb.Block, 1),
+		requests:    make(chan generateRequest),
+	}
+	var err error
+	if srv.wallet, srv.signer, srv.signerKeys, err = openKeys(cfg); err != nil {
+		return nil, err
+	}
+	if srv.wallet == nil && srv.signer == nil {
+		return nil, errors.New("instant mining requires validator keys")
+	}
+	return srv, nil
+}
+
+// Name implements the Service interface.
+func (s *instantService) Name() string {
+	return "consensus"
+}
+
+// Start implements the Service interface.
+func (s *instantService) Start() {
+	if s.started.CompareAndSwap(false, true) {
+		s.log.Info("starting consensus service in instant mining mode")
+		go s.txLoop()
+		go s.eventLoop()
+	}
+}
+
+// Shutdown implements the Service interface.
+func (s *instantService) Shutdown() {
+	if s.started.CompareAndSwap(true, false) {
+		s.log.Info("stopping consensus service")
+		close(s.quit)
+		<-s.finished
+		if s.wallet != nil {
+			s.wallet.Close()
+		}
+	}
+	_ = s.log.Sync()
+}
+
+// OnPayload implements the Service interface, dBFT messages are ignored.
+func (s *instantService) OnPayload(*npayload.Extensible) error {
+	return nil
+}
+
+// OnTransaction implements the Service interface, transactions are taken
+// from the memory pool, so it's a no-op.
+func (s *instantService) OnTransaction(*transaction.Transaction) {}
+
+// GetState returns the current consensus state. There are no dBFT rounds in
+// instant mining mode, so only the next block height, validators and the
+// index of the first validator the node holds the key for are returned.
+func (s *instantService) GetState() (*result.ConsensusState, error) {
+	if !s.started.Load() {
+		return nil, errNotRunning
+	}
+	pubs, err := s.Chain.GetNextBlockValidators()
+	if err != nil {
+		return nil, err
+	}
+	var (
+		height = s.Chain.BlockHeight() + 1
+		st     = &result.ConsensusState{
+			Height:     height,
+			Index:      -1,
+			Validators: make([]result.ConsensusValidator, len(pubs)),
+			Rounds:     []result.ConsensusRound{},
+		}
+	)
+	if len(pubs) != 0 {
+		st.Primary = uint(height % uint32(len(pubs)))
+	}
+	for i := range pubs {
+		st.Validators[i] = result.ConsensusValidator{Index: i, PublicKey: pubs[i]}
+		if st.Index < 0 && s.hasKey(pubs[i]) {
+			st.Index = i
+		}
+	}
+	return st, nil
+}
+
+// GenerateBlocks creates n blocks one by one (including transactions from the
+// memory pool in them) and returns their hashes once they're accepted by the
+// chain.
+func (s *instantService) GenerateBlocks(n int) ([]util.Uint256, error) {
+	if !s.started.Load() {
+		return nil, errNotRunning
+	}
+	var req = generateRequest{count: n, result: make(chan generateResult, 1)}
+	select {
+	case s.requests <- req:
+	case <-s.finished:
+		return nil, errNotRunning
+	}
+	select {
+	case res := <-req.result:
+		return res.hashes, res.err
+	case <-s.finished:
+		return nil, errNotRunning
+	}
+}
+
+// txLoop handles memory pool events.
+func (s *instantService) txLoop() {
+	for {
+		select {
+		case e := <-s.txEvents:
+			if e.Type != mempoolevent.TransactionAdded {
+				continue
+			}
+			select {
+			case s.newTx <- struct{}{}:
+			default:
+			}
+		case <-s.txDone:
+			return
+		}
+	}
+}
+
+func (s *instantService) eventLoop() {
+	var pool = s.Chain.GetMemPool()
+
+	pool.SubscribeForTransactions(s.txEvents)
+	s.Chain.SubscribeForBlocks(s.blockEvents)
+	// Transactions could be pooled before the service start.
+	s.mine()
+events:
+	for {
+		select {
+		case <-s.quit:
+			break events
+		case <-s.newTx:
+			s.mine()
+		case <-s.blockEvents:
+		case req := <-s.requests:
+			var res generateResult
+			for i := 0; i < req.count; i++ {
+				var b *coreb.Block
+				if b, res.err = s.produceBlock(true); res.err != nil {
+					break
+				}
+				res.hashes = append(res.hashes, b.Hash())
+			}
+			req.result <- res
+		}
+	}
+	pool.UnsubscribeFromTransactions(s.txEvents)
+	close(s.txDone)
+	s.Chain.UnsubscribeFromBlocks(s.blockEvents)
+drainLoop:
+	for {
+		select {
+		case <-s.blockEvents:
+		case <-s.newTx:
+		default:
+			break drainLoop
+		}
+	}
+	close(s.finished)
+}
+
+// mine creates blocks while there are transactions in the memory pool that
+// can be included into them.
+func (s *instantService) mine() {
+	for s.Chain.GetMemPool().Count() > 0 {
+		b, err := s.produceBlock(false)
+		if err != nil {
+			s.log.Error("can't create block", zap.Error(err))
+			return
+		}
+		// Pooled transactions don't fit into the block.
+		if b == nil {
+			return
+		}
+		select {
+		case <-s.quit:
+			return
+		default:
+		}
+	}
+}
+
+// produceBlock creates a new block, passes it to the block queue and waits
+// for it to be accepted by the chain. If empty is false and there are no
+// transactions to be included into the block, nothing is done and nil is
+// returned.
+func (s *instantService) produceBlock(empty bool) (*coreb.Block, error) {
+	b, err := s.newBlock()
+	if err != nil {
+		return nil, err
+	}
+	if !empty && len(b.Transactions) == 0 {
+		return nil, nil
+	}
+	if err = s.BlockQueue.PutBlock(b); err != nil {
+		return nil, fmt.Errorf("can't enqueue block: %w", err)
+	}
+	var timeout = time.NewTimer(s.TimePerBlock)
+	defer timeout.Stop()
+	for s.Chain.BlockHeight() < b.Index {
+		select {
+		case <-s.blockEvents:
+		case <-timeout.C:
+			return nil, fmt.Errorf("block %d wasn't accepted in time", b.Index)
+		case <-s.quit:
+			return nil, errNotRunning
+		}
+	}
+	s.log.Info("block created",
+		zap.Uint32("index", b.Index),
+		zap.Int("tx count", len(b.Transactions)))
+	return b, nil
+}
+
+// newBlock creates the next block with transactions from the memory pool and
+// signs it.
+func (s *instantService) newBlock() (*coreb.Block, error) {
+	prev, err := s.Chain.GetBlock(s.Chain.CurrentBlockHash())
+	if err != nil {
+		return nil, fmt.Errorf("can't get current block: %w", err)
+	}
+	validators, err := s.Chain.GetNextBlockValidators()
+	if err != nil {
+		return nil, fmt.Errorf("can't get validators: %w", err)
+	}
+	txx := s.Chain.GetMemPool().GetVerifiedTransactions()
+	if len(txx) > 0 {
+		txx = s.Chain.ApplyPolicyToTxSet(txx)
+	}
+	script, err := smartcontract.CreateDefaultMultiSigRedeemScript(s.Chain.ComputeNextBlockValidators())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create multisignature script: %w", err)
+	}
+	ts := uint64(s.Timer.Now().UnixMilli())
+	if ts <= prev.Timestamp {
+		ts = prev.Timestamp + 1
+	}
+	b := &coreb.Block{
+		Header: coreb.Header{
+			Version:       coreb.VersionInitial,
+			PrevHash:      prev.Hash(),
+			Timestamp:     ts,
+			Nonce:         rand.Uint64(),
+			Index:         prev.Index + 1,
+			PrimaryIndex:  byte((prev.Index + 1) % uint32(len(validators))),
+			NextConsensus: hash.Hash160(script),
+		},
+		Transactions: txx,
+	}
+	if s.ProtocolConfiguration.StateRootInHeader {
+		sr, err := s.Chain.GetStateRoot(prev.Index)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get state root: %w", err)
+		}
+		b.StateRootEnabled = true
+		b.PrevStateRoot = sr.Root
+	}
+	b.RebuildMerkleRoot()
+	if err = s.signBlock(b, validators); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// signBlock creates the block witness with the signatures of validators the
+// node holds keys for.
+func (s *instantService) signBlock(b *coreb.Block, validators keys.PublicKeys) error {
+	var (
+		m    = smartcontract.GetDefaultHonestNodeCount(len(validators))
+		pubs = make(keys.PublicKeys, len(validators))
+		buf  = io.NewBufBinWriter()
+		n    int
+	)
+	verif, err := smartcontract.CreateMultiSigRedeemScript(m, validators)
+	if err != nil {
+		return fmt.Errorf("can't create multisig redeem script: %w", err)
+	}
+	copy(pubs, validators)
+	sort.Sort(pubs)
+	for i := 0; i < len(pubs) && n < m; i++ {
+		priv := s.getKey(pubs[i])
+		if priv == nil {
+			continue
+		}
+		sig, err := priv.signBlock(s.ProtocolConfiguration.Magic, &b.Header)
+		if err != nil {
+			return fmt.Errorf("can't sign block: %w", err)
+		}
+		emit.Bytes(buf.BinWriter, sig)
+		n++
+	}
+	// Chains that don't verify blocks (like the forked ones with validators
+	// unknown to the node) accept blocks without enough signatures.
+	if n < m && !s.Chain.GetConfig().SkipBlockVerification {
+		return fmt.Errorf("%w: %d of %d", errNotEnoughKeys, n, m)
+	}
+	b.Script = transaction.Witness{
+		InvocationScript:   buf.Bytes(),
+		VerificationScript: verif,
+	}
+	return nil
+}
+
+// hasKey checks whether the node holds the key for the given validator.
+func (s *instantService) hasKey(pub *keys.PublicKey) bool {
+	if s.signer != nil {
+		for _, k := range s.signerKeys {
+			if k.Equal(pub) {
+				return true
+			}
+		}
+		return false
+	}
+	return s.wallet.GetAccount(pub.GetScriptHash()) != nil
+}
+
+// getKey returns the private key for the given validator or nil if the node
+// doesn't hold it.
+func (s *instantService) getKey(pub *keys.PublicKey) *privateKey {
+	if s.signer != nil {
+		for _, k := range s.signerKeys {
+			if k.Equal(pub) {
+				return &privateKey{signer: s.signer, pub: k}
+			}
+		}
+		return nil
+	}
+	acc := s.wallet.GetAccount(pub.GetScriptHash())
+	if acc == nil {
+		return nil
+	}
+	if !acc.CanSign() {
+		if err := acc.Decrypt(s.Config.Wallet.Password, s.wallet.Scrypt); err != nil {
+			return nil
+		}
+	}
+	return &privateKey{PrivateKey: acc.PrivateKey()}
+}
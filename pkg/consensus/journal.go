@@ -0,0 +1,226 @@
+package consensus
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	gio "io"
+	"os"
+	"path/filepath"
+	"runtime"
+
+	"github.com/nspcc-dev/neo-go/pkg/io"
+	npayload "github.com/nspcc-dev/neo-go/pkg/network/payload"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"go.uber.org/zap"
+)
+
+const (
+	// journalFileVersion is the current version of the journal file format.
+	journalFileVersion = 0
+	// maxJournalPayloadSize is the limit for the serialized Extensible
+	// payload in the journal record (its data is limited by npayload.MaxSize,
+	// the rest is a small header and witness).
+	maxJournalPayloadSize = 2 * npayload.MaxSize
+)
+
+// journalRecord is a consensus message sent by the node.
+type journalRecord struct {
+	Type   messageType
+	Height uint32
+	View   byte
+	// BlockHash is the hash of the signed block for Commit messages.
+	BlockHash util.Uint256
+	// Payload is the serialized signed Extensible payload.
+	Payload []byte
+}
+
+// journal is a write-ahead log of PrepareRequest, PrepareResponse and Commit
+// messages sent by the node. Every record is synced to the disk before the
+// message is broadcasted, so after restart the node knows what it has already
+// signed for the current height.
+type journal struct {
+	file    *os.File
+	path    string
+	records []journalRecord
+}
+
+// EncodeBinary implements the io.Serializable interface.
+func (r *journalRecord) EncodeBinary(w *io.BinWriter) {
+	w.WriteB(byte(r.Type))
+	w.WriteU32LE(r.Height)
+	w.WriteB(r.View)
+	w.WriteBytes(r.BlockHash[:])
+	w.WriteVarBytes(r.Payload)
+}
+
+// DecodeBinary implements the io.Serializable interface.
+func (r *journalRecord) DecodeBinary(br *io.BinReader) {
+	r.Type = messageType(br.ReadB())
+	r.Height = br.ReadU32LE()
+	r.View = br.ReadB()
+	br.ReadBytes(r.BlockHash[:])
+	r.Payload = br.ReadVarBytes(maxJournalPayloadSize)
+}
+
+// openJournal opens (creating if needed) the journal file and reads all
+// records from it. Incomplete trailing record (that can be left after crash)
+// is dropped, any other decoding error means the journal is corrupted and is
+// returned without changing the file.
+func openJournal(path string, log *zap.Logger) (*journal, error) {
+	data, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	var (
+		j     = &journal{path: path}
+		valid int
+	)
+	if len(data) != 0 {
+		if data[0] != journalFileVersion {
+			return nil, fmt.Errorf("unsupported journal file version %d", data[0])
+		}
+		var (
+			buf = bytes.NewReader(data[1:])
+			br  = io.NewBinReaderFromIO(buf)
+		)
+		valid = 1
+		for buf.Len() != 0 {
+			var r journalRecord
+			r.DecodeBinary(br)
+			if br.Err != nil {
+				// Records are appended with a single write, so only the last
+				// one can be torn by a crash.
+				if !errors.Is(br.Err, gio.EOF) && !errors.Is(br.Err, gio.ErrUnexpectedEOF) {
+					return nil, fmt.Errorf("corrupted journal record #%d at offset %d: %w", len(j.records), valid, br.Err)
+				}
+				log.Warn("dropping incomplete consensus journal record",
+					zap.Int("records", len(j.records)),
+					zap.Int("dropped bytes", len(data)-valid))
+				break
+			}
+			j.records = append(j.records, r)
+			valid = len(data) - buf.Len()
+		}
+	}
+	j.file, err = os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	if valid == 0 {
+		_, err = j.file.Write([]byte{journalFileVersion})
+	} else {
+		err = j.file.Truncate(int64(valid))
+	}
+	if err == nil {
+		err = j.file.Sync()
+	}
+	if err != nil {
+		j.file.Close()
+		return nil, err
+	}
+	return j, nil
+}
+
+// append writes the record to the journal and syncs it to the disk.
+func (j *journal) append(r journalRecord) error {
+	w := io.NewBufBinWriter()
+	r.EncodeBinary(w.BinWriter)
+	if _, err := j.file.Write(w.Bytes()); err != nil {
+		return err
+	}
+	if err := j.file.Sync(); err != nil {
+		return err
+	}
+	j.records = append(j.records, r)
+	return nil
+}
+
+// prune removes records for heights lower than the given one. The journal is
+// rewritten to a temporary file that replaces the original one, the journal
+// is left intact if anything fails.
+func (j *journal) prune(height uint32) error {
+	var (
+		keep []journalRecord
+		w    = io.NewBufBinWriter()
+	)
+	for _, r := range j.records {
+		if r.Height >= height {
+			keep = append(keep, r)
+		}
+	}
+	if len(keep) == len(j.records) {
+		return nil
+	}
+	w.WriteB(journalFileVersion)
+	for i := range keep {
+		keep[i].EncodeBinary(w.BinWriter)
+	}
+	tmp := j.path + ".tmp"
+	// The new file is kept open to be used for appending after rename, so
+	// that there is no moment without a valid journal handle.
+	f, err := os.OpenFile(tmp, os.O_CREATE|os.O_TRUNC|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(w.Bytes())
+	if err == nil {
+		err = f.Sync()
+	}
+	if err == nil {
+		err = os.Rename(tmp, j.path)
+	}
+	if err != nil {
+		_ = f.Close()
+		_ = os.Remove(tmp)
+		return err
+	}
+	_ = j.file.Close()
+	j.file = f
+	j.records = keep
+	return syncDir(filepath.Dir(j.path))
+}
+
+// syncDir syncs the directory to make the rename of the file in it durable.
+// Directories can't be synced on Windows where it's not needed.
+func syncDir(path string) error {
+	if runtime.GOOS == "windows" {
+		return nil
+	}
+	d, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	err = d.Sync()
+	if cerr := d.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// commit returns Commit record for the given height if there is any.
+func (j *journal) commit(height uint32) *journalRecord {
+	for i := range j.records {
+		if j.records[i].Type == commitType && j.records[i].Height == height {
+			return &j.records[i]
+		}
+	}
+	return nil
+}
+
+// find returns the record of the given type for the given height and view if
+// there is any.
+func (j *journal) find(t messageType, height uint32, view byte) *journalRecord {
+	for i := range j.records {
+		r := &j.records[i]
+		if r.Type == t && r.Height == height && r.View == view {
+			return r
+		}
+	}
+	return nil
+}
+
+// close closes the journal file.
+func (j *journal) close() error {
+	return j.file.Close()
+}
@@ -0,0 +1,169 @@
+package consensus
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/nspcc-dev/dbft"
+	"github.com/nspcc-dev/neo-go/internal/random"
+	"github.com/nspcc-dev/neo-go/pkg/config"
+	"github.com/nspcc-dev/neo-go/pkg/io"
+	npayload "github.com/nspcc-dev/neo-go/pkg/network/payload"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestJournal(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "journal")
+	j, err := openJournal(path, zaptest.NewLogger(t))
+	require.NoError(t, err)
+	require.Equal(t, 0, len(j.records))
+
+	recs := []journalRecord{
+		{Type: prepareRequestType, Height: 1, View: 0, Payload: []byte{1, 2, 3}},
+		{Type: commitType, Height: 1, View: 1, BlockHash: random.Uint256(), Payload: []byte{4, 5}},
+		{Type: prepareResponseType, Height: 2, View: 0, Payload: []byte{6}},
+	}
+	for _, r := range recs {
+		require.NoError(t, j.append(r))
+	}
+	require.Equal(t, &recs[1], j.commit(1))
+	require.Nil(t, j.commit(2))
+	require.Equal(t, &recs[2], j.find(prepareResponseType, 2, 0))
+	require.Nil(t, j.find(prepareResponseType, 2, 1))
+	require.NoError(t, j.close())
+
+	// Incomplete trailing record is dropped.
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0)
+	require.NoError(t, err)
+	_, err = f.Write([]byte{byte(commitType), 2, 0})
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	j, err = openJournal(path, zaptest.NewLogger(t))
+	require.NoError(t, err)
+	require.Equal(t, recs, j.records)
+	require.NoError(t, j.prune(2))
+	require.Equal(t, recs[2:], j.records)
+	require.NoError(t, j.append(recs[1]))
+	require.NoError(t, j.close())
+
+	j, err = openJournal(path, zaptest.NewLogger(t))
+	require.NoError(t, err)
+	require.Equal(t, []journalRecord{recs[2], recs[1]}, j.records)
+	require.NoError(t, j.close())
+
+	t.Run("prune failure", func(t *testing.T) {
+		j, err := openJournal(path, zaptest.NewLogger(t))
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = j.close() })
+		require.NoError(t, os.Mkdir(path+".tmp", 0o700))
+		t.Cleanup(func() { _ = os.Remove(path + ".tmp") })
+		require.Error(t, j.prune(3))
+		require.Equal(t, []journalRecord{recs[2], recs[1]}, j.records)
+		require.NoError(t, j.append(recs[0]))
+	})
+
+	t.Run("corrupted record", func(t *testing.T) {
+		p := filepath.Join(t.TempDir(), "journal")
+		w := io.NewBufBinWriter()
+		w.WriteB(journalFileVersion)
+		recs[0].EncodeBinary(w.BinWriter)
+		w.WriteBytes([]byte{byte(commitType), 2, 0, 0, 0, 0})
+		w.WriteBytes(make([]byte, util.Uint256Size))
+		w.WriteVarUint(maxJournalPayloadSize + 1)
+		recs[1].EncodeBinary(w.BinWriter)
+		require.NoError(t, w.Err)
+		data := w.Bytes()
+		require.NoError(t, os.WriteFile(p, data, 0o600))
+		_, err := openJournal(p, zaptest.NewLogger(t))
+		require.ErrorContains(t, err, "corrupted journal record #1")
+		actual, err := os.ReadFile(p)
+		require.NoError(t, err)
+		require.Equal(t, data, actual)
+	})
+
+	require.NoError(t, os.WriteFile(path, []byte{0xff}, 0o600))
+	_, err = openJournal(path, zaptest.NewLogger(t))
+	require.Error(t, err)
+}
+
+func newTestServiceWithJournal(t *testing.T, path string) *service {
+	bc := newTestChain(t, false)
+	srv, err := NewService(Config{
+		Logger:                zaptest.NewLogger(t),
+		Broadcast:             func(*npayload.Extensible) {},
+		Chain:                 bc,
+		BlockQueue:            testBlockQueuer{bc: bc},
+		ProtocolConfiguration: bc.GetConfig().ProtocolConfiguration,
+		RequestTx:             func(...util.Uint256) {},
+		StopTxFlow:            func() {},
+		TimePerBlock:          bc.GetConfig().TimePerBlock,
+		Wallet: config.Wallet{
+			Path:     "./testdata/wallet1.json",
+			Password: "one",
+		},
+		JournalFile: path,
+	})
+	require.NoError(t, err)
+	s := srv.(*service)
+	s.dbft.Start(0)
+	t.Cleanup(func() {
+		s.dbft.Timer.Stop()
+		_ = s.journal.close()
+	})
+	return s
+}
+
+func TestService_Journal(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "journal")
+	srv := newTestServiceWithJournal(t, path)
+	height := srv.dbft.BlockIndex
+
+	var sent []*npayload.Extensible
+	srv.Config.Broadcast = func(p *npayload.Extensible) { sent = append(sent, p) }
+
+	// PrepareResponse is journaled, a different one for the same view is
+	// replaced with the journaled one.
+	resp1 := srv.newPayload(&srv.dbft.Context, dbft.PrepareResponseType, srv.newPrepareResponse(random.Uint256()))
+	srv.broadcast(resp1)
+	resp2 := srv.newPayload(&srv.dbft.Context, dbft.PrepareResponseType, srv.newPrepareResponse(random.Uint256()))
+	srv.broadcast(resp2)
+	require.Equal(t, 2, len(sent))
+	require.Equal(t, sent[0].Hash(), sent[1].Hash())
+	require.Equal(t, resp1.Hash(), sent[1].Hash())
+
+	// Commit the block.
+	srv.dbft.Timestamp = uint64(srv.dbft.Timer.Now().UnixNano())
+	srv.dbft.TransactionHashes = []util.Uint256{}
+	srv.dbft.PreparationPayloads[srv.dbft.PrimaryIndex] = resp1
+	header := srv.dbft.MakeHeader().(*neoBlock)
+	require.NoError(t, header.Sign(srv.dbft.Priv))
+	commit := srv.newPayload(&srv.dbft.Context, dbft.CommitType, srv.newCommit(header.Signature()))
+	srv.broadcast(commit)
+	require.Equal(t, 3, len(sent))
+	r := srv.journal.commit(height)
+	require.NotNil(t, r)
+	require.Equal(t, header.Hash(), r.BlockHash)
+
+	// The same block can be signed again, but not a different one.
+	require.NoError(t, header.Sign(srv.dbft.Priv))
+	other := srv.newBlockFromContext(&srv.dbft.Context).(*neoBlock)
+	other.Block.Nonce++
+	require.ErrorIs(t, other.Sign(srv.dbft.Priv), errConflictingCommit)
+
+	// Restart, the commit is restored from the journal.
+	require.NoError(t, srv.journal.close())
+	srv2 := newTestServiceWithJournal(t, path)
+	require.Equal(t, height, srv2.dbft.BlockIndex)
+	require.False(t, srv2.dbft.CommitSent())
+	srv2.restoreJournal()
+	require.True(t, srv2.dbft.CommitSent())
+	require.Equal(t, commit.Hash(), srv2.dbft.CommitPayloads[srv2.dbft.MyIndex].Hash())
+
+	// New blocks remove outdated records.
+	require.NoError(t, srv2.journal.prune(height+1))
+	require.Nil(t, srv2.journal.commit(height))
+}
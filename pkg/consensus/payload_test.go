@@ -252,7 +252,7 @@ func TestPayload_Sign(t *testing.T) {
 	key, err := keys.NewPrivateKey()
 	require.NoError(t, err)
 
-	priv := &privateKey{key}
+	priv := &privateKey{PrivateKey: key}
 
 	p := randomPayload(t, prepareRequestType)
 	h := priv.PublicKey().GetScriptHash()
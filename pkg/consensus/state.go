@@ -0,0 +1,129 @@
+package consensus
+
+import (
+	"errors"
+
+	"github.com/nspcc-dev/dbft"
+	"github.com/nspcc-dev/neo-go/pkg/neorpc/result"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+)
+
+// maxRounds is the number of recent rounds kept in the timeline.
+const maxRounds = 100
+
+// errNotRunning is returned on attempt to get state of the service that is not
+// running.
+var errNotRunning = errors.New("consensus service is not running")
+
+// GetState returns the current dBFT state and the timeline of recent rounds.
+// The state is collected by the event loop, so it's consistent with the
+// messages processed by the service.
+func (s *service) GetState() (*result.ConsensusState, error) {
+	if !s.started.Load() {
+		return nil, errNotRunning
+	}
+	ch := make(chan *result.ConsensusState, 1)
+	select {
+	case s.stateRequests <- ch:
+	case <-s.finished:
+		return nil, errNotRunning
+	}
+	select {
+	case st := <-ch:
+		return st, nil
+	case <-s.finished:
+		return nil, errNotRunning
+	}
+}
+
+// getState returns the current dBFT state, it must be called from the event
+// loop.
+func (s *service) getState() *result.ConsensusState {
+	var (
+		ctx = &s.dbft.Context
+		st  = &result.ConsensusState{
+			Height:     ctx.BlockIndex,
+			View:       ctx.ViewNumber,
+			Primary:    ctx.PrimaryIndex,
+			Index:      ctx.MyIndex,
+			Validators: make([]result.ConsensusValidator, len(ctx.Validators)),
+			Rounds:     make([]result.ConsensusRound, len(s.rounds)),
+		}
+	)
+	for i := range ctx.Validators {
+		v := &st.Validators[i]
+		v.Index = i
+		v.PublicKey = ctx.Validators[i].(*publicKey).PublicKey
+		v.Preparation = i < len(ctx.PreparationPayloads) && ctx.PreparationPayloads[i] != nil
+		v.Commit = i < len(ctx.CommitPayloads) && ctx.CommitPayloads[i] != nil
+		if i < len(ctx.ChangeViewPayloads) && ctx.ChangeViewPayloads[i] != nil {
+			cv := ctx.ChangeViewPayloads[i].GetChangeView()
+			v.ChangeView = &result.ConsensusChangeView{
+				NewView: cv.NewViewNumber(),
+				Reason:  cv.Reason().String(),
+			}
+		}
+		if i < len(ctx.LastSeenMessage) && ctx.LastSeenMessage[i] != nil {
+			v.LastSeen = &result.ConsensusHeightView{
+				Height: ctx.LastSeenMessage[i].Height,
+				View:   ctx.LastSeenMessage[i].View,
+			}
+		}
+	}
+	copy(st.Rounds, s.rounds)
+	if n := len(st.Rounds); n != 0 {
+		cur := &st.Rounds[n-1]
+		cur.Duration = uint64(s.dbft.Timer.Now().UnixMilli()) - cur.Start
+	}
+	return st
+}
+
+// trackRound updates the rounds timeline if dBFT has moved to a new height or
+// view since the last call, it must be called from the event loop.
+func (s *service) trackRound() {
+	var ctx = &s.dbft.Context
+	if n := len(s.rounds); n != 0 {
+		cur := &s.rounds[n-1]
+		if cur.Height == ctx.BlockIndex && cur.View == ctx.ViewNumber {
+			return
+		}
+		now := uint64(s.dbft.Timer.Now().UnixMilli())
+		cur.Duration = now - cur.Start
+		if cur.Height == ctx.BlockIndex {
+			cur.Result = result.ConsensusRoundChangeView
+			cur.Reason = changeViewReason(ctx.LastChangeViewPayloads).String()
+		} else {
+			cur.Result = result.ConsensusRoundBlock
+		}
+	}
+	if len(s.rounds) == maxRounds {
+		copy(s.rounds, s.rounds[1:])
+		s.rounds = s.rounds[:maxRounds-1]
+	}
+	s.rounds = append(s.rounds, result.ConsensusRound{
+		Height:  ctx.BlockIndex,
+		View:    ctx.ViewNumber,
+		Primary: ctx.PrimaryIndex,
+		Start:   uint64(s.dbft.Timer.Now().UnixMilli()),
+	})
+}
+
+// changeViewReason returns the most common reason among the given ChangeView
+// payloads (CVUnknown if there are none).
+func changeViewReason(ps []dbft.ConsensusPayload[util.Uint256]) dbft.ChangeViewReason {
+	var (
+		counts = make(map[dbft.ChangeViewReason]int)
+		reason = dbft.CVUnknown
+	)
+	for _, p := range ps {
+		if p == nil {
+			continue
+		}
+		r := p.GetChangeView().Reason()
+		counts[r]++
+		if counts[r] > counts[reason] || (counts[r] == counts[reason] && r < reason) {
+			reason = r
+		}
+	}
+	return reason
+}
@@ -20,6 +20,7 @@ import (
 	"github.com/nspcc-dev/neo-go/pkg/core/interop"
 	"github.com/nspcc-dev/neo-go/pkg/core/interop/contract"
 	"github.com/nspcc-dev/neo-go/pkg/core/mempool"
+	"github.com/nspcc-dev/neo-go/pkg/core/mempoolevent"
 	"github.com/nspcc-dev/neo-go/pkg/core/mpt"
 	"github.com/nspcc-dev/neo-go/pkg/core/native"
 	"github.com/nspcc-dev/neo-go/pkg/core/native/noderoles"
@@ -35,6 +36,7 @@ import (
 	"github.com/nspcc-dev/neo-go/pkg/smartcontract"
 	"github.com/nspcc-dev/neo-go/pkg/smartcontract/callflag"
 	"github.com/nspcc-dev/neo-go/pkg/smartcontract/manifest"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract/nef"
 	"github.com/nspcc-dev/neo-go/pkg/smartcontract/trigger"
 	"github.com/nspcc-dev/neo-go/pkg/util"
 	"github.com/nspcc-dev/neo-go/pkg/vm"
@@ -109,6 +111,9 @@ var (
 	// conflicts with other transaction in the chain or pool according to
 	// Conflicts attribute.
 	ErrHasConflicts = errors.New("has conflicts")
+	// ErrReadOnly is returned when trying to add blocks, headers or
+	// transactions to the read-only replica Blockchain.
+	ErrReadOnly = errors.New("read-only replica")
 )
 var (
 	persistInterval = 1 * time.Second
@@ -161,6 +166,13 @@ type Blockchain struct {
 	runToExitCh chan struct{}
 	// isRunning denotes whether blockchain routines are currently running.
 	isRunning atomic.Value
+	// rebuildState denotes that the chain state is behind the blocks present
+	// in the storage (it was initialized from scratch or wasn't saved
+	// completely), so Run should process them again.
+	rebuildState bool
+	// genesisContracts are contract dumps to be imported in the genesis
+	// block, they're only loaded when it's being created.
+	genesisContracts []*state.ContractDump
 
 	memPool *mempool.Pool
 
@@ -196,6 +208,7 @@ type StateRoot interface {
 	CurrentLocalHeight() uint32
 	CurrentLocalStateRoot() util.Uint256
 	CurrentValidatedHeight() uint32
+	DiffStates(old, root util.Uint256, cont func(key, oldValue, newValue []byte) bool) error
 	FindStates(root util.Uint256, prefix, start []byte, max int) ([]storage.KeyValue, error)
 	SeekStates(root util.Uint256, prefix []byte, f func(k, v []byte) bool)
 	GetState(root util.Uint256, key []byte) ([]byte, error)
@@ -302,6 +315,9 @@ func NewBlockchain(s storage.Store, cfg config.Blockchain, log *zap.Logger) (*Bl
 	}
 
 	// Local config consistency checks.
+	if cfg.Mempool.MaxSenderTransactions < 0 || cfg.Mempool.MaxSenderSize < 0 {
+		return nil, errors.New("negative per-sender memory pool limit")
+	}
 	if cfg.Ledger.RemoveUntraceableBlocks && cfg.Ledger.GarbageCollectionPeriod == 0 {
 		cfg.Ledger.GarbageCollectionPeriod = defaultGCPeriod
 		log.Info("GarbageCollectionPeriod is not set or wrong, using default value", zap.Uint32("GarbageCollectionPeriod", cfg.Ledger.GarbageCollectionPeriod))
@@ -313,7 +329,7 @@ func NewBlockchain(s storage.Store, cfg config.Blockchain, log *zap.Logger) (*Bl
 		store:       s,
 		stopCh:      make(chan struct{}),
 		runToExitCh: make(chan struct{}),
-		memPool:     mempool.New(cfg.MemPoolSize, 0, false, updateMempoolMetrics),
+		memPool:     mempool.New(cfg.MemPoolSize, 0, true, updateMempoolMetrics),
 		log:         log,
 		events:      make(chan bcEvent),
 		subCh:       make(chan any),
@@ -321,6 +337,8 @@ func NewBlockchain(s storage.Store, cfg config.Blockchain, log *zap.Logger) (*Bl
 		contracts:   *native.NewContracts(cfg.ProtocolConfiguration),
 	}
 
+	bc.memPool.SetSenderLimits(cfg.Mempool.MaxSenderTransactions, cfg.Mempool.MaxSenderSize, cfg.Mempool.FairEviction)
+
 	bc.stateRoot = stateroot.NewModule(cfg, bc.VerifyWitness, bc.log, bc.dao.Store)
 	bc.contracts.Designate.StateRootService = bc.stateRoot
 
@@ -407,21 +425,20 @@ func (bc *Blockchain) SetNotary(mod native.NotaryService) {
 func (bc *Blockchain) init() error {
 	// If we could not find the version in the Store, we know that there is nothing stored.
 	ver, err := bc.dao.GetVersion()
+	if err != nil && bc.config.Replica {
+		return errors.New("replica DB is empty, it must be a copy of the primary node DB")
+	}
 	if err != nil {
 		bc.log.Info("no storage version found! creating genesis block")
-		ver = dao.Version{
-			StoragePrefix:              storage.STStorage,
-			StateRootInHeader:          bc.config.StateRootInHeader,
-			P2PSigExtensions:           bc.config.P2PSigExtensions,
-			P2PStateExchangeExtensions: bc.config.P2PStateExchangeExtensions,
-			KeepOnlyLatestState:        bc.config.Ledger.KeepOnlyLatestState,
-			Magic:                      uint32(bc.config.Magic),
-			Value:                      version,
-		}
+		ver = newStorageVersion(bc.config)
 		bc.dao.PutVersion(ver)
 		bc.dao.Version = ver
 		bc.persistent.Version = ver
-		genesisBlock, err := CreateGenesisBlock(bc.config.ProtocolConfiguration)
+		bc.genesisContracts, err = loadContractDumps(bc.config.Genesis.ContractDumps)
+		if err != nil {
+			return fmt.Errorf("failed to load genesis contracts: %w", err)
+		}
+		genesisBlock, err := createGenesisBlock(bc.config.ProtocolConfiguration, bc.genesisContracts)
 		if err != nil {
 			return err
 		}
@@ -429,7 +446,7 @@ func (bc *Blockchain) init() error {
 		if err := bc.stateRoot.Init(0); err != nil {
 			return fmt.Errorf("can't init MPT: %w", err)
 		}
-		return bc.storeBlock(genesisBlock, nil)
+		return bc.storeGenesisBlock(genesisBlock)
 	}
 	if ver.Value != version {
 		return fmt.Errorf("storage version mismatch (expected=%s, actual=%s)", version, ver.Value)
@@ -470,6 +487,9 @@ func (bc *Blockchain) init() error {
 	// Check whether StateChangeState stage is in the storage and continue interrupted state jump / state reset if so.
 	stateChStage, err := bc.dao.Store.Get([]byte{byte(storage.SYSStateChangeStage)})
 	if err == nil {
+		if bc.config.Replica {
+			return errors.New("replica DB has unfinished state jump or reset")
+		}
 		if len(stateChStage) != 1 {
 			return fmt.Errorf("invalid state jump stage format")
 		}
@@ -489,6 +509,9 @@ func (bc *Blockchain) init() error {
 	}
 
 	bHeight, err := bc.dao.GetCurrentBlockHeight()
+	if errors.Is(err, storage.ErrKeyNotFound) && !bc.config.Replica && bc.isStateEmpty() {
+		return bc.initStateFromGenesis()
+	}
 	if err != nil {
 		return fmt.Errorf("failed to retrieve current block height: %w", err)
 	}
@@ -543,6 +566,21 @@ func (bc *Blockchain) init() error {
 		}
 	}
 
+	// Chain data and state can be kept in different DBs (see
+	// storage.SplitStore) that are not updated atomically, so the state
+	// may lag behind the blocks stored, they're processed again by Run then.
+	// State sync stores blocks ahead of the current height too, they're
+	// handled by the statesync module.
+	if _, split := bc.store.(*storage.SplitStore); split && !bc.config.Replica &&
+		bHeight < bc.HeaderHeight() && !bc.isStateSyncStarted() {
+		if _, err := bc.GetBlock(bc.GetHeaderHash(bHeight + 1)); err == nil {
+			bc.log.Info("chain state is behind the stored blocks, processing them again",
+				zap.Uint32("blockHeight", bHeight),
+				zap.Uint32("headerHeight", bc.HeaderHeight()))
+			bc.rebuildState = true
+		}
+	}
+
 	updateBlockHeightMetric(bHeight)
 	updatePersistedHeightMetric(bHeight)
 	updateHeaderHeightMetric(bc.HeaderHeight())
@@ -550,6 +588,123 @@ func (bc *Blockchain) init() error {
 	return bc.updateExtensibleWhitelist(bHeight)
 }
 
+// initStateFromGenesis initializes an empty chain state keeping the stored
+// headers and blocks. It happens when the state is kept in a separate DB (see
+// storage.SplitStore) that was dropped, stored blocks are then processed again
+// by Run.
+func (bc *Blockchain) initStateFromGenesis() error {
+	if bc.config.Ledger.RemoveUntraceableBlocks {
+		return errors.New("chain state is missing and can't be rebuilt with RemoveUntraceableBlocks enabled")
+	}
+	dumps, err := loadContractDumps(bc.config.Genesis.ContractDumps)
+	if err != nil {
+		return fmt.Errorf("failed to load genesis contracts: %w", err)
+	}
+	genesisBlock, err := createGenesisBlock(bc.config.ProtocolConfiguration, dumps)
+	if err != nil {
+		return err
+	}
+	if !genesisBlock.Hash().Equals(bc.GetHeaderHash(0)) {
+		return errors.New("chain state is missing and the stored genesis block doesn't match the configuration")
+	}
+	bc.log.Info("no chain state found, rebuilding it from the stored blocks",
+		zap.Uint32("headerHeight", bc.HeaderHeight()))
+	if err := bc.stateRoot.Init(0); err != nil {
+		return fmt.Errorf("can't init MPT: %w", err)
+	}
+	bc.rebuildState = true
+	bc.genesisContracts = dumps
+	return bc.storeGenesisBlock(genesisBlock)
+}
+
+// storeGenesisBlock stores the genesis block and checks that its transaction
+// (if any) is successfully executed, otherwise genesis allocations and
+// contracts are missing.
+func (bc *Blockchain) storeGenesisBlock(b *block.Block) error {
+	if err := bc.storeBlock(b, nil); err != nil {
+		return err
+	}
+	for _, tx := range b.Transactions {
+		aers, err := bc.GetAppExecResults(tx.Hash(), trigger.Application)
+		if err != nil {
+			return fmt.Errorf("can't get genesis transaction result: %w", err)
+		}
+		if aers[0].VMState != vmstate.Halt {
+			return fmt.Errorf("genesis transaction failed: %s", aers[0].FaultException)
+		}
+	}
+	return nil
+}
+
+// isStateSyncStarted returns true if there is some state sync data in the
+// storage.
+func (bc *Blockchain) isStateSyncStarted() bool {
+	if _, err := bc.dao.GetStateSyncPoint(); err == nil {
+		return true
+	}
+	_, err := bc.dao.GetStateSyncCurrentBlockHeight()
+	return err == nil
+}
+
+// isStateEmpty returns true if there are no contract storage items in the
+// storage.
+func (bc *Blockchain) isStateEmpty() bool {
+	var empty = true
+	for _, p := range []storage.KeyPrefix{storage.STStorage, storage.STTempStorage} {
+		bc.dao.Store.Seek(storage.SeekRange{Prefix: []byte{byte(p)}}, func(_, _ []byte) bool {
+			empty = false
+			return false
+		})
+	}
+	return empty
+}
+
+// processStoredBlocks applies blocks present in the storage to the chain
+// state until it reaches the header height, the first block missing or
+// Blockchain is closed.
+func (bc *Blockchain) processStoredBlocks() {
+	var start = time.Now()
+	for {
+		bc.addLock.Lock()
+		select {
+		case <-bc.stopCh:
+			bc.addLock.Unlock()
+			return
+		default:
+		}
+		var h = bc.BlockHeight() + 1
+		if h > bc.HeaderHeight() {
+			bc.addLock.Unlock()
+			break
+		}
+		b, err := bc.GetBlock(bc.GetHeaderHash(h))
+		if err == nil {
+			err = bc.storeBlock(b, nil)
+		}
+		bc.addLock.Unlock()
+		if err != nil {
+			bc.log.Info("stopped processing stored blocks", zap.Uint32("height", h), zap.Error(err))
+			break
+		}
+	}
+	bc.log.Info("chain state rebuilt", zap.Uint32("blockHeight", bc.BlockHeight()),
+		zap.Duration("took", time.Since(start)))
+}
+
+// newStorageVersion returns the version of a fresh storage for the given
+// configuration.
+func newStorageVersion(cfg config.Blockchain) dao.Version {
+	return dao.Version{
+		StoragePrefix:              storage.STStorage,
+		StateRootInHeader:          cfg.StateRootInHeader,
+		P2PSigExtensions:           cfg.P2PSigExtensions,
+		P2PStateExchangeExtensions: cfg.P2PStateExchangeExtensions,
+		KeepOnlyLatestState:        cfg.Ledger.KeepOnlyLatestState,
+		Magic:                      uint32(cfg.Magic),
+		Value:                      version,
+	}
+}
+
 // jumpToState is an atomic operation that changes Blockchain state to the one
 // specified by the state sync point p. All the data needed for the jump must be
 // collected by the state sync module.
@@ -1084,6 +1239,7 @@ func (bc *Blockchain) Run() {
 	persistTimer := time.NewTimer(persistInterval)
 	defer func() {
 		persistTimer.Stop()
+		bc.memPool.StopSubscriptions()
 		if _, err := bc.persist(true); err != nil {
 			bc.log.Warn("failed to persist", zap.Error(err))
 		}
@@ -1093,7 +1249,11 @@ func (bc *Blockchain) Run() {
 		bc.isRunning.Store(false)
 		close(bc.runToExitCh)
 	}()
+	bc.memPool.RunSubscriptions()
 	go bc.notificationDispatcher()
+	if bc.rebuildState {
+		go bc.processStoredBlocks()
+	}
 	var nextSync bool
 	for {
 		select {
@@ -1103,14 +1263,16 @@ func (bc *Blockchain) Run() {
 			var oldPersisted uint32
 			var gcDur time.Duration
 
-			if bc.config.Ledger.RemoveUntraceableBlocks {
+			// Replica data is removed by the primary node.
+			var gc = bc.config.Ledger.RemoveUntraceableBlocks && !bc.config.Replica
+			if gc {
 				oldPersisted = atomic.LoadUint32(&bc.persistedHeight)
 			}
 			dur, err := bc.persist(nextSync)
 			if err != nil {
 				bc.log.Warn("failed to persist blockchain", zap.Error(err))
 			}
-			if bc.config.Ledger.RemoveUntraceableBlocks {
+			if gc {
 				gcDur = bc.tryRunGC(oldPersisted)
 			}
 			nextSync = dur > persistInterval*2
@@ -1477,6 +1639,9 @@ func (bc *Blockchain) Close() {
 // AddBlock accepts successive block for the Blockchain, verifies it and
 // stores internally. Eventually it will be persisted to the backing storage.
 func (bc *Blockchain) AddBlock(block *block.Block) error {
+	if bc.config.Replica {
+		return ErrReadOnly
+	}
 	bc.addLock.Lock()
 	defer bc.addLock.Unlock()
 
@@ -1529,9 +1694,108 @@ func (bc *Blockchain) AddBlock(block *block.Block) error {
 // AddHeaders processes the given headers and add them to the
 // HeaderHashList. It expects headers to be sorted by index.
 func (bc *Blockchain) AddHeaders(headers ...*block.Header) error {
+	if bc.config.Replica {
+		return ErrReadOnly
+	}
 	return bc.addHeaders(!bc.config.SkipBlockVerification, headers...)
 }
 
+// ApplyChangeSet writes the set of DB changes made by some other node (in the
+// same format storage.Store.PutChangeSet accepts them) directly to the
+// underlying persistent Store and reloads chain state from it. It's only
+// allowed for the replica Blockchain (see config.Blockchain.Replica) which is
+// expected to receive all changes made to the primary node DB in the same
+// order. Subscribers are notified about every new block the same way it's
+// done for the blocks added.
+func (bc *Blockchain) ApplyChangeSet(changes map[string][]byte, storageChanges map[string][]byte) error {
+	if !bc.config.Replica {
+		return errors.New("changes can only be applied to replica")
+	}
+	bc.addLock.Lock()
+	defer bc.addLock.Unlock()
+
+	err := bc.store.PutChangeSet(changes, storageChanges)
+	if err != nil {
+		return fmt.Errorf("failed to store changes: %w", err)
+	}
+	height, err := bc.persistent.GetCurrentBlockHeight()
+	if err != nil {
+		return fmt.Errorf("failed to retrieve current block height: %w", err)
+	}
+	oldHeight := bc.BlockHeight()
+
+	bc.lock.Lock()
+	err = bc.reloadState(height)
+	bc.lock.Unlock()
+	if err != nil {
+		return err
+	}
+
+	for h := oldHeight + 1; h <= height; h++ {
+		b, err := bc.GetBlock(bc.GetHeaderHash(h))
+		if err != nil {
+			return fmt.Errorf("failed to get block %d: %w", h, err)
+		}
+		aers, err := bc.dao.GetAppExecResults(b.Hash(), trigger.All)
+		if err != nil {
+			return fmt.Errorf("failed to get block %d execution results: %w", h, err)
+		}
+		if len(aers) < 2 {
+			return fmt.Errorf("block %d has %d execution results instead of 2", h, len(aers))
+		}
+		var event = bcEvent{block: b, appExecResults: make([]*state.AppExecResult, 0, len(b.Transactions)+2)}
+		event.appExecResults = append(event.appExecResults, &aers[0])
+		for _, tx := range b.Transactions {
+			txAERs, err := bc.dao.GetAppExecResults(tx.Hash(), trigger.Application)
+			if err != nil {
+				return fmt.Errorf("failed to get transaction %s execution result: %w", tx.Hash().StringLE(), err)
+			}
+			if len(txAERs) == 0 {
+				return fmt.Errorf("no execution result for transaction %s", tx.Hash().StringLE())
+			}
+			event.appExecResults = append(event.appExecResults, &txAERs[0])
+		}
+		event.appExecResults = append(event.appExecResults, &aers[1])
+		bc.events <- event
+	}
+	return nil
+}
+
+// reloadState reinitializes in-memory chain state (headers, MPT, native
+// contract caches) from the persistent store at the given block height.
+func (bc *Blockchain) reloadState(height uint32) error {
+	bc.HeaderHashes.lock.Lock()
+	err := bc.HeaderHashes.init(bc.dao)
+	bc.HeaderHashes.lock.Unlock()
+	if err != nil {
+		return fmt.Errorf("failed to reload headers: %w", err)
+	}
+	b, err := bc.GetBlock(bc.GetHeaderHash(height))
+	if err != nil {
+		return fmt.Errorf("failed to get current block: %w", err)
+	}
+	if err = bc.stateRoot.Init(height); err != nil {
+		return fmt.Errorf("can't init MPT at height %d: %w", height, err)
+	}
+	cache := bc.dao.GetPrivate()
+	if err = bc.initializeNativeCache(height, cache); err != nil {
+		return fmt.Errorf("failed to initialize natives cache: %w", err)
+	}
+	if _, err = cache.Persist(); err != nil {
+		return err
+	}
+	bc.topBlock.Store(b)
+	atomic.StoreUint32(&bc.blockHeight, height)
+	atomic.StoreUint32(&bc.persistedHeight, height)
+	if err = bc.updateExtensibleWhitelist(height); err != nil {
+		return fmt.Errorf("failed to update extensible whitelist: %w", err)
+	}
+	updateBlockHeightMetric(height)
+	updatePersistedHeightMetric(height)
+	updateHeaderHeightMetric(bc.HeaderHeight())
+	return nil
+}
+
 // addHeaders is an internal implementation of AddHeaders (`verify` parameter
 // tells it to verify or not verify given headers).
 func (bc *Blockchain) addHeaders(verify bool, headers ...*block.Header) error {
@@ -1586,6 +1850,69 @@ func (bc *Blockchain) GetStateModule() StateRoot {
 	return bc.stateRoot
 }
 
+// GetStateDiff returns the set of contract storage changes made by the block
+// with the specified index. It's computed from the MPT states of this block
+// and the previous one, so both of them must be present in the storage.
+func (bc *Blockchain) GetStateDiff(index uint32) (*state.StateDiff, error) {
+	if bc.config.Ledger.KeepOnlyLatestState {
+		return nil, errors.New("only latest state is supported")
+	}
+	if index > bc.stateRoot.CurrentLocalHeight() {
+		return nil, fmt.Errorf("no state for height %d, current state height %d", index, bc.stateRoot.CurrentLocalHeight())
+	}
+	sr, err := bc.stateRoot.GetStateRoot(index)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get stateroot for height %d: %w", index, err)
+	}
+	res := &state.StateDiff{
+		Index:     index,
+		Root:      sr.Root,
+		Contracts: []state.ContractStorageDiff{},
+	}
+	if index > 0 {
+		prev, err := bc.stateRoot.GetStateRoot(index - 1)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get stateroot for height %d: %w", index-1, err)
+		}
+		res.PrevRoot = prev.Root
+	}
+	err = bc.stateRoot.DiffStates(res.PrevRoot, res.Root, func(k, oldV, newV []byte) bool {
+		if len(k) < 4 {
+			return true
+		}
+		var (
+			id = int32(binary.LittleEndian.Uint32(k))
+			ch = state.StorageChange{
+				Type:     state.StorageModified,
+				Key:      k[4:],
+				OldValue: oldV,
+				NewValue: newV,
+			}
+		)
+		switch {
+		case oldV == nil:
+			ch.Type = state.StorageAdded
+		case newV == nil:
+			ch.Type = state.StorageDeleted
+		}
+		// MPT keys start with contract ID, so changes of a single contract
+		// are adjacent.
+		if l := len(res.Contracts); l == 0 || res.Contracts[l-1].ID != id {
+			res.Contracts = append(res.Contracts, state.ContractStorageDiff{ID: id})
+		}
+		c := &res.Contracts[len(res.Contracts)-1]
+		c.Changes = append(c.Changes, ch)
+		return true
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to compare states: %w", err)
+	}
+	sort.Slice(res.Contracts, func(i, j int) bool {
+		return res.Contracts[i].ID < res.Contracts[j].ID
+	})
+	return res, nil
+}
+
 // GetStateSyncModule returns new state sync service instance.
 func (bc *Blockchain) GetStateSyncModule() *statesync.Module {
 	return statesync.NewModule(bc, bc.stateRoot, bc.log, bc.dao, bc.jumpToState)
@@ -1688,6 +2015,13 @@ func (bc *Blockchain) storeBlock(block *block.Block, txpool *mempool.Pool) error
 		<-aerdone
 		return fmt.Errorf("onPersist failed: %w", err)
 	}
+	if block.Index == 0 {
+		if err := bc.importGenesisContracts(cache); err != nil {
+			close(aerchan)
+			<-aerdone
+			return fmt.Errorf("failed to import genesis contracts: %w", err)
+		}
+	}
 	appExecResults = append(appExecResults, aer)
 	aerchan <- aer
 
@@ -1795,7 +2129,9 @@ func (bc *Blockchain) storeBlock(block *block.Block, txpool *mempool.Pool) error
 	bc.stateRoot.UpdateCurrentLocal(mpt, sr)
 	bc.topBlock.Store(block)
 	atomic.StoreUint32(&bc.blockHeight, block.Index)
-	bc.memPool.RemoveStale(func(tx *transaction.Transaction) bool { return bc.IsTxStillRelevant(tx, txpool, false) }, bc)
+	bc.memPool.RemoveStaleWithReason(func(tx *transaction.Transaction) mempoolevent.Reason {
+		return bc.staleTxReason(tx, txpool)
+	}, bc)
 	for _, f := range bc.postBlock {
 		f(bc.IsTxStillRelevant, txpool, block)
 	}
@@ -2440,6 +2776,36 @@ unsubloop:
 	}
 }
 
+// SubscribeForMempoolEvents adds given channel to the main memory pool event
+// broadcasting, so when a transaction is added to the pool or removed from it
+// you'll receive an event via this channel. Make sure it's read from regularly
+// as events are dropped if this subscriber can't keep up with them (memory
+// pool operations are never blocked by it). Make sure you're not changing the
+// received events, as it may affect the functionality of Blockchain and other
+// subscribers.
+func (bc *Blockchain) SubscribeForMempoolEvents(ch chan mempoolevent.Event) {
+	bc.memPool.SubscribeForTransactionsNonBlocking(ch)
+}
+
+// UnsubscribeFromMempoolEvents unsubscribes given channel from memory pool
+// events, you can close it afterwards. Passing non-subscribed channel is a
+// no-op, but the method can read from this channel (discarding any read data)
+// which allows to avoid deadlocks.
+func (bc *Blockchain) UnsubscribeFromMempoolEvents(ch chan mempoolevent.Event) {
+	var done = make(chan struct{})
+	go func() {
+		bc.memPool.UnsubscribeFromTransactions(ch)
+		close(done)
+	}()
+	for {
+		select {
+		case <-ch:
+		case <-done:
+			return
+		}
+	}
+}
+
 // CalculateClaimable calculates the amount of GAS generated by owning specified
 // amount of NEO between specified blocks.
 func (bc *Blockchain) CalculateClaimable(acc util.Uint160, endHeight uint32) (*big.Int, error) {
@@ -2592,6 +2958,9 @@ func (bc *Blockchain) verifyAndPoolTx(t *transaction.Transaction, pool *mempool.
 	}
 	err = pool.Add(t, feer, data...)
 	if err != nil {
+		if pool == bc.memPool {
+			updateMempoolRejectionMetrics(err)
+		}
 		switch {
 		case errors.Is(err, mempool.ErrConflict):
 			return ErrMemPoolConflict
@@ -2601,6 +2970,8 @@ func (bc *Blockchain) verifyAndPoolTx(t *transaction.Transaction, pool *mempool.
 			return ErrInsufficientFunds
 		case errors.Is(err, mempool.ErrOOM):
 			return ErrOOM
+		case errors.Is(err, mempool.ErrSenderTxLimit), errors.Is(err, mempool.ErrSenderSizeLimit):
+			return fmt.Errorf("%w: %w", ErrOOM, err)
 		case errors.Is(err, mempool.ErrConflictsAttribute):
 			return fmt.Errorf("mempool: %w: %w", ErrHasConflicts, err)
 		default:
@@ -2747,6 +3118,24 @@ func (bc *Blockchain) IsTxStillRelevant(t *transaction.Transaction, txpool *memp
 	return true
 }
 
+// staleTxReason returns the reason for the main pool transaction removal after
+// the block (with transactions from txpool) acceptance or mempoolevent.NoReason
+// if the transaction is still relevant.
+func (bc *Blockchain) staleTxReason(t *transaction.Transaction, txpool *mempool.Pool) mempoolevent.Reason {
+	switch {
+	case txpool.ContainsKey(t.Hash()):
+		return mempoolevent.ReasonIncluded
+	case t.ValidUntilBlock <= bc.BlockHeight():
+		return mempoolevent.ReasonExpired
+	case bc.IsTxStillRelevant(t, txpool, false):
+		return mempoolevent.NoReason
+	case txpool.HasConflicts(t, bc):
+		return mempoolevent.ReasonConflict
+	default:
+		return mempoolevent.ReasonInvalid
+	}
+}
+
 // VerifyTx verifies whether transaction is bonafide or not relative to the
 // current blockchain state. Note that this verification is completely isolated
 // from the main node's mempool.
@@ -2762,6 +3151,10 @@ func (bc *Blockchain) VerifyTx(t *transaction.Transaction) error {
 func (bc *Blockchain) PoolTx(t *transaction.Transaction, pools ...*mempool.Pool) error {
 	var pool = bc.memPool
 
+	if bc.config.Replica {
+		return ErrReadOnly
+	}
+
 	bc.lock.RLock()
 	defer bc.lock.RUnlock()
 	// Programmer error.
@@ -2776,6 +3169,9 @@ func (bc *Blockchain) PoolTx(t *transaction.Transaction, pools ...*mempool.Pool)
 
 // PoolTxWithData verifies and tries to add given transaction with additional data into the mempool.
 func (bc *Blockchain) PoolTxWithData(t *transaction.Transaction, data any, mp *mempool.Pool, feer mempool.Feer, verificationFunction func(tx *transaction.Transaction, data any) error) error {
+	if bc.config.Replica {
+		return ErrReadOnly
+	}
 	bc.lock.RLock()
 	defer bc.lock.RUnlock()
 
@@ -2821,6 +3217,67 @@ func (bc *Blockchain) GetEnrollments() ([]state.Validator, error) {
 	return bc.contracts.NEO.GetCandidates(bc.dao)
 }
 
+// GetCommitteeHistoric returns the sorted list of committee members using the
+// state of the specified height (the one used by GetCommittee when the chain
+// was at this height).
+func (bc *Blockchain) GetCommitteeHistoric(height uint32) (keys.PublicKeys, error) {
+	d, err := bc.getHistoricDAO(height, height)
+	if err != nil {
+		return nil, err
+	}
+	pubs := bc.contracts.NEO.GetCommitteeMembers(d)
+	sort.Sort(pubs)
+	return pubs, nil
+}
+
+// GetCandidatesHistoric returns validators of the block following the one
+// with the specified height along with all the candidates registered at this
+// height (what GetNextBlockValidators and GetEnrollments returned when the
+// chain was at this height).
+func (bc *Blockchain) GetCandidatesHistoric(height uint32) ([]*keys.PublicKey, []state.Validator, error) {
+	d, err := bc.getHistoricDAO(height, height)
+	if err != nil {
+		return nil, nil, err
+	}
+	enrollments, err := bc.contracts.NEO.GetCandidates(d)
+	if err != nil {
+		return nil, nil, err
+	}
+	return bc.contracts.NEO.GetNextBlockValidatorsInternal(d), enrollments, nil
+}
+
+// getHistoricDAO returns DAO backed by the MPT state of the specified height
+// with native contract caches initialized for the given block height (the
+// same one for state queries and the next one for test invocations). It
+// requires the state to be present in the storage.
+func (bc *Blockchain) getHistoricDAO(height uint32, cacheHeight uint32) (*dao.Simple, error) {
+	if bc.config.Ledger.KeepOnlyLatestState {
+		return nil, errors.New("only latest state is supported")
+	}
+	if height > bc.BlockHeight() {
+		return nil, fmt.Errorf("unsupported historic chain's height: requested state for %d, chain height %d", height, bc.BlockHeight())
+	}
+	var mode = mpt.ModeAll
+	if bc.config.Ledger.RemoveUntraceableBlocks {
+		if h := bc.BlockHeight(); h > bc.config.MaxTraceableBlocks && height < h-bc.config.MaxTraceableBlocks {
+			return nil, fmt.Errorf("state for height %d is outdated and removed from the storage", height)
+		}
+		mode |= mpt.ModeGCFlag
+	}
+	sr, err := bc.stateRoot.GetStateRoot(height)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve stateroot for height %d: %w", height, err)
+	}
+	s := mpt.NewTrieStore(sr.Root, mode, storage.NewPrivateMemCachedStore(bc.dao.Store))
+	d := dao.NewSimple(s, bc.config.StateRootInHeader)
+	d.Version = bc.dao.Version
+	err = bc.initializeNativeCache(cacheHeight, d)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize native cache backed by historic DAO: %w", err)
+	}
+	return d, nil
+}
+
 // GetTestVM returns an interop context with VM set up for a test run.
 func (bc *Blockchain) GetTestVM(t trigger.Type, tx *transaction.Transaction, b *block.Block) (*interop.Context, error) {
 	if b == nil {
@@ -2838,40 +3295,145 @@ func (bc *Blockchain) GetTestVM(t trigger.Type, tx *transaction.Transaction, b *
 
 // GetTestHistoricVM returns an interop context with VM set up for a test run.
 func (bc *Blockchain) GetTestHistoricVM(t trigger.Type, tx *transaction.Transaction, nextBlockHeight uint32) (*interop.Context, error) {
-	if bc.config.Ledger.KeepOnlyLatestState {
-		return nil, errors.New("only latest state is supported")
+	if nextBlockHeight < 1 {
+		return nil, fmt.Errorf("unsupported historic chain's height: requested state for %d, chain height %d", nextBlockHeight, bc.BlockHeight())
+	}
+	// Assuming that block N-th is processing during historic call, the historic invocation should be based on the storage state of height N-1.
+	// Native cache is initialized before passing DAO to interop context constructor, because
+	// the constructor will call BaseExecFee/StoragePrice policy methods on the passed DAO.
+	dTrie, err := bc.getHistoricDAO(nextBlockHeight-1, nextBlockHeight)
+	if err != nil {
+		return nil, err
 	}
 	b, err := bc.getFakeNextBlock(nextBlockHeight)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create fake block for height %d: %w", nextBlockHeight, err)
 	}
-	var mode = mpt.ModeAll
-	if bc.config.Ledger.RemoveUntraceableBlocks {
-		if b.Index < bc.BlockHeight()-bc.config.MaxTraceableBlocks {
-			return nil, fmt.Errorf("state for height %d is outdated and removed from the storage", b.Index)
-		}
-		mode |= mpt.ModeGCFlag
+	systemInterop := bc.newInteropContext(t, dTrie, b, tx)
+	_ = systemInterop.SpawnVM() // All the other code suppose that the VM is ready.
+	return systemInterop, nil
+}
+
+// GetTestHistoricTxVM returns an interop context with VM set up for the
+// re-execution of the persisted transaction with the given hash. The state is
+// exactly the one transaction was executed with originally: it's based on the
+// state of the previous block with OnPersist and all preceding transactions of
+// the same block applied. Transaction script is loaded into the VM, GAS limit
+// is set to the transaction system fee, so Exec can be called right away.
+func (bc *Blockchain) GetTestHistoricTxVM(h util.Uint256) (*interop.Context, error) {
+	tx, height, err := bc.dao.GetTransaction(h)
+	if err != nil {
+		return nil, err
 	}
-	if b.Index < 1 || b.Index > bc.BlockHeight()+1 {
-		return nil, fmt.Errorf("unsupported historic chain's height: requested state for %d, chain height %d", b.Index, bc.blockHeight)
+	b, err := bc.GetBlock(bc.GetHeaderHash(height))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get block %d: %w", height, err)
 	}
-	// Assuming that block N-th is processing during historic call, the historic invocation should be based on the storage state of height N-1.
-	sr, err := bc.stateRoot.GetStateRoot(b.Index - 1)
+	ic, err := bc.GetTestHistoricVM(trigger.OnPersist, nil, height)
 	if err != nil {
-		return nil, fmt.Errorf("failed to retrieve stateroot for height %d: %w", b.Index, err)
+		return nil, err
 	}
-	s := mpt.NewTrieStore(sr.Root, mode, storage.NewPrivateMemCachedStore(bc.dao.Store))
-	dTrie := dao.NewSimple(s, bc.config.StateRootInHeader)
-	dTrie.Version = bc.dao.Version
-	// Initialize native cache before passing DAO to interop context constructor, because
-	// the constructor will call BaseExecFee/StoragePrice policy methods on the passed DAO.
-	err = bc.initializeNativeCache(b.Index, dTrie)
+	var cache = ic.DAO
+	_, v, err := bc.runPersist(bc.contracts.GetPersistScript(), b, cache, trigger.OnPersist, ic.VM)
 	if err != nil {
-		return nil, fmt.Errorf("failed to initialize native cache backed by historic DAO: %w", err)
+		return nil, fmt.Errorf("onPersist failed: %w", err)
 	}
-	systemInterop := bc.newInteropContext(t, dTrie, b, tx)
-	_ = systemInterop.SpawnVM() // All the other code suppose that the VM is ready.
-	return systemInterop, nil
+	for _, btx := range b.Transactions {
+		systemInterop := bc.newInteropContext(trigger.Application, cache, b, btx)
+		systemInterop.ReuseVM(v)
+		v.LoadScriptWithFlags(btx.Script, callflag.All)
+		v.GasLimit = btx.SystemFee
+		if btx.Hash() == tx.Hash() {
+			return systemInterop, nil
+		}
+		_ = systemInterop.Exec() // Changes of failed transactions are just dropped.
+		if !v.HasFailed() {
+			if _, err = systemInterop.DAO.Persist(); err != nil {
+				return nil, fmt.Errorf("failed to persist invocation results: %w", err)
+			}
+		}
+	}
+	return nil, fmt.Errorf("transaction %s is not found in block %d", h.StringLE(), height)
+}
+
+// ApplyStateOverrides applies the given overrides to the test invocation
+// context. Modifications are made in a separate DAO layer, so they're not
+// included into the set of context DAO changes.
+func (bc *Blockchain) ApplyStateOverrides(ic *interop.Context, o *state.Overrides) error {
+	for _, c := range o.Contracts {
+		nf, err := nef.FileFromBytes(c.NEF)
+		if err != nil {
+			return fmt.Errorf("contract %s: invalid NEF: %w", c.Hash.StringLE(), err)
+		}
+		if err = c.Manifest.IsValid(c.Hash, true); err != nil {
+			return fmt.Errorf("contract %s: invalid manifest: %w", c.Hash.StringLE(), err)
+		}
+		cs := &state.Contract{ContractBase: state.ContractBase{Hash: c.Hash, NEF: nf, Manifest: c.Manifest}}
+		if err = bc.contracts.Management.Inject(ic.DAO, cs); err != nil {
+			return fmt.Errorf("contract %s: %w", c.Hash.StringLE(), err)
+		}
+	}
+	for _, st := range o.Storage {
+		cs, err := native.GetContract(ic.DAO, st.Hash)
+		if err != nil {
+			return fmt.Errorf("storage of %s: %w", st.Hash.StringLE(), err)
+		}
+		if st.Value == nil {
+			ic.DAO.DeleteStorageItem(cs.ID, st.Key)
+		} else {
+			ic.DAO.PutStorageItem(cs.ID, st.Key, st.Value)
+		}
+	}
+	for _, b := range o.Balances {
+		if b.Amount == nil || b.Amount.Sign() < 0 {
+			return fmt.Errorf("balance of %s: invalid amount", b.Account.StringLE())
+		}
+		switch b.Asset {
+		case bc.contracts.NEO.Hash:
+			if err := bc.contracts.NEO.SetBalance(ic.DAO, b.Account, b.Amount, ic.Block.Index); err != nil {
+				return fmt.Errorf("NEO balance of %s: %w", b.Account.StringLE(), err)
+			}
+		case bc.contracts.GAS.Hash:
+			bc.contracts.GAS.SetBalance(ic.DAO, b.Account, b.Amount)
+		default:
+			return fmt.Errorf("balance of %s: unsupported asset %s", b.Account.StringLE(), b.Asset.StringLE())
+		}
+	}
+	if o.Block != nil {
+		blk := *ic.Block
+		if o.Block.Index != nil {
+			blk.Index = *o.Block.Index
+		}
+		if o.Block.Timestamp != nil {
+			blk.Timestamp = *o.Block.Timestamp
+		}
+		ic.Block = &blk
+	}
+	ic.DAO = ic.DAO.GetPrivate()
+	return nil
+}
+
+// importGenesisContracts puts contracts from the configured dumps along with
+// their storage into the given DAO. Contracts keep their hashes and update
+// counters, but get new IDs.
+func (bc *Blockchain) importGenesisContracts(d *dao.Simple) error {
+	for _, cd := range bc.genesisContracts {
+		cs := cd.Contract
+		if err := bc.contracts.Management.Inject(d, &cs); err != nil {
+			return fmt.Errorf("contract %s: %w", cs.Hash.StringLE(), err)
+		}
+		if cd.Contract.UpdateCounter != 0 {
+			cs.UpdateCounter = cd.Contract.UpdateCounter
+			if err := native.PutContractState(d, &cs); err != nil {
+				return fmt.Errorf("contract %s: %w", cs.Hash.StringLE(), err)
+			}
+		}
+		for _, item := range cd.Storage {
+			d.PutStorageItem(cs.ID, item.Key, item.Value)
+		}
+	}
+	bc.genesisContracts = nil
+	return nil
 }
 
 // getFakeNextBlock returns fake block with the specified index and pre-filled Timestamp field.
@@ -410,3 +410,51 @@ func TestNewBlockchain_InitHardforks(t *testing.T) {
 		}, bc.GetConfig().Hardforks)
 	})
 }
+
+func TestBlockchain_Replica(t *testing.T) {
+	_, err := initTestChainNoCheck(t, nil, func(c *config.Config) {
+		c.ApplicationConfiguration.Replica.Enabled = true
+	})
+	require.ErrorContains(t, err, "replica DB is empty")
+
+	bc := newTestChain(t)
+	require.Error(t, bc.ApplyChangeSet(nil, nil))
+}
+
+func TestBlockchain_InitLaggingState(t *testing.T) {
+	// lagging creates a chain with two blocks in the given store and moves the
+	// current block pointer one block back.
+	lagging := func(t *testing.T, st storage.Store) *Blockchain {
+		bc := newTestChainWithCustomCfgAndStore(t, st, nil)
+		require.NoError(t, bc.AddBlock(bc.newBlock()))
+		b1, err := bc.GetBlock(bc.GetHeaderHash(1))
+		require.NoError(t, err)
+		require.NoError(t, bc.AddBlock(bc.newBlock()))
+		bc.dao.StoreAsCurrentBlock(b1)
+		_, err = bc.dao.PersistSync()
+		require.NoError(t, err)
+		return bc
+	}
+	t.Run("split store", func(t *testing.T) {
+		st := storage.NewSplitStore(storage.NewMemoryStore(), storage.NewMemoryStore())
+		lagging(t, st)
+		bc := initTestChain(t, st, nil)
+		require.Equal(t, uint32(1), bc.BlockHeight())
+		require.True(t, bc.rebuildState)
+	})
+	t.Run("state sync", func(t *testing.T) {
+		st := storage.NewSplitStore(storage.NewMemoryStore(), storage.NewMemoryStore())
+		old := lagging(t, st)
+		old.dao.PutStateSyncPoint(2)
+		_, err := old.dao.PersistSync()
+		require.NoError(t, err)
+		bc := initTestChain(t, st, nil)
+		require.False(t, bc.rebuildState)
+	})
+	t.Run("single store", func(t *testing.T) {
+		st := storage.NewMemoryStore()
+		lagging(t, st)
+		bc := initTestChain(t, st, nil)
+		require.False(t, bc.rebuildState)
+	})
+}
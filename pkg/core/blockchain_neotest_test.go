@@ -1,10 +1,13 @@
 package core_test
 
 import (
+	"bytes"
 	"encoding/binary"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"math/big"
+	"os"
 	"path/filepath"
 	"sort"
 	"strings"
@@ -36,11 +39,14 @@ import (
 	"github.com/nspcc-dev/neo-go/pkg/crypto/hash"
 	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
 	"github.com/nspcc-dev/neo-go/pkg/encoding/address"
+	"github.com/nspcc-dev/neo-go/pkg/encoding/fixedn"
 	"github.com/nspcc-dev/neo-go/pkg/io"
 	"github.com/nspcc-dev/neo-go/pkg/neotest"
 	"github.com/nspcc-dev/neo-go/pkg/neotest/chain"
 	"github.com/nspcc-dev/neo-go/pkg/smartcontract"
 	"github.com/nspcc-dev/neo-go/pkg/smartcontract/callflag"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract/manifest"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract/nef"
 	"github.com/nspcc-dev/neo-go/pkg/smartcontract/trigger"
 	"github.com/nspcc-dev/neo-go/pkg/util"
 	"github.com/nspcc-dev/neo-go/pkg/vm/emit"
@@ -1468,6 +1474,19 @@ func TestBlockchain_VerifyTx(t *testing.T) {
 		err := bc.PoolTx(tx2, mp)
 		require.ErrorIs(t, err, core.ErrOOM)
 	})
+	t.Run("MemPoolSenderLimit", func(t *testing.T) {
+		mp := mempool.New(10, 0, false, nil)
+		mp.SetSenderLimits(1, 0, false)
+		tx1 := newTestTx(t, h, testScript)
+		require.NoError(t, accs[0].SignTx(netmode.UnitTestNet, tx1))
+		require.NoError(t, bc.PoolTx(tx1, mp))
+
+		tx2 := newTestTx(t, h, testScript)
+		require.NoError(t, accs[0].SignTx(netmode.UnitTestNet, tx2))
+		err := bc.PoolTx(tx2, mp)
+		require.ErrorIs(t, err, core.ErrOOM)
+		require.ErrorIs(t, err, mempool.ErrSenderTxLimit)
+	})
 	t.Run("Attribute", func(t *testing.T) {
 		t.Run("InvalidHighPriority", func(t *testing.T) {
 			tx := newTestTx(t, h, testScript)
@@ -2568,6 +2587,186 @@ func TestBlockchain_GenesisTransactionExtension(t *testing.T) {
 	require.Equal(t, 0, int(lub))
 }
 
+func TestBlockchain_GenesisContractDumps(t *testing.T) {
+	bc, acc := chain.NewSingle(t)
+	e := neotest.NewExecutor(t, bc, acc, acc)
+	src := `package foo
+	import "github.com/nspcc-dev/neo-go/pkg/interop/storage"
+	func Put(k, v []byte) { storage.Put(storage.GetContext(), k, v) }
+	func Get(k []byte) []byte { return storage.Get(storage.GetReadOnlyContext(), k).([]byte) }`
+	c := neotest.CompileSource(t, acc.ScriptHash(), strings.NewReader(src), &compiler.Options{Name: "Storage contract"})
+	e.DeployContract(t, c, nil)
+	inv := e.CommitteeInvoker(c.Hash)
+	inv.Invoke(t, stackitem.Null{}, "put", []byte{1}, []byte{2})
+	inv.Invoke(t, stackitem.Null{}, "put", []byte{3}, []byte{4})
+	inv.Invoke(t, stackitem.Null{}, "put", []byte{1}, []byte{5}) // Update (UpdateCounter is not affected).
+
+	cs := bc.GetContractState(c.Hash)
+	d := &state.ContractDump{
+		Height:    bc.BlockHeight(),
+		StateRoot: bc.GetStateModule().CurrentLocalStateRoot(),
+		Contract:  *cs,
+	}
+	bc.SeekStorage(cs.ID, nil, func(k, v []byte) bool {
+		d.Storage = append(d.Storage, state.ContractDumpItem{Key: bytes.Clone(k), Value: bytes.Clone(v)})
+		return true
+	})
+	require.Equal(t, 2, len(d.Storage))
+
+	t.Run("good", func(t *testing.T) {
+		dump := *d
+		dump.Contract.UpdateCounter = 3
+		bc2, acc2 := chain.NewSingleWithOptions(t, &chain.Options{Contracts: []*state.ContractDump{&dump}})
+		e2 := neotest.NewExecutor(t, bc2, acc2, acc2)
+
+		// Imported dumps are a part of the genesis block.
+		e2.CheckHalt(t, e2.GetBlockByIndex(t, 0).Transactions[0].Hash())
+		require.NotEqual(t, bc.GetHeaderHash(0), bc2.GetHeaderHash(0))
+		other := dump
+		other.Contract.UpdateCounter = 4
+		bc3, _ := chain.NewSingleWithOptions(t, &chain.Options{Contracts: []*state.ContractDump{&other}})
+		require.NotEqual(t, bc2.GetHeaderHash(0), bc3.GetHeaderHash(0))
+
+		cs2 := bc2.GetContractState(c.Hash)
+		require.NotNil(t, cs2)
+		require.Equal(t, int32(1), cs2.ID)
+		require.Equal(t, uint16(3), cs2.UpdateCounter)
+		require.Equal(t, cs.NEF, cs2.NEF)
+		require.Equal(t, cs.Manifest.ABI, cs2.Manifest.ABI)
+
+		inv2 := e2.CommitteeInvoker(c.Hash)
+		inv2.Invoke(t, stackitem.NewBuffer([]byte{5}), "get", []byte{1})
+		inv2.Invoke(t, stackitem.NewBuffer([]byte{4}), "get", []byte{3})
+		inv2.Invoke(t, stackitem.Null{}, "put", []byte{3}, []byte{6})
+		inv2.Invoke(t, stackitem.NewBuffer([]byte{6}), "get", []byte{3})
+
+		// New contracts don't collide with the imported one.
+		c2 := neotest.CompileSource(t, acc2.ScriptHash(), strings.NewReader(src), &compiler.Options{Name: "Another contract"})
+		e2.DeployContract(t, c2, nil)
+		require.Equal(t, int32(2), bc2.GetContractState(c2.Hash).ID)
+	})
+
+	t.Run("bad", func(t *testing.T) {
+		check := func(t *testing.T, opts *chain.Options) {
+			opts.SkipRun = true
+			_, _, _, err := chain.NewMultiWithOptionsNoCheck(t, opts)
+			require.Error(t, err)
+		}
+		t.Run("duplicate", func(t *testing.T) {
+			check(t, &chain.Options{Contracts: []*state.ContractDump{d, d}})
+		})
+		t.Run("native", func(t *testing.T) {
+			dump := *d
+			dump.Contract = *bc.GetContractState(nativehashes.GasToken)
+			check(t, &chain.Options{Contracts: []*state.ContractDump{&dump}})
+		})
+		t.Run("native hash", func(t *testing.T) {
+			dump := *d
+			dump.Contract.Hash = nativehashes.GasToken
+			dump.Contract.Manifest.Groups = nil
+			check(t, &chain.Options{Contracts: []*state.ContractDump{&dump}})
+		})
+		t.Run("missing file", func(t *testing.T) {
+			check(t, &chain.Options{BlockchainConfigHook: func(c *config.Blockchain) {
+				c.Genesis.ContractDumps = []string{filepath.Join(t.TempDir(), "missing.json")}
+			}})
+		})
+	})
+}
+
+func TestBlockchain_GenesisAllocations(t *testing.T) {
+	src := `package foo
+	import "github.com/nspcc-dev/neo-go/pkg/interop/storage"
+	func _deploy(data any, isUpdate bool) { storage.Put(storage.GetContext(), "data", data) }
+	func Get() any { return storage.Get(storage.GetReadOnlyContext(), "data") }`
+	c := neotest.CompileSource(t, util.Uint160{}, strings.NewReader(src), &compiler.Options{Name: "Genesis contract"})
+	dir := t.TempDir()
+	nefPath := filepath.Join(dir, "contract.nef")
+	manifestPath := filepath.Join(dir, "contract.manifest.json")
+	nefBytes, err := c.NEF.Bytes()
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(nefPath, nefBytes, 0644))
+	manifestBytes, err := json.Marshal(c.Manifest)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(manifestPath, manifestBytes, 0644))
+	to := util.Uint160{1, 2, 3}
+
+	t.Run("good", func(t *testing.T) {
+		bc, acc := chain.NewSingleWithCustomConfig(t, func(cfg *config.Blockchain) {
+			cfg.Genesis.Allocations = []config.GenesisAllocation{
+				{Address: to, NEO: 100, GAS: fixedn.Fixed8FromInt64(5)},
+			}
+			cfg.Genesis.Contracts = []config.GenesisContract{
+				{NEF: nefPath, Manifest: manifestPath, Data: "string:hello"},
+			}
+		})
+		e := neotest.NewExecutor(t, bc, acc, acc)
+		tx := e.GetBlockByIndex(t, 0).Transactions[0]
+		e.CheckHalt(t, tx.Hash())
+
+		neo, _ := bc.GetGoverningTokenBalance(to)
+		require.Equal(t, int64(100), neo.Int64())
+		e.CheckGASBalance(t, to, big.NewInt(5_00000000))
+		e.CheckGASBalance(t, e.Validator.ScriptHash(), big.NewInt(core.DefaultInitialGAS-5_00000000-tx.SystemFee))
+
+		h := state.CreateContractHash(e.Validator.ScriptHash(), c.NEF.Checksum, c.Manifest.Name)
+		cs := bc.GetContractState(h)
+		require.NotNil(t, cs)
+		require.Equal(t, int32(1), cs.ID)
+		e.CommitteeInvoker(h).Invoke(t, stackitem.NewByteArray([]byte("hello")), "get")
+	})
+
+	t.Run("bad", func(t *testing.T) {
+		check := func(t *testing.T, f func(*config.Blockchain)) {
+			_, _, _, err := chain.NewMultiWithOptionsNoCheck(t, &chain.Options{BlockchainConfigHook: f, SkipRun: true})
+			require.Error(t, err)
+		}
+		t.Run("duplicate", func(t *testing.T) {
+			check(t, func(cfg *config.Blockchain) {
+				cfg.Genesis.Contracts = []config.GenesisContract{
+					{NEF: nefPath, Manifest: manifestPath},
+					{NEF: nefPath, Manifest: manifestPath},
+				}
+			})
+		})
+		t.Run("bad NEF", func(t *testing.T) {
+			check(t, func(cfg *config.Blockchain) {
+				cfg.Genesis.Contracts = []config.GenesisContract{{NEF: manifestPath, Manifest: manifestPath}}
+			})
+		})
+		t.Run("bad manifest", func(t *testing.T) {
+			check(t, func(cfg *config.Blockchain) {
+				cfg.Genesis.Contracts = []config.GenesisContract{{NEF: nefPath, Manifest: nefPath}}
+			})
+		})
+		t.Run("failing _deploy", func(t *testing.T) {
+			bad := neotest.CompileSource(t, util.Uint160{}, strings.NewReader(`package foo
+			func _deploy(data any, isUpdate bool) { panic("no way") }`), &compiler.Options{Name: "Failing contract"})
+			badNEF, err := bad.NEF.Bytes()
+			require.NoError(t, err)
+			badManifest, err := json.Marshal(bad.Manifest)
+			require.NoError(t, err)
+			badNEFPath := filepath.Join(dir, "bad.nef")
+			badManifestPath := filepath.Join(dir, "bad.manifest.json")
+			require.NoError(t, os.WriteFile(badNEFPath, badNEF, 0644))
+			require.NoError(t, os.WriteFile(badManifestPath, badManifest, 0644))
+			_, _, _, err = chain.NewMultiWithOptionsNoCheck(t, &chain.Options{BlockchainConfigHook: func(cfg *config.Blockchain) {
+				cfg.Genesis.Contracts = []config.GenesisContract{{NEF: badNEFPath, Manifest: badManifestPath}}
+			}, SkipRun: true})
+			require.ErrorContains(t, err, "genesis transaction failed")
+		})
+		t.Run("no GAS for system fee", func(t *testing.T) {
+			_, _, _, err := chain.NewMultiWithOptionsNoCheck(t, &chain.Options{BlockchainConfigHook: func(cfg *config.Blockchain) {
+				cfg.InitialGASSupply = fixedn.Fixed8FromInt64(10)
+				cfg.Genesis.Allocations = []config.GenesisAllocation{
+					{Address: to, GAS: fixedn.Fixed8FromInt64(10)},
+				}
+			}, SkipRun: true})
+			require.ErrorContains(t, err, "genesis GAS allocations (10) and system fee (1) exceed InitialGASSupply (10)")
+		})
+	})
+}
+
 // TestNativenames ensures that nativenames.All contains all expected native contract names
 // in the right order.
 func TestNativenames(t *testing.T) {
@@ -2620,3 +2819,306 @@ func TestBlockchain_StoreAsTransaction_ExecutableConflict(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, 2, len(aer))
 }
+
+func TestBlockchain_GetTestHistoricTxVM(t *testing.T) {
+	bc, acc := chain.NewSingle(t)
+	e := neotest.NewExecutor(t, bc, acc, acc)
+	gasInvoker := e.ValidatorInvoker(e.NativeHash(t, nativenames.Gas))
+	to := random.Uint160()
+
+	// The second transaction depends on the changes made by the first one
+	// in the same block.
+	transferTx := gasInvoker.PrepareInvoke(t, "transfer", acc.ScriptHash(), to, 1_0000_0000, nil)
+	balanceTx := gasInvoker.PrepareInvoke(t, "balanceOf", to)
+	e.AddNewBlock(t, transferTx, balanceTx)
+	e.CheckHalt(t, transferTx.Hash(), stackitem.Make(true))
+	e.CheckHalt(t, balanceTx.Hash(), stackitem.Make(1_0000_0000))
+
+	for _, tx := range []*transaction.Transaction{transferTx, balanceTx} {
+		aer, err := bc.GetAppExecResults(tx.Hash(), trigger.Application)
+		require.NoError(t, err)
+
+		ic, err := bc.GetTestHistoricTxVM(tx.Hash())
+		require.NoError(t, err)
+		require.Equal(t, tx.Hash(), ic.Tx.Hash())
+		require.NoError(t, ic.Exec())
+		require.Equal(t, aer[0].VMState, ic.VM.State())
+		require.Equal(t, aer[0].GasConsumed, ic.VM.GasConsumed())
+		require.Equal(t, aer[0].Stack, ic.VM.Estack().ToArray())
+	}
+
+	t.Run("unknown transaction", func(t *testing.T) {
+		_, err := bc.GetTestHistoricTxVM(random.Uint256())
+		require.ErrorIs(t, err, storage.ErrKeyNotFound)
+	})
+	t.Run("KeepOnlyLatestState", func(t *testing.T) {
+		bc, acc := chain.NewSingleWithCustomConfig(t, func(c *config.Blockchain) {
+			c.Ledger.KeepOnlyLatestState = true
+		})
+		e := neotest.NewExecutor(t, bc, acc, acc)
+		h := e.InvokeScript(t, []byte{byte(opcode.PUSHT)}, []neotest.Signer{acc})
+		e.CheckHalt(t, h, stackitem.Make(true))
+		_, err := bc.GetTestHistoricTxVM(h)
+		require.Error(t, err)
+	})
+}
+
+func TestBlockchain_GetStateDiff(t *testing.T) {
+	bc, acc := chain.NewSingle(t)
+	e := neotest.NewExecutor(t, bc, acc, acc)
+	gasInvoker := e.ValidatorInvoker(e.NativeHash(t, nativenames.Gas))
+	gasID := e.NativeID(t, nativenames.Gas)
+	to := random.Uint160()
+
+	t.Run("genesis", func(t *testing.T) {
+		diff, err := bc.GetStateDiff(0)
+		require.NoError(t, err)
+		require.Equal(t, util.Uint256{}, diff.PrevRoot)
+		require.NotEqual(t, 0, len(diff.Contracts))
+		for i, c := range diff.Contracts {
+			if i > 0 {
+				require.Less(t, diff.Contracts[i-1].ID, c.ID)
+			}
+			for _, ch := range c.Changes {
+				require.Equal(t, state.StorageAdded, ch.Type)
+				require.Nil(t, ch.OldValue)
+				require.NotNil(t, ch.NewValue)
+			}
+		}
+	})
+
+	gasInvoker.Invoke(t, true, "transfer", acc.ScriptHash(), to, 1_0000_0000, nil)
+	diff, err := bc.GetStateDiff(bc.BlockHeight())
+	require.NoError(t, err)
+	require.Equal(t, bc.BlockHeight(), diff.Index)
+	prev, err := bc.GetStateModule().GetStateRoot(bc.BlockHeight() - 1)
+	require.NoError(t, err)
+	require.Equal(t, prev.Root, diff.PrevRoot)
+	require.Equal(t, bc.GetStateModule().CurrentLocalStateRoot(), diff.Root)
+
+	var (
+		added    bool
+		modified bool
+	)
+	for _, c := range diff.Contracts {
+		for _, ch := range c.Changes {
+			if ch.Type == state.StorageDeleted {
+				require.Nil(t, bc.GetStorageItem(c.ID, ch.Key))
+			} else {
+				require.Equal(t, state.StorageItem(ch.NewValue), bc.GetStorageItem(c.ID, ch.Key))
+			}
+			if c.ID != gasID {
+				continue
+			}
+			switch {
+			case bytes.Equal(ch.Key, append([]byte{20}, to.BytesBE()...)):
+				require.Equal(t, state.StorageAdded, ch.Type)
+				added = true
+			case bytes.Equal(ch.Key, append([]byte{20}, acc.ScriptHash().BytesBE()...)):
+				require.Equal(t, state.StorageModified, ch.Type)
+				require.NotEqual(t, ch.OldValue, ch.NewValue)
+				modified = true
+			}
+		}
+	}
+	require.True(t, added)
+	require.True(t, modified)
+
+	t.Run("unknown height", func(t *testing.T) {
+		_, err := bc.GetStateDiff(bc.BlockHeight() + 1)
+		require.Error(t, err)
+	})
+	t.Run("KeepOnlyLatestState", func(t *testing.T) {
+		bc, _ := chain.NewSingleWithCustomConfig(t, func(c *config.Blockchain) {
+			c.Ledger.KeepOnlyLatestState = true
+		})
+		_, err := bc.GetStateDiff(0)
+		require.Error(t, err)
+	})
+}
+
+func TestBlockchain_ApplyStateOverrides(t *testing.T) {
+	bc, acc := chain.NewSingle(t)
+	e := neotest.NewExecutor(t, bc, acc, acc)
+	neoHash := e.NativeHash(t, nativenames.Neo)
+	gasHash := e.NativeHash(t, nativenames.Gas)
+	to := random.Uint160()
+
+	run := func(t *testing.T, o *state.Overrides, script []byte) ([]stackitem.Item, error) {
+		ic, err := bc.GetTestVM(trigger.Application, &transaction.Transaction{}, nil)
+		require.NoError(t, err)
+		defer ic.Finalize()
+		if err = bc.ApplyStateOverrides(ic, o); err != nil {
+			return nil, err
+		}
+		ic.VM.LoadScriptWithFlags(script, callflag.All)
+		require.NoError(t, ic.VM.Run())
+		return ic.VM.Estack().ToArray(), nil
+	}
+
+	t.Run("balances", func(t *testing.T) {
+		w := io.NewBufBinWriter()
+		emit.AppCall(w.BinWriter, neoHash, "balanceOf", callflag.All, to)
+		emit.AppCall(w.BinWriter, gasHash, "balanceOf", callflag.All, to)
+		require.NoError(t, w.Err)
+		stack, err := run(t, &state.Overrides{Balances: []state.BalanceOverride{
+			{Asset: neoHash, Account: to, Amount: big.NewInt(10)},
+			{Asset: gasHash, Account: to, Amount: big.NewInt(20)},
+		}}, w.Bytes())
+		require.NoError(t, err)
+		require.Equal(t, []stackitem.Item{stackitem.Make(10), stackitem.Make(20)}, stack)
+		// Real state is not affected.
+		require.Equal(t, int64(0), bc.GetUtilityTokenBalance(to).Int64())
+	})
+	t.Run("block", func(t *testing.T) {
+		var (
+			index uint32 = 100500
+			ts    uint64 = 42
+		)
+		w := io.NewBufBinWriter()
+		emit.Syscall(w.BinWriter, interopnames.SystemRuntimeGetTime)
+		stack, err := run(t, &state.Overrides{Block: &state.BlockOverride{Index: &index, Timestamp: &ts}}, w.Bytes())
+		require.NoError(t, err)
+		require.Equal(t, []stackitem.Item{stackitem.Make(42)}, stack)
+	})
+	t.Run("native contract", func(t *testing.T) {
+		ne, err := nef.NewFile([]byte{byte(opcode.RET)})
+		require.NoError(t, err)
+		nefBytes, err := ne.Bytes()
+		require.NoError(t, err)
+		m := manifest.NewManifest("Fake")
+		m.ABI.Methods = []manifest.Method{{Name: "main", ReturnType: smartcontract.VoidType}}
+		_, err = run(t, &state.Overrides{Contracts: []state.ContractOverride{
+			{Hash: neoHash, NEF: nefBytes, Manifest: *m},
+		}}, []byte{byte(opcode.RET)})
+		require.Error(t, err)
+	})
+	t.Run("unknown contract storage", func(t *testing.T) {
+		_, err := run(t, &state.Overrides{Storage: []state.StorageOverride{
+			{Hash: random.Uint160(), Key: []byte{1}, Value: []byte{2}},
+		}}, []byte{byte(opcode.RET)})
+		require.ErrorIs(t, err, storage.ErrKeyNotFound)
+	})
+}
+
+func TestBlockchain_RebuildState(t *testing.T) {
+	var (
+		chainPath = t.TempDir()
+		statePath = t.TempDir()
+		newSplit  = func(t *testing.T) storage.Store {
+			chainStore, _ := newLevelDBForTestingWithPath(t, chainPath)
+			stateStore, _ := newLevelDBForTestingWithPath(t, statePath)
+			return storage.NewSplitStore(chainStore, stateStore)
+		}
+		customConfig = func(c *config.Blockchain) {
+			c.P2PSigExtensions = true // Need for basic chain initializer.
+		}
+	)
+	bc, validators, committee, err := chain.NewMultiWithCustomConfigAndStoreNoCheck(t, customConfig, newSplit(t))
+	require.NoError(t, err)
+	go bc.Run()
+	e := neotest.NewExecutor(t, bc, validators, committee)
+	basicchain.Init(t, "../../", e)
+
+	h := bc.BlockHeight()
+	sr, err := bc.GetStateModule().GetStateRoot(h)
+	require.NoError(t, err)
+	top := bc.GetHeaderHash(h)
+	aers, err := bc.GetAppExecResults(top, trigger.All)
+	require.NoError(t, err)
+	bc.Close()
+
+	// Chain data is kept in its own DB, so only the state is dropped.
+	chainStore, _ := newLevelDBForTestingWithPath(t, chainPath)
+	for _, p := range []storage.KeyPrefix{storage.DataMPT, storage.STStorage, storage.SYSCurrentBlock} {
+		chainStore.Seek(storage.SeekRange{Prefix: []byte{byte(p)}}, func(k, _ []byte) bool {
+			t.Fatalf("unexpected key %x in the chain DB", k)
+			return false
+		})
+	}
+	require.NoError(t, chainStore.Close())
+	statePath = t.TempDir()
+
+	t.Run("untraceable blocks", func(t *testing.T) {
+		st := newSplit(t)
+		defer func() { require.NoError(t, st.Close()) }()
+		_, _, _, err := chain.NewMultiWithCustomConfigAndStoreNoCheck(t, func(c *config.Blockchain) {
+			customConfig(c)
+			c.Ledger.RemoveUntraceableBlocks = true
+		}, st)
+		require.ErrorContains(t, err, "can't be rebuilt")
+	})
+
+	bc, _, _, err = chain.NewMultiWithCustomConfigAndStoreNoCheck(t, customConfig, newSplit(t))
+	require.NoError(t, err)
+	require.Equal(t, uint32(0), bc.BlockHeight())
+	require.Equal(t, h, bc.HeaderHeight())
+	go bc.Run()
+	t.Cleanup(bc.Close)
+	require.Eventually(t, func() bool { return bc.BlockHeight() == h }, 10*time.Second, 10*time.Millisecond)
+
+	actual, err := bc.GetStateModule().GetStateRoot(h)
+	require.NoError(t, err)
+	require.Equal(t, sr.Root, actual.Root)
+	actualAERs, err := bc.GetAppExecResults(top, trigger.All)
+	require.NoError(t, err)
+	require.Equal(t, aers, actualAERs)
+}
+
+// failingStore is a Store that can't save any changes.
+type failingStore struct {
+	storage.Store
+}
+
+func (s failingStore) PutChangeSet(_ map[string][]byte, _ map[string][]byte) error {
+	return errors.New("can't save changes")
+}
+
+func TestBlockchain_LaggingState(t *testing.T) {
+	var (
+		chainPath = t.TempDir()
+		statePath = t.TempDir()
+		newSplit  = func(t *testing.T, failState bool) storage.Store {
+			chainStore, _ := newLevelDBForTestingWithPath(t, chainPath)
+			stateStore, _ := newLevelDBForTestingWithPath(t, statePath)
+			if failState {
+				stateStore = failingStore{stateStore}
+			}
+			return storage.NewSplitStore(chainStore, stateStore)
+		}
+		acc = random.Uint160()
+	)
+	bc, validators, committee, err := chain.NewMultiWithCustomConfigAndStoreNoCheck(t, nil, newSplit(t, false))
+	require.NoError(t, err)
+	go bc.Run()
+	e := neotest.NewExecutor(t, bc, validators, committee)
+	e.AddNewBlock(t)
+	h := bc.BlockHeight()
+	bc.Close()
+
+	// Blocks are saved to the chain DB while the state isn't.
+	bc, validators, committee, err = chain.NewMultiWithCustomConfigAndStoreNoCheck(t, nil, newSplit(t, true))
+	require.NoError(t, err)
+	go bc.Run()
+	e = neotest.NewExecutor(t, bc, validators, committee)
+	gas := e.ValidatorInvoker(e.NativeHash(t, nativenames.Gas))
+	gas.Invoke(t, true, "transfer", e.Validator.ScriptHash(), acc, 1_0000_0000, nil)
+	e.AddNewBlock(t)
+	top := bc.BlockHeight()
+	sr, err := bc.GetStateModule().GetStateRoot(top)
+	require.NoError(t, err)
+	bc.Close()
+
+	bc, _, _, err = chain.NewMultiWithCustomConfigAndStoreNoCheck(t, nil, newSplit(t, false))
+	require.NoError(t, err)
+	require.Equal(t, h, bc.BlockHeight())
+	require.Equal(t, top, bc.HeaderHeight())
+	go bc.Run()
+	t.Cleanup(bc.Close)
+	require.Eventually(t, func() bool { return bc.BlockHeight() == top }, 10*time.Second, 10*time.Millisecond)
+
+	actual, err := bc.GetStateModule().GetStateRoot(top)
+	require.NoError(t, err)
+	require.Equal(t, sr.Root, actual.Root)
+	require.Equal(t, int64(1_0000_0000), bc.GetUtilityTokenBalance(acc).Int64())
+}
@@ -0,0 +1,75 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/nspcc-dev/neo-go/pkg/config"
+	"github.com/nspcc-dev/neo-go/pkg/core/block"
+	"github.com/nspcc-dev/neo-go/pkg/core/dao"
+	"github.com/nspcc-dev/neo-go/pkg/core/state"
+	"github.com/nspcc-dev/neo-go/pkg/core/stateroot"
+	"github.com/nspcc-dev/neo-go/pkg/core/storage"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"go.uber.org/zap"
+)
+
+// InitFork prepares an empty store for the Blockchain that forks some other
+// network at the given block. It saves the storage version, the block itself,
+// headers of the previous blocks needed to restore the header hash list
+// (getHeader is used to retrieve them by hash) and an empty local state root
+// for the block height. Blockchain created with NewBlockchain over this store
+// continues from the given block. Contract storage is not copied, it's the
+// store that should provide the network state at the fork point (see the fork
+// package). Only headers of the last (up to 2000) blocks before the fork
+// point are known to such Blockchain, older headers and transactions of all
+// the previous blocks are not available.
+// Local state roots only reflect changes made after the fork point.
+func InitFork(s storage.Store, cfg config.Blockchain, b *block.Block, getHeader func(util.Uint256) (*block.Header, error)) error {
+	var (
+		d = dao.NewSimple(s, cfg.StateRootInHeader)
+		// HeaderHashes restores the latest hashes walking back from the
+		// current header up to the last hash of the previous page.
+		stored = (b.Index + 1) / headerBatchCount * headerBatchCount
+		prev   = b.PrevHash
+		last   = b.Hash()
+	)
+	if _, err := d.GetVersion(); err == nil {
+		return errors.New("store is not empty")
+	}
+	d.PutVersion(newStorageVersion(cfg))
+	for i := b.Index; i >= stored && i > 0; i-- {
+		h, err := getHeader(prev)
+		if err != nil {
+			return fmt.Errorf("failed to get header %d: %w", i-1, err)
+		}
+		if h.Index != i-1 || !h.Hash().Equals(prev) {
+			return fmt.Errorf("invalid header %d (%s)", h.Index, h.Hash().StringLE())
+		}
+		if err = d.StoreHeader(h); err != nil {
+			return err
+		}
+		last, prev = prev, h.PrevHash
+	}
+	if stored >= headerBatchCount {
+		// Only the last hash of the page is needed to restore the chain.
+		var hashes = make([]util.Uint256, headerBatchCount)
+		hashes[headerBatchCount-1] = last
+		if err := d.StoreHeaderHashes(hashes, stored-headerBatchCount); err != nil {
+			return err
+		}
+	}
+	if err := d.StoreAsBlock(b, nil, nil); err != nil {
+		return err
+	}
+	for _, tx := range b.Transactions {
+		if err := d.StoreAsTransaction(tx, b.Index, nil); err != nil {
+			return err
+		}
+	}
+	d.StoreAsCurrentBlock(b)
+	d.PutCurrentHeader(b.Hash(), b.Index)
+	stateroot.NewModule(cfg, nil, zap.NewNop(), d.Store).JumpToState(&state.MPTRoot{Index: b.Index})
+	_, err := d.Persist()
+	return err
+}
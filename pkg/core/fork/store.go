@@ -0,0 +1,449 @@
+/*
+Package fork implements a storage that allows to fork a remote network at
+some height and continue it locally.
+
+The fork Store serves contract storage items of the remote network state at
+the fork point fetched lazily via RPC (getstate and findstates calls against
+the state root of the fork point) and caches them in the local store along
+with all the changes made locally after the fork point. Everything else
+(blocks, transactions, MPT nodes, etc.) is stored locally.
+*/
+package fork
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/nspcc-dev/neo-go/pkg/config"
+	"github.com/nspcc-dev/neo-go/pkg/core"
+	"github.com/nspcc-dev/neo-go/pkg/core/block"
+	"github.com/nspcc-dev/neo-go/pkg/core/native"
+	"github.com/nspcc-dev/neo-go/pkg/core/native/nativenames"
+	"github.com/nspcc-dev/neo-go/pkg/core/state"
+	"github.com/nspcc-dev/neo-go/pkg/core/storage"
+	"github.com/nspcc-dev/neo-go/pkg/neorpc"
+	"github.com/nspcc-dev/neo-go/pkg/neorpc/result"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"go.uber.org/zap"
+)
+
+// RPC is a set of RPC methods the Store needs to fetch the remote network
+// state, it's implemented by rpcclient.Client (which must be initialized).
+type RPC interface {
+	GetBlockByIndex(index uint32) (*block.Block, error)
+	GetBlockHeader(hash util.Uint256) (*block.Header, error)
+	GetStateRootByHeight(height uint32) (*state.MPTRoot, error)
+	GetState(stateroot util.Uint256, historicalContractHash util.Uint160, historicalKey []byte) ([]byte, error)
+	FindStates(stateroot util.Uint256, historicalContractHash util.Uint160, historicalPrefix []byte,
+		start []byte, maxCount *int) (result.FindStates, error)
+}
+
+// Prefixes of the SYSFork-prefixed keys.
+const (
+	// forkPoint stores the fork height and the remote state root.
+	forkPoint byte = iota
+	// missingItem marks storage items missing from the remote state or
+	// deleted locally.
+	missingItem
+	// fetchedPrefix marks storage prefixes that were fetched completely.
+	fetchedPrefix
+)
+
+// storageKeyLen is the length of the storage prefix and contract ID in the
+// storage item key.
+const storageKeyLen = 5
+
+const (
+	// remoteAttempts is the number of attempts made for every remote node
+	// request before giving up.
+	remoteAttempts = 5
+	// remoteRetryDelay is the delay before the first retry, it's doubled for
+	// every subsequent one.
+	remoteRetryDelay = 50 * time.Millisecond
+)
+
+// Store is a storage.Store that provides the state of the remote network at
+// the fork point. Contract storage items are fetched from the remote node on
+// demand, everything fetched and all the local changes are stored in the
+// local store. Storage items can be fetched only for the contracts known at
+// the fork point, seeking over multiple contracts only returns the items
+// cached locally. Remote node requests are retried several times in case of
+// errors, if they still fail, nothing is cached, Get returns an error and
+// Seek only iterates over the items cached locally. Callers of Get and Seek
+// may treat this as missing items, so any such failure also makes all
+// subsequent PutChangeSet calls fail to not save the state that can be
+// incorrect, the node must be restarted then.
+type Store struct {
+	local  storage.Store
+	remote RPC
+	log    *zap.Logger
+	height uint32
+	root   util.Uint256
+
+	// lock protects local store cache from concurrent updates.
+	lock    sync.Mutex
+	hashes  map[int32]util.Uint160
+	fetched map[string]struct{}
+	// err is the first remote node error that wasn't resolved by retries.
+	err error
+}
+
+// NewStore creates a Store over the local store that forks the remote network
+// at the given height. An empty local store is initialized with the fork
+// point data (see core.InitFork) first, a store that was used with the
+// Store before must be opened with the same height. The configuration must
+// match the one of the remote network, though it's usually extended with
+// SkipBlockVerification to be able to produce blocks without the remote
+// network validators.
+func NewStore(local storage.Store, remote RPC, cfg config.Blockchain, height uint32, log *zap.Logger) (*Store, error) {
+	var s = &Store{
+		local:   local,
+		remote:  remote,
+		log:     log,
+		height:  height,
+		hashes:  make(map[int32]util.Uint160, len(nativenames.All)),
+		fetched: make(map[string]struct{}),
+	}
+	for i, name := range nativenames.All {
+		s.hashes[int32(-i-1)] = state.CreateNativeContractHash(name)
+	}
+	data, err := local.Get([]byte{byte(storage.SYSFork), forkPoint})
+	switch {
+	case err == nil:
+		if len(data) != 4+util.Uint256Size {
+			return nil, errors.New("invalid fork point")
+		}
+		if h := binary.LittleEndian.Uint32(data); h != height {
+			return nil, fmt.Errorf("store is forked at height %d, not %d", h, height)
+		}
+		s.root, _ = util.Uint256DecodeBytesBE(data[4:])
+	case errors.Is(err, storage.ErrKeyNotFound):
+		if err = s.init(cfg); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, err
+	}
+	local.Seek(storage.SeekRange{Prefix: []byte{byte(storage.SYSFork), fetchedPrefix}}, func(k, _ []byte) bool {
+		s.fetched[string(k[2:])] = struct{}{}
+		return true
+	})
+	return s, nil
+}
+
+// init saves the fork point data into the local store.
+func (s *Store) init(cfg config.Blockchain) error {
+	sr, err := s.remote.GetStateRootByHeight(s.height)
+	if err != nil {
+		return fmt.Errorf("failed to get state root %d: %w", s.height, err)
+	}
+	b, err := s.remote.GetBlockByIndex(s.height)
+	if err != nil {
+		return fmt.Errorf("failed to get block %d: %w", s.height, err)
+	}
+	s.root = sr.Root
+	s.log.Info("initializing fork",
+		zap.Uint32("height", s.height),
+		zap.Stringer("block", b.Hash()),
+		zap.Stringer("stateroot", s.root))
+	if err = core.InitFork(s.local, cfg, b, s.remote.GetBlockHeader); err != nil {
+		return fmt.Errorf("failed to initialize fork: %w", err)
+	}
+	var data = make([]byte, 4, 4+util.Uint256Size)
+	binary.LittleEndian.PutUint32(data, s.height)
+	data = append(data, s.root.BytesBE()...)
+	return s.local.PutChangeSet(map[string][]byte{string([]byte{byte(storage.SYSFork), forkPoint}): data}, nil)
+}
+
+// Height returns the height of the fork point.
+func (s *Store) Height() uint32 {
+	return s.height
+}
+
+// StateRoot returns the remote network state root at the fork point.
+func (s *Store) StateRoot() util.Uint256 {
+	return s.root
+}
+
+// Get implements the storage.Store interface.
+func (s *Store) Get(key []byte) ([]byte, error) {
+	v, err := s.local.Get(key)
+	if !errors.Is(err, storage.ErrKeyNotFound) || !isStorageKey(key) {
+		return v, err
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.get(key)
+}
+
+// get returns the storage item fetching it from the remote node if it's not
+// cached yet. It must be called with the lock held.
+func (s *Store) get(key []byte) ([]byte, error) {
+	v, err := s.local.Get(key)
+	if !errors.Is(err, storage.ErrKeyNotFound) || s.isCached(key) {
+		return v, err
+	}
+	h, ok, err := s.getContractHash(int32(binary.LittleEndian.Uint32(key[1:])))
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		err = s.retry(func() error {
+			v, err = s.getRemote(h, key[storageKeyLen:])
+			return err
+		})
+		if err != nil {
+			return nil, s.fail(fmt.Errorf("failed to fetch %s storage item %x: %w", h.StringLE(), key[storageKeyLen:], err))
+		}
+	}
+	var puts, stor map[string][]byte
+	if v != nil {
+		stor = map[string][]byte{string(key): v}
+	} else {
+		puts = map[string][]byte{string(missingKey(key)): {}}
+	}
+	if err = s.local.PutChangeSet(puts, stor); err != nil {
+		return nil, err
+	}
+	if v == nil {
+		return nil, storage.ErrKeyNotFound
+	}
+	return v, nil
+}
+
+// getRemote fetches the storage item from the remote node, nil is returned if
+// it doesn't exist there. NeoGo nodes return ErrInvalidParams for missing
+// items (as well as for invalid requests), so such answers are double-checked
+// with findstates.
+func (s *Store) getRemote(h util.Uint160, key []byte) ([]byte, error) {
+	v, err := s.remote.GetState(s.root, h, key)
+	switch {
+	case err == nil:
+		return v, nil
+	case isNotFound(err):
+		return nil, nil
+	case !errors.Is(err, neorpc.ErrInvalidParams):
+		return nil, err
+	}
+	var one = 1
+	res, err := s.remote.FindStates(s.root, h, key, nil, &one)
+	switch {
+	case err == nil:
+		if len(res.Results) != 0 && bytes.Equal(res.Results[0].Key, key) {
+			return res.Results[0].Value, nil
+		}
+		return nil, nil
+	case isNotFound(err):
+		return nil, nil
+	default:
+		return nil, err
+	}
+}
+
+// getContractHash returns the hash of the contract with the given ID, false
+// is returned if there is no such contract. It must be called with the lock
+// held.
+func (s *Store) getContractHash(id int32) (util.Uint160, bool, error) {
+	if h, ok := s.hashes[id]; ok {
+		return h, true, nil
+	}
+	if id < 0 {
+		return util.Uint160{}, false, nil
+	}
+	v, err := s.get(makeStorageKey(native.ManagementContractID, native.MakeContractIDKey(id)))
+	if errors.Is(err, storage.ErrKeyNotFound) {
+		return util.Uint160{}, false, nil
+	}
+	if err != nil {
+		return util.Uint160{}, false, err
+	}
+	h, err := util.Uint160DecodeBytesBE(v)
+	if err != nil {
+		return util.Uint160{}, false, nil
+	}
+	s.hashes[id] = h
+	return h, true, nil
+}
+
+// retry calls f until it succeeds (or reports missing data) or the number of
+// attempts is exhausted doubling the delay between attempts, the last error
+// is returned.
+func (s *Store) retry(f func() error) error {
+	var (
+		delay = remoteRetryDelay
+		err   error
+	)
+	for i := 0; i < remoteAttempts; i++ {
+		if i != 0 {
+			s.log.Warn("remote node request failed, retrying", zap.Duration("delay", delay), zap.Error(err))
+			time.Sleep(delay)
+			delay *= 2
+		}
+		if err = f(); err == nil || isNotFound(err) {
+			return err
+		}
+	}
+	return err
+}
+
+// fail remembers the remote node error (if it's the first one) and returns
+// it. It must be called with the lock held.
+func (s *Store) fail(err error) error {
+	if s.err == nil {
+		s.log.Error("failed to fetch remote state, changes won't be saved anymore", zap.Error(err))
+		s.err = err
+	}
+	return err
+}
+
+// isCached checks whether the storage item is missing or fetched as a part
+// of some prefix, so that there is no need to fetch it.
+func (s *Store) isCached(key []byte) bool {
+	for i := storageKeyLen; i <= len(key); i++ {
+		if _, ok := s.fetched[string(key[:i])]; ok {
+			return true
+		}
+	}
+	_, err := s.local.Get(missingKey(key))
+	return err == nil
+}
+
+// fetch fetches all the remote storage items with the given prefix that are
+// not cached yet. It must be called with the lock held.
+func (s *Store) fetch(prefix []byte) error {
+	if s.isFetched(prefix) {
+		return nil
+	}
+	var (
+		stor  = make(map[string][]byte)
+		start []byte
+	)
+	h, ok, err := s.getContractHash(int32(binary.LittleEndian.Uint32(prefix[1:])))
+	if err != nil {
+		return err
+	}
+	for ok {
+		var res result.FindStates
+		err := s.retry(func() error {
+			var err error
+			res, err = s.remote.FindStates(s.root, h, prefix[storageKeyLen:], start, nil)
+			return err
+		})
+		if err != nil {
+			if isNotFound(err) {
+				break
+			}
+			return s.fail(fmt.Errorf("failed to fetch %s storage items: %w", h.StringLE(), err))
+		}
+		for _, kv := range res.Results {
+			var key = makeStorageKey(0, kv.Key)
+			copy(key, prefix[:storageKeyLen])
+			if _, err := s.local.Get(key); err == nil || s.isCached(key) {
+				continue
+			}
+			stor[string(key)] = kv.Value
+		}
+		if !res.Truncated || len(res.Results) == 0 {
+			break
+		}
+		start = res.Results[len(res.Results)-1].Key
+	}
+	var fetchedKey = append([]byte{byte(storage.SYSFork), fetchedPrefix}, prefix...)
+	if err := s.local.PutChangeSet(map[string][]byte{string(fetchedKey): {}}, stor); err != nil {
+		return err
+	}
+	s.fetched[string(prefix)] = struct{}{}
+	return nil
+}
+
+// isFetched checks whether the prefix or one of its parts was fetched
+// completely.
+func (s *Store) isFetched(prefix []byte) bool {
+	for i := storageKeyLen; i <= len(prefix); i++ {
+		if _, ok := s.fetched[string(prefix[:i])]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// PutChangeSet implements the storage.Store interface. Storage items deleted
+// locally are marked as missing, so that they're not fetched again. It fails
+// if some remote state wasn't fetched before.
+func (s *Store) PutChangeSet(puts map[string][]byte, stor map[string][]byte) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.err != nil {
+		return fmt.Errorf("remote state is not available: %w", s.err)
+	}
+	if len(stor) != 0 {
+		var p = make(map[string][]byte, len(puts)+len(stor))
+		for k, v := range puts {
+			p[k] = v
+		}
+		for k, v := range stor {
+			if !isStorageKey([]byte(k)) {
+				continue
+			}
+			var mk = string(missingKey([]byte(k)))
+			if v == nil {
+				p[mk] = []byte{}
+			} else {
+				p[mk] = nil
+			}
+		}
+		puts = p
+	}
+	return s.local.PutChangeSet(puts, stor)
+}
+
+// Seek implements the storage.Store interface. Storage items of a single
+// contract are fetched from the remote node before seeking.
+func (s *Store) Seek(rng storage.SeekRange, f func(k, v []byte) bool) {
+	if isStorageKey(rng.Prefix) {
+		s.lock.Lock()
+		// Errors are remembered by fetch, cached items are still available.
+		_ = s.fetch(rng.Prefix)
+		s.lock.Unlock()
+	}
+	s.local.Seek(rng, f)
+}
+
+// SeekGC implements the storage.Store interface.
+func (s *Store) SeekGC(rng storage.SeekRange, keep func(k, v []byte) bool) error {
+	return s.local.SeekGC(rng, keep)
+}
+
+// Close implements the storage.Store interface.
+func (s *Store) Close() error {
+	return s.local.Close()
+}
+
+// isStorageKey checks whether the key is a contract storage item key or
+// prefix including the contract ID.
+func isStorageKey(key []byte) bool {
+	return len(key) >= storageKeyLen && key[0] == byte(storage.STStorage)
+}
+
+// isNotFound checks whether the remote node error means that the contract or
+// the item doesn't exist.
+func isNotFound(err error) bool {
+	return errors.Is(err, neorpc.ErrUnknownContract) ||
+		errors.Is(err, neorpc.ErrUnknownStorageItem)
+}
+
+func makeStorageKey(id int32, key []byte) []byte {
+	var k = make([]byte, storageKeyLen+len(key))
+	k[0] = byte(storage.STStorage)
+	binary.LittleEndian.PutUint32(k[1:], uint32(id))
+	copy(k[storageKeyLen:], key)
+	return k
+}
+
+func missingKey(key []byte) []byte {
+	return append([]byte{byte(storage.SYSFork), missingItem}, key...)
+}
@@ -0,0 +1,316 @@
+package fork_test
+
+import (
+	"encoding/binary"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/nspcc-dev/neo-go/pkg/compiler"
+	"github.com/nspcc-dev/neo-go/pkg/core"
+	"github.com/nspcc-dev/neo-go/pkg/core/block"
+	"github.com/nspcc-dev/neo-go/pkg/core/dao"
+	"github.com/nspcc-dev/neo-go/pkg/core/fork"
+	"github.com/nspcc-dev/neo-go/pkg/core/mpt"
+	"github.com/nspcc-dev/neo-go/pkg/core/native"
+	"github.com/nspcc-dev/neo-go/pkg/core/native/nativenames"
+	"github.com/nspcc-dev/neo-go/pkg/core/state"
+	"github.com/nspcc-dev/neo-go/pkg/core/storage"
+	"github.com/nspcc-dev/neo-go/pkg/core/storage/dbconfig"
+	"github.com/nspcc-dev/neo-go/pkg/neorpc"
+	"github.com/nspcc-dev/neo-go/pkg/neorpc/result"
+	"github.com/nspcc-dev/neo-go/pkg/neotest"
+	"github.com/nspcc-dev/neo-go/pkg/neotest/chain"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/nspcc-dev/neo-go/pkg/vm/stackitem"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+)
+
+// remoteChain is an RPC node stand-in serving the state of the local chain.
+type remoteChain struct {
+	bc *core.Blockchain
+	// getCalls and findCalls count GetState and FindStates calls.
+	getCalls  int
+	findCalls int
+	// err is returned from GetState and FindStates if set, failures limits
+	// the number of such calls if positive.
+	err      error
+	failures int
+	// invalidParams makes GetState return ErrInvalidParams for missing
+	// items like NeoGo nodes do.
+	invalidParams bool
+}
+
+func (r *remoteChain) GetBlockByIndex(index uint32) (*block.Block, error) {
+	return r.bc.GetBlock(r.bc.GetHeaderHash(index))
+}
+
+func (r *remoteChain) GetBlockHeader(hash util.Uint256) (*block.Header, error) {
+	return r.bc.GetHeader(hash)
+}
+
+func (r *remoteChain) GetStateRootByHeight(height uint32) (*state.MPTRoot, error) {
+	return r.bc.GetStateModule().GetStateRoot(height)
+}
+
+func (r *remoteChain) GetState(root util.Uint256, h util.Uint160, key []byte) ([]byte, error) {
+	r.getCalls++
+	if err := r.fail(); err != nil {
+		return nil, err
+	}
+	id, err := r.getContractID(root, h)
+	if err != nil {
+		return nil, err
+	}
+	v, err := r.bc.GetStateModule().GetState(root, makeMPTKey(id, key))
+	if errors.Is(err, mpt.ErrNotFound) {
+		if r.invalidParams {
+			return nil, neorpc.WrapErrorWithData(neorpc.ErrInvalidParams, "invalid key")
+		}
+		return nil, neorpc.ErrUnknownStorageItem
+	}
+	return v, err
+}
+
+func (r *remoteChain) FindStates(root util.Uint256, h util.Uint160, prefix []byte, start []byte, maxCount *int) (result.FindStates, error) {
+	var res result.FindStates
+
+	r.findCalls++
+	if err := r.fail(); err != nil {
+		return res, err
+	}
+	id, err := r.getContractID(root, h)
+	if err != nil {
+		return res, err
+	}
+	var count = 2 // Small pages to check pagination.
+	if maxCount != nil {
+		count = *maxCount
+	}
+	if len(start) != 0 {
+		start = start[len(prefix):]
+	}
+	kvs, err := r.bc.GetStateModule().FindStates(root, makeMPTKey(id, prefix), start, count+1)
+	if err != nil && !errors.Is(err, mpt.ErrNotFound) {
+		return res, err
+	}
+	if len(kvs) == count+1 {
+		res.Truncated = true
+		kvs = kvs[:count]
+	}
+	for _, kv := range kvs {
+		res.Results = append(res.Results, result.KeyValue{Key: kv.Key[4:], Value: kv.Value})
+	}
+	return res, nil
+}
+
+func (r *remoteChain) fail() error {
+	err := r.err
+	if r.failures > 0 {
+		r.failures--
+		if r.failures == 0 {
+			r.err = nil
+		}
+	}
+	return err
+}
+
+func (r *remoteChain) getContractID(root util.Uint256, h util.Uint160) (int32, error) {
+	v, err := r.bc.GetStateModule().GetState(root, makeMPTKey(native.ManagementContractID, native.MakeContractKey(h)))
+	if err != nil {
+		return 0, neorpc.ErrUnknownContract
+	}
+	var cs = new(state.Contract)
+	if err = stackitem.DeserializeConvertible(v, cs); err != nil {
+		return 0, err
+	}
+	return cs.ID, nil
+}
+
+func makeMPTKey(id int32, key []byte) []byte {
+	var k = make([]byte, 4+len(key))
+	binary.LittleEndian.PutUint32(k, uint32(id))
+	copy(k[4:], key)
+	return k
+}
+
+const storageContractSrc = `package storagecontract
+import (
+	"github.com/nspcc-dev/neo-go/pkg/interop/iterator"
+	"github.com/nspcc-dev/neo-go/pkg/interop/storage"
+)
+func Put(k, v []byte) {
+	storage.Put(storage.GetContext(), k, v)
+}
+func Get(k []byte) any {
+	return storage.Get(storage.GetReadOnlyContext(), k)
+}
+func Delete(k []byte) {
+	storage.Delete(storage.GetContext(), k)
+}
+func Count() int {
+	var n int
+	it := storage.Find(storage.GetReadOnlyContext(), []byte("k"), storage.KeysOnly)
+	for iterator.Next(it) {
+		n++
+	}
+	return n
+}`
+
+func TestFork(t *testing.T) {
+	bc, acc := chain.NewSingle(t)
+	e := neotest.NewExecutor(t, bc, acc, acc)
+	c := neotest.CompileSource(t, acc.ScriptHash(), strings.NewReader(storageContractSrc), &compiler.Options{Name: "Storage"})
+	e.DeployContract(t, c, nil)
+	ctr := e.CommitteeInvoker(c.Hash)
+	for _, k := range []string{"k1", "k2", "k3"} {
+		ctr.Invoke(t, stackitem.Null{}, "put", k, "v"+k[1:])
+	}
+	var (
+		receiver = util.Uint160{1, 2, 3}
+		gas      = e.CommitteeInvoker(e.NativeHash(t, nativenames.Gas))
+	)
+	gas.Invoke(t, true, "transfer", acc.ScriptHash(), receiver, 123, nil)
+
+	// The remote chain goes on after the fork point.
+	height := bc.BlockHeight()
+	ctr.Invoke(t, stackitem.Null{}, "put", "k1", "changed")
+
+	remote := &remoteChain{bc: bc}
+	dbPath := t.TempDir()
+	db, err := storage.NewLevelDBStore(dbconfig.LevelDBOptions{DataDirectoryPath: dbPath})
+	require.NoError(t, err)
+	fbc, facc := chain.NewForkWithOptions(t, remote, height, &chain.Options{Store: db, SkipRun: true})
+	go fbc.Run()
+	require.Equal(t, height, fbc.BlockHeight())
+	require.Equal(t, bc.GetHeaderHash(height), fbc.CurrentBlockHash())
+	require.Equal(t, bc.GetHeaderHash(1), fbc.GetHeaderHash(1))
+
+	fe := neotest.NewExecutor(t, fbc, facc, facc)
+	fctr := fe.CommitteeInvoker(c.Hash)
+	fgas := fe.CommitteeInvoker(fe.NativeHash(t, nativenames.Gas))
+	fctr.Invoke(t, []byte("v1"), "get", "k1")
+	fgas.Invoke(t, 123, "balanceOf", receiver)
+
+	fctr.Invoke(t, stackitem.Null{}, "put", "k4", "v4")
+	fctr.Invoke(t, stackitem.Null{}, "delete", "k2")
+	fctr.Invoke(t, stackitem.Null{}, "get", "k2")
+	fctr.Invoke(t, 3, "count")
+	fgas.Invoke(t, true, "transfer", facc.ScriptHash(), receiver, 1, nil)
+	fgas.Invoke(t, 124, "balanceOf", receiver)
+
+	// Remote state is not changed.
+	ctr.Invoke(t, stackitem.Null{}, "get", "k4")
+	ctr.Invoke(t, 3, "count")
+	gas.Invoke(t, 123, "balanceOf", receiver)
+
+	t.Run("cached", func(t *testing.T) {
+		getCalls, findCalls := remote.getCalls, remote.findCalls
+		fctr.Invoke(t, []byte("v1"), "get", "k1")
+		fctr.Invoke(t, stackitem.Null{}, "get", "k2")
+		fctr.Invoke(t, 3, "count")
+		require.Equal(t, getCalls, remote.getCalls)
+		require.Equal(t, findCalls, remote.findCalls)
+	})
+
+	t.Run("reopen", func(t *testing.T) {
+		top := fbc.CurrentBlockHash()
+		fbc.Close()
+
+		db, err := storage.NewLevelDBStore(dbconfig.LevelDBOptions{DataDirectoryPath: dbPath})
+		require.NoError(t, err)
+		_, err = fork.NewStore(db, remote, fbc.GetConfig(), height+1, zaptest.NewLogger(t))
+		require.ErrorContains(t, err, "forked at height")
+
+		fbc, facc := chain.NewForkWithOptions(t, remote, height, &chain.Options{Store: db})
+		require.Equal(t, top, fbc.CurrentBlockHash())
+		fe := neotest.NewExecutor(t, fbc, facc, facc)
+		fctr := fe.CommitteeInvoker(c.Hash)
+		fctr.Invoke(t, []byte("v4"), "get", "k4")
+		fctr.Invoke(t, stackitem.Null{}, "get", "k2")
+		fctr.Invoke(t, 3, "count")
+	})
+
+	t.Run("non-empty store", func(t *testing.T) {
+		var (
+			st = storage.NewMemoryStore()
+			d  = dao.NewSimple(st, false)
+		)
+		d.PutVersion(dao.Version{Value: "0.2.12"})
+		_, err := d.Persist()
+		require.NoError(t, err)
+		_, err = fork.NewStore(st, remote, bc.GetConfig(), height, zaptest.NewLogger(t))
+		require.ErrorContains(t, err, "store is not empty")
+	})
+}
+
+func TestForkRemoteErrors(t *testing.T) {
+	bc, acc := chain.NewSingle(t)
+	e := neotest.NewExecutor(t, bc, acc, acc)
+	c := neotest.CompileSource(t, acc.ScriptHash(), strings.NewReader(storageContractSrc), &compiler.Options{Name: "Storage"})
+	e.DeployContract(t, c, nil)
+	ctr := e.CommitteeInvoker(c.Hash)
+	for _, k := range []string{"k1", "k2"} {
+		ctr.Invoke(t, stackitem.Null{}, "put", k, "v"+k[1:])
+	}
+
+	remote := &remoteChain{bc: bc, invalidParams: true}
+	s, err := fork.NewStore(storage.NewMemoryStore(), remote, bc.GetConfig(), bc.BlockHeight(), zaptest.NewLogger(t))
+	require.NoError(t, err)
+	id := bc.GetContractState(c.Hash).ID
+	key := func(k string) []byte {
+		return append([]byte{byte(storage.STStorage)}, makeMPTKey(id, []byte(k))...)
+	}
+
+	// Transient errors are retried.
+	remote.err, remote.failures = errors.New("connection refused"), 2
+	v, err := s.Get(key("k1"))
+	require.NoError(t, err)
+	require.Equal(t, []byte("v1"), v)
+
+	// Invalid params answer is checked with findstates.
+	_, err = s.Get(key("missing"))
+	require.ErrorIs(t, err, storage.ErrKeyNotFound)
+	getCalls, findCalls := remote.getCalls, remote.findCalls
+	_, err = s.Get(key("missing"))
+	require.ErrorIs(t, err, storage.ErrKeyNotFound)
+	require.Equal(t, getCalls, remote.getCalls)
+	require.Equal(t, findCalls, remote.findCalls)
+
+	// Missing contract answer is not retried.
+	remote.err = neorpc.ErrUnknownContract
+	s.Seek(storage.SeekRange{Prefix: key("m")}, func(k, v []byte) bool { return true })
+	require.Equal(t, findCalls+1, remote.findCalls)
+	remote.err = nil
+	require.NoError(t, s.PutChangeSet(map[string][]byte{"x": {1}}, nil))
+
+	remote.err = neorpc.ErrInvalidParams
+	_, err = s.Get(key("k2"))
+	require.ErrorIs(t, err, neorpc.ErrInvalidParams)
+	var seen int
+	s.Seek(storage.SeekRange{Prefix: key("k")}, func(k, v []byte) bool { seen++; return true })
+	require.Equal(t, 1, seen) // Only k1 is cached.
+
+	// Errors are not cached, but the state isn't saved anymore.
+	remote.err = nil
+	v, err = s.Get(key("k2"))
+	require.NoError(t, err)
+	require.Equal(t, []byte("v2"), v)
+	require.ErrorIs(t, s.PutChangeSet(map[string][]byte{"x": {2}}, nil), neorpc.ErrInvalidParams)
+}
+
+func TestForkHeaderPages(t *testing.T) {
+	bc, acc := chain.NewSingle(t)
+	e := neotest.NewExecutor(t, bc, acc, acc)
+	e.GenerateNewBlocks(t, 2003)
+
+	for _, height := range []uint32{1999, 2000, 2003} {
+		fbc, facc := chain.NewForkWithOptions(t, &remoteChain{bc: bc}, height, nil)
+		require.Equal(t, height, fbc.HeaderHeight())
+		require.Equal(t, bc.GetHeaderHash(height), fbc.CurrentBlockHash())
+		require.Equal(t, bc.GetHeaderHash(1999), fbc.GetHeaderHash(1999))
+		fe := neotest.NewExecutor(t, fbc, facc, facc)
+		fe.GenerateNewBlocks(t, 1)
+	}
+}
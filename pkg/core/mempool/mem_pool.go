@@ -14,6 +14,9 @@ import (
 	"github.com/nspcc-dev/neo-go/pkg/util"
 )
 
+// minEventsQueueSize is the minimum size of the mempool events queue.
+const minEventsQueueSize = 1024
+
 var (
 	// ErrInsufficientFunds is returned when the Sender is not able to pay for
 	// the transaction being added irrespective of the other contents of the
@@ -35,6 +38,12 @@ var (
 	// ErrOracleResponse is returned when the mempool already contains a transaction
 	// with the same oracle response ID and higher network fee.
 	ErrOracleResponse = errors.New("conflicts with memory pool due to OracleResponse attribute")
+	// ErrSenderTxLimit is returned when the Sender already has the maximum
+	// allowed number of transactions in the pool.
+	ErrSenderTxLimit = errors.New("too many transactions from the sender")
+	// ErrSenderSizeLimit is returned when the transaction being added exceeds
+	// the limit of the total size of the Sender's transactions in the pool.
+	ErrSenderSizeLimit = errors.New("sender transactions size limit exceeded")
 )
 
 // item represents a transaction in the the Memory pool.
@@ -48,10 +57,14 @@ type item struct {
 type items []item
 
 // utilityBalanceAndFees stores the sender's balance and overall fees of
-// the sender's transactions which are currently in the mempool.
+// the sender's transactions which are currently in the mempool. It also
+// stores the number and the total size of the sender's transactions subject
+// to per-sender limits (see isLimited).
 type utilityBalanceAndFees struct {
 	balance uint256.Int
 	feeSum  uint256.Int
+	txCount int
+	txSize  int
 }
 
 // Pool stores the unconfirmed transactions.
@@ -70,6 +83,16 @@ type Pool struct {
 	payerIndex      int
 	updateMetricsCb func(int)
 
+	// Per-sender limits, 0 means no limit.
+	maxSenderTxs  int
+	maxSenderSize int
+	fairEviction  bool
+	// senders maps the number of transactions subject to per-sender limits
+	// to the set of senders having this number of them in the pool, it's
+	// used for fair eviction along with maxSenderCount.
+	senders        map[int]map[util.Uint160]struct{}
+	maxSenderCount int
+
 	resendThreshold uint32
 	resendFunc      func(*transaction.Transaction, any)
 
@@ -78,8 +101,9 @@ type Pool struct {
 	subscriptionsOn      atomic.Bool
 	stopCh               chan struct{}
 	events               chan mempoolevent.Event
-	subCh                chan chan<- mempoolevent.Event // there are no other events in mempool except Event, so no need in generic subscribers type
-	unsubCh              chan chan<- mempoolevent.Event
+	subsLock             sync.RWMutex
+	subscribers          map[chan<- mempoolevent.Event]bool // value denotes non-blocking subscriber; there are no other events in mempool except Event, so no need in generic subscribers type
+	hasSubscribers       atomic.Bool
 }
 
 func (p items) Len() int           { return len(p) }
@@ -119,6 +143,11 @@ func (mp *Pool) count() int {
 	return len(mp.verifiedTxes)
 }
 
+// Capacity returns the maximum number of transactions the Pool can hold.
+func (mp *Pool) Capacity() int {
+	return mp.capacity
+}
+
 // ContainsKey checks if the transactions hash is in the Pool.
 func (mp *Pool) ContainsKey(hash util.Uint256) bool {
 	mp.lock.RLock()
@@ -175,10 +204,113 @@ func (mp *Pool) tryAddSendersFee(tx *transaction.Transaction, feer Feer, needChe
 	} else {
 		senderFee.feeSum.AddUint64(&senderFee.feeSum, uint64(tx.SystemFee+tx.NetworkFee))
 	}
+	mp.trackSender(&senderFee, payer, tx, true)
 	mp.fees[payer] = senderFee
 	return true
 }
 
+// trackSender updates the number and the total size of the payer's
+// transactions subject to per-sender limits after the given transaction
+// addition (or removal) and moves the payer to the appropriate mp.senders
+// bucket.
+func (mp *Pool) trackSender(senderFee *utilityBalanceAndFees, payer util.Uint160, tx *transaction.Transaction, add bool) {
+	if !isLimited(tx) {
+		return
+	}
+	old := senderFee.txCount
+	if add {
+		senderFee.txCount++
+		senderFee.txSize += tx.Size()
+	} else {
+		senderFee.txCount--
+		senderFee.txSize -= tx.Size()
+	}
+	if old > 0 {
+		delete(mp.senders[old], payer)
+		if len(mp.senders[old]) == 0 {
+			delete(mp.senders, old)
+		}
+	}
+	if n := senderFee.txCount; n > 0 {
+		if mp.senders[n] == nil {
+			mp.senders[n] = make(map[util.Uint160]struct{})
+		}
+		mp.senders[n][payer] = struct{}{}
+		if n > mp.maxSenderCount {
+			mp.maxSenderCount = n
+		}
+	}
+	for mp.maxSenderCount > 0 && len(mp.senders[mp.maxSenderCount]) == 0 {
+		mp.maxSenderCount--
+	}
+}
+
+// isLimited returns true if per-sender limits are applicable to the
+// transaction. Transactions with HighPriority, OracleResponse and
+// NotaryAssisted attributes are exempt from them since their senders are
+// either the committee or native contracts shared by many users.
+func isLimited(tx *transaction.Transaction) bool {
+	return !tx.HasAttribute(transaction.HighPriority) &&
+		!tx.HasAttribute(transaction.OracleResponseT) &&
+		!tx.HasAttribute(transaction.NotaryAssistedT)
+}
+
+// checkSenderLimits checks whether the transaction fits into per-sender
+// limits taking into account sender's transactions that are to be removed
+// from the pool because of conflicts.
+func (mp *Pool) checkSenderLimits(tx *transaction.Transaction, conflicts []*transaction.Transaction) error {
+	if mp.maxSenderTxs == 0 && mp.maxSenderSize == 0 || !isLimited(tx) {
+		return nil
+	}
+	payer := tx.Signers[mp.payerIndex].Account
+	senderFee := mp.fees[payer]
+	count, size := senderFee.txCount+1, senderFee.txSize+tx.Size()
+	for _, c := range conflicts {
+		if isLimited(c) && c.Signers[mp.payerIndex].Account.Equals(payer) {
+			count--
+			size -= c.Size()
+		}
+	}
+	if mp.maxSenderTxs != 0 && count > mp.maxSenderTxs {
+		return fmt.Errorf("%w: %d > %d", ErrSenderTxLimit, count, mp.maxSenderTxs)
+	}
+	if mp.maxSenderSize != 0 && size > mp.maxSenderSize {
+		return fmt.Errorf("%w: %d > %d", ErrSenderSizeLimit, size, mp.maxSenderSize)
+	}
+	return nil
+}
+
+// fairEvictionCandidate returns the hash of the least prioritized transaction
+// of the sender having the largest number of pooled transactions (subject to
+// per-sender limits) if this sender has more of them than the sender of the
+// given item would have after its addition. Only transactions that are less
+// prioritized than the given item (which is to be inserted at position n)
+// can be evicted.
+func (mp *Pool) fairEvictionCandidate(pItem item, n int) (util.Uint256, bool) {
+	var (
+		largest util.Uint160
+		top     = -1
+	)
+	if mp.maxSenderCount <= mp.fees[pItem.txn.Signers[mp.payerIndex].Account].txCount+1 {
+		return util.Uint256{}, false
+	}
+	// There are usually not many senders with the same number of transactions,
+	// the largest (and then the lowest by address) one is picked.
+	for acc := range mp.senders[mp.maxSenderCount] {
+		size := mp.fees[acc].txSize
+		if size > top || size == top && acc.Less(largest) {
+			largest, top = acc, size
+		}
+	}
+	for i := len(mp.verifiedTxes) - 1; i >= n; i-- {
+		tx := mp.verifiedTxes[i].txn
+		if isLimited(tx) && tx.Signers[mp.payerIndex].Account.Equals(largest) {
+			return tx.Hash(), true
+		}
+	}
+	return util.Uint256{}, false
+}
+
 // checkBalance returns a new cumulative fee balance for the account or an error in
 // case the sender doesn't have enough GAS to pay for the transaction.
 func checkBalance(tx *transaction.Transaction, balance utilityBalanceAndFees) (uint256.Int, error) {
@@ -214,6 +346,10 @@ func (mp *Pool) Add(t *transaction.Transaction, fee Feer, data ...any) error {
 		mp.lock.Unlock()
 		return err
 	}
+	if err = mp.checkSenderLimits(t, conflictsToBeRemoved); err != nil {
+		mp.lock.Unlock()
+		return err
+	}
 	if attrs := t.GetAttributes(transaction.OracleResponseT); len(attrs) != 0 {
 		id := attrs[0].Value.(*transaction.OracleResponse).ID
 		h, ok := mp.oracleResp[id]
@@ -222,14 +358,14 @@ func (mp *Pool) Add(t *transaction.Transaction, fee Feer, data ...any) error {
 				mp.lock.Unlock()
 				return ErrOracleResponse
 			}
-			mp.removeInternal(h, fee)
+			mp.removeInternal(h, fee, mempoolevent.ReasonConflict)
 		}
 		mp.oracleResp[id] = t.Hash()
 	}
 
 	// Remove conflicting transactions.
 	for _, conflictingTx := range conflictsToBeRemoved {
-		mp.removeInternal(conflictingTx.Hash(), fee)
+		mp.removeInternal(conflictingTx.Hash(), fee, mempoolevent.ReasonConflict)
 	}
 	// Insert into a sorted array (from max to min, that could also be done
 	// using sort.Sort(sort.Reverse()), but it incurs more overhead. Notice
@@ -241,6 +377,15 @@ func (mp *Pool) Add(t *transaction.Transaction, fee Feer, data ...any) error {
 		return pItem.CompareTo(mp.verifiedTxes[n]) > 0
 	})
 
+	// Evict the transaction of the largest contributor if possible.
+	if len(mp.verifiedTxes) == mp.capacity && mp.fairEviction {
+		if h, ok := mp.fairEvictionCandidate(pItem, n); ok {
+			mp.removeInternal(h, fee, mempoolevent.ReasonEvicted)
+			n = sort.Search(len(mp.verifiedTxes), func(n int) bool {
+				return pItem.CompareTo(mp.verifiedTxes[n]) > 0
+			})
+		}
+	}
 	// We've reached our capacity already.
 	if len(mp.verifiedTxes) == mp.capacity {
 		// Less prioritized than the least prioritized we already have, won't fit.
@@ -251,18 +396,23 @@ func (mp *Pool) Add(t *transaction.Transaction, fee Feer, data ...any) error {
 		// Ditch the last one.
 		unlucky := mp.verifiedTxes[len(mp.verifiedTxes)-1]
 		delete(mp.verifiedMap, unlucky.txn.Hash())
+		if isLimited(unlucky.txn) {
+			payer := unlucky.txn.Signers[mp.payerIndex].Account
+			senderFee := mp.fees[payer]
+			mp.trackSender(&senderFee, payer, unlucky.txn, false)
+			mp.fees[payer] = senderFee
+		}
 		mp.removeConflictsOf(unlucky.txn)
 		if attrs := unlucky.txn.GetAttributes(transaction.OracleResponseT); len(attrs) != 0 {
 			delete(mp.oracleResp, attrs[0].Value.(*transaction.OracleResponse).ID)
 		}
 		mp.verifiedTxes[len(mp.verifiedTxes)-1] = pItem
-		if mp.subscriptionsOn.Load() {
-			mp.events <- mempoolevent.Event{
-				Type: mempoolevent.TransactionRemoved,
-				Tx:   unlucky.txn,
-				Data: unlucky.data,
-			}
-		}
+		mp.notify(mempoolevent.Event{
+			Type:   mempoolevent.TransactionRemoved,
+			Tx:     unlucky.txn,
+			Data:   unlucky.data,
+			Reason: mempoolevent.ReasonEvicted,
+		})
 	} else {
 		mp.verifiedTxes = append(mp.verifiedTxes, pItem)
 	}
@@ -284,13 +434,11 @@ func (mp *Pool) Add(t *transaction.Transaction, fee Feer, data ...any) error {
 	}
 	mp.lock.Unlock()
 
-	if mp.subscriptionsOn.Load() {
-		mp.events <- mempoolevent.Event{
-			Type: mempoolevent.TransactionAdded,
-			Tx:   pItem.txn,
-			Data: pItem.data,
-		}
-	}
+	mp.notify(mempoolevent.Event{
+		Type: mempoolevent.TransactionAdded,
+		Tx:   pItem.txn,
+		Data: pItem.data,
+	})
 	return nil
 }
 
@@ -298,12 +446,13 @@ func (mp *Pool) Add(t *transaction.Transaction, fee Feer, data ...any) error {
 // nothing if it doesn't).
 func (mp *Pool) Remove(hash util.Uint256, feer Feer) {
 	mp.lock.Lock()
-	mp.removeInternal(hash, feer)
+	mp.removeInternal(hash, feer, mempoolevent.ReasonRemoved)
 	mp.lock.Unlock()
 }
 
-// removeInternal is an internal unlocked representation of Remove.
-func (mp *Pool) removeInternal(hash util.Uint256, feer Feer) {
+// removeInternal is an internal unlocked representation of Remove, reason is
+// used for mempool event.
+func (mp *Pool) removeInternal(hash util.Uint256, feer Feer, reason mempoolevent.Reason) {
 	if tx, ok := mp.verifiedMap[hash]; ok {
 		var num int
 		delete(mp.verifiedMap, hash)
@@ -321,19 +470,19 @@ func (mp *Pool) removeInternal(hash util.Uint256, feer Feer) {
 		payer := itm.txn.Signers[mp.payerIndex].Account
 		senderFee := mp.fees[payer]
 		senderFee.feeSum.SubUint64(&senderFee.feeSum, uint64(tx.SystemFee+tx.NetworkFee))
+		mp.trackSender(&senderFee, payer, tx, false)
 		mp.fees[payer] = senderFee
 		// remove all conflicting hashes from mp.conflicts list
 		mp.removeConflictsOf(tx)
 		if attrs := tx.GetAttributes(transaction.OracleResponseT); len(attrs) != 0 {
 			delete(mp.oracleResp, attrs[0].Value.(*transaction.OracleResponse).ID)
 		}
-		if mp.subscriptionsOn.Load() {
-			mp.events <- mempoolevent.Event{
-				Type: mempoolevent.TransactionRemoved,
-				Tx:   itm.txn,
-				Data: itm.data,
-			}
-		}
+		mp.notify(mempoolevent.Event{
+			Type:   mempoolevent.TransactionRemoved,
+			Tx:     itm.txn,
+			Data:   itm.data,
+			Reason: reason,
+		})
 	}
 	if mp.updateMetricsCb != nil {
 		mp.updateMetricsCb(len(mp.verifiedTxes))
@@ -344,19 +493,41 @@ func (mp *Pool) removeInternal(hash util.Uint256, feer Feer) {
 // only the transactions for which it returns true result. It's used to quickly
 // drop a part of the mempool that is now invalid after the block acceptance.
 func (mp *Pool) RemoveStale(isOK func(*transaction.Transaction) bool, feer Feer) {
+	height := feer.BlockHeight()
+	mp.RemoveStaleWithReason(func(tx *transaction.Transaction) mempoolevent.Reason {
+		if isOK(tx) {
+			return mempoolevent.NoReason
+		}
+		if tx.ValidUntilBlock <= height {
+			return mempoolevent.ReasonExpired
+		}
+		return mempoolevent.ReasonInvalid
+	}, feer)
+}
+
+// RemoveStaleWithReason is similar to RemoveStale, but the given function
+// returns the reason for transaction removal (used for mempool events) keeping
+// the transactions for which it returns mempoolevent.NoReason.
+func (mp *Pool) RemoveStaleWithReason(check func(*transaction.Transaction) mempoolevent.Reason, feer Feer) {
 	mp.lock.Lock()
 	policyChanged := mp.loadPolicy(feer)
 	// We can reuse already allocated slice
 	// because items are iterated one-by-one in increasing order.
 	newVerifiedTxes := mp.verifiedTxes[:0]
 	mp.fees = make(map[util.Uint160]utilityBalanceAndFees) // it'd be nice to reuse existing map, but we can't easily clear it
+	mp.senders = make(map[int]map[util.Uint160]struct{})
+	mp.maxSenderCount = 0
 	mp.conflicts = make(map[util.Uint256][]util.Uint256)
 	height := feer.BlockHeight()
 	var (
 		staleItems []item
 	)
 	for _, itm := range mp.verifiedTxes {
-		if isOK(itm.txn) && mp.checkPolicy(itm.txn, policyChanged) && mp.tryAddSendersFee(itm.txn, feer, true) {
+		reason := check(itm.txn)
+		if reason == mempoolevent.NoReason && (!mp.checkPolicy(itm.txn, policyChanged) || !mp.tryAddSendersFee(itm.txn, feer, true)) {
+			reason = mempoolevent.ReasonInvalid
+		}
+		if reason == mempoolevent.NoReason {
 			newVerifiedTxes = append(newVerifiedTxes, itm)
 			for _, attr := range itm.txn.GetAttributes(transaction.ConflictsT) {
 				hash := attr.Value.(*transaction.Conflicts).Hash
@@ -375,13 +546,12 @@ func (mp *Pool) RemoveStale(isOK func(*transaction.Transaction) bool, feer Feer)
 			if attrs := itm.txn.GetAttributes(transaction.OracleResponseT); len(attrs) != 0 {
 				delete(mp.oracleResp, attrs[0].Value.(*transaction.OracleResponse).ID)
 			}
-			if mp.subscriptionsOn.Load() {
-				mp.events <- mempoolevent.Event{
-					Type: mempoolevent.TransactionRemoved,
-					Tx:   itm.txn,
-					Data: itm.data,
-				}
-			}
+			mp.notify(mempoolevent.Event{
+				Type:   mempoolevent.TransactionRemoved,
+				Tx:     itm.txn,
+				Data:   itm.data,
+				Reason: reason,
+			})
 		}
 	}
 	if len(staleItems) != 0 {
@@ -418,19 +588,44 @@ func New(capacity int, payerIndex int, enableSubscriptions bool, updateMetricsCb
 		capacity:             capacity,
 		payerIndex:           payerIndex,
 		fees:                 make(map[util.Uint160]utilityBalanceAndFees),
+		senders:              make(map[int]map[util.Uint160]struct{}),
 		conflicts:            make(map[util.Uint256][]util.Uint256),
 		oracleResp:           make(map[uint64]util.Uint256),
 		subscriptionsEnabled: enableSubscriptions,
 		stopCh:               make(chan struct{}),
-		events:               make(chan mempoolevent.Event),
-		subCh:                make(chan chan<- mempoolevent.Event),
-		unsubCh:              make(chan chan<- mempoolevent.Event),
+		subscribers:          make(map[chan<- mempoolevent.Event]bool),
 		updateMetricsCb:      updateMetricsCb,
 	}
-	mp.subscriptionsOn.Store(false)
+	if enableSubscriptions {
+		// Every pooled transaction can produce at most one removal event,
+		// but small pools still need some room for bursts.
+		queueSize := capacity
+		if queueSize < minEventsQueueSize {
+			queueSize = minEventsQueueSize
+		}
+		mp.events = make(chan mempoolevent.Event, queueSize)
+	}
 	return mp
 }
 
+// SetSenderLimits sets the maximum number of transactions and their total size
+// (in bytes) for every sender (0 means no limit), transactions exceeding these
+// limits are rejected by Add. If fairEviction is set, then the least
+// prioritized transaction of the sender having the largest number of pooled
+// transactions is evicted from the full pool instead of the least prioritized
+// transaction of the pool (if this sender has more transactions than the
+// sender of the transaction being added and the transaction being added is
+// not less prioritized than the evicted one). Transactions with HighPriority,
+// OracleResponse and NotaryAssisted attributes are not affected by these
+// settings. It doesn't affect the transactions that are already in the pool.
+func (mp *Pool) SetSenderLimits(maxTxs, maxSize int, fairEviction bool) {
+	mp.lock.Lock()
+	defer mp.lock.Unlock()
+	mp.maxSenderTxs = maxTxs
+	mp.maxSenderSize = maxSize
+	mp.fairEviction = fairEviction
+}
+
 // SetResendThreshold sets a threshold after which the transaction will be considered stale
 // and returned for retransmission by `GetStaleTransactions`.
 func (mp *Pool) SetResendThreshold(h uint32, f func(*transaction.Transaction, any)) {
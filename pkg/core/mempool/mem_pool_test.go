@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/holiman/uint256"
+	"github.com/nspcc-dev/neo-go/pkg/core/mempoolevent"
 	"github.com/nspcc-dev/neo-go/pkg/core/transaction"
 	"github.com/nspcc-dev/neo-go/pkg/network/payload"
 	"github.com/nspcc-dev/neo-go/pkg/util"
@@ -267,6 +268,8 @@ func TestMemPoolFees(t *testing.T) {
 	require.Equal(t, utilityBalanceAndFees{
 		balance: *uint256.NewInt(uint64(fs.balance)),
 		feeSum:  *uint256.NewInt(uint64(tx1.NetworkFee)),
+		txCount: 1,
+		txSize:  tx1.Size(),
 	}, mp.fees[sender0])
 
 	// balance shouldn't change after adding one more transaction
@@ -279,6 +282,8 @@ func TestMemPoolFees(t *testing.T) {
 	require.Equal(t, utilityBalanceAndFees{
 		balance: *uint256.NewInt(uint64(fs.balance)),
 		feeSum:  *uint256.NewInt(uint64(fs.balance)),
+		txCount: 2,
+		txSize:  tx1.Size() + tx2.Size(),
 	}, mp.fees[sender0])
 
 	// can't add more transactions as we don't have enough GAS
@@ -291,6 +296,8 @@ func TestMemPoolFees(t *testing.T) {
 	require.Equal(t, utilityBalanceAndFees{
 		balance: *uint256.NewInt(uint64(fs.balance)),
 		feeSum:  *uint256.NewInt(uint64(fs.balance)),
+		txCount: 2,
+		txSize:  tx1.Size() + tx2.Size(),
 	}, mp.fees[sender0])
 
 	// check whether sender's fee updates correctly
@@ -301,6 +308,8 @@ func TestMemPoolFees(t *testing.T) {
 	require.Equal(t, utilityBalanceAndFees{
 		balance: *uint256.NewInt(uint64(fs.balance)),
 		feeSum:  *uint256.NewInt(uint64(tx2.NetworkFee)),
+		txCount: 1,
+		txSize:  tx2.Size(),
 	}, mp.fees[sender0])
 
 	// there should be nothing left
@@ -821,3 +830,129 @@ func TestMempoolIterateVerifiedTransactions(t *testing.T) {
 	}
 	checkPooledRequest(t, r5, false)
 }
+
+func TestMempoolSenderLimits(t *testing.T) {
+	var (
+		fs     = &FeerStub{balance: 100_0000_0000}
+		sender = util.Uint160{1, 2, 3}
+		nonce  uint32
+	)
+	newTx := func(acc util.Uint160, netFee int64, script []byte) *transaction.Transaction {
+		tx := transaction.New(script, 0)
+		tx.Nonce = nonce
+		nonce++
+		tx.NetworkFee = netFee
+		tx.Signers = []transaction.Signer{{Account: acc}}
+		return tx
+	}
+
+	t.Run("transactions", func(t *testing.T) {
+		mp := New(10, 0, false, nil)
+		mp.SetSenderLimits(2, 0, false)
+		require.NoError(t, mp.Add(newTx(sender, 1, []byte{byte(opcode.PUSH1)}), fs))
+		tx := newTx(sender, 1, []byte{byte(opcode.PUSH1)})
+		require.NoError(t, mp.Add(tx, fs))
+		require.ErrorIs(t, mp.Add(newTx(sender, 1, []byte{byte(opcode.PUSH1)}), fs), ErrSenderTxLimit)
+
+		// Other senders are not affected.
+		require.NoError(t, mp.Add(newTx(util.Uint160{4, 5, 6}, 1, []byte{byte(opcode.PUSH1)}), fs))
+
+		// High priority transactions are not limited.
+		hp := newTx(sender, 1, []byte{byte(opcode.PUSH1)})
+		hp.Attributes = []transaction.Attribute{{Type: transaction.HighPriority}}
+		require.NoError(t, mp.Add(hp, fs))
+
+		// Conflicting transaction replaces the existing one.
+		cTx := newTx(sender, 2, []byte{byte(opcode.PUSH1)})
+		cTx.Attributes = []transaction.Attribute{{Type: transaction.ConflictsT, Value: &transaction.Conflicts{Hash: tx.Hash()}}}
+		require.NoError(t, mp.Add(cTx, fs))
+		require.False(t, mp.ContainsKey(tx.Hash()))
+
+		// Removal frees the space.
+		mp.Remove(cTx.Hash(), fs)
+		require.NoError(t, mp.Add(newTx(sender, 1, []byte{byte(opcode.PUSH1)}), fs))
+		require.Equal(t, 2, mp.fees[sender].txCount)
+	})
+	t.Run("size", func(t *testing.T) {
+		mp := New(10, 0, false, nil)
+		tx := newTx(sender, 1, []byte{byte(opcode.PUSH1)})
+		mp.SetSenderLimits(0, 2*tx.Size()+1, false)
+		require.NoError(t, mp.Add(tx, fs))
+		require.NoError(t, mp.Add(newTx(sender, 1, []byte{byte(opcode.PUSH1)}), fs))
+		require.ErrorIs(t, mp.Add(newTx(sender, 1, []byte{byte(opcode.PUSH1)}), fs), ErrSenderSizeLimit)
+
+		mp.Remove(tx.Hash(), fs)
+		require.ErrorIs(t, mp.Add(newTx(sender, 1, []byte{byte(opcode.PUSH1), byte(opcode.PUSH1), byte(opcode.PUSH1)}), fs), ErrSenderSizeLimit)
+		require.NoError(t, mp.Add(newTx(sender, 1, []byte{byte(opcode.PUSH1)}), fs))
+	})
+	t.Run("fair eviction", func(t *testing.T) {
+		var (
+			whale = util.Uint160{7, 8, 9}
+			mp    = New(5, 0, true, nil)
+		)
+		mp.RunSubscriptions()
+		t.Cleanup(mp.StopSubscriptions)
+		events := make(chan mempoolevent.Event, 20)
+		mp.SubscribeForTransactions(events)
+		t.Cleanup(func() { mp.UnsubscribeFromTransactions(events) })
+
+		mp.SetSenderLimits(0, 0, true)
+		var whaleTxs []*transaction.Transaction
+		for i := 0; i < 4; i++ {
+			tx := newTx(whale, int64(1000+i), []byte{byte(opcode.PUSH1)})
+			require.NoError(t, mp.Add(tx, fs))
+			whaleTxs = append(whaleTxs, tx)
+		}
+		low := newTx(sender, 10, []byte{byte(opcode.PUSH1)})
+		require.NoError(t, mp.Add(low, fs))
+		require.Equal(t, 4, mp.maxSenderCount)
+
+		// The pool is full, but whale transactions are more prioritized than
+		// the new one, so the default policy applies.
+		other := util.Uint160{4, 5, 6}
+		tx := newTx(other, 50, []byte{byte(opcode.PUSH1)})
+		require.NoError(t, mp.Add(tx, fs))
+		require.False(t, mp.ContainsKey(low.Hash()))
+		require.Equal(t, 4, mp.fees[whale].txCount)
+		require.ErrorIs(t, mp.Add(newTx(other, 5, []byte{byte(opcode.PUSH1)}), fs), ErrOOM)
+
+		// The lowest-fee whale transaction is evicted instead of the least
+		// prioritized transaction of the pool.
+		require.NoError(t, mp.Add(newTx(sender, 1001, []byte{byte(opcode.PUSH1)}), fs))
+		require.False(t, mp.ContainsKey(whaleTxs[0].Hash()))
+		require.True(t, mp.ContainsKey(tx.Hash()))
+		require.Equal(t, 3, mp.fees[whale].txCount)
+		require.Equal(t, 3, mp.maxSenderCount)
+
+		// Whale has 3 transactions, the sender of the new one would have 2.
+		require.NoError(t, mp.Add(newTx(sender, 1002, []byte{byte(opcode.PUSH1)}), fs))
+		require.False(t, mp.ContainsKey(whaleTxs[1].Hash()))
+
+		// Now whale is not larger than the sender, the default policy
+		// applies.
+		require.NoError(t, mp.Add(newTx(sender, 1003, []byte{byte(opcode.PUSH1)}), fs))
+		require.False(t, mp.ContainsKey(tx.Hash()))
+		require.Equal(t, 3, mp.maxSenderCount)
+		mp.RemoveStale(func(tx *transaction.Transaction) bool { return !tx.Hash().Equals(whaleTxs[2].Hash()) }, fs)
+		require.Equal(t, 3, mp.maxSenderCount)
+		require.Equal(t, map[util.Uint160]struct{}{whale: {}}, mp.senders[1])
+
+		var evicted []util.Uint256
+		require.Eventually(t, func() bool {
+			for {
+				select {
+				case e := <-events:
+					if e.Type == mempoolevent.TransactionRemoved {
+						if len(evicted) < 4 {
+							require.Equal(t, mempoolevent.ReasonEvicted, e.Reason)
+						}
+						evicted = append(evicted, e.Tx.Hash())
+					}
+				default:
+					return len(evicted) == 5
+				}
+			}
+		}, time.Second, 10*time.Millisecond)
+		require.Equal(t, []util.Uint256{low.Hash(), whaleTxs[0].Hash(), whaleTxs[1].Hash(), tx.Hash()}, evicted[:4])
+	})
+}
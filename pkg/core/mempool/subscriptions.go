@@ -28,41 +28,76 @@ func (mp *Pool) StopSubscriptions() {
 // SubscribeForTransactions adds the given channel to the new mempool event broadcasting, so when
 // there is a new transactions added to the mempool or an existing transaction removed from
 // the mempool, you'll receive it via this channel. Make sure you're not changing the received
-// mempool events, as it may affect the functionality of other subscribers.
+// mempool events, as it may affect the functionality of other subscribers. Subscription can be
+// made before RunSubscriptions, events are only produced when subscriptions are running and
+// there are subscribers. Every event is delivered, so make sure the channel is read from
+// regularly as not reading these events blocks mempool operations.
 func (mp *Pool) SubscribeForTransactions(ch chan<- mempoolevent.Event) {
-	if mp.subscriptionsOn.Load() {
-		mp.subCh <- ch
+	mp.subscribe(ch, false)
+}
+
+// SubscribeForTransactionsNonBlocking is the same as SubscribeForTransactions,
+// but mempool operations are never blocked by this subscriber, events are
+// dropped if it can't keep up with them.
+func (mp *Pool) SubscribeForTransactionsNonBlocking(ch chan<- mempoolevent.Event) {
+	mp.subscribe(ch, true)
+}
+
+func (mp *Pool) subscribe(ch chan<- mempoolevent.Event, nonBlocking bool) {
+	if !mp.subscriptionsEnabled {
+		return
 	}
+	mp.subsLock.Lock()
+	defer mp.subsLock.Unlock()
+	mp.subscribers[ch] = nonBlocking
+	mp.hasSubscribers.Store(true)
 }
 
 // UnsubscribeFromTransactions unsubscribes the given channel from new mempool notifications,
-// you can close it afterwards. Passing non-subscribed channel is a no-op.
+// you can close it afterwards. Passing non-subscribed channel is a no-op. The channel
+// must be read from until this method returns.
 func (mp *Pool) UnsubscribeFromTransactions(ch chan<- mempoolevent.Event) {
-	if mp.subscriptionsOn.Load() {
-		mp.unsubCh <- ch
+	if !mp.subscriptionsEnabled {
+		return
+	}
+	mp.subsLock.Lock()
+	defer mp.subsLock.Unlock()
+	delete(mp.subscribers, ch)
+	mp.hasSubscribers.Store(len(mp.subscribers) != 0)
+}
+
+// notify passes the event to the dispatcher if subscriptions are running and
+// there are any subscribers. It blocks if the dispatcher is busy delivering
+// previous events to blocking subscribers.
+func (mp *Pool) notify(e mempoolevent.Event) {
+	if !mp.subscriptionsOn.Load() || !mp.hasSubscribers.Load() {
+		return
+	}
+	select {
+	case mp.events <- e:
+	case <-mp.stopCh:
 	}
 }
 
-// notificationDispatcher manages subscription to events and broadcasts new events.
+// notificationDispatcher broadcasts new events to subscribers.
 func (mp *Pool) notificationDispatcher() {
-	var (
-		// These are just sets of subscribers, though modelled as maps
-		// for ease of management (not a lot of subscriptions is really
-		// expected, but maps are convenient for adding/deleting elements).
-		txFeed = make(map[chan<- mempoolevent.Event]bool)
-	)
 	for {
 		select {
 		case <-mp.stopCh:
 			return
-		case sub := <-mp.subCh:
-			txFeed[sub] = true
-		case unsub := <-mp.unsubCh:
-			delete(txFeed, unsub)
 		case event := <-mp.events:
-			for ch := range txFeed {
-				ch <- event
+			mp.subsLock.RLock()
+			for ch, nonBlocking := range mp.subscribers {
+				if !nonBlocking {
+					ch <- event
+					continue
+				}
+				select {
+				case ch <- event:
+				default:
+				}
 			}
+			mp.subsLock.RUnlock()
 		}
 	}
 }
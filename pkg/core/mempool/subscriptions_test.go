@@ -37,6 +37,7 @@ func TestSubscriptions(t *testing.T) {
 			txs[i].Nonce = uint32(i)
 			txs[i].Signers = []transaction.Signer{{Account: util.Uint160{1, 2, 3}}}
 			txs[i].NetworkFee = int64(i)
+			txs[i].ValidUntilBlock = 10
 		}
 
 		// add tx
@@ -59,8 +60,8 @@ func TestSubscriptions(t *testing.T) {
 		require.Eventually(t, func() bool { return len(subChan1) == 2 && len(subChan2) == 2 }, time.Second, time.Millisecond*100)
 		event1 = <-subChan1
 		event2 = <-subChan2
-		require.Equal(t, mempoolevent.Event{Type: mempoolevent.TransactionRemoved, Tx: txs[0]}, event1)
-		require.Equal(t, mempoolevent.Event{Type: mempoolevent.TransactionRemoved, Tx: txs[0]}, event2)
+		require.Equal(t, mempoolevent.Event{Type: mempoolevent.TransactionRemoved, Tx: txs[0], Reason: mempoolevent.ReasonEvicted}, event1)
+		require.Equal(t, mempoolevent.Event{Type: mempoolevent.TransactionRemoved, Tx: txs[0], Reason: mempoolevent.ReasonEvicted}, event2)
 		event1 = <-subChan1
 		event2 = <-subChan2
 		require.Equal(t, mempoolevent.Event{Type: mempoolevent.TransactionAdded, Tx: txs[2]}, event1)
@@ -71,8 +72,8 @@ func TestSubscriptions(t *testing.T) {
 		require.Eventually(t, func() bool { return len(subChan1) == 1 && len(subChan2) == 1 }, time.Second, time.Millisecond*100)
 		event1 = <-subChan1
 		event2 = <-subChan2
-		require.Equal(t, mempoolevent.Event{Type: mempoolevent.TransactionRemoved, Tx: txs[1]}, event1)
-		require.Equal(t, mempoolevent.Event{Type: mempoolevent.TransactionRemoved, Tx: txs[1]}, event2)
+		require.Equal(t, mempoolevent.Event{Type: mempoolevent.TransactionRemoved, Tx: txs[1], Reason: mempoolevent.ReasonRemoved}, event1)
+		require.Equal(t, mempoolevent.Event{Type: mempoolevent.TransactionRemoved, Tx: txs[1], Reason: mempoolevent.ReasonRemoved}, event2)
 
 		// remove stale
 		mp.RemoveStale(func(tx *transaction.Transaction) bool {
@@ -81,8 +82,8 @@ func TestSubscriptions(t *testing.T) {
 		require.Eventually(t, func() bool { return len(subChan1) == 1 && len(subChan2) == 1 }, time.Second, time.Millisecond*100)
 		event1 = <-subChan1
 		event2 = <-subChan2
-		require.Equal(t, mempoolevent.Event{Type: mempoolevent.TransactionRemoved, Tx: txs[2]}, event1)
-		require.Equal(t, mempoolevent.Event{Type: mempoolevent.TransactionRemoved, Tx: txs[2]}, event2)
+		require.Equal(t, mempoolevent.Event{Type: mempoolevent.TransactionRemoved, Tx: txs[2], Reason: mempoolevent.ReasonInvalid}, event1)
+		require.Equal(t, mempoolevent.Event{Type: mempoolevent.TransactionRemoved, Tx: txs[2], Reason: mempoolevent.ReasonInvalid}, event2)
 
 		// unsubscribe
 		mp.UnsubscribeFromTransactions(subChan1)
@@ -92,4 +93,142 @@ func TestSubscriptions(t *testing.T) {
 		require.Equal(t, 0, len(subChan1))
 		require.Equal(t, mempoolevent.Event{Type: mempoolevent.TransactionAdded, Tx: txs[3]}, event2)
 	})
+	t.Run("removal reasons", func(t *testing.T) {
+		fs := &FeerStub{balance: 100}
+		mp := New(10, 0, true, nil)
+		mp.RunSubscriptions()
+		subChan := make(chan mempoolevent.Event, 10)
+		mp.SubscribeForTransactions(subChan)
+		t.Cleanup(mp.StopSubscriptions)
+
+		txs := make([]*transaction.Transaction, 4)
+		for i := range txs {
+			txs[i] = transaction.New([]byte{byte(opcode.PUSH1)}, 0)
+			txs[i].Nonce = uint32(i)
+			txs[i].Signers = []transaction.Signer{{Account: util.Uint160{1, 2, 3}}}
+			txs[i].NetworkFee = int64(i)
+			txs[i].ValidUntilBlock = uint32(10 + i)
+		}
+		conflicting := transaction.New([]byte{byte(opcode.PUSH1)}, 0)
+		conflicting.Signers = []transaction.Signer{{Account: util.Uint160{1, 2, 3}}}
+		conflicting.NetworkFee = 10
+		conflicting.ValidUntilBlock = 100
+		conflicting.Attributes = []transaction.Attribute{{
+			Type:  transaction.ConflictsT,
+			Value: &transaction.Conflicts{Hash: txs[0].Hash()},
+		}}
+		for _, tx := range txs {
+			require.NoError(t, mp.Add(tx, fs))
+		}
+		require.NoError(t, mp.Add(conflicting, fs))
+
+		fs.blockHeight = 11
+		mp.RemoveStaleWithReason(func(tx *transaction.Transaction) mempoolevent.Reason {
+			switch {
+			case tx.Hash() == txs[2].Hash():
+				return mempoolevent.ReasonIncluded
+			case tx.ValidUntilBlock <= fs.blockHeight:
+				return mempoolevent.ReasonExpired
+			}
+			return mempoolevent.NoReason
+		}, fs)
+		mp.RemoveStale(func(tx *transaction.Transaction) bool {
+			return tx.Hash() != txs[3].Hash()
+		}, fs)
+
+		var reasons = make(map[util.Uint256]mempoolevent.Reason)
+		require.Eventually(t, func() bool { return len(subChan) == 9 }, time.Second, time.Millisecond*100)
+		for i := 0; i < 9; i++ {
+			e := <-subChan
+			if e.Type == mempoolevent.TransactionRemoved {
+				reasons[e.Tx.Hash()] = e.Reason
+			} else {
+				require.Equal(t, mempoolevent.NoReason, e.Reason)
+			}
+		}
+		require.Equal(t, map[util.Uint256]mempoolevent.Reason{
+			txs[0].Hash(): mempoolevent.ReasonConflict,
+			txs[1].Hash(): mempoolevent.ReasonExpired,
+			txs[2].Hash(): mempoolevent.ReasonIncluded,
+			txs[3].Hash(): mempoolevent.ReasonInvalid,
+		}, reasons)
+		require.Equal(t, 1, mp.Count())
+	})
+	t.Run("subscribe before run", func(t *testing.T) {
+		fs := &FeerStub{balance: 100}
+		mp := New(5, 0, true, nil)
+		subChan := make(chan mempoolevent.Event, 1)
+		mp.SubscribeForTransactions(subChan)
+		mp.RunSubscriptions()
+		t.Cleanup(mp.StopSubscriptions)
+
+		tx := transaction.New([]byte{byte(opcode.PUSH1)}, 0)
+		tx.Signers = []transaction.Signer{{Account: util.Uint160{1, 2, 3}}}
+		tx.ValidUntilBlock = 10
+		require.NoError(t, mp.Add(tx, fs))
+		require.Eventually(t, func() bool { return len(subChan) == 1 }, time.Second, time.Millisecond*100)
+		require.Equal(t, mempoolevent.Event{Type: mempoolevent.TransactionAdded, Tx: tx}, <-subChan)
+	})
+	t.Run("slow non-blocking subscriber", func(t *testing.T) {
+		fs := &FeerStub{balance: 100}
+		mp := New(5, 0, true, nil)
+		subChan := make(chan mempoolevent.Event)
+		mp.SubscribeForTransactionsNonBlocking(subChan)
+		mp.RunSubscriptions()
+
+		// Nobody reads from subChan, but the pool is not blocked.
+		for i := 0; i < 20; i++ {
+			tx := transaction.New([]byte{byte(opcode.PUSH1)}, 0)
+			tx.Nonce = uint32(i)
+			tx.Signers = []transaction.Signer{{Account: util.Uint160{1, 2, 3}}}
+			tx.ValidUntilBlock = 10
+			require.NoError(t, mp.Add(tx, fs))
+			mp.Remove(tx.Hash(), fs)
+		}
+		var done = make(chan struct{})
+		go func() {
+			mp.UnsubscribeFromTransactions(subChan)
+			close(done)
+		}()
+		for {
+			select {
+			case <-subChan:
+				continue
+			case <-done:
+			}
+			break
+		}
+		mp.StopSubscriptions()
+	})
+	t.Run("slow blocking subscriber", func(t *testing.T) {
+		fs := &FeerStub{balance: 100}
+		mp := New(5, 0, true, nil)
+		subChan := make(chan mempoolevent.Event)
+		mp.SubscribeForTransactions(subChan)
+		mp.RunSubscriptions()
+		t.Cleanup(mp.StopSubscriptions)
+
+		const n = 2 * minEventsQueueSize
+		go func() {
+			for i := 0; i < n; i++ {
+				tx := transaction.New([]byte{byte(opcode.PUSH1)}, 0)
+				tx.Nonce = uint32(i)
+				tx.Signers = []transaction.Signer{{Account: util.Uint160{1, 2, 3}}}
+				tx.ValidUntilBlock = 10
+				if mp.Add(tx, fs) == nil {
+					mp.Remove(tx.Hash(), fs)
+				}
+			}
+		}()
+		// Every event is delivered in order even though there are more of
+		// them than the queue can hold.
+		for i := 0; i < n; i++ {
+			e := <-subChan
+			require.Equal(t, mempoolevent.TransactionAdded, e.Type)
+			require.Equal(t, uint32(i), e.Tx.Nonce)
+			e = <-subChan
+			require.Equal(t, mempoolevent.TransactionRemoved, e.Type)
+			require.Equal(t, uint32(i), e.Tx.Nonce)
+		}
+	})
 }
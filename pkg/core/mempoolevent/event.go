@@ -17,11 +17,39 @@ const (
 	TransactionRemoved Type = 0x02
 )
 
+// Reason represents the reason of transaction removal from the mempool.
+type Reason byte
+
+const (
+	// NoReason is used for TransactionAdded events.
+	NoReason Reason = iota
+	// ReasonIncluded is used for transactions removed because they're
+	// included into a block.
+	ReasonIncluded
+	// ReasonExpired is used for transactions removed because their
+	// ValidUntilBlock is reached.
+	ReasonExpired
+	// ReasonEvicted is used for transactions removed from the full mempool
+	// to free space for more prioritized ones.
+	ReasonEvicted
+	// ReasonConflict is used for transactions removed because of conflicts
+	// with other transactions (pooled or included into a block).
+	ReasonConflict
+	// ReasonInvalid is used for transactions removed because they're no
+	// longer valid (failing verification or policy checks).
+	ReasonInvalid
+	// ReasonRemoved is used for transactions explicitly removed from the
+	// mempool by the node (via Remove call).
+	ReasonRemoved
+)
+
 // Event represents one of mempool events: transaction was added or removed from the mempool.
 type Event struct {
 	Type Type
 	Tx   *transaction.Transaction
 	Data any
+	// Reason is set for TransactionRemoved events.
+	Reason Reason
 }
 
 // String is a Stringer implementation.
@@ -48,6 +76,71 @@ func GetEventTypeFromString(s string) (Type, error) {
 	}
 }
 
+// String is a Stringer implementation.
+func (r Reason) String() string {
+	switch r {
+	case NoReason:
+		return ""
+	case ReasonIncluded:
+		return "included"
+	case ReasonExpired:
+		return "expired"
+	case ReasonEvicted:
+		return "evicted"
+	case ReasonConflict:
+		return "conflict"
+	case ReasonInvalid:
+		return "invalid"
+	case ReasonRemoved:
+		return "removed"
+	default:
+		return "unknown"
+	}
+}
+
+// GetReasonFromString converts the input string into the Reason if it's possible.
+func GetReasonFromString(s string) (Reason, error) {
+	switch s {
+	case "":
+		return NoReason, nil
+	case "included":
+		return ReasonIncluded, nil
+	case "expired":
+		return ReasonExpired, nil
+	case "evicted":
+		return ReasonEvicted, nil
+	case "conflict":
+		return ReasonConflict, nil
+	case "invalid":
+		return ReasonInvalid, nil
+	case "removed":
+		return ReasonRemoved, nil
+	default:
+		return 0, errors.New("invalid removal reason")
+	}
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (r Reason) MarshalJSON() ([]byte, error) {
+	return json.Marshal(r.String())
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (r *Reason) UnmarshalJSON(b []byte) error {
+	var s string
+
+	err := json.Unmarshal(b, &s)
+	if err != nil {
+		return err
+	}
+	reason, err := GetReasonFromString(s)
+	if err != nil {
+		return err
+	}
+	*r = reason
+	return nil
+}
+
 // MarshalJSON implements the json.Marshaler interface.
 func (e Type) MarshalJSON() ([]byte, error) {
 	return json.Marshal(e.String())
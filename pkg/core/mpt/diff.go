@@ -0,0 +1,144 @@
+package mpt
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/nspcc-dev/neo-go/pkg/util"
+)
+
+// Diff compares t with the trie that has the specified root and is stored in
+// the same storage. It calls cont for every key which value differs between
+// them: oldValue is nil for keys that are missing from the old trie and
+// newValue is nil for keys that are missing from t. Keys are passed in
+// ascending order, traversal stops when cont returns false. Subtrees with
+// equal hashes are not traversed, so the cost of this operation depends on the
+// number of changes rather than on the size of the trie. Zero old root means
+// empty trie.
+func (t *Trie) Diff(old util.Uint256, cont func(key, oldValue, newValue []byte) bool) error {
+	var oldRoot Node = EmptyNode{}
+	if !old.Equals(util.Uint256{}) {
+		oldRoot = NewHashNode(old)
+	}
+	err := t.diff(oldRoot, t.root, []byte{}, cont)
+	if err != nil && !errors.Is(err, errStop) {
+		return err
+	}
+	return nil
+}
+
+func (t *Trie) diff(a, b Node, path []byte, cont func(key, oldValue, newValue []byte) bool) error {
+	if !isEmpty(a) && !isEmpty(b) && a.Hash().Equals(b.Hash()) {
+		return nil
+	}
+	a, err := t.resolve(a)
+	if err != nil {
+		return err
+	}
+	b, err = t.resolve(b)
+	if err != nil {
+		return err
+	}
+	switch {
+	case isEmpty(a) && isEmpty(b):
+		return nil
+	case isEmpty(a):
+		return t.walk(b, path, func(k, v []byte) bool { return cont(k, nil, v) })
+	case isEmpty(b):
+		return t.walk(a, path, func(k, v []byte) bool { return cont(k, v, nil) })
+	}
+	if la, ok := a.(*LeafNode); ok {
+		if lb, ok := b.(*LeafNode); ok {
+			if !bytes.Equal(la.value, lb.value) && !cont(fromNibbles(path), la.value, lb.value) {
+				return errStop
+			}
+			return nil
+		}
+	}
+	if ea, ok := a.(*ExtensionNode); ok {
+		if eb, ok := b.(*ExtensionNode); ok && bytes.Equal(ea.key, eb.key) {
+			return t.diff(ea.next, eb.next, appendPath(path, ea.key...), cont)
+		}
+	}
+	// Nodes of different types are compared child by child, the value child
+	// goes first since its key is the shortest one.
+	ca, cb := children(a), children(b)
+	if err = t.diff(ca[lastChild], cb[lastChild], path, cont); err != nil {
+		return err
+	}
+	for i := 0; i < lastChild; i++ {
+		if err = t.diff(ca[i], cb[i], appendPath(path, byte(i)), cont); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// walk calls f for every key-value pair stored in the subtrie with the root n
+// located at the specified path.
+func (t *Trie) walk(n Node, path []byte, f func(k, v []byte) bool) error {
+	n, err := t.resolve(n)
+	if err != nil {
+		return err
+	}
+	switch n := n.(type) {
+	case *LeafNode:
+		if !f(fromNibbles(path), n.value) {
+			return errStop
+		}
+	case *ExtensionNode:
+		return t.walk(n.next, appendPath(path, n.key...), f)
+	case *BranchNode:
+		if err = t.walk(n.Children[lastChild], path, f); err != nil {
+			return err
+		}
+		for i := 0; i < lastChild; i++ {
+			if err = t.walk(n.Children[i], appendPath(path, byte(i)), f); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// resolve returns the node referenced by the hash node, other nodes are
+// returned as is.
+func (t *Trie) resolve(n Node) (Node, error) {
+	h, ok := n.(*HashNode)
+	if !ok {
+		return n, nil
+	}
+	r, err := t.getFromStore(h.Hash())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get node %s: %w", h.Hash().StringLE(), err)
+	}
+	return r, nil
+}
+
+// children returns the node represented as a branch node children.
+func children(n Node) [childrenCount]Node {
+	var res [childrenCount]Node
+	for i := range res {
+		res[i] = EmptyNode{}
+	}
+	switch n := n.(type) {
+	case *BranchNode:
+		res = n.Children
+	case *ExtensionNode:
+		if len(n.key) == 1 {
+			res[n.key[0]] = n.next
+		} else {
+			res[n.key[0]] = NewExtensionNode(n.key[1:], n.next)
+		}
+	case *LeafNode:
+		res[lastChild] = n
+	}
+	return res
+}
+
+func appendPath(path []byte, suffix ...byte) []byte {
+	res := make([]byte, len(path), len(path)+len(suffix))
+	copy(res, path)
+	return append(res, suffix...)
+}
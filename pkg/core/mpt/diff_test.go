@@ -0,0 +1,113 @@
+package mpt
+
+import (
+	"bytes"
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/nspcc-dev/neo-go/internal/random"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/stretchr/testify/require"
+)
+
+type testDiffItem struct {
+	key, oldValue, newValue []byte
+}
+
+func collectDiff(t *testing.T, tr *Trie, old util.Uint256) []testDiffItem {
+	var res []testDiffItem
+	require.NoError(t, tr.Diff(old, func(k, o, n []byte) bool {
+		res = append(res, testDiffItem{key: k, oldValue: o, newValue: n})
+		return true
+	}))
+	return res
+}
+
+func expectedDiff(m1, m2 map[string][]byte) []testDiffItem {
+	var res []testDiffItem
+	for k, v := range m1 {
+		if n, ok := m2[k]; !ok || !bytes.Equal(v, n) {
+			res = append(res, testDiffItem{key: []byte(k), oldValue: v, newValue: n})
+		}
+	}
+	for k, v := range m2 {
+		if _, ok := m1[k]; !ok {
+			res = append(res, testDiffItem{key: []byte(k), newValue: v})
+		}
+	}
+	sort.Slice(res, func(i, j int) bool { return bytes.Compare(res[i].key, res[j].key) < 0 })
+	return res
+}
+
+func TestTrie_Diff(t *testing.T) {
+	var (
+		m1 = make(map[string][]byte)
+		m2 = make(map[string][]byte)
+		tr = NewTrie(nil, ModeAll, newTestStore())
+	)
+	// Short keys make keys being prefixes of each other and extensions
+	// being split.
+	for i := 0; i < 300; i++ {
+		k := string(random.Bytes(1 + rand.Intn(3)))
+		m1[k] = random.Bytes(1 + rand.Intn(10))
+	}
+	for k, v := range m1 {
+		require.NoError(t, tr.Put([]byte(k), v))
+	}
+	tr.Flush(0)
+	root1 := tr.StateRoot()
+
+	for k, v := range m1 {
+		switch rand.Intn(4) {
+		case 0:
+			require.NoError(t, tr.Delete([]byte(k)))
+		case 1:
+			v = random.Bytes(1 + rand.Intn(10))
+			require.NoError(t, tr.Put([]byte(k), v))
+			m2[k] = v
+		default:
+			m2[k] = v
+		}
+	}
+	for i := 0; i < 100; i++ {
+		k := string(random.Bytes(1 + rand.Intn(4)))
+		if _, ok := m1[k]; ok {
+			continue
+		}
+		m2[k] = random.Bytes(1 + rand.Intn(10))
+		require.NoError(t, tr.Put([]byte(k), m2[k]))
+	}
+	tr.Flush(1)
+	root2 := tr.StateRoot()
+
+	t.Run("forward", func(t *testing.T) {
+		tr := NewTrie(NewHashNode(root2), ModeAll, tr.Store)
+		require.Equal(t, expectedDiff(m1, m2), collectDiff(t, tr, root1))
+	})
+	t.Run("backward", func(t *testing.T) {
+		tr := NewTrie(NewHashNode(root1), ModeAll, tr.Store)
+		require.Equal(t, expectedDiff(m2, m1), collectDiff(t, tr, root2))
+	})
+	t.Run("same", func(t *testing.T) {
+		tr := NewTrie(NewHashNode(root1), ModeAll, tr.Store)
+		require.Nil(t, collectDiff(t, tr, root1))
+	})
+	t.Run("from empty", func(t *testing.T) {
+		tr := NewTrie(NewHashNode(root1), ModeAll, tr.Store)
+		require.Equal(t, expectedDiff(nil, m1), collectDiff(t, tr, util.Uint256{}))
+	})
+	t.Run("stop", func(t *testing.T) {
+		var count int
+		tr := NewTrie(NewHashNode(root2), ModeAll, tr.Store)
+		require.NoError(t, tr.Diff(root1, func(_, _, _ []byte) bool {
+			count++
+			return count < 3
+		}))
+		require.Equal(t, 3, count)
+	})
+	t.Run("missing root", func(t *testing.T) {
+		tr := NewTrie(NewHashNode(root2), ModeAll, tr.Store)
+		require.Error(t, tr.Diff(random.Uint256(), func(_, _, _ []byte) bool { return true }))
+	})
+}
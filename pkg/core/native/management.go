@@ -96,6 +96,12 @@ func MakeContractKey(h util.Uint160) []byte {
 	return makeUint160Key(PrefixContract, h)
 }
 
+// MakeContractIDKey creates a key for the contract hash stored by the contract
+// ID.
+func MakeContractIDKey(id int32) []byte {
+	return putHashKey(make([]byte, 5), id)
+}
+
 // newManagement creates a new Management native contract.
 func newManagement() *Management {
 	var m = &Management{
@@ -521,6 +527,30 @@ func (m *Management) Destroy(d *dao.Simple, hash util.Uint160) error {
 	return nil
 }
 
+// Inject saves the given contract into DAO bypassing all deployment checks.
+// ID (and update counter) of the contract with the same hash is reused if it
+// exists, otherwise a new ID is allocated. It doesn't run _deploy method and
+// doesn't emit notification, so it's only suitable for test invocations.
+func (m *Management) Inject(d *dao.Simple, cs *state.Contract) error {
+	old, err := GetContract(d, cs.Hash)
+	switch {
+	case err == nil && old.ID < 0:
+		return fmt.Errorf("can't replace native contract %s", cs.Hash.StringLE())
+	case err == nil:
+		cs.ID = old.ID
+		cs.UpdateCounter = old.UpdateCounter + 1
+	case errors.Is(err, storage.ErrKeyNotFound):
+		cs.ID, err = m.getNextContractID(d)
+		if err != nil {
+			return err
+		}
+		cs.UpdateCounter = 0
+	default:
+		return err
+	}
+	return putContractState(d, cs, true)
+}
+
 func (m *Management) getMinimumDeploymentFee(ic *interop.Context, args []stackitem.Item) stackitem.Item {
 	return stackitem.NewBigInteger(big.NewInt(m.minimumDeploymentFee(ic.DAO)))
 }
@@ -151,6 +151,19 @@ func (g *GAS) BalanceOf(d *dao.Simple, acc util.Uint160) *big.Int {
 	return g.balanceOfInternal(d, acc)
 }
 
+// SetBalance sets native GAS token balance for the acc bypassing all checks
+// and notifications, total supply is not changed. It's only suitable for
+// test invocations.
+func (g *GAS) SetBalance(d *dao.Simple, acc util.Uint160, amount *big.Int) {
+	key := makeAccountKey(acc)
+	if amount.Sign() == 0 {
+		d.DeleteStorageItem(g.ID, key)
+		return
+	}
+	bal := state.NEP17Balance{Balance: *amount}
+	d.PutStorageItem(g.ID, key, bal.Bytes(nil))
+}
+
 func getStandbyValidatorsHash(ic *interop.Context) (util.Uint160, error) {
 	cfg := ic.Chain.GetConfig()
 	committee, err := keys.NewPublicKeysFromStrings(cfg.StandbyCommittee)
@@ -1291,6 +1291,38 @@ func (n *NEO) BalanceOf(d *dao.Simple, acc util.Uint160) (*big.Int, uint32) {
 	return &st.Balance, st.BalanceHeight
 }
 
+// SetBalance sets native NEO token balance for the acc bypassing all checks
+// and notifications, total supply is not changed while votes of the account
+// are adjusted accordingly. Balance height of a new account is set to the
+// specified height. It's only suitable for test invocations.
+func (n *NEO) SetBalance(d *dao.Simple, acc util.Uint160, amount *big.Int, height uint32) error {
+	key := makeAccountKey(acc)
+	st := &state.NEOBalance{BalanceHeight: height}
+	if si := d.GetStorageItem(n.ID, key); si != nil {
+		var err error
+		st, err = state.NEOBalanceFromBytes(si)
+		if err != nil {
+			return fmt.Errorf("failed to decode NEO balance state: %w", err)
+		}
+	}
+	diff := new(big.Int).Sub(amount, &st.Balance)
+	if err := n.ModifyAccountVotes(st, d, diff, false); err != nil {
+		return err
+	}
+	if st.VoteTo != nil {
+		if err := n.modifyVoterTurnout(d, diff); err != nil {
+			return err
+		}
+	}
+	st.Balance.Set(amount)
+	if amount.Sign() == 0 {
+		d.DeleteStorageItem(n.ID, key)
+		return nil
+	}
+	d.PutStorageItem(n.ID, key, st.Bytes(d.GetItemCtx()))
+	return nil
+}
+
 func pubsToArray(pubs keys.PublicKeys) stackitem.Item {
 	arr := make([]stackitem.Item, len(pubs))
 	for i := range pubs {
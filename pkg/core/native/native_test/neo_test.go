@@ -185,6 +185,77 @@ func TestNEO_CommitteeEvents(t *testing.T) {
 	}
 }
 
+func TestNEO_HistoricCommittee(t *testing.T) {
+	neoCommitteeInvoker := newNeoCommitteeClient(t, 100_0000_0000)
+	neoValidatorsInvoker := neoCommitteeInvoker.WithSigners(neoCommitteeInvoker.Validator)
+	e := neoCommitteeInvoker.Executor
+
+	cfg := e.Chain.GetConfig()
+	committeeSize := cfg.GetCommitteeSize(0)
+
+	type snapshot struct {
+		committee   keys.PublicKeys
+		validators  []*keys.PublicKey
+		enrollments []state.Validator
+	}
+	var snapshots = make(map[uint32]snapshot)
+	takeSnapshot := func(t *testing.T) {
+		committee, err := e.Chain.GetCommittee()
+		require.NoError(t, err)
+		validators, err := e.Chain.GetNextBlockValidators()
+		require.NoError(t, err)
+		enrollments, err := e.Chain.GetEnrollments()
+		require.NoError(t, err)
+		snapshots[e.Chain.BlockHeight()] = snapshot{committee, validators, enrollments}
+	}
+	takeSnapshot(t)
+
+	voters := make([]neotest.Signer, committeeSize)
+	candidates := make([]neotest.Signer, committeeSize)
+	for i := 0; i < committeeSize; i++ {
+		voters[i] = e.NewAccount(t, 10_0000_0000)
+		candidates[i] = e.NewAccount(t, 2000_0000_0000) // enough for one registration
+	}
+	txes := make([]*transaction.Transaction, 0, committeeSize*3)
+	for i := 0; i < committeeSize; i++ {
+		transferTx := neoValidatorsInvoker.PrepareInvoke(t, "transfer", e.Validator.ScriptHash(), voters[i].(neotest.SingleSigner).Account().PrivateKey().GetScriptHash(), int64(committeeSize-i)*1000000, nil)
+		txes = append(txes, transferTx)
+		registerTx := neoValidatorsInvoker.WithSigners(candidates[i]).PrepareInvoke(t, "registerCandidate", candidates[i].(neotest.SingleSigner).Account().PublicKey().Bytes())
+		txes = append(txes, registerTx)
+		voteTx := neoValidatorsInvoker.WithSigners(voters[i]).PrepareInvoke(t, "vote", voters[i].(neotest.SingleSigner).Account().PrivateKey().GetScriptHash(), candidates[i].(neotest.SingleSigner).Account().PublicKey().Bytes())
+		txes = append(txes, voteTx)
+	}
+	block := neoValidatorsInvoker.AddNewBlock(t, txes...)
+	for _, tx := range txes {
+		e.CheckHalt(t, tx.Hash(), stackitem.Make(true))
+	}
+	takeSnapshot(t)
+
+	// Advance the chain to get the committee changed.
+	for block.Index%uint32(committeeSize) != 0 {
+		block = neoCommitteeInvoker.AddNewBlock(t)
+		takeSnapshot(t)
+	}
+	neoCommitteeInvoker.AddNewBlock(t)
+	takeSnapshot(t)
+
+	first, last := snapshots[0], snapshots[e.Chain.BlockHeight()]
+	require.NotEqual(t, first.committee, last.committee)
+	require.NotEqual(t, first.validators, last.validators)
+	require.NotEqual(t, len(first.enrollments), len(last.enrollments))
+	for h, expected := range snapshots {
+		committee, err := e.Chain.GetCommitteeHistoric(h)
+		require.NoError(t, err)
+		require.Equal(t, expected.committee, committee, h)
+		validators, enrollments, err := e.Chain.GetCandidatesHistoric(h)
+		require.NoError(t, err)
+		require.Equal(t, expected.validators, validators, h)
+		require.Equal(t, expected.enrollments, enrollments, h)
+	}
+	_, err := e.Chain.GetCommitteeHistoric(e.Chain.BlockHeight() + 1)
+	require.Error(t, err)
+}
+
 func TestNEO_Vote(t *testing.T) {
 	neoCommitteeInvoker := newNeoCommitteeClient(t, 100_0000_0000)
 	neoValidatorsInvoker := neoCommitteeInvoker.WithSigners(neoCommitteeInvoker.Validator)
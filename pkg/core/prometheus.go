@@ -1,6 +1,9 @@
 package core
 
 import (
+	"errors"
+
+	"github.com/nspcc-dev/neo-go/pkg/core/mempool"
 	"github.com/prometheus/client_golang/prometheus"
 )
 
@@ -38,6 +41,15 @@ var (
 			Namespace: "neogo",
 		},
 	)
+	// mempoolRejectedTx prometheus metric.
+	mempoolRejectedTx = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Help:      "Number of transactions rejected by mempool",
+			Name:      "mempool_rejected_tx",
+			Namespace: "neogo",
+		},
+		[]string{"reason"},
+	)
 )
 
 func init() {
@@ -46,6 +58,7 @@ func init() {
 		persistedHeight,
 		headerHeight,
 		mempoolUnsortedTx,
+		mempoolRejectedTx,
 	)
 }
 
@@ -65,3 +78,28 @@ func updateBlockHeightMetric(bHeight uint32) {
 func updateMempoolMetrics(unsortedTxnLen int) {
 	mempoolUnsortedTx.Set(float64(unsortedTxnLen))
 }
+
+// updateMempoolRejectionMetrics updates metric of the number of transactions
+// rejected by the mempool with the given error.
+func updateMempoolRejectionMetrics(err error) {
+	var reason string
+	switch {
+	case errors.Is(err, mempool.ErrDup):
+		reason = "duplicate"
+	case errors.Is(err, mempool.ErrInsufficientFunds):
+		reason = "insufficient_funds"
+	case errors.Is(err, mempool.ErrConflict):
+		reason = "pooled_fees"
+	case errors.Is(err, mempool.ErrOOM):
+		reason = "capacity"
+	case errors.Is(err, mempool.ErrSenderTxLimit):
+		reason = "sender_tx_limit"
+	case errors.Is(err, mempool.ErrSenderSizeLimit):
+		reason = "sender_size_limit"
+	case errors.Is(err, mempool.ErrConflictsAttribute), errors.Is(err, mempool.ErrOracleResponse):
+		reason = "conflict"
+	default:
+		reason = "other"
+	}
+	mempoolRejectedTx.WithLabelValues(reason).Inc()
+}
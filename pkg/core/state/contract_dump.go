@@ -0,0 +1,106 @@
+package state
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/nspcc-dev/neo-go/pkg/io"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/nspcc-dev/neo-go/pkg/vm/stackitem"
+)
+
+type (
+	// ContractDump is a contract state along with all of its storage items
+	// at some height of some network. It allows to recreate the contract at
+	// the same hash and with the same storage in another chain. ContractDump
+	// can be encoded either in JSON or in binary form, DecodeContractDump
+	// handles both.
+	ContractDump struct {
+		Height    uint32       `json:"height"`
+		StateRoot util.Uint256 `json:"stateroot"`
+		Contract  Contract     `json:"contract"`
+		// Storage is sorted by key.
+		Storage []ContractDumpItem `json:"storage"`
+	}
+
+	// ContractDumpItem is a single contract storage item, Key doesn't include
+	// contract ID.
+	ContractDumpItem struct {
+		Key   []byte `json:"key"`
+		Value []byte `json:"value"`
+	}
+)
+
+// DecodeContractDump decodes ContractDump from the given data in either JSON
+// or binary form.
+func DecodeContractDump(data []byte) (*ContractDump, error) {
+	var d = new(ContractDump)
+	if json.Valid(data) {
+		if err := json.Unmarshal(data, d); err != nil {
+			return nil, err
+		}
+	} else {
+		r := io.NewBinReaderFromBuf(data)
+		d.DecodeBinary(r)
+		if r.Err != nil {
+			return nil, r.Err
+		}
+	}
+	return d, d.Validate()
+}
+
+// Validate checks that the dump contains a valid non-native contract.
+func (d *ContractDump) Validate() error {
+	if d.Contract.ID <= 0 {
+		return fmt.Errorf("contract %s is native or has invalid ID %d", d.Contract.Hash.StringLE(), d.Contract.ID)
+	}
+	if err := d.Contract.Manifest.IsValid(d.Contract.Hash, true); err != nil {
+		return fmt.Errorf("contract %s: invalid manifest: %w", d.Contract.Hash.StringLE(), err)
+	}
+	for i := range d.Storage {
+		if d.Storage[i].Value == nil {
+			return fmt.Errorf("contract %s: storage item %d has no value", d.Contract.Hash.StringLE(), i)
+		}
+	}
+	return nil
+}
+
+// EncodeBinary implements the io.Serializable interface.
+func (d *ContractDump) EncodeBinary(w *io.BinWriter) {
+	w.WriteU32LE(d.Height)
+	d.StateRoot.EncodeBinary(w)
+	cs, err := stackitem.SerializeConvertible(&d.Contract)
+	if err != nil {
+		w.Err = err
+		return
+	}
+	w.WriteVarBytes(cs)
+	w.WriteArray(d.Storage)
+}
+
+// DecodeBinary implements the io.Serializable interface.
+func (d *ContractDump) DecodeBinary(r *io.BinReader) {
+	d.Height = r.ReadU32LE()
+	d.StateRoot.DecodeBinary(r)
+	cs := r.ReadVarBytes()
+	if r.Err != nil {
+		return
+	}
+	if err := stackitem.DeserializeConvertible(cs, &d.Contract); err != nil {
+		r.Err = fmt.Errorf("invalid contract state: %w", err)
+		return
+	}
+	r.ReadArray(&d.Storage)
+}
+
+// EncodeBinary implements the io.Serializable interface.
+func (i *ContractDumpItem) EncodeBinary(w *io.BinWriter) {
+	w.WriteVarBytes(i.Key)
+	w.WriteVarBytes(i.Value)
+}
+
+// DecodeBinary implements the io.Serializable interface.
+func (i *ContractDumpItem) DecodeBinary(r *io.BinReader) {
+	i.Key = r.ReadVarBytes()
+	i.Value = r.ReadVarBytes()
+}
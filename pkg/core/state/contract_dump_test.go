@@ -0,0 +1,88 @@
+package state
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/nspcc-dev/neo-go/internal/random"
+	"github.com/nspcc-dev/neo-go/pkg/crypto/hash"
+	"github.com/nspcc-dev/neo-go/pkg/io"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract/manifest"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract/nef"
+	"github.com/stretchr/testify/require"
+)
+
+func TestContractDump(t *testing.T) {
+	script := []byte{0x40}
+	d := &ContractDump{
+		Height:    123,
+		StateRoot: random.Uint256(),
+		Contract: Contract{
+			UpdateCounter: 2,
+			ContractBase: ContractBase{
+				ID:   5,
+				Hash: hash.Hash160(script),
+				NEF: nef.File{
+					Header:   nef.Header{Magic: nef.Magic, Compiler: "neo-go.test-test"},
+					Tokens:   []nef.MethodToken{},
+					Script:   script,
+					Checksum: 0,
+				},
+				Manifest: *manifest.NewManifest("Test"),
+			},
+		},
+		Storage: []ContractDumpItem{
+			{Key: []byte{1}, Value: []byte{2, 3}},
+			{Key: []byte{4, 5}, Value: []byte{}},
+		},
+	}
+	d.Contract.NEF.Checksum = d.Contract.NEF.CalculateChecksum()
+	d.Contract.Manifest.ABI.Methods = []manifest.Method{{Name: "main", Parameters: []manifest.Parameter{}, ReturnType: smartcontract.VoidType}}
+
+	t.Run("JSON", func(t *testing.T) {
+		data, err := json.Marshal(d)
+		require.NoError(t, err)
+		actual, err := DecodeContractDump(data)
+		require.NoError(t, err)
+		require.Equal(t, d, actual)
+	})
+
+	t.Run("binary", func(t *testing.T) {
+		w := io.NewBufBinWriter()
+		d.EncodeBinary(w.BinWriter)
+		require.NoError(t, w.Err)
+		data := w.Bytes()
+		actual, err := DecodeContractDump(data)
+		require.NoError(t, err)
+		require.Equal(t, d, actual)
+
+		_, err = DecodeContractDump(data[:len(data)-1])
+		require.Error(t, err)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		check := func(t *testing.T, f func(d *ContractDump)) {
+			bad := *d
+			bad.Storage = append([]ContractDumpItem{}, d.Storage...)
+			f(&bad)
+			data, err := json.Marshal(bad)
+			require.NoError(t, err)
+			_, err = DecodeContractDump(data)
+			require.Error(t, err)
+		}
+		t.Run("native", func(t *testing.T) {
+			check(t, func(d *ContractDump) { d.Contract.ID = -1 })
+		})
+		t.Run("manifest", func(t *testing.T) {
+			check(t, func(d *ContractDump) { d.Contract.Manifest.Name = "" })
+		})
+		t.Run("no value", func(t *testing.T) {
+			check(t, func(d *ContractDump) { d.Storage[0].Value = nil })
+		})
+		t.Run("bad JSON", func(t *testing.T) {
+			_, err := DecodeContractDump([]byte(`{"height":"123"}`))
+			require.Error(t, err)
+		})
+	})
+}
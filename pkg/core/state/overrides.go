@@ -0,0 +1,53 @@
+package state
+
+import (
+	"math/big"
+
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract/manifest"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+)
+
+type (
+	// Overrides is a set of state modifications applied to the state before
+	// the test invocation. Contracts are injected first, so storage overrides
+	// can refer to them.
+	Overrides struct {
+		Contracts []ContractOverride `json:"contracts,omitempty"`
+		Storage   []StorageOverride  `json:"storage,omitempty"`
+		Balances  []BalanceOverride  `json:"balances,omitempty"`
+		Block     *BlockOverride     `json:"block,omitempty"`
+	}
+
+	// ContractOverride puts the contract with the given NEF and manifest at
+	// the given hash, the contract existing at this hash (if any) is replaced
+	// with its storage kept intact.
+	ContractOverride struct {
+		Hash util.Uint160 `json:"hash"`
+		// NEF is a serialized NEF file.
+		NEF      []byte            `json:"nef"`
+		Manifest manifest.Manifest `json:"manifest"`
+	}
+
+	// StorageOverride replaces the value of the contract storage item, nil
+	// Value deletes the item.
+	StorageOverride struct {
+		Hash  util.Uint160 `json:"hash"`
+		Key   []byte       `json:"key"`
+		Value []byte       `json:"value"`
+	}
+
+	// BalanceOverride sets the balance of the account for the native NEO or
+	// GAS token.
+	BalanceOverride struct {
+		Asset   util.Uint160 `json:"asset"`
+		Account util.Uint160 `json:"account"`
+		Amount  *big.Int     `json:"amount"`
+	}
+
+	// BlockOverride replaces the index and/or timestamp of the block the
+	// invocation is performed in.
+	BlockOverride struct {
+		Index     *uint32 `json:"index,omitempty"`
+		Timestamp *uint64 `json:"timestamp,omitempty"`
+	}
+)
@@ -0,0 +1,42 @@
+package state
+
+import (
+	"github.com/nspcc-dev/neo-go/pkg/util"
+)
+
+// Storage change types used in StorageChange.
+const (
+	StorageAdded    = "added"
+	StorageModified = "modified"
+	StorageDeleted  = "deleted"
+)
+
+type (
+	// StateDiff is a set of contract storage changes made by a block.
+	StateDiff struct {
+		Index uint32 `json:"index"`
+		// PrevRoot is the state root of the previous block, it's zero
+		// for the genesis block.
+		PrevRoot util.Uint256 `json:"prevstateroot"`
+		Root     util.Uint256 `json:"stateroot"`
+		// Contracts are sorted by ID.
+		Contracts []ContractStorageDiff `json:"contracts"`
+	}
+
+	// ContractStorageDiff is a set of storage changes of a single contract.
+	ContractStorageDiff struct {
+		ID int32 `json:"id"`
+		// Changes are sorted by key.
+		Changes []StorageChange `json:"changes"`
+	}
+
+	// StorageChange is a change of a single storage item, OldValue is nil
+	// for the added items and NewValue is nil for the deleted ones.
+	StorageChange struct {
+		// Type is one of Storage* constants.
+		Type     string `json:"type"`
+		Key      []byte `json:"key"`
+		OldValue []byte `json:"oldvalue,omitempty"`
+		NewValue []byte `json:"newvalue,omitempty"`
+	}
+)
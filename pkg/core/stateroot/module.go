@@ -119,6 +119,19 @@ func (s *Module) SeekStates(root util.Uint256, prefix []byte, cont func(k, v []b
 	})
 }
 
+// DiffStates compares the MPT with the specified root to the one with the old
+// root and calls cont for every changed key (see mpt.Trie.Diff for details).
+// Traversal process is stopped when `false` is returned from `cont`.
+func (s *Module) DiffStates(old, root util.Uint256, cont func(key, oldValue, newValue []byte) bool) error {
+	var r mpt.Node
+	if !root.Equals(util.Uint256{}) {
+		r = mpt.NewHashNode(root)
+	}
+	// Allow accessing old values, it's RO thing.
+	tr := mpt.NewTrie(r, s.mode&^mpt.ModeGCFlag, storage.NewMemCachedStore(s.Store))
+	return tr.Diff(old, cont)
+}
+
 // GetStateProof returns proof of having key in the MPT with the specified root.
 func (s *Module) GetStateProof(root util.Uint256, key []byte) ([][]byte, error) {
 	// Allow accessing old values, it's RO thing.
@@ -194,7 +207,11 @@ func (s *Module) Init(height uint32) error {
 	}
 	s.currentLocal.Store(r.Root)
 	s.localHeight.Store(r.Index)
-	s.mpt = mpt.NewTrie(mpt.NewHashNode(r.Root), s.mode, s.Store)
+	var root mpt.Node
+	if !r.Root.Equals(util.Uint256{}) {
+		root = mpt.NewHashNode(r.Root)
+	}
+	s.mpt = mpt.NewTrie(root, s.mode, s.Store)
 	return nil
 }
 
@@ -10,6 +10,18 @@ type (
 		Type           string         `yaml:"Type"`
 		LevelDBOptions LevelDBOptions `yaml:"LevelDBOptions"`
 		BoltDBOptions  BoltDBOptions  `yaml:"BoltDBOptions"`
+		// StateDB is an optional separate DB for the mutable chain state
+		// (contract storage, MPT, token transfer logs). If its Type is
+		// empty, everything is stored in the main DB, otherwise the main
+		// DB only keeps blocks, transactions and headers.
+		StateDB StateDBConfiguration `yaml:"StateDB"`
+	}
+	// StateDBConfiguration describes configuration for the state DB, it has
+	// the same options as DBConfiguration.
+	StateDBConfiguration struct {
+		Type           string         `yaml:"Type"`
+		LevelDBOptions LevelDBOptions `yaml:"LevelDBOptions"`
+		BoltDBOptions  BoltDBOptions  `yaml:"BoltDBOptions"`
 	}
 	// LevelDBOptions configuration for LevelDB.
 	LevelDBOptions struct {
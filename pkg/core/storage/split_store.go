@@ -0,0 +1,153 @@
+package storage
+
+import (
+	"errors"
+)
+
+// SplitStore is a Store that keeps immutable chain data (blocks, transactions,
+// headers) and mutable chain state (contract storage, MPT, token transfer logs
+// and the current block pointer) in two different Stores. Keys are routed to
+// the appropriate Store by their prefix, so the chain data Store can be left
+// intact when the state is dropped and the state can then be rebuilt from the
+// blocks stored.
+type SplitStore struct {
+	chain Store
+	state Store
+}
+
+// NewSplitStore creates a SplitStore using chain for the chain data and state
+// for the chain state.
+func NewSplitStore(chain Store, state Store) *SplitStore {
+	return &SplitStore{
+		chain: chain,
+		state: state,
+	}
+}
+
+// IsStateKey returns true if the key belongs to the chain state (the part
+// that can be recalculated from blocks) and false if it's the chain data.
+func IsStateKey(key []byte) bool {
+	if len(key) == 0 {
+		return false
+	}
+	switch KeyPrefix(key[0]) {
+	case DataMPT, DataMPTAux, STStorage, STTempStorage, STNEP11Transfers,
+		STNEP17Transfers, STTokenTransferInfo, SYSCurrentBlock,
+		SYSStateSyncCurrentBlockHeight, SYSStateSyncPoint, SYSStateChangeStage,
+		SYSFork:
+		return true
+	default:
+		return false
+	}
+}
+
+func (s *SplitStore) chooseStore(key []byte) Store {
+	if IsStateKey(key) {
+		return s.state
+	}
+	return s.chain
+}
+
+// Get implements the Store interface.
+func (s *SplitStore) Get(key []byte) ([]byte, error) {
+	return s.chooseStore(key).Get(key)
+}
+
+// PutChangeSet implements the Store interface. Changes are not atomic across
+// the two Stores, chain data is saved first, so the state never references
+// blocks that are not stored. If the state is not saved the Blockchain
+// detects that it's behind the stored blocks on start and processes them
+// again.
+func (s *SplitStore) PutChangeSet(puts map[string][]byte, stor map[string][]byte) error {
+	var (
+		chainPuts = make(map[string][]byte)
+		chainStor = make(map[string][]byte)
+		statePuts = make(map[string][]byte)
+		stateStor = make(map[string][]byte)
+	)
+	for k, v := range puts {
+		if IsStateKey([]byte(k)) {
+			statePuts[k] = v
+		} else {
+			chainPuts[k] = v
+		}
+	}
+	for k, v := range stor {
+		if IsStateKey([]byte(k)) {
+			stateStor[k] = v
+		} else {
+			chainStor[k] = v
+		}
+	}
+	if len(chainPuts) != 0 || len(chainStor) != 0 {
+		if err := s.chain.PutChangeSet(chainPuts, chainStor); err != nil {
+			return err
+		}
+	}
+	if len(statePuts) != 0 || len(stateStor) != 0 {
+		return s.state.PutChangeSet(statePuts, stateStor)
+	}
+	return nil
+}
+
+// Seek implements the Store interface. Seeking with an empty prefix is
+// performed over both Stores with keys properly ordered.
+func (s *SplitStore) Seek(rng SeekRange, f func(k, v []byte) bool) {
+	if len(rng.Prefix) != 0 {
+		s.chooseStore(rng.Prefix).Seek(rng, f)
+		return
+	}
+	var cont = true
+	s.forEachPrefix(rng, func(st Store, r SeekRange) {
+		st.Seek(r, func(k, v []byte) bool {
+			cont = f(k, v)
+			return cont
+		})
+	}, func() bool { return cont })
+}
+
+// SeekGC implements the Store interface.
+func (s *SplitStore) SeekGC(rng SeekRange, keep func(k, v []byte) bool) error {
+	if len(rng.Prefix) != 0 {
+		return s.chooseStore(rng.Prefix).SeekGC(rng, keep)
+	}
+	var err error
+	s.forEachPrefix(rng, func(st Store, r SeekRange) {
+		err = st.SeekGC(r, keep)
+	}, func() bool { return err == nil })
+	return err
+}
+
+// forEachPrefix splits the empty-prefix range into single-byte prefix ones
+// and calls f for every one of them (in the range order) with the Store the
+// prefix belongs to while next returns true.
+func (s *SplitStore) forEachPrefix(rng SeekRange, f func(Store, SeekRange), next func() bool) {
+	for i := 0; i < 256; i++ {
+		var b = byte(i)
+		if rng.Backwards {
+			b = byte(255 - i)
+		}
+		var r = SeekRange{
+			Prefix:      []byte{b},
+			Backwards:   rng.Backwards,
+			SearchDepth: rng.SearchDepth,
+		}
+		if len(rng.Start) != 0 {
+			switch {
+			case b == rng.Start[0]:
+				r.Start = rng.Start[1:]
+			case !rng.Backwards && b < rng.Start[0], rng.Backwards && b > rng.Start[0]:
+				continue
+			}
+		}
+		f(s.chooseStore(r.Prefix), r)
+		if !next() {
+			return
+		}
+	}
+}
+
+// Close implements the Store interface, it closes both Stores.
+func (s *SplitStore) Close() error {
+	return errors.Join(s.chain.Close(), s.state.Close())
+}
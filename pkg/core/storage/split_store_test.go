@@ -0,0 +1,74 @@
+package storage
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newSplitStoreForTesting(t testing.TB) Store {
+	return NewSplitStore(NewMemoryStore(), NewMemoryStore())
+}
+
+func TestSplitStore(t *testing.T) {
+	var (
+		chain    = NewMemoryStore()
+		state    = NewMemoryStore()
+		s        = NewSplitStore(chain, state)
+		blockKey = []byte{byte(DataExecutable), 1}
+		stateKey = []byte{byte(STStorage), 1}
+		mptKey   = []byte{byte(DataMPT), 1}
+		curKey   = []byte{byte(SYSCurrentBlock)}
+		hdrKey   = []byte{byte(SYSCurrentHeader)}
+	)
+	require.NoError(t, s.PutChangeSet(map[string][]byte{
+		string(blockKey): {1},
+		string(mptKey):   {2},
+		string(curKey):   {3},
+		string(hdrKey):   {4},
+	}, map[string][]byte{
+		string(stateKey): {5},
+	}))
+	for _, k := range [][]byte{blockKey, hdrKey} {
+		_, err := chain.Get(k)
+		require.NoError(t, err)
+		_, err = state.Get(k)
+		require.ErrorIs(t, err, ErrKeyNotFound)
+	}
+	for _, k := range [][]byte{mptKey, curKey, stateKey} {
+		_, err := state.Get(k)
+		require.NoError(t, err)
+		_, err = chain.Get(k)
+		require.ErrorIs(t, err, ErrKeyNotFound)
+	}
+	v, err := s.Get(stateKey)
+	require.NoError(t, err)
+	require.Equal(t, []byte{5}, v)
+
+	var collect = func(rng SeekRange) [][]byte {
+		var res [][]byte
+		s.Seek(rng, func(k, _ []byte) bool {
+			res = append(res, bytes.Clone(k))
+			return true
+		})
+		return res
+	}
+	require.Equal(t, [][]byte{blockKey, mptKey, stateKey, curKey, hdrKey}, collect(SeekRange{}))
+	require.Equal(t, [][]byte{hdrKey, curKey, stateKey, mptKey, blockKey}, collect(SeekRange{Backwards: true}))
+	require.Equal(t, [][]byte{stateKey, curKey, hdrKey}, collect(SeekRange{Start: []byte{byte(STStorage)}}))
+	require.Equal(t, [][]byte{stateKey, mptKey, blockKey}, collect(SeekRange{Start: []byte{byte(STStorage), 1}, Backwards: true}))
+
+	var n int
+	s.Seek(SeekRange{}, func(_, _ []byte) bool {
+		n++
+		return n < 2
+	})
+	require.Equal(t, 2, n)
+
+	require.NoError(t, s.SeekGC(SeekRange{}, func(k, _ []byte) bool {
+		return !IsStateKey(k)
+	}))
+	require.Equal(t, [][]byte{blockKey, hdrKey}, collect(SeekRange{}))
+	require.NoError(t, s.Close())
+}
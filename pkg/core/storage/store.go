@@ -37,7 +37,10 @@ const (
 	// and the last bit reserved for the state reset process marker (set to 1 on
 	// unfinished state reset and to 0 on unfinished state jump).
 	SYSStateChangeStage KeyPrefix = 0xc4
-	SYSVersion          KeyPrefix = 0xf0
+	// SYSFork is used by the forked chain storage to keep the fork point
+	// and the data needed to cache the remote network state.
+	SYSFork    KeyPrefix = 0xc5
+	SYSVersion KeyPrefix = 0xf0
 )
 
 // Executable subtypes.
@@ -121,18 +124,33 @@ func seekRangeToPrefixes(sr SeekRange) *util.Range {
 }
 
 // NewStore creates storage with preselected in configuration database type.
+// If a separate state DB is configured, a SplitStore over both databases is
+// returned.
 func NewStore(cfg dbconfig.DBConfiguration) (Store, error) {
+	store, err := newStore(cfg.Type, cfg.LevelDBOptions, cfg.BoltDBOptions)
+	if err != nil || cfg.StateDB.Type == "" {
+		return store, err
+	}
+	stateStore, err := newStore(cfg.StateDB.Type, cfg.StateDB.LevelDBOptions, cfg.StateDB.BoltDBOptions)
+	if err != nil {
+		_ = store.Close()
+		return nil, fmt.Errorf("state DB: %w", err)
+	}
+	return NewSplitStore(store, stateStore), nil
+}
+
+func newStore(typ string, levelOpts dbconfig.LevelDBOptions, boltOpts dbconfig.BoltDBOptions) (Store, error) {
 	var store Store
 	var err error
-	switch cfg.Type {
+	switch typ {
 	case dbconfig.LevelDB:
-		store, err = NewLevelDBStore(cfg.LevelDBOptions)
+		store, err = NewLevelDBStore(levelOpts)
 	case dbconfig.InMemoryDB:
 		store = NewMemoryStore()
 	case dbconfig.BoltDB:
-		store, err = NewBoltDBStore(cfg.BoltDBOptions)
+		store, err = NewBoltDBStore(boltOpts)
 	default:
-		return nil, fmt.Errorf("unknown storage: %s", cfg.Type)
+		return nil, fmt.Errorf("unknown storage: %s", typ)
 	}
 	return store, err
 }
@@ -27,3 +27,27 @@ func TestStorageNames(t *testing.T) {
 		})
 	}
 }
+
+func TestStorageStateDB(t *testing.T) {
+	tmp := t.TempDir()
+	cfg := dbconfig.DBConfiguration{
+		Type: dbconfig.LevelDB,
+		LevelDBOptions: dbconfig.LevelDBOptions{
+			DataDirectoryPath: filepath.Join(tmp, "chain"),
+		},
+		StateDB: dbconfig.StateDBConfiguration{
+			Type: dbconfig.BoltDB,
+			BoltDBOptions: dbconfig.BoltDBOptions{
+				FilePath: filepath.Join(tmp, "state.bolt"),
+			},
+		},
+	}
+	s, err := NewStore(cfg)
+	require.NoError(t, err)
+	require.IsType(t, &SplitStore{}, s)
+	require.NoError(t, s.Close())
+
+	cfg.StateDB.Type = "unknown"
+	_, err = NewStore(cfg)
+	require.ErrorContains(t, err, "state DB")
+}
@@ -1,21 +1,59 @@
 package core
 
 import (
+	"encoding/json"
 	"fmt"
+	"os"
 	"time"
 
 	"github.com/nspcc-dev/neo-go/pkg/config"
 	"github.com/nspcc-dev/neo-go/pkg/core/block"
+	"github.com/nspcc-dev/neo-go/pkg/core/native"
+	"github.com/nspcc-dev/neo-go/pkg/core/native/nativehashes"
+	"github.com/nspcc-dev/neo-go/pkg/core/state"
 	"github.com/nspcc-dev/neo-go/pkg/core/transaction"
 	"github.com/nspcc-dev/neo-go/pkg/crypto/hash"
 	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
+	"github.com/nspcc-dev/neo-go/pkg/encoding/fixedn"
+	"github.com/nspcc-dev/neo-go/pkg/io"
 	"github.com/nspcc-dev/neo-go/pkg/smartcontract"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract/callflag"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract/manifest"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract/nef"
 	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/nspcc-dev/neo-go/pkg/vm/emit"
 	"github.com/nspcc-dev/neo-go/pkg/vm/opcode"
 )
 
+const (
+	// genesisTransferFee is the system fee reserved for every genesis
+	// allocation transfer.
+	genesisTransferFee = 1_00000000
+	// genesisMinDeploymentFee is the default minimum contract deployment fee
+	// of the native ContractManagement contract.
+	genesisMinDeploymentFee = 10_00000000
+	// genesisDeployExecFee is the system fee reserved for every genesis
+	// contract _deploy method execution.
+	genesisDeployExecFee = 10_00000000
+	// genesisDumpHashFee is the system fee reserved for every contract dump
+	// hash pushed by the genesis script.
+	genesisDumpHashFee = 1000000
+)
+
 // CreateGenesisBlock creates a genesis block based on the given configuration.
+// Genesis contract dumps are read from the configured files, so that they
+// affect the block hash.
 func CreateGenesisBlock(cfg config.ProtocolConfiguration) (*block.Block, error) {
+	dumps, err := loadContractDumps(cfg.Genesis.ContractDumps)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load genesis contracts: %w", err)
+	}
+	return createGenesisBlock(cfg, dumps)
+}
+
+// createGenesisBlock creates a genesis block based on the given configuration
+// and contract dumps to be imported.
+func createGenesisBlock(cfg config.ProtocolConfiguration, dumps []*state.ContractDump) (*block.Block, error) {
 	validators, committee, err := validatorsFromConfig(cfg)
 	if err != nil {
 		return nil, err
@@ -27,12 +65,22 @@ func CreateGenesisBlock(cfg config.ProtocolConfiguration) (*block.Block, error)
 	}
 
 	txs := []*transaction.Transaction{}
-	if cfg.Genesis.Transaction != nil {
+	script, sysFee, err := createGenesisScript(cfg.Genesis, dumps, nextConsensus)
+	if err != nil {
+		return nil, err
+	}
+	if tx := cfg.Genesis.Transaction; tx != nil {
+		script = append(script, tx.Script...)
+		sysFee += tx.SystemFee
+	}
+	if err := checkGenesisGAS(cfg, sysFee); err != nil {
+		return nil, err
+	}
+	if len(script) != 0 {
 		committeeH, err := getCommitteeAddress(committee)
 		if err != nil {
 			return nil, fmt.Errorf("failed to calculate committee address: %w", err)
 		}
-		tx := cfg.Genesis.Transaction
 		signers := []transaction.Signer{
 			{
 				Account: nextConsensus,
@@ -61,9 +109,9 @@ func CreateGenesisBlock(cfg config.ProtocolConfiguration) (*block.Block, error)
 		}
 
 		txs = append(txs, &transaction.Transaction{
-			SystemFee:       tx.SystemFee,
+			SystemFee:       sysFee,
 			ValidUntilBlock: 1,
-			Script:          tx.Script,
+			Script:          script,
 			Signers:         signers,
 			Scripts:         scripts,
 		})
@@ -92,6 +140,133 @@ func CreateGenesisBlock(cfg config.ProtocolConfiguration) (*block.Block, error)
 	return b, nil
 }
 
+// checkGenesisGAS checks that the standby validators account has enough GAS
+// for genesis allocations and the genesis transaction system fee.
+func checkGenesisGAS(cfg config.ProtocolConfiguration, sysFee int64) error {
+	var (
+		gas        int64
+		initialGAS = int64(cfg.InitialGASSupply)
+	)
+	if initialGAS <= 0 {
+		initialGAS = DefaultInitialGAS
+	}
+	for _, a := range cfg.Genesis.Allocations {
+		gas += int64(a.GAS)
+	}
+	if gas+sysFee > initialGAS {
+		return fmt.Errorf("genesis GAS allocations (%s) and system fee (%s) exceed InitialGASSupply (%s)",
+			fixedn.Fixed8(gas), fixedn.Fixed8(sysFee), fixedn.Fixed8(initialGAS))
+	}
+	return nil
+}
+
+// createGenesisScript creates a script transferring genesis allocations from
+// the sender and deploying genesis contracts on its behalf. Hashes of the
+// imported contract dumps are pushed (and dropped) by this script to make
+// them a part of the genesis block. It also returns the system fee needed to
+// run this script.
+func createGenesisScript(g config.Genesis, dumps []*state.ContractDump, sender util.Uint160) ([]byte, int64, error) {
+	var (
+		sysFee int64
+		w      = io.NewBufBinWriter()
+		hashes = make(map[util.Uint160]bool, len(g.Contracts)+len(dumps))
+	)
+	for _, d := range dumps {
+		hashes[d.Contract.Hash] = true
+		buf := io.NewBufBinWriter()
+		d.EncodeBinary(buf.BinWriter)
+		if buf.Err != nil {
+			return nil, 0, fmt.Errorf("contract dump %s: %w", d.Contract.Hash.StringLE(), buf.Err)
+		}
+		emit.Bytes(w.BinWriter, hash.Sha256(buf.Bytes()).BytesBE())
+		emit.Opcodes(w.BinWriter, opcode.DROP)
+		sysFee += genesisDumpHashFee
+	}
+	for _, a := range g.Allocations {
+		for _, asset := range []struct {
+			hash   util.Uint160
+			amount int64
+		}{{nativehashes.NeoToken, a.NEO}, {nativehashes.GasToken, int64(a.GAS)}} {
+			if asset.amount == 0 {
+				continue
+			}
+			emit.AppCall(w.BinWriter, asset.hash, "transfer", callflag.All, sender, a.Address, asset.amount, nil)
+			emit.Opcodes(w.BinWriter, opcode.ASSERT)
+			sysFee += genesisTransferFee
+		}
+	}
+	for i, c := range g.Contracts {
+		nefBytes, err := os.ReadFile(c.NEF)
+		if err != nil {
+			return nil, 0, fmt.Errorf("genesis contract #%d: %w", i, err)
+		}
+		nefFile, err := nef.FileFromBytes(nefBytes)
+		if err != nil {
+			return nil, 0, fmt.Errorf("genesis contract #%d: invalid NEF file: %w", i, err)
+		}
+		manifestBytes, err := os.ReadFile(c.Manifest)
+		if err != nil {
+			return nil, 0, fmt.Errorf("genesis contract #%d: %w", i, err)
+		}
+		m := new(manifest.Manifest)
+		if err := json.Unmarshal(manifestBytes, m); err != nil {
+			return nil, 0, fmt.Errorf("genesis contract #%d: invalid manifest: %w", i, err)
+		}
+		h := state.CreateContractHash(sender, nefFile.Checksum, m.Name)
+		if err := m.IsValid(h, true); err != nil {
+			return nil, 0, fmt.Errorf("genesis contract #%d: invalid manifest: %w", i, err)
+		}
+		if hashes[h] {
+			return nil, 0, fmt.Errorf("genesis contract #%d: contract %s is deployed twice or imported from a dump", i, h.StringLE())
+		}
+		hashes[h] = true
+		data, err := c.DeployData()
+		if err != nil {
+			return nil, 0, fmt.Errorf("genesis contract #%d: invalid data: %w", i, err)
+		}
+		params := []any{nefBytes, manifestBytes}
+		if data != nil {
+			params = append(params, data)
+		}
+		emit.AppCall(w.BinWriter, nativehashes.ContractManagement, "deploy", callflag.All, params...)
+		emit.Opcodes(w.BinWriter, opcode.DROP)
+		deployFee := native.DefaultStoragePrice * int64(len(nefBytes)+len(manifestBytes))
+		if deployFee < genesisMinDeploymentFee {
+			deployFee = genesisMinDeploymentFee
+		}
+		sysFee += deployFee + genesisDeployExecFee
+	}
+	if w.Err != nil {
+		return nil, 0, w.Err
+	}
+	return w.Bytes(), sysFee, nil
+}
+
+// loadContractDumps reads contract dumps from the given files, every contract
+// can only be imported once.
+func loadContractDumps(paths []string) ([]*state.ContractDump, error) {
+	var (
+		dumps  = make([]*state.ContractDump, 0, len(paths))
+		hashes = make(map[util.Uint160]bool, len(paths))
+	)
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		d, err := state.DecodeContractDump(data)
+		if err != nil {
+			return nil, fmt.Errorf("invalid contract dump %s: %w", path, err)
+		}
+		if hashes[d.Contract.Hash] {
+			return nil, fmt.Errorf("contract %s is imported twice", d.Contract.Hash.StringLE())
+		}
+		hashes[d.Contract.Hash] = true
+		dumps = append(dumps, d)
+	}
+	return dumps, nil
+}
+
 func validatorsFromConfig(cfg config.ProtocolConfiguration) ([]*keys.PublicKey, []*keys.PublicKey, error) {
 	vs, err := keys.NewPublicKeysFromStrings(cfg.StandbyCommittee)
 	if err != nil {
@@ -1,11 +1,20 @@
 package core
 
 import (
+	"encoding/json"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/nspcc-dev/neo-go/pkg/config"
 	"github.com/nspcc-dev/neo-go/pkg/config/netmode"
+	"github.com/nspcc-dev/neo-go/pkg/core/state"
 	"github.com/nspcc-dev/neo-go/pkg/encoding/address"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract/manifest"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract/nef"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/nspcc-dev/neo-go/pkg/vm/opcode"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -39,3 +48,34 @@ func TestGetConsensusAddressMainNet(t *testing.T) {
 	assert.Equal(t, consensusScript, script.String())
 	assert.Equal(t, consensusAddr, address.Uint160ToString(script))
 }
+
+func TestCreateGenesisScriptDumpCollision(t *testing.T) {
+	ne, err := nef.NewFile([]byte{byte(opcode.RET)})
+	require.NoError(t, err)
+	nefBytes, err := ne.Bytes()
+	require.NoError(t, err)
+	m := manifest.NewManifest("Test")
+	m.ABI.Methods = []manifest.Method{{Name: "main", ReturnType: smartcontract.VoidType}}
+	manifestBytes, err := json.Marshal(m)
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	g := config.Genesis{Contracts: []config.GenesisContract{{
+		NEF:      filepath.Join(dir, "test.nef"),
+		Manifest: filepath.Join(dir, "test.manifest.json"),
+	}}}
+	require.NoError(t, os.WriteFile(g.Contracts[0].NEF, nefBytes, 0644))
+	require.NoError(t, os.WriteFile(g.Contracts[0].Manifest, manifestBytes, 0644))
+
+	sender := util.Uint160{1, 2, 3}
+	_, _, err = createGenesisScript(g, nil, sender)
+	require.NoError(t, err)
+
+	dump := &state.ContractDump{Contract: state.Contract{ContractBase: state.ContractBase{
+		Hash:     state.CreateContractHash(sender, ne.Checksum, m.Name),
+		NEF:      *ne,
+		Manifest: *m,
+	}}}
+	_, _, err = createGenesisScript(g, []*state.ContractDump{dump}, sender)
+	require.ErrorContains(t, err, "imported from a dump")
+}
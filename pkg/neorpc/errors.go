@@ -136,6 +136,12 @@ const (
 	ErrInvalidProofCode = -607
 	// ErrExecutionFailedCode is returned from a call made a VM execution, but it has failed.
 	ErrExecutionFailedCode = -608
+	// ErrConsensusDisabledCode is returned if consensus service is not enabled in the configuration
+	// or is not running.
+	ErrConsensusDisabledCode = -609
+	// ErrInstantMiningDisabledCode is returned if blocks are requested to be generated, but consensus
+	// service doesn't run in instant mining mode.
+	ErrInstantMiningDisabledCode = -610
 )
 
 var (
@@ -268,6 +274,12 @@ var (
 	// ErrExecutionFailed represents an error with code [ErrExecutionFailedCode].
 	// Call made a VM execution, but it has failed.
 	ErrExecutionFailed = NewErrorWithCode(ErrExecutionFailedCode, "Execution failed")
+	// ErrConsensusDisabled represents an error with code [ErrConsensusDisabledCode].
+	// Consensus service is not enabled in the configuration or is not running.
+	ErrConsensusDisabled = NewErrorWithCode(ErrConsensusDisabledCode, "Consensus service is not running")
+	// ErrInstantMiningDisabled represents an error with code [ErrInstantMiningDisabledCode].
+	// Consensus service doesn't run in instant mining mode.
+	ErrInstantMiningDisabled = NewErrorWithCode(ErrInstantMiningDisabledCode, "Instant mining is disabled")
 )
 
 // NewError is an Error constructor that takes Error contents from its parameters.
@@ -52,6 +52,16 @@ type (
 		Signer *util.Uint160      `json:"signer,omitempty"`
 		Type   *mempoolevent.Type `json:"type,omitempty"`
 	}
+	// MempoolEventFilter is a wrapper structure used for memory pool events.
+	// It allows to choose memory pool events with the specified transaction
+	// sender, signer, event type and/or removal reason. nil value treated as
+	// missing filter.
+	MempoolEventFilter struct {
+		Sender *util.Uint160        `json:"sender,omitempty"`
+		Signer *util.Uint160        `json:"signer,omitempty"`
+		Type   *mempoolevent.Type   `json:"type,omitempty"`
+		Reason *mempoolevent.Reason `json:"reason,omitempty"`
+	}
 )
 
 // SubscriptionFilter is an interface for all subscription filters.
@@ -190,3 +200,36 @@ func (f *NotaryRequestFilter) Copy() *NotaryRequestFilter {
 func (f NotaryRequestFilter) IsValid() error {
 	return nil
 }
+
+// Copy creates a deep copy of the MempoolEventFilter. It handles nil MempoolEventFilter correctly.
+func (f *MempoolEventFilter) Copy() *MempoolEventFilter {
+	if f == nil {
+		return nil
+	}
+	var res = new(MempoolEventFilter)
+	if f.Sender != nil {
+		res.Sender = new(util.Uint160)
+		*res.Sender = *f.Sender
+	}
+	if f.Signer != nil {
+		res.Signer = new(util.Uint160)
+		*res.Signer = *f.Signer
+	}
+	if f.Type != nil {
+		res.Type = new(mempoolevent.Type)
+		*res.Type = *f.Type
+	}
+	if f.Reason != nil {
+		res.Reason = new(mempoolevent.Reason)
+		*res.Reason = *f.Reason
+	}
+	return res
+}
+
+// IsValid implements SubscriptionFilter interface.
+func (f MempoolEventFilter) IsValid() error {
+	if f.Reason != nil && *f.Reason != mempoolevent.NoReason && f.Type != nil && *f.Type != mempoolevent.TransactionRemoved {
+		return fmt.Errorf("%w: MempoolEventFilter reason parameter can only be used for %s events", ErrInvalidSubscriptionFilter, mempoolevent.TransactionRemoved)
+	}
+	return nil
+}
@@ -3,6 +3,7 @@ package neorpc
 import (
 	"testing"
 
+	"github.com/nspcc-dev/neo-go/pkg/core/mempoolevent"
 	"github.com/nspcc-dev/neo-go/pkg/util"
 	"github.com/stretchr/testify/require"
 )
@@ -114,3 +115,55 @@ func TestExecutionFilterCopy(t *testing.T) {
 	*bf.Container = util.Uint256{3, 2, 1}
 	require.NotEqual(t, bf, tf)
 }
+
+func TestMempoolEventFilterCopy(t *testing.T) {
+	var bf, tf *MempoolEventFilter
+
+	require.Nil(t, bf.Copy())
+
+	bf = new(MempoolEventFilter)
+	tf = bf.Copy()
+	require.Equal(t, bf, tf)
+
+	bf.Sender = &util.Uint160{1, 2, 3}
+
+	tf = bf.Copy()
+	require.Equal(t, bf, tf)
+	*bf.Sender = util.Uint160{3, 2, 1}
+	require.NotEqual(t, bf, tf)
+
+	bf.Signer = &util.Uint160{1, 2, 3}
+
+	tf = bf.Copy()
+	require.Equal(t, bf, tf)
+	*bf.Signer = util.Uint160{3, 2, 1}
+	require.NotEqual(t, bf, tf)
+
+	bf.Type = new(mempoolevent.Type)
+	*bf.Type = mempoolevent.TransactionRemoved
+
+	tf = bf.Copy()
+	require.Equal(t, bf, tf)
+	*bf.Type = mempoolevent.TransactionAdded
+	require.NotEqual(t, bf, tf)
+
+	bf.Reason = new(mempoolevent.Reason)
+	*bf.Reason = mempoolevent.ReasonIncluded
+
+	tf = bf.Copy()
+	require.Equal(t, bf, tf)
+	*bf.Reason = mempoolevent.ReasonExpired
+	require.NotEqual(t, bf, tf)
+}
+
+func TestMempoolEventFilterIsValid(t *testing.T) {
+	var (
+		added   = mempoolevent.TransactionAdded
+		removed = mempoolevent.TransactionRemoved
+		reason  = mempoolevent.ReasonEvicted
+	)
+	require.NoError(t, MempoolEventFilter{}.IsValid())
+	require.NoError(t, MempoolEventFilter{Type: &removed, Reason: &reason}.IsValid())
+	require.NoError(t, MempoolEventFilter{Reason: &reason}.IsValid())
+	require.ErrorIs(t, MempoolEventFilter{Type: &added, Reason: &reason}.IsValid(), ErrInvalidSubscriptionFilter)
+}
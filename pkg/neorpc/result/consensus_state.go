@@ -0,0 +1,78 @@
+package result
+
+import (
+	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
+)
+
+// Consensus round results.
+const (
+	// ConsensusRoundBlock means that the round ended with a new block accepted.
+	ConsensusRoundBlock = "block"
+	// ConsensusRoundChangeView means that the round ended with a view change.
+	ConsensusRoundChangeView = "changeview"
+)
+
+type (
+	// ConsensusState represents the state of the dBFT consensus service as
+	// returned by getconsensusstate RPC call.
+	ConsensusState struct {
+		// Height is the index of the block being agreed upon.
+		Height uint32 `json:"height"`
+		View   byte   `json:"view"`
+		// Primary is the index of the primary node for the current view.
+		Primary uint `json:"primary"`
+		// Index is the index of this node in the validators list, it's -1
+		// for the node that doesn't take part in the consensus.
+		Index      int                  `json:"index"`
+		Validators []ConsensusValidator `json:"validators"`
+		// Rounds is the timeline of recent rounds, the last one is the
+		// current (unfinished) round.
+		Rounds []ConsensusRound `json:"rounds"`
+	}
+
+	// ConsensusValidator describes messages received from a single validator
+	// for the current round.
+	ConsensusValidator struct {
+		Index     int             `json:"index"`
+		PublicKey *keys.PublicKey `json:"publickey"`
+		// Preparation is set when PrepareRequest (for the primary) or
+		// PrepareResponse (for backups) was received.
+		Preparation bool `json:"preparation"`
+		// Commit is set when Commit was received (it's kept across views).
+		Commit     bool                 `json:"commit"`
+		ChangeView *ConsensusChangeView `json:"changeview,omitempty"`
+		// LastSeen is the height and view of the last message received from
+		// the validator, it's nil if nothing was received yet.
+		LastSeen *ConsensusHeightView `json:"lastseen,omitempty"`
+	}
+
+	// ConsensusChangeView is a ChangeView message sent by some validator.
+	ConsensusChangeView struct {
+		NewView byte   `json:"newview"`
+		Reason  string `json:"reason"`
+	}
+
+	// ConsensusHeightView is a pair of height and view.
+	ConsensusHeightView struct {
+		Height uint32 `json:"height"`
+		View   byte   `json:"view"`
+	}
+
+	// ConsensusRound is a single round (height and view) of the consensus.
+	ConsensusRound struct {
+		Height  uint32 `json:"height"`
+		View    byte   `json:"view"`
+		Primary uint   `json:"primary"`
+		// Start is the round start time (Unix timestamp in milliseconds).
+		Start uint64 `json:"start"`
+		// Duration is the round duration in milliseconds, for the current
+		// round it's the time passed since its start.
+		Duration uint64 `json:"duration"`
+		// Result is either ConsensusRoundBlock or ConsensusRoundChangeView,
+		// it's empty for the current round.
+		Result string `json:"result,omitempty"`
+		// Reason is the most common view change reason among ChangeView
+		// messages that lead to the view change.
+		Reason string `json:"reason,omitempty"`
+	}
+)
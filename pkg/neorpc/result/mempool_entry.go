@@ -0,0 +1,33 @@
+package result
+
+import (
+	"github.com/nspcc-dev/neo-go/pkg/util"
+)
+
+// MempoolEntry represents a transaction in the memory pool as returned by
+// getmempoolentries RPC call.
+type MempoolEntry struct {
+	Hash   util.Uint256 `json:"hash"`
+	Sender util.Uint160 `json:"sender"`
+	// FeePerByte is the network fee per byte of transaction, that's what
+	// the memory pool uses to prioritize transactions.
+	FeePerByte      int64  `json:"feeperbyte,string"`
+	NetworkFee      int64  `json:"netfee,string"`
+	SystemFee       int64  `json:"sysfee,string"`
+	Size            int    `json:"size"`
+	ValidUntilBlock uint32 `json:"validuntilblock"`
+	// HighPriority is set for transactions with HighPriority attribute that
+	// are always placed before other ones.
+	HighPriority bool `json:"highpriority,omitempty"`
+	// Conflicts contains hashes from transaction's Conflicts attributes.
+	Conflicts []util.Uint256 `json:"conflicts"`
+	// Position is the transaction position in the memory pool priority
+	// order (starting from 0 for the most prioritized one), transactions
+	// with the lowest priority are the first to be evicted from the full
+	// pool.
+	Position int `json:"position"`
+	// SenderFees is the sum of network and system fees of all pooled
+	// transactions with the same sender, it can't exceed sender's GAS
+	// balance.
+	SenderFees int64 `json:"senderfees,string"`
+}
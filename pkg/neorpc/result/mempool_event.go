@@ -0,0 +1,14 @@
+package result
+
+import (
+	"github.com/nspcc-dev/neo-go/pkg/core/mempoolevent"
+	"github.com/nspcc-dev/neo-go/pkg/core/transaction"
+)
+
+// MempoolEvent represents a transaction event either added to or removed from
+// the memory pool. Reason is only set for removal events.
+type MempoolEvent struct {
+	Type        mempoolevent.Type        `json:"type"`
+	Reason      mempoolevent.Reason      `json:"reason,omitempty"`
+	Transaction *transaction.Transaction `json:"transaction"`
+}
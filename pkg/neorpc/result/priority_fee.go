@@ -0,0 +1,33 @@
+package result
+
+// PriorityFee represents a result of estimatepriorityfee RPC call. All fee
+// values are network fee per byte of transaction (see
+// transaction.Transaction.FeePerByte), that's what the memory pool uses to
+// prioritize transactions and choose the ones to evict when it's full.
+type PriorityFee struct {
+	// FeePerByte is the suggested fee per byte value. It's never lower than
+	// PolicyFeePerByte, which means that the minimum network fee (as
+	// returned by calculatenetworkfee) is enough.
+	FeePerByte int64 `json:"feeperbyte,string"`
+	// PolicyFeePerByte is the per-byte fee set by the Policy contract.
+	PolicyFeePerByte int64 `json:"policyfeeperbyte,string"`
+	// MempoolCount is the current number of transactions in the memory pool.
+	MempoolCount int `json:"mempoolcount"`
+	// MempoolCapacity is the maximum number of transactions in the memory
+	// pool.
+	MempoolCapacity int `json:"mempoolcapacity"`
+	// MempoolMinFeePerByte is the lowest fee per byte value among the pooled
+	// transactions (except for high-priority ones), 0 if there are none.
+	MempoolMinFeePerByte int64 `json:"mempoolminfeeperbyte,string"`
+	// Blocks is the number of latest blocks analyzed (the ones not
+	// available in the node's storage are skipped).
+	Blocks uint32 `json:"blocks"`
+	// BlockTransactions is the number of transactions in analyzed blocks.
+	BlockTransactions int `json:"blocktransactions"`
+	// BlocksMinFeePerByte is the lowest fee per byte value of transactions
+	// in analyzed blocks, 0 if there are none.
+	BlocksMinFeePerByte int64 `json:"blocksminfeeperbyte,string"`
+	// BlocksMedianFeePerByte is the median fee per byte value of
+	// transactions in analyzed blocks, 0 if there are none.
+	BlocksMedianFeePerByte int64 `json:"blocksmedianfeeperbyte,string"`
+}
@@ -0,0 +1,206 @@
+package result
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/nspcc-dev/neo-go/pkg/vm/stackitem"
+)
+
+// Storage operation types used in TraceStorageOp.
+const (
+	TraceStorageGet    = "get"
+	TraceStoragePut    = "put"
+	TraceStorageDelete = "delete"
+	TraceStorageFind   = "find"
+)
+
+type (
+	// TransactionTrace is the execution trace of a persisted transaction as
+	// returned by tracetransaction RPC call.
+	TransactionTrace struct {
+		TxHash     util.Uint256 `json:"txid"`
+		BlockIndex uint32       `json:"blockindex"`
+		// VMState is the resulting VM state (HALT or FAULT), it's the same
+		// as the one from the application log.
+		VMState        string           `json:"vmstate"`
+		GasConsumed    int64            `json:"gasconsumed,string"`
+		FaultException string           `json:"exception,omitempty"`
+		Stack          []stackitem.Item `json:"-"`
+		// Call is the root call frame corresponding to the transaction
+		// script, all contract calls are nested into it.
+		Call *TraceCall `json:"call"`
+		// StorageChanges contains all storage items changed by the
+		// transaction (including native contract ones), it's empty for
+		// FAULTed transactions since their changes are not persisted.
+		StorageChanges []TraceStorageChange `json:"storagechanges"`
+		// Steps contains executed instructions, it's only filled if
+		// requested.
+		Steps []TraceStep `json:"steps,omitempty"`
+		// StepsTruncated is set if there are too many instructions executed
+		// and only the first ones are returned in Steps.
+		StepsTruncated bool `json:"stepstruncated,omitempty"`
+	}
+
+	// TraceCall is a single contract call frame.
+	TraceCall struct {
+		Contract util.Uint160 `json:"contract"`
+		// Method is the name of the contract method called, it's empty
+		// for the transaction script and for calls to unknown offsets.
+		Method    string           `json:"method,omitempty"`
+		Arguments []stackitem.Item `json:"-"`
+		// Result is the value returned by the call, it's nil if the call
+		// has failed or if its result is unknown.
+		Result stackitem.Item `json:"-"`
+		// State is HALT for calls that returned normally and FAULT for
+		// calls interrupted by an exception.
+		State string `json:"state"`
+		// GasConsumed is the amount of GAS spent by the call including
+		// nested ones.
+		GasConsumed int64            `json:"gasconsumed,string"`
+		Syscalls    []string         `json:"syscalls,omitempty"`
+		Storage     []TraceStorageOp `json:"storage,omitempty"`
+		Calls       []*TraceCall     `json:"calls,omitempty"`
+	}
+
+	// TraceStorageOp is a contract storage access made via syscall.
+	TraceStorageOp struct {
+		// Type is one of TraceStorage* constants.
+		Type string `json:"type"`
+		// ID is the ID of the contract the storage belongs to.
+		ID int32 `json:"id"`
+		// Key is the item key (prefix for Find).
+		Key []byte `json:"key"`
+		// Value is the value read by Get or written by Put.
+		Value []byte `json:"value,omitempty"`
+		// OldValue is the value overwritten by Put or removed by Delete.
+		OldValue []byte `json:"oldvalue,omitempty"`
+	}
+
+	// TraceStorageChange is a storage item changed by transaction, NewValue
+	// is nil for deleted items and OldValue is nil for the new ones.
+	TraceStorageChange struct {
+		ID       int32  `json:"id"`
+		Key      []byte `json:"key"`
+		OldValue []byte `json:"oldvalue,omitempty"`
+		NewValue []byte `json:"newvalue,omitempty"`
+	}
+
+	// TraceStep is a single executed instruction.
+	TraceStep struct {
+		Contract util.Uint160 `json:"contract"`
+		Offset   int          `json:"offset"`
+		Opcode   string       `json:"opcode"`
+		// Depth is the invocation stack depth.
+		Depth int `json:"depth"`
+		// GasConsumed is the amount of GAS consumed by the transaction
+		// before this instruction execution.
+		GasConsumed int64 `json:"gasconsumed,string"`
+	}
+)
+
+// MarshalJSON implements the json.Marshaler interface.
+func (t TransactionTrace) MarshalJSON() ([]byte, error) {
+	type traceAlias TransactionTrace
+	return json.Marshal(struct {
+		traceAlias
+		Stack []json.RawMessage `json:"stack"`
+	}{
+		traceAlias: traceAlias(t),
+		Stack:      itemsToJSON(t.Stack),
+	})
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (t *TransactionTrace) UnmarshalJSON(data []byte) error {
+	type traceAlias TransactionTrace
+	var aux = struct {
+		*traceAlias
+		Stack []json.RawMessage `json:"stack"`
+	}{traceAlias: (*traceAlias)(t)}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	st, err := itemsFromJSON(aux.Stack)
+	if err != nil {
+		return fmt.Errorf("failed to unmarshal stack: %w", err)
+	}
+	t.Stack = st
+	return nil
+}
+
+// MarshalJSON implements the json.Marshaler interface. Items that can't be
+// represented in JSON (recursive or too big ones) are replaced with Null.
+func (c TraceCall) MarshalJSON() ([]byte, error) {
+	type callAlias TraceCall
+	var res json.RawMessage
+	if c.Result != nil {
+		res = itemsToJSON([]stackitem.Item{c.Result})[0]
+	}
+	return json.Marshal(struct {
+		callAlias
+		Arguments []json.RawMessage `json:"arguments,omitempty"`
+		Result    json.RawMessage   `json:"result,omitempty"`
+	}{
+		callAlias: callAlias(c),
+		Arguments: itemsToJSON(c.Arguments),
+		Result:    res,
+	})
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (c *TraceCall) UnmarshalJSON(data []byte) error {
+	type callAlias TraceCall
+	var aux = struct {
+		*callAlias
+		Arguments []json.RawMessage `json:"arguments"`
+		Result    json.RawMessage   `json:"result"`
+	}{callAlias: (*callAlias)(c)}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	args, err := itemsFromJSON(aux.Arguments)
+	if err != nil {
+		return fmt.Errorf("failed to unmarshal arguments: %w", err)
+	}
+	c.Arguments = args
+	c.Result = nil
+	if len(aux.Result) != 0 {
+		if c.Result, err = stackitem.FromJSONWithTypes(aux.Result); err != nil {
+			return fmt.Errorf("failed to unmarshal result: %w", err)
+		}
+	}
+	return nil
+}
+
+func itemsToJSON(items []stackitem.Item) []json.RawMessage {
+	if items == nil {
+		return nil
+	}
+	var res = make([]json.RawMessage, len(items))
+	for i := range items {
+		data, err := stackitem.ToJSONWithTypes(items[i])
+		if err != nil {
+			data, _ = stackitem.ToJSONWithTypes(stackitem.Null{})
+		}
+		res[i] = data
+	}
+	return res
+}
+
+func itemsFromJSON(data []json.RawMessage) ([]stackitem.Item, error) {
+	if data == nil {
+		return nil, nil
+	}
+	var (
+		res = make([]stackitem.Item, len(data))
+		err error
+	)
+	for i := range data {
+		if res[i], err = stackitem.FromJSONWithTypes(data[i]); err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
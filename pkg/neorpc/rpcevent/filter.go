@@ -89,6 +89,23 @@ func Matches(f Comparator, r Container) bool {
 			}
 		}
 		return senderOk && signerOK && typeOk
+	case neorpc.MempoolEventID:
+		filt := filter.(neorpc.MempoolEventFilter)
+		e := r.EventPayload().(*result.MempoolEvent)
+		typeOk := filt.Type == nil || e.Type == *filt.Type
+		reasonOk := filt.Reason == nil || e.Reason == *filt.Reason
+		senderOk := filt.Sender == nil || e.Transaction.Sender().Equals(*filt.Sender)
+		signerOK := true
+		if filt.Signer != nil {
+			signerOK = false
+			for i := range e.Transaction.Signers {
+				if e.Transaction.Signers[i].Account.Equals(*filt.Signer) {
+					signerOK = true
+					break
+				}
+			}
+		}
+		return senderOk && signerOK && typeOk && reasonOk
 	}
 	return false
 }
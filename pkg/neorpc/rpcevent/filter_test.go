@@ -90,6 +90,17 @@ func TestMatches(t *testing.T) {
 			},
 		},
 	}
+	mpType := mempoolevent.TransactionRemoved
+	mpReason := mempoolevent.ReasonIncluded
+	badReason := mempoolevent.ReasonEvicted
+	mpContainer := testContainer{
+		id: neorpc.MempoolEventID,
+		pld: &result.MempoolEvent{
+			Type:        mpType,
+			Reason:      mpReason,
+			Transaction: &transaction.Transaction{Signers: []transaction.Signer{{Account: sender}, {Account: signer}}},
+		},
+	}
 	missedContainer := testContainer{
 		id: neorpc.MissedEventID,
 	}
@@ -336,6 +347,57 @@ func TestMatches(t *testing.T) {
 			container: ntrContainer,
 			expected:  true,
 		},
+		{
+			name:       "mempool event, no filter",
+			comparator: testComparator{id: neorpc.MempoolEventID},
+			container:  mpContainer,
+			expected:   true,
+		},
+		{
+			name: "mempool event, sender mismatch",
+			comparator: testComparator{
+				id:     neorpc.MempoolEventID,
+				filter: neorpc.MempoolEventFilter{Sender: &signer},
+			},
+			container: mpContainer,
+			expected:  false,
+		},
+		{
+			name: "mempool event, signer mismatch",
+			comparator: testComparator{
+				id:     neorpc.MempoolEventID,
+				filter: neorpc.MempoolEventFilter{Signer: &badUint160},
+			},
+			container: mpContainer,
+			expected:  false,
+		},
+		{
+			name: "mempool event, type mismatch",
+			comparator: testComparator{
+				id:     neorpc.MempoolEventID,
+				filter: neorpc.MempoolEventFilter{Type: &notaryType},
+			},
+			container: mpContainer,
+			expected:  false,
+		},
+		{
+			name: "mempool event, reason mismatch",
+			comparator: testComparator{
+				id:     neorpc.MempoolEventID,
+				filter: neorpc.MempoolEventFilter{Reason: &badReason},
+			},
+			container: mpContainer,
+			expected:  false,
+		},
+		{
+			name: "mempool event, filter match",
+			comparator: testComparator{
+				id:     neorpc.MempoolEventID,
+				filter: neorpc.MempoolEventFilter{Sender: &sender, Signer: &signer, Type: &mpType, Reason: &mpReason},
+			},
+			container: mpContainer,
+			expected:  true,
+		},
 	}
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
@@ -76,6 +76,13 @@ type (
 		transaction.Signer
 		transaction.Witness
 	}
+
+	// ScriptWithSigners is a script with its own set of signers, a list of
+	// them is used as an `invokescripts` parameter.
+	ScriptWithSigners struct {
+		Script  []byte               `json:"script"`
+		Signers []transaction.Signer `json:"signers,omitempty"`
+	}
 )
 
 // signerWithWitnessAux is an auxiliary struct for JSON marshalling. We need it because of
@@ -2,6 +2,9 @@ package chain
 
 import (
 	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
 	"sort"
 	"testing"
 	"time"
@@ -9,8 +12,11 @@ import (
 	"github.com/nspcc-dev/neo-go/pkg/config"
 	"github.com/nspcc-dev/neo-go/pkg/config/netmode"
 	"github.com/nspcc-dev/neo-go/pkg/core"
+	"github.com/nspcc-dev/neo-go/pkg/core/fork"
+	"github.com/nspcc-dev/neo-go/pkg/core/state"
 	"github.com/nspcc-dev/neo-go/pkg/core/storage"
 	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
+	"github.com/nspcc-dev/neo-go/pkg/io"
 	"github.com/nspcc-dev/neo-go/pkg/neotest"
 	"github.com/nspcc-dev/neo-go/pkg/smartcontract"
 	"github.com/nspcc-dev/neo-go/pkg/wallet"
@@ -74,6 +80,11 @@ type Options struct {
 	// If SkipRun is true, it is caller's responsibility to call Run before using
 	// the chain and to properly Close the chain when done.
 	SkipRun bool
+	// Contracts are imported in the genesis block along with their storage
+	// (see config.Genesis.ContractDumps), this allows to test contracts with
+	// the state dumped from real networks. They're added to the configuration
+	// before BlockchainConfigHook is called.
+	Contracts []*state.ContractDump
 }
 
 func init() {
@@ -171,20 +182,7 @@ func NewSingleWithOptions(t testing.TB, options *Options) (*core.Blockchain, neo
 		options = &Options{}
 	}
 
-	cfg := config.Blockchain{
-		ProtocolConfiguration: config.ProtocolConfiguration{
-			Magic:              netmode.UnitTestNet,
-			MaxTraceableBlocks: MaxTraceableBlocks,
-			TimePerBlock:       TimePerBlock,
-			StandbyCommittee:   []string{hex.EncodeToString(committeeAcc.PublicKey().Bytes())},
-			ValidatorsCount:    1,
-			VerifyTransactions: true,
-		},
-	}
-	if options.BlockchainConfigHook != nil {
-		options.BlockchainConfigHook(&cfg)
-	}
-
+	cfg := newSingleConfig(t, options)
 	store := options.Store
 	if store == nil {
 		store = storage.NewMemoryStore()
@@ -204,6 +202,78 @@ func NewSingleWithOptions(t testing.TB, options *Options) (*core.Blockchain, neo
 	return bc, neotest.NewMultiSigner(committeeAcc)
 }
 
+// NewForkWithOptions creates a new blockchain instance with a single validator
+// that forks the remote network at the given height (see the fork package).
+// The configuration used is the same as for NewSingle (options can adjust it
+// to match the remote network) with SkipBlockVerification enabled, so that
+// the Signer returned can add blocks on top of any network. Options.Store (if
+// set) is used as the local store for the remote state cache.
+func NewForkWithOptions(t testing.TB, remote fork.RPC, height uint32, options *Options) (*core.Blockchain, neotest.Signer) {
+	if options == nil {
+		options = &Options{}
+	}
+
+	cfg := newSingleConfig(t, options)
+	cfg.SkipBlockVerification = true
+
+	local := options.Store
+	if local == nil {
+		local = storage.NewMemoryStore()
+	}
+
+	logger := options.Logger
+	if logger == nil {
+		logger = zaptest.NewLogger(t)
+	}
+
+	store, err := fork.NewStore(local, remote, cfg, height, logger)
+	require.NoError(t, err)
+	bc, err := core.NewBlockchain(store, cfg, logger)
+	require.NoError(t, err)
+	if !options.SkipRun {
+		go bc.Run()
+		t.Cleanup(bc.Close)
+	}
+	return bc, neotest.NewMultiSigner(committeeAcc)
+}
+
+// newSingleConfig returns a single validator chain configuration adjusted by
+// options.
+func newSingleConfig(t testing.TB, options *Options) config.Blockchain {
+	cfg := config.Blockchain{
+		ProtocolConfiguration: config.ProtocolConfiguration{
+			Magic:              netmode.UnitTestNet,
+			MaxTraceableBlocks: MaxTraceableBlocks,
+			TimePerBlock:       TimePerBlock,
+			StandbyCommittee:   []string{hex.EncodeToString(committeeAcc.PublicKey().Bytes())},
+			ValidatorsCount:    1,
+			VerifyTransactions: true,
+		},
+	}
+	importContracts(t, options.Contracts, &cfg)
+	if options.BlockchainConfigHook != nil {
+		options.BlockchainConfigHook(&cfg)
+	}
+	return cfg
+}
+
+// importContracts saves the given contract dumps into temporary files and adds
+// them to the genesis configuration.
+func importContracts(t testing.TB, dumps []*state.ContractDump, cfg *config.Blockchain) {
+	if len(dumps) == 0 {
+		return
+	}
+	dir := t.TempDir()
+	for i, d := range dumps {
+		w := io.NewBufBinWriter()
+		d.EncodeBinary(w.BinWriter)
+		require.NoError(t, w.Err)
+		path := filepath.Join(dir, fmt.Sprintf("contract%d.dump", i))
+		require.NoError(t, os.WriteFile(path, w.Bytes(), 0644))
+		cfg.Genesis.ContractDumps = append(cfg.Genesis.ContractDumps, path)
+	}
+}
+
 // NewMulti creates a new blockchain instance with four validators and six
 // committee members. Otherwise, it does not differ much from NewSingle. The
 // second value returned contains the validators Signer, the third -- the committee one.
@@ -268,6 +338,7 @@ func NewMultiWithOptionsNoCheck(t testing.TB, options *Options) (*core.Blockchai
 			VerifyTransactions: true,
 		},
 	}
+	importContracts(t, options.Contracts, &cfg)
 	if options.BlockchainConfigHook != nil {
 		options.BlockchainConfigHook(&cfg)
 	}
@@ -0,0 +1,342 @@
+package network
+
+import (
+	"errors"
+	"fmt"
+	gio "io"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/nspcc-dev/neo-go/pkg/config"
+	"github.com/nspcc-dev/neo-go/pkg/config/netmode"
+	"github.com/nspcc-dev/neo-go/pkg/io"
+	"github.com/nspcc-dev/neo-go/pkg/network/payload"
+)
+
+const (
+	// defaultCaptureFileSize is the default capture file size limit (100 MiB).
+	defaultCaptureFileSize = 100 * 1024 * 1024
+	// captureVersion is the current version of the capture file format.
+	captureVersion = 0
+	// maxCapturePeerLength is the limit for the peer address in the capture
+	// record.
+	maxCapturePeerLength = 256
+	// maxCapturePacketSize is the limit for the packet data in the capture
+	// record, packets can contain more than one message.
+	maxCapturePacketSize = 2 * payload.MaxSize
+)
+
+// captureMagic is the capture file signature.
+var captureMagic = [4]byte{'N', 'G', 'C', 'P'}
+
+// CaptureDirection is the direction of captured P2P packet.
+type CaptureDirection byte
+
+// Possible capture directions.
+const (
+	// CaptureInbound is used for packets received from the peer.
+	CaptureInbound CaptureDirection = iota
+	// CaptureOutbound is used for packets sent to the peer.
+	CaptureOutbound
+)
+
+// String implements the fmt.Stringer interface.
+func (d CaptureDirection) String() string {
+	switch d {
+	case CaptureInbound:
+		return "in"
+	case CaptureOutbound:
+		return "out"
+	default:
+		return "unknown"
+	}
+}
+
+type (
+	// CaptureHeader is the header of P2P capture file.
+	CaptureHeader struct {
+		// Magic is the network magic of the node that made the capture.
+		Magic netmode.Magic
+		// StateRootInHeader is the node's StateRootInHeader protocol setting,
+		// it's required to decode blocks and headers correctly.
+		StateRootInHeader bool
+	}
+
+	// CaptureRecord is a single P2P packet captured by the node. A packet
+	// can contain several serialized Messages.
+	CaptureRecord struct {
+		// Timestamp is the time the packet was sent or received at.
+		Timestamp time.Time
+		// Direction is the packet direction.
+		Direction CaptureDirection
+		// Peer is the remote peer address.
+		Peer string
+		// Data is the raw packet data.
+		Data []byte
+	}
+
+	// CaptureReader reads P2P capture files written by the node.
+	CaptureReader struct {
+		CaptureHeader
+
+		r *io.BinReader
+	}
+
+	// captureWriter writes captured packets into a set of rotated files.
+	captureWriter struct {
+		lock   sync.Mutex
+		cfg    config.P2PCapture
+		header CaptureHeader
+		file   *os.File
+		size   int64
+		// full is set when the file size limit is reached and there are no
+		// rotated files to keep, capturing is stopped then.
+		full bool
+	}
+)
+
+// EncodeBinary implements the io.Serializable interface.
+func (h *CaptureHeader) EncodeBinary(w *io.BinWriter) {
+	w.WriteBytes(captureMagic[:])
+	w.WriteB(captureVersion)
+	w.WriteU32LE(uint32(h.Magic))
+	w.WriteBool(h.StateRootInHeader)
+}
+
+// DecodeBinary implements the io.Serializable interface.
+func (h *CaptureHeader) DecodeBinary(r *io.BinReader) {
+	var magic [4]byte
+	r.ReadBytes(magic[:])
+	if r.Err == nil && magic != captureMagic {
+		r.Err = errors.New("not a P2P capture file")
+		return
+	}
+	if v := r.ReadB(); r.Err == nil && v != captureVersion {
+		r.Err = fmt.Errorf("unsupported capture version %d", v)
+		return
+	}
+	h.Magic = netmode.Magic(r.ReadU32LE())
+	h.StateRootInHeader = r.ReadBool()
+}
+
+// EncodeBinary implements the io.Serializable interface.
+func (c *CaptureRecord) EncodeBinary(w *io.BinWriter) {
+	w.WriteU64LE(uint64(c.Timestamp.UnixNano()))
+	w.WriteB(byte(c.Direction))
+	w.WriteString(c.Peer)
+	w.WriteVarBytes(c.Data)
+}
+
+// DecodeBinary implements the io.Serializable interface.
+func (c *CaptureRecord) DecodeBinary(r *io.BinReader) {
+	c.Timestamp = time.Unix(0, int64(r.ReadU64LE()))
+	c.Direction = CaptureDirection(r.ReadB())
+	c.Peer = r.ReadString(maxCapturePeerLength)
+	c.Data = r.ReadVarBytes(maxCapturePacketSize)
+}
+
+// Messages decodes all messages contained in the captured packet.
+func (c *CaptureRecord) Messages(stateRootInHeader bool) ([]*Message, error) {
+	var (
+		res []*Message
+		r   = io.NewBinReaderFromBuf(c.Data)
+	)
+	for r.Len() > 0 {
+		msg := &Message{StateRootInHeader: stateRootInHeader}
+		if err := msg.Decode(r); err != nil {
+			return res, err
+		}
+		res = append(res, msg)
+	}
+	return res, nil
+}
+
+// receivedBytes serializes the decoded Message exactly the way it was received
+// (with the original compression flag and payload).
+func (m *Message) receivedBytes() []byte {
+	w := io.NewBufBinWriter()
+	w.WriteB(byte(m.Flags))
+	w.WriteB(byte(m.Command))
+	w.WriteVarBytes(m.compressedPayload)
+	return w.Bytes()
+}
+
+// NewCaptureReader reads capture file header from the given reader and returns
+// a CaptureReader that can be used to read the records.
+func NewCaptureReader(rd gio.Reader) (*CaptureReader, error) {
+	var c = &CaptureReader{r: io.NewBinReaderFromIO(rd)}
+	c.CaptureHeader.DecodeBinary(c.r)
+	if c.r.Err != nil {
+		return nil, c.r.Err
+	}
+	return c, nil
+}
+
+// Next returns the next record from the capture file. It returns io.EOF when
+// there are no more records.
+func (c *CaptureReader) Next() (*CaptureRecord, error) {
+	var rec = new(CaptureRecord)
+	rec.DecodeBinary(c.r)
+	if c.r.Err != nil {
+		if errors.Is(c.r.Err, gio.ErrUnexpectedEOF) {
+			// Truncated last record (node was killed).
+			return nil, gio.EOF
+		}
+		return nil, c.r.Err
+	}
+	return rec, nil
+}
+
+// newCaptureWriter creates a new capture file (rotating the existing one if
+// needed) with the given header. If rotation is disabled, the existing file is
+// appended to, it must have the same header then.
+func newCaptureWriter(cfg config.P2PCapture, h CaptureHeader) (*captureWriter, error) {
+	if cfg.FilePath == "" {
+		return nil, errors.New("capture file path is not set")
+	}
+	if cfg.MaxFileSize <= 0 {
+		cfg.MaxFileSize = defaultCaptureFileSize
+	}
+	var c = &captureWriter{
+		cfg:    cfg,
+		header: h,
+	}
+	if _, err := os.Stat(cfg.FilePath); err == nil {
+		if cfg.MaxFiles == 0 {
+			err = c.reopen()
+		} else {
+			err = c.rotate()
+		}
+		if err != nil {
+			return nil, err
+		}
+	} else if err = c.open(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// open creates a new capture file and writes the header into it.
+func (c *captureWriter) open() error {
+	f, err := os.OpenFile(c.cfg.FilePath, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to create capture file: %w", err)
+	}
+	w := io.NewBufBinWriter()
+	c.header.EncodeBinary(w.BinWriter)
+	n, err := f.Write(w.Bytes())
+	if err != nil {
+		_ = f.Close()
+		return fmt.Errorf("failed to write capture header: %w", err)
+	}
+	c.file = f
+	c.size = int64(n)
+	return nil
+}
+
+// reopen opens the existing capture file for appending, its header must match
+// the current one.
+func (c *captureWriter) reopen() error {
+	f, err := os.OpenFile(c.cfg.FilePath, os.O_RDWR|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to open capture file: %w", err)
+	}
+	r, err := NewCaptureReader(f)
+	if err == nil && r.CaptureHeader != c.header {
+		err = errors.New("header mismatch")
+	}
+	if err != nil {
+		_ = f.Close()
+		return fmt.Errorf("can't append to the existing capture file %s (remove it or enable rotation): %w", c.cfg.FilePath, err)
+	}
+	st, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return fmt.Errorf("failed to get capture file size: %w", err)
+	}
+	c.file = f
+	c.size = st.Size()
+	return nil
+}
+
+// rotate closes the current file, shifts rotated files and opens a new one.
+func (c *captureWriter) rotate() error {
+	if c.file != nil {
+		if err := c.file.Close(); err != nil {
+			return err
+		}
+		c.file = nil
+	}
+	var name = func(i int) string {
+		if i == 0 {
+			return c.cfg.FilePath
+		}
+		return c.cfg.FilePath + "." + strconv.Itoa(i)
+	}
+	_ = os.Remove(name(c.cfg.MaxFiles))
+	for i := c.cfg.MaxFiles - 1; i >= 0; i-- {
+		if _, err := os.Stat(name(i)); err != nil {
+			continue
+		}
+		if err := os.Rename(name(i), name(i+1)); err != nil {
+			return fmt.Errorf("failed to rotate capture file: %w", err)
+		}
+	}
+	return c.open()
+}
+
+// capture writes a packet into the file.
+func (c *captureWriter) capture(d CaptureDirection, peer string, data []byte) error {
+	var rec = CaptureRecord{
+		Timestamp: time.Now(),
+		Direction: d,
+		Peer:      peer,
+		Data:      data,
+	}
+	w := io.NewBufBinWriter()
+	rec.EncodeBinary(w.BinWriter)
+	if w.Err != nil {
+		return w.Err
+	}
+	b := w.Bytes()
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if c.full {
+		return nil
+	}
+	if c.file == nil {
+		return errors.New("capture file is closed")
+	}
+	if c.size+int64(len(b)) > c.cfg.MaxFileSize {
+		if c.cfg.MaxFiles == 0 {
+			c.full = true
+			err := c.file.Close()
+			c.file = nil
+			if err != nil {
+				return err
+			}
+			return fmt.Errorf("capture file %s size limit reached, capturing is stopped", c.cfg.FilePath)
+		}
+		if err := c.rotate(); err != nil {
+			return err
+		}
+	}
+	n, err := c.file.Write(b)
+	c.size += int64(n)
+	return err
+}
+
+// Close closes the capture file.
+func (c *captureWriter) Close() error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if c.file == nil {
+		return nil
+	}
+	err := c.file.Close()
+	c.file = nil
+	return err
+}
@@ -0,0 +1,151 @@
+package network
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/nspcc-dev/neo-go/pkg/config"
+	"github.com/nspcc-dev/neo-go/pkg/config/netmode"
+	nio "github.com/nspcc-dev/neo-go/pkg/io"
+	"github.com/nspcc-dev/neo-go/pkg/network/payload"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/stretchr/testify/require"
+)
+
+func readCaptureFile(t *testing.T, path string) (*CaptureReader, []*CaptureRecord) {
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	t.Cleanup(func() { f.Close() })
+	cr, err := NewCaptureReader(f)
+	require.NoError(t, err)
+	var recs []*CaptureRecord
+	for {
+		rec, err := cr.Next()
+		if err == io.EOF {
+			return cr, recs
+		}
+		require.NoError(t, err)
+		recs = append(recs, rec)
+	}
+}
+
+func TestCapture(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "p2p.cap")
+	h := CaptureHeader{Magic: netmode.UnitTestNet, StateRootInHeader: true}
+	c, err := newCaptureWriter(config.P2PCapture{Enabled: true, FilePath: path}, h)
+	require.NoError(t, err)
+
+	ping, err := NewMessage(CMDPing, payload.NewPing(1, 2)).Bytes()
+	require.NoError(t, err)
+	pong, err := NewMessage(CMDPong, payload.NewPing(3, 4)).Bytes()
+	require.NoError(t, err)
+	require.NoError(t, c.capture(CaptureInbound, "127.0.0.1:20333", ping))
+	require.NoError(t, c.capture(CaptureOutbound, "127.0.0.1:20333", append(ping, pong...)))
+	require.NoError(t, c.Close())
+	require.Error(t, c.capture(CaptureInbound, "127.0.0.1:20333", ping))
+
+	cr, recs := readCaptureFile(t, path)
+	require.Equal(t, h, cr.CaptureHeader)
+	require.Equal(t, 2, len(recs))
+	require.Equal(t, CaptureInbound, recs[0].Direction)
+	require.Equal(t, "127.0.0.1:20333", recs[0].Peer)
+	require.Equal(t, ping, recs[0].Data)
+	require.Equal(t, CaptureOutbound, recs[1].Direction)
+
+	msgs, err := recs[1].Messages(h.StateRootInHeader)
+	require.NoError(t, err)
+	require.Equal(t, 2, len(msgs))
+	require.Equal(t, CMDPing, msgs[0].Command)
+	require.Equal(t, uint32(2), msgs[0].Payload.(*payload.Ping).Nonce)
+	require.Equal(t, CMDPong, msgs[1].Command)
+	require.Equal(t, uint32(4), msgs[1].Payload.(*payload.Ping).Nonce)
+
+	t.Run("truncated", func(t *testing.T) {
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(path, data[:len(data)-1], 0o644))
+		_, recs := readCaptureFile(t, path)
+		require.Equal(t, 1, len(recs))
+	})
+	t.Run("bad header", func(t *testing.T) {
+		_, err := NewCaptureReader(bytes.NewReader([]byte("NGCX\x00\x00\x00\x00\x00\x00")))
+		require.Error(t, err)
+	})
+}
+
+func TestCaptureRotation(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "p2p.cap")
+	require.NoError(t, os.WriteFile(path, []byte{1, 2, 3}, 0o644))
+
+	cfg := config.P2PCapture{Enabled: true, FilePath: path, MaxFileSize: 100, MaxFiles: 2}
+	c, err := newCaptureWriter(cfg, CaptureHeader{Magic: netmode.UnitTestNet})
+	require.NoError(t, err)
+	t.Cleanup(func() { c.Close() })
+
+	// Existing file is rotated on start.
+	data, err := os.ReadFile(path + ".1")
+	require.NoError(t, err)
+	require.Equal(t, []byte{1, 2, 3}, data)
+
+	for i := 0; i < 5; i++ {
+		require.NoError(t, c.capture(CaptureInbound, "peer", make([]byte, 60)))
+	}
+	require.NoError(t, c.Close())
+	for _, p := range []string{path, path + ".1", path + ".2"} {
+		_, recs := readCaptureFile(t, p)
+		require.Equal(t, 1, len(recs))
+	}
+	_, err = os.Stat(path + ".3")
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestCaptureAppend(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "p2p.cap")
+	h := CaptureHeader{Magic: netmode.UnitTestNet}
+	cfg := config.P2PCapture{Enabled: true, FilePath: path, MaxFileSize: 200}
+	c, err := newCaptureWriter(cfg, h)
+	require.NoError(t, err)
+	require.NoError(t, c.capture(CaptureInbound, "peer", make([]byte, 60)))
+	require.NoError(t, c.Close())
+
+	// Existing file is appended to without rotation.
+	c, err = newCaptureWriter(cfg, h)
+	require.NoError(t, err)
+	t.Cleanup(func() { c.Close() })
+	require.NoError(t, c.capture(CaptureInbound, "peer", make([]byte, 60)))
+	// Size limit reached, capturing is stopped without overwriting the file.
+	require.Error(t, c.capture(CaptureInbound, "peer", make([]byte, 60)))
+	require.NoError(t, c.capture(CaptureInbound, "peer", make([]byte, 60)))
+	_, recs := readCaptureFile(t, path)
+	require.Equal(t, 2, len(recs))
+	_, err = os.Stat(path + ".1")
+	require.ErrorIs(t, err, os.ErrNotExist)
+
+	t.Run("header mismatch", func(t *testing.T) {
+		_, err := newCaptureWriter(cfg, CaptureHeader{Magic: netmode.UnitTestNet, StateRootInHeader: true})
+		require.Error(t, err)
+	})
+	t.Run("bad file", func(t *testing.T) {
+		bad := filepath.Join(t.TempDir(), "bad.cap")
+		require.NoError(t, os.WriteFile(bad, []byte{1, 2, 3}, 0o644))
+		_, err := newCaptureWriter(config.P2PCapture{Enabled: true, FilePath: bad}, h)
+		require.Error(t, err)
+		data, err := os.ReadFile(bad)
+		require.NoError(t, err)
+		require.Equal(t, []byte{1, 2, 3}, data)
+	})
+}
+
+func TestMessageReceivedBytes(t *testing.T) {
+	m := NewMessage(CMDGetData, payload.NewInventory(payload.TXType, make([]util.Uint256, 100)))
+	data, err := m.Bytes()
+	require.NoError(t, err)
+	require.NotEqual(t, 0, m.Flags&Compressed)
+
+	decoded := &Message{}
+	require.NoError(t, decoded.Decode(nio.NewBinReaderFromBuf(data)))
+	require.Equal(t, data, decoded.receivedBytes())
+}
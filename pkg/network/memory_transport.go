@@ -0,0 +1,435 @@
+package network
+
+import (
+	"errors"
+	"fmt"
+	"hash/fnv"
+	mrand "math/rand"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// memoryQueueSize is the number of packets that can be in flight over a
+// single direction of in-memory connection before Write blocks.
+const memoryQueueSize = 1024
+
+var (
+	errConnRefused = errors.New("connection refused")
+	errUnreachable = errors.New("network is unreachable")
+)
+
+type (
+	// MemoryNetwork is an in-process network that connects MemoryTransport
+	// instances with each other. It allows to emulate link latency, packet
+	// (message) loss and network partitions between nodes which are
+	// identified by the host part of their bind addresses. All of its methods
+	// are safe for concurrent use, so link conditions can be changed while
+	// nodes are running.
+	MemoryNetwork struct {
+		lock      sync.Mutex
+		listeners map[string]*MemoryTransport
+		nextPort  map[string]int
+		latency   time.Duration
+		links     map[memoryLink]time.Duration
+		groups    map[string]int
+		loss      float64
+		seed      int64
+		// rnds contains random sources of the hosts sending packets.
+		rnds map[string]*mrand.Rand
+	}
+
+	// MemoryTransport is a Transporter that doesn't use any real sockets and
+	// communicates via MemoryNetwork instead.
+	MemoryTransport struct {
+		log      *zap.Logger
+		server   *Server
+		network  *MemoryNetwork
+		bindAddr string
+		hostPort hostPort
+		lock     sync.RWMutex
+		quit     chan struct{}
+		closed   bool
+	}
+
+	memoryLink struct {
+		from string
+		to   string
+	}
+
+	// memoryConn is one side of in-memory connection. Data written to it is
+	// delivered to the other side asynchronously in the order it was written
+	// with respect to the link conditions set in MemoryNetwork.
+	memoryConn struct {
+		net.Conn
+		network *MemoryNetwork
+		local   *net.TCPAddr
+		remote  *net.TCPAddr
+		queue   chan memoryPacket
+		done    chan struct{}
+		once    sync.Once
+	}
+
+	memoryPacket struct {
+		deliverAt time.Time
+		data      []byte
+	}
+)
+
+// NewMemoryNetwork creates a new MemoryNetwork with a perfect connectivity
+// between all nodes. The seed given is used for random message loss
+// decisions, every host gets its own random source derived from it, so that
+// the same sequence of packets sent by the host leads to the same set of
+// dropped messages irrespective of other hosts.
+func NewMemoryNetwork(seed int64) *MemoryNetwork {
+	return &MemoryNetwork{
+		listeners: make(map[string]*MemoryTransport),
+		nextPort:  make(map[string]int),
+		links:     make(map[memoryLink]time.Duration),
+		groups:    make(map[string]int),
+		seed:      seed,
+		rnds:      make(map[string]*mrand.Rand),
+	}
+}
+
+// SetLatency sets the default one-way latency for all links of the network.
+func (n *MemoryNetwork) SetLatency(d time.Duration) {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	n.latency = d
+}
+
+// SetLinkLatency sets one-way latency for packets sent from the host `from`
+// to the host `to`, it overrides the default network latency for this link.
+func (n *MemoryNetwork) SetLinkLatency(from, to string, d time.Duration) {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	n.links[memoryLink{from: from, to: to}] = d
+}
+
+// SetLoss sets the probability (0.0-1.0) of any packet being dropped. Packets
+// are only dropped as a whole, so the stream of messages stays consistent.
+func (n *MemoryNetwork) SetLoss(rate float64) {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	n.loss = rate
+}
+
+// Partition splits the network into the given groups of hosts. Hosts from
+// different groups can't connect to each other and all packets sent between
+// them over the existing connections are silently dropped (so these
+// connections eventually break by ping timeout). Hosts not mentioned in any
+// group form a separate group of their own.
+func (n *MemoryNetwork) Partition(groups ...[]string) {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	n.groups = make(map[string]int)
+	for i, g := range groups {
+		for _, h := range g {
+			n.groups[h] = i + 1
+		}
+	}
+}
+
+// Heal removes all partitions set previously.
+func (n *MemoryNetwork) Heal() {
+	n.Partition()
+}
+
+// reachable checks whether the host `to` can be reached from the host `from`.
+// It must be called with the lock held.
+func (n *MemoryNetwork) reachable(from, to string) bool {
+	return n.groups[from] == n.groups[to]
+}
+
+// route returns the time the packet sent now from `from` to `to` should be
+// delivered at and whether it should be delivered at all.
+func (n *MemoryNetwork) route(from, to string) (time.Time, bool) {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	if !n.reachable(from, to) {
+		return time.Time{}, false
+	}
+	if n.loss > 0 && n.hostRand(from).Float64() < n.loss {
+		return time.Time{}, false
+	}
+	d, ok := n.links[memoryLink{from: from, to: to}]
+	if !ok {
+		d = n.latency
+	}
+	return time.Now().Add(d), true
+}
+
+// hostRand returns the random source of the given host. It must be called
+// with the lock held.
+func (n *MemoryNetwork) hostRand(host string) *mrand.Rand {
+	r, ok := n.rnds[host]
+	if !ok {
+		h := fnv.New64a()
+		_, _ = h.Write([]byte(host))
+		r = mrand.New(mrand.NewSource(n.seed ^ int64(h.Sum64())))
+		n.rnds[host] = r
+	}
+	return r
+}
+
+// listen registers the given transport in the network, it allocates a port
+// if it's not specified.
+func (n *MemoryNetwork) listen(t *MemoryTransport) error {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	host, port := t.hostPort.Host, t.hostPort.Port
+	if port == "" || port == "0" {
+		port = strconv.Itoa(n.allocPort(host))
+	}
+	addr := net.JoinHostPort(host, port)
+	if _, ok := n.listeners[addr]; ok {
+		return fmt.Errorf("address %s is already in use", addr)
+	}
+	n.listeners[addr] = t
+	t.bindAddr = addr
+	t.hostPort.Port = port
+	return nil
+}
+
+// allocPort returns a new unique port number for the given host. It must be
+// called with the lock held.
+func (n *MemoryNetwork) allocPort(host string) int {
+	var p = n.nextPort[host]
+	if p == 0 {
+		p = 40000
+	}
+	n.nextPort[host] = p + 1
+	return p
+}
+
+// unlisten removes the given transport from the network.
+func (n *MemoryNetwork) unlisten(t *MemoryTransport) {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	if n.listeners[t.bindAddr] == t {
+		delete(n.listeners, t.bindAddr)
+	}
+}
+
+// connect creates a pair of connected memoryConn for the given source
+// transport and destination address. The first one belongs to the dialer and
+// the second one is to be accepted by the destination transport.
+func (n *MemoryNetwork) connect(from *MemoryTransport, addr string) (*memoryConn, *memoryConn, *MemoryTransport, error) {
+	remote, err := net.ResolveTCPAddr("tcp", addr)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	srcHost, _ := from.HostPort()
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	dst, ok := n.listeners[addr]
+	if !ok {
+		return nil, nil, nil, fmt.Errorf("dial %s: %w", addr, errConnRefused)
+	}
+	if !n.reachable(srcHost, remote.IP.String()) {
+		return nil, nil, nil, fmt.Errorf("dial %s: %w", addr, errUnreachable)
+	}
+	local, err := net.ResolveTCPAddr("tcp", net.JoinHostPort(srcHost, strconv.Itoa(n.allocPort(srcHost))))
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	c1, c2 := net.Pipe()
+	return newMemoryConn(n, c1, local, remote), newMemoryConn(n, c2, remote, local), dst, nil
+}
+
+func newMemoryConn(n *MemoryNetwork, c net.Conn, local, remote *net.TCPAddr) *memoryConn {
+	mc := &memoryConn{
+		Conn:    c,
+		network: n,
+		local:   local,
+		remote:  remote,
+		queue:   make(chan memoryPacket, memoryQueueSize),
+		done:    make(chan struct{}),
+	}
+	go mc.deliver()
+	return mc
+}
+
+// deliver is a goroutine that passes queued packets to the other side of the
+// connection when their time comes.
+func (c *memoryConn) deliver() {
+	for {
+		select {
+		case <-c.done:
+			return
+		case p := <-c.queue:
+			if d := time.Until(p.deliverAt); d > 0 {
+				t := time.NewTimer(d)
+				select {
+				case <-c.done:
+					t.Stop()
+					return
+				case <-t.C:
+				}
+			}
+			if _, err := c.Conn.Write(p.data); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// Write implements the net.Conn interface. It never fails for packets dropped
+// due to link conditions, the same way a real network doesn't report it.
+func (c *memoryConn) Write(b []byte) (int, error) {
+	select {
+	case <-c.done:
+		return 0, net.ErrClosed
+	default:
+	}
+	at, ok := c.network.route(c.local.IP.String(), c.remote.IP.String())
+	if !ok {
+		return len(b), nil
+	}
+	p := memoryPacket{deliverAt: at, data: make([]byte, len(b))}
+	copy(p.data, b)
+	select {
+	case c.queue <- p:
+		return len(b), nil
+	case <-c.done:
+		return 0, net.ErrClosed
+	}
+}
+
+// Close implements the net.Conn interface.
+func (c *memoryConn) Close() error {
+	c.once.Do(func() { close(c.done) })
+	return c.Conn.Close()
+}
+
+// LocalAddr implements the net.Conn interface.
+func (c *memoryConn) LocalAddr() net.Addr {
+	return c.local
+}
+
+// RemoteAddr implements the net.Conn interface.
+func (c *memoryConn) RemoteAddr() net.Addr {
+	return c.remote
+}
+
+// SetDeadline implements the net.Conn interface. Writes are asynchronous, so
+// only the read deadline is set.
+func (c *memoryConn) SetDeadline(t time.Time) error {
+	return c.Conn.SetReadDeadline(t)
+}
+
+// SetWriteDeadline implements the net.Conn interface. Writes are
+// asynchronous, so it's a no-op.
+func (c *memoryConn) SetWriteDeadline(time.Time) error {
+	return nil
+}
+
+// NewMemoryTransport returns a new MemoryTransport that will accept
+// connections from the given MemoryNetwork. The host part of bindAddr must be
+// an IP address, it's used to identify the node in the network.
+func NewMemoryTransport(s *Server, n *MemoryNetwork, bindAddr string, log *zap.Logger) *MemoryTransport {
+	host, port, err := net.SplitHostPort(bindAddr)
+	if err != nil {
+		// Only host can be provided, it's OK.
+		host = bindAddr
+	}
+	return &MemoryTransport{
+		log:      log,
+		server:   s,
+		network:  n,
+		bindAddr: bindAddr,
+		hostPort: hostPort{
+			Host: host,
+			Port: port,
+		},
+		quit: make(chan struct{}),
+	}
+}
+
+// NewMemoryServer returns a new Server that uses MemoryTransport for all of
+// its bind addresses. It's intended to be used in tests and simulations.
+func NewMemoryServer(config ServerConfig, chain Ledger, stSync StateSync, n *MemoryNetwork, log *zap.Logger) (*Server, error) {
+	return newServerFromConstructors(config, chain, stSync, log, func(s *Server, addr string) Transporter {
+		return NewMemoryTransport(s, n, addr, s.log)
+	}, newDefaultDiscovery)
+}
+
+// Dial implements the Transporter interface.
+func (t *MemoryTransport) Dial(addr string, timeout time.Duration) (AddressablePeer, error) {
+	local, remote, dst, err := t.network.connect(t, addr)
+	if err != nil {
+		return nil, err
+	}
+	if !dst.accept(remote) {
+		local.Close()
+		return nil, fmt.Errorf("dial %s: %w", addr, errConnRefused)
+	}
+	p := NewTCPPeer(local, addr, t.server)
+	go p.handleConn()
+	return p, nil
+}
+
+// accept handles incoming connection, it returns false if the transport is
+// closed already.
+func (t *MemoryTransport) accept(conn *memoryConn) bool {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+	if t.closed {
+		return false
+	}
+	p := NewTCPPeer(conn, "", t.server)
+	go p.handleConn()
+	return true
+}
+
+// Accept implements the Transporter interface.
+func (t *MemoryTransport) Accept() {
+	t.lock.Lock()
+	if t.closed {
+		t.lock.Unlock()
+		return
+	}
+	err := t.network.listen(t)
+	t.lock.Unlock()
+	if err != nil {
+		t.log.Panic("memory listen error", zap.Error(err))
+		return
+	}
+	<-t.quit
+	t.network.unlisten(t)
+}
+
+// Close implements the Transporter interface.
+func (t *MemoryTransport) Close() {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	if !t.closed {
+		t.closed = true
+		close(t.quit)
+	}
+}
+
+// Proto implements the Transporter interface.
+func (t *MemoryTransport) Proto() string {
+	return "tcp"
+}
+
+// HostPort implements the Transporter interface.
+func (t *MemoryTransport) HostPort() (string, string) {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	return t.hostPort.Host, t.hostPort.Port
+}
+
+// Address returns the address the transport is bound to.
+func (t *MemoryTransport) Address() string {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	return t.bindAddr
+}
@@ -0,0 +1,123 @@
+package network
+
+import (
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+)
+
+func newListeningMemoryTransport(t *testing.T, n *MemoryNetwork, addr string) *MemoryTransport {
+	tr := NewMemoryTransport(nil, n, addr, zaptest.NewLogger(t))
+	require.NoError(t, n.listen(tr))
+	return tr
+}
+
+func TestMemoryTransport_Listen(t *testing.T) {
+	n := NewMemoryNetwork(0)
+	tr := newListeningMemoryTransport(t, n, "10.0.0.1:0")
+	host, port := tr.HostPort()
+	require.Equal(t, "10.0.0.1", host)
+	require.Equal(t, "40000", port)
+	require.Equal(t, "10.0.0.1:40000", tr.Address())
+
+	require.Error(t, n.listen(NewMemoryTransport(nil, n, "10.0.0.1:40000", zaptest.NewLogger(t))))
+
+	n.unlisten(tr)
+	_, _, _, err := n.connect(tr, "10.0.0.1:40000")
+	require.ErrorIs(t, err, errConnRefused)
+}
+
+func TestMemoryTransport_Conn(t *testing.T) {
+	n := NewMemoryNetwork(0)
+	a := newListeningMemoryTransport(t, n, "10.0.0.1:20333")
+	b := newListeningMemoryTransport(t, n, "10.0.0.2:20333")
+
+	ca, cb, dst, err := n.connect(a, b.Address())
+	require.NoError(t, err)
+	require.Equal(t, b, dst)
+	t.Cleanup(func() {
+		ca.Close()
+		cb.Close()
+	})
+	require.Equal(t, "10.0.0.2:20333", ca.RemoteAddr().String())
+	require.Equal(t, "tcp", ca.RemoteAddr().Network())
+	require.Equal(t, ca.LocalAddr().String(), cb.RemoteAddr().String())
+
+	read := func(c io.Reader, l int) []byte {
+		buf := make([]byte, l)
+		_, err := io.ReadFull(c, buf)
+		require.NoError(t, err)
+		return buf
+	}
+
+	t.Run("order", func(t *testing.T) {
+		_, err := ca.Write([]byte{1, 2})
+		require.NoError(t, err)
+		_, err = ca.Write([]byte{3})
+		require.NoError(t, err)
+		require.Equal(t, []byte{1, 2, 3}, read(cb, 3))
+	})
+	t.Run("latency", func(t *testing.T) {
+		n.SetLinkLatency("10.0.0.2", "10.0.0.1", 50*time.Millisecond)
+		start := time.Now()
+		_, err := cb.Write([]byte{4})
+		require.NoError(t, err)
+		require.Equal(t, []byte{4}, read(ca, 1))
+		require.GreaterOrEqual(t, time.Since(start), 50*time.Millisecond)
+		n.SetLinkLatency("10.0.0.2", "10.0.0.1", 0)
+	})
+	t.Run("partition", func(t *testing.T) {
+		n.Partition([]string{"10.0.0.1"})
+		_, err := ca.Write([]byte{5})
+		require.NoError(t, err) // Silently dropped.
+		_, _, _, err = n.connect(a, b.Address())
+		require.ErrorIs(t, err, errUnreachable)
+
+		n.Heal()
+		_, err = ca.Write([]byte{6})
+		require.NoError(t, err)
+		require.Equal(t, []byte{6}, read(cb, 1))
+	})
+	t.Run("loss", func(t *testing.T) {
+		n.SetLoss(1)
+		_, err := ca.Write([]byte{7})
+		require.NoError(t, err)
+		n.SetLoss(0)
+		_, err = ca.Write([]byte{8})
+		require.NoError(t, err)
+		require.Equal(t, []byte{8}, read(cb, 1))
+	})
+	t.Run("close", func(t *testing.T) {
+		require.NoError(t, ca.Close())
+		_, err := ca.Write([]byte{9})
+		require.Error(t, err)
+		_, err = cb.Read(make([]byte, 1))
+		require.Error(t, err)
+	})
+}
+
+func TestMemoryNetwork_LossDeterministic(t *testing.T) {
+	var drops = func() []bool {
+		n := NewMemoryNetwork(42)
+		n.SetLoss(0.5)
+		res := make([]bool, 100)
+		for i := range res {
+			_, res[i] = n.route("10.0.0.1", "10.0.0.2")
+		}
+		return res
+	}
+	require.Equal(t, drops(), drops())
+
+	// Packets sent by other hosts don't affect the decisions.
+	n := NewMemoryNetwork(42)
+	n.SetLoss(0.5)
+	res := make([]bool, 100)
+	for i := range res {
+		_, _ = n.route("10.0.0.2", "10.0.0.1")
+		_, res[i] = n.route("10.0.0.1", "10.0.0.2")
+	}
+	require.Equal(t, drops(), res)
+}
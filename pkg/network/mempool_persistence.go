@@ -0,0 +1,128 @@
+package network
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/nspcc-dev/neo-go/pkg/core/transaction"
+	"github.com/nspcc-dev/neo-go/pkg/io"
+	"github.com/nspcc-dev/neo-go/pkg/network/payload"
+	"go.uber.org/zap"
+)
+
+// mempoolFileVersion is the current version of the memory pool file format.
+const mempoolFileVersion = 0
+
+// poolDump is the memory pool file contents.
+type poolDump struct {
+	Transactions   []*transaction.Transaction
+	NotaryRequests []*payload.P2PNotaryRequest
+}
+
+// EncodeBinary implements the io.Serializable interface.
+func (d *poolDump) EncodeBinary(w *io.BinWriter) {
+	w.WriteB(mempoolFileVersion)
+	w.WriteArray(d.Transactions)
+	w.WriteArray(d.NotaryRequests)
+}
+
+// DecodeBinary implements the io.Serializable interface.
+func (d *poolDump) DecodeBinary(r *io.BinReader) {
+	if v := r.ReadB(); r.Err == nil && v != mempoolFileVersion {
+		r.Err = fmt.Errorf("unsupported memory pool file version %d", v)
+		return
+	}
+	r.ReadArray(&d.Transactions)
+	r.ReadArray(&d.NotaryRequests)
+}
+
+// saveMemPools writes the contents of the memory pool and P2PNotaryRequest
+// payload pool (if any) to the file specified in the configuration.
+func (s *Server) saveMemPools() error {
+	var d = poolDump{
+		Transactions: s.mempool.GetVerifiedTransactions(),
+	}
+	if s.chain.P2PSigExtensionsEnabled() {
+		s.notaryRequestPool.IterateVerifiedTransactions(func(_ *transaction.Transaction, data any) bool {
+			d.NotaryRequests = append(d.NotaryRequests, data.(*payload.P2PNotaryRequest))
+			return true
+		})
+	}
+	w := io.NewBufBinWriter()
+	d.EncodeBinary(w.BinWriter)
+	if w.Err != nil {
+		return w.Err
+	}
+	// Write to a temporary file first to not break the existing one.
+	tmp := s.MempoolCfg.PersistFile + ".tmp"
+	if err := os.WriteFile(tmp, w.Bytes(), 0o644); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, s.MempoolCfg.PersistFile); err != nil {
+		_ = os.Remove(tmp)
+		return err
+	}
+	s.log.Info("memory pool saved",
+		zap.Int("transactions", len(d.Transactions)),
+		zap.Int("notary requests", len(d.NotaryRequests)))
+	return nil
+}
+
+// restoreMemPools reads the file saved by saveMemPools and adds its contents
+// to the pools with full verification. Expired transactions are skipped as well
+// as the ones failing verification (conflicting with the chain or other pooled
+// transactions). The file is removed after loading to not restore the same
+// contents after a crash.
+func (s *Server) restoreMemPools() error {
+	data, err := os.ReadFile(s.MempoolCfg.PersistFile)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+	var (
+		d              poolDump
+		r              = io.NewBinReaderFromBuf(data)
+		height         = s.chain.BlockHeight()
+		txs, requests  int
+		expired, fails int
+	)
+	d.DecodeBinary(r)
+	if r.Err != nil {
+		return fmt.Errorf("failed to decode memory pool file: %w", r.Err)
+	}
+	for _, tx := range d.Transactions {
+		if tx.ValidUntilBlock <= height {
+			expired++
+			continue
+		}
+		if err := s.chain.PoolTx(tx); err != nil {
+			s.log.Debug("failed to restore transaction", zap.Stringer("hash", tx.Hash()), zap.Error(err))
+			fails++
+			continue
+		}
+		txs++
+	}
+	if s.chain.P2PSigExtensionsEnabled() {
+		for _, req := range d.NotaryRequests {
+			if req.MainTransaction.ValidUntilBlock <= height || req.FallbackTransaction.ValidUntilBlock <= height {
+				expired++
+				continue
+			}
+			if err := s.verifyAndPoolNotaryRequest(req); err != nil {
+				s.log.Debug("failed to restore notary request", zap.Stringer("hash", req.Hash()), zap.Error(err))
+				fails++
+				continue
+			}
+			requests++
+		}
+	}
+	s.log.Info("memory pool restored",
+		zap.Int("transactions", txs),
+		zap.Int("notary requests", requests),
+		zap.Int("expired", expired),
+		zap.Int("failed", fails))
+	return os.Remove(s.MempoolCfg.PersistFile)
+}
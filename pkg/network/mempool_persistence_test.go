@@ -0,0 +1,111 @@
+package network
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/nspcc-dev/neo-go/internal/fakechain"
+	"github.com/nspcc-dev/neo-go/internal/random"
+	"github.com/nspcc-dev/neo-go/internal/testserdes"
+	"github.com/nspcc-dev/neo-go/pkg/config"
+	"github.com/nspcc-dev/neo-go/pkg/core/transaction"
+	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
+	"github.com/nspcc-dev/neo-go/pkg/network/payload"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/nspcc-dev/neo-go/pkg/vm/opcode"
+	"github.com/stretchr/testify/require"
+)
+
+func newDummyNotaryRequest(vub uint32) *payload.P2PNotaryRequest {
+	mainTx := transaction.New(random.Bytes(10), 123)
+	mainTx.ValidUntilBlock = vub
+	mainTx.Signers = []transaction.Signer{{Account: random.Uint160()}}
+	mainTx.Attributes = []transaction.Attribute{{Type: transaction.NotaryAssistedT, Value: &transaction.NotaryAssisted{NKeys: 1}}}
+	mainTx.Scripts = []transaction.Witness{{InvocationScript: []byte{}, VerificationScript: []byte{}}}
+	fallbackTx := transaction.New(random.Bytes(10), 123)
+	fallbackTx.ValidUntilBlock = vub
+	fallbackTx.Signers = []transaction.Signer{{Account: random.Uint160()}, {Account: random.Uint160()}}
+	fallbackTx.Attributes = []transaction.Attribute{
+		{Type: transaction.NotValidBeforeT, Value: &transaction.NotValidBefore{Height: vub - 1}},
+		{Type: transaction.ConflictsT, Value: &transaction.Conflicts{Hash: mainTx.Hash()}},
+		{Type: transaction.NotaryAssistedT, Value: &transaction.NotaryAssisted{NKeys: 0}},
+	}
+	fallbackTx.Scripts = []transaction.Witness{
+		{InvocationScript: append([]byte{byte(opcode.PUSHDATA1), keys.SignatureLen}, make([]byte, keys.SignatureLen)...), VerificationScript: []byte{}},
+		{InvocationScript: []byte{}, VerificationScript: []byte{}},
+	}
+	return &payload.P2PNotaryRequest{
+		MainTransaction:     mainTx,
+		FallbackTransaction: fallbackTx,
+		Witness: transaction.Witness{
+			InvocationScript:   []byte{1, 2, 3},
+			VerificationScript: []byte{1, 2, 3},
+		},
+	}
+}
+
+func TestPoolDumpEncodeDecode(t *testing.T) {
+	d := &poolDump{
+		Transactions:   []*transaction.Transaction{newDummyTx(), newDummyTx()},
+		NotaryRequests: []*payload.P2PNotaryRequest{newDummyNotaryRequest(10)},
+	}
+	actual := new(poolDump)
+	data, err := testserdes.EncodeBinary(d)
+	require.NoError(t, err)
+	require.NoError(t, testserdes.DecodeBinary(data, actual))
+	require.Equal(t, len(d.Transactions), len(actual.Transactions))
+	for i := range d.Transactions {
+		require.Equal(t, d.Transactions[i].Hash(), actual.Transactions[i].Hash())
+	}
+	require.Equal(t, 1, len(actual.NotaryRequests))
+	require.Equal(t, d.NotaryRequests[0].Hash(), actual.NotaryRequests[0].Hash())
+
+	data[0] = 1
+	require.Error(t, testserdes.DecodeBinary(data, new(poolDump)))
+}
+
+func TestMemPoolPersistence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "mempool.bin")
+	cfg := ServerConfig{MempoolCfg: config.Mempool{PersistFile: path}}
+
+	s := newTestServer(t, cfg)
+	startWithCleanup(t, s)
+	bc := s.chain.(*fakechain.FakeChain)
+	bc.Blockheight.Store(10)
+	var newTx = func(vub uint32) *transaction.Transaction {
+		tx := transaction.New(random.Bytes(10), 123)
+		tx.ValidUntilBlock = vub
+		tx.Signers = []transaction.Signer{{Account: random.Uint160()}}
+		tx.Scripts = []transaction.Witness{{InvocationScript: []byte{}, VerificationScript: []byte{}}}
+		return tx
+	}
+	var valid, expired = newTx(100), newTx(15)
+	for _, tx := range []*transaction.Transaction{valid, expired} {
+		require.NoError(t, bc.Pool.Add(tx, &feerStub{blockHeight: 10}))
+	}
+	r := newDummyNotaryRequest(100)
+	require.NoError(t, s.notaryRequestPool.Add(r.FallbackTransaction, &feerStub{blockHeight: 10}, r))
+	s.Shutdown()
+
+	f, err := os.ReadFile(path)
+	require.NoError(t, err)
+	var d poolDump
+	require.NoError(t, testserdes.DecodeBinary(f, &d))
+	require.Equal(t, 2, len(d.Transactions))
+	require.Equal(t, 1, len(d.NotaryRequests))
+	require.Equal(t, r.Hash(), d.NotaryRequests[0].Hash())
+
+	s = newTestServer(t, cfg)
+	bc = s.chain.(*fakechain.FakeChain)
+	bc.Blockheight.Store(20)
+	var restored []util.Uint256
+	bc.PoolTxF = func(tx *transaction.Transaction) error {
+		restored = append(restored, tx.Hash())
+		return nil
+	}
+	startWithCleanup(t, s)
+	require.Equal(t, []util.Uint256{valid.Hash()}, restored)
+	_, err = os.Stat(path)
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
@@ -0,0 +1,190 @@
+package network
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"time"
+
+	"github.com/nspcc-dev/neo-go/pkg/config"
+	"golang.org/x/net/proxy"
+)
+
+var (
+	errPeerDenied       = errors.New("peer address is denied by policy")
+	errSubnetPeersLimit = errors.New("max peers per subnet reached")
+)
+
+// peerPolicy implements restrictions for inbound and outbound P2P connections
+// and dials outbound ones (directly or via SOCKS5 proxy).
+type peerPolicy struct {
+	cfg      config.P2PPeerPolicy
+	allowIn  []*net.IPNet
+	denyIn   []*net.IPNet
+	allowOut []*net.IPNet
+	denyOut  []*net.IPNet
+	// proxy is nil for direct connections.
+	proxy proxy.ContextDialer
+}
+
+// newPeerPolicy creates peerPolicy from the given configuration.
+func newPeerPolicy(cfg config.P2PPeerPolicy, px config.P2PProxy) (*peerPolicy, error) {
+	var (
+		p   = &peerPolicy{cfg: cfg}
+		err error
+	)
+	if cfg.DisableIPv4 && cfg.DisableIPv6 {
+		return nil, errors.New("both IPv4 and IPv6 are disabled")
+	}
+	for _, l := range []struct {
+		name string
+		list []string
+		res  *[]*net.IPNet
+	}{
+		{"AllowInbound", cfg.AllowInbound, &p.allowIn},
+		{"DenyInbound", cfg.DenyInbound, &p.denyIn},
+		{"AllowOutbound", cfg.AllowOutbound, &p.allowOut},
+		{"DenyOutbound", cfg.DenyOutbound, &p.denyOut},
+	} {
+		*l.res, err = parseCIDRs(l.list)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s list: %w", l.name, err)
+		}
+	}
+	if px.Address != "" {
+		var auth *proxy.Auth
+		if px.Username != "" {
+			auth = &proxy.Auth{User: px.Username, Password: px.Password}
+		}
+		d, err := proxy.SOCKS5("tcp", px.Address, auth, proxy.Direct)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy configuration: %w", err)
+		}
+		p.proxy = d.(proxy.ContextDialer)
+	}
+	return p, nil
+}
+
+// parseCIDRs parses a list of CIDRs, plain IP addresses are also accepted
+// (as single-address networks).
+func parseCIDRs(list []string) ([]*net.IPNet, error) {
+	var res = make([]*net.IPNet, 0, len(list))
+	for _, s := range list {
+		_, n, err := net.ParseCIDR(s)
+		if err != nil {
+			ip := net.ParseIP(s)
+			if ip == nil {
+				return nil, fmt.Errorf("bad CIDR %q", s)
+			}
+			bits := 8 * net.IPv6len
+			if ip4 := ip.To4(); ip4 != nil {
+				ip, bits = ip4, 8*net.IPv4len
+			}
+			n = &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)}
+		}
+		res = append(res, n)
+	}
+	return res, nil
+}
+
+// matchNets checks whether the IP belongs to any of the networks.
+func matchNets(ip net.IP, nets []*net.IPNet) bool {
+	for _, n := range nets {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// subnetOf returns the /24 subnet for IPv4 addresses and /64 for IPv6 ones.
+func subnetOf(ip net.IP) string {
+	if ip4 := ip.To4(); ip4 != nil {
+		return ip4.Mask(net.CIDRMask(24, 8*net.IPv4len)).String()
+	}
+	return ip.Mask(net.CIDRMask(64, 8*net.IPv6len)).String()
+}
+
+// allowInbound checks whether connections from the given IP are allowed.
+func (p *peerPolicy) allowInbound(ip net.IP) bool {
+	return (len(p.allowIn) == 0 || matchNets(ip, p.allowIn)) && !matchNets(ip, p.denyIn)
+}
+
+// allowOutbound checks whether connections to the given IP are allowed.
+func (p *peerPolicy) allowOutbound(ip net.IP) bool {
+	if ip.To4() != nil {
+		if p.cfg.DisableIPv4 {
+			return false
+		}
+	} else if p.cfg.DisableIPv6 {
+		return false
+	}
+	return (len(p.allowOut) == 0 || matchNets(ip, p.allowOut)) && !matchNets(ip, p.denyOut)
+}
+
+// restrictsOutbound returns true if outbound connections depend on the
+// target IP address, so host names need to be resolved before dialing.
+func (p *peerPolicy) restrictsOutbound() bool {
+	return p.cfg.DisableIPv4 || p.cfg.DisableIPv6 || p.cfg.PreferIPv6 ||
+		len(p.allowOut) != 0 || len(p.denyOut) != 0 || p.cfg.MaxPeersPerSubnet > 0
+}
+
+// dial establishes outbound TCP connection to the given address. If policy
+// restricts outbound connections, host names are resolved locally (even if
+// proxy is used) and all allowed addresses are tried in order of preference.
+// check is called for every address before dialing it, any error returned
+// from it prevents the connection.
+func (p *peerPolicy) dial(addr string, timeout time.Duration, check func(net.IP) error) (net.Conn, error) {
+	var ctx = context.Background()
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, err
+	}
+	var ips []net.IP
+	if ip := net.ParseIP(host); ip != nil {
+		ips = []net.IP{ip}
+	} else if !p.restrictsOutbound() {
+		return p.dialContext(ctx, addr)
+	} else {
+		ips, err = net.DefaultResolver.LookupIP(ctx, "ip", host)
+		if err != nil {
+			return nil, err
+		}
+		sort.SliceStable(ips, func(i, j int) bool {
+			return (ips[i].To4() == nil) == p.cfg.PreferIPv6 && (ips[j].To4() == nil) != p.cfg.PreferIPv6
+		})
+	}
+	err = fmt.Errorf("%w: %s", errPeerDenied, addr)
+	for _, ip := range ips {
+		if !p.allowOutbound(ip) {
+			continue
+		}
+		if check != nil {
+			if err = check(ip); err != nil {
+				continue
+			}
+		}
+		var conn net.Conn
+		conn, err = p.dialContext(ctx, net.JoinHostPort(ip.String(), port))
+		if err == nil {
+			return conn, nil
+		}
+	}
+	return nil, err
+}
+
+// dialContext connects to the given address directly or via proxy.
+func (p *peerPolicy) dialContext(ctx context.Context, addr string) (net.Conn, error) {
+	if p.proxy != nil {
+		return p.proxy.DialContext(ctx, "tcp", addr)
+	}
+	var d net.Dialer
+	return d.DialContext(ctx, "tcp", addr)
+}
@@ -0,0 +1,264 @@
+package network
+
+import (
+	"encoding/binary"
+	"io"
+	"net"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/nspcc-dev/neo-go/pkg/config"
+	"github.com/stretchr/testify/require"
+)
+
+// startSOCKS5 starts a minimal SOCKS5 proxy stand-in (CONNECT command only)
+// and returns its address and a channel with requested targets.
+func startSOCKS5(t *testing.T, user, pass string) (string, <-chan string) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { l.Close() })
+
+	targets := make(chan string, 10)
+	handle := func(c net.Conn) {
+		defer c.Close()
+		var buf = make([]byte, 256)
+		// Greeting.
+		if _, err := io.ReadFull(c, buf[:2]); err != nil || buf[0] != 5 {
+			return
+		}
+		if _, err := io.ReadFull(c, buf[:buf[1]]); err != nil {
+			return
+		}
+		if user == "" {
+			_, _ = c.Write([]byte{5, 0})
+		} else {
+			_, _ = c.Write([]byte{5, 2})
+			if _, err := io.ReadFull(c, buf[:2]); err != nil {
+				return
+			}
+			u := make([]byte, buf[1])
+			if _, err := io.ReadFull(c, u); err != nil {
+				return
+			}
+			if _, err := io.ReadFull(c, buf[:1]); err != nil {
+				return
+			}
+			p := make([]byte, buf[0])
+			if _, err := io.ReadFull(c, p); err != nil {
+				return
+			}
+			if string(u) != user || string(p) != pass {
+				_, _ = c.Write([]byte{1, 1})
+				return
+			}
+			_, _ = c.Write([]byte{1, 0})
+		}
+		// Request.
+		if _, err := io.ReadFull(c, buf[:4]); err != nil || buf[1] != 1 {
+			return
+		}
+		var host string
+		switch buf[3] {
+		case 1, 4:
+			ip := make(net.IP, 4)
+			if buf[3] == 4 {
+				ip = make(net.IP, 16)
+			}
+			if _, err := io.ReadFull(c, ip); err != nil {
+				return
+			}
+			host = ip.String()
+		case 3:
+			if _, err := io.ReadFull(c, buf[:1]); err != nil {
+				return
+			}
+			name := make([]byte, buf[0])
+			if _, err := io.ReadFull(c, name); err != nil {
+				return
+			}
+			host = string(name)
+		default:
+			return
+		}
+		if _, err := io.ReadFull(c, buf[:2]); err != nil {
+			return
+		}
+		target := net.JoinHostPort(host, strconv.Itoa(int(binary.BigEndian.Uint16(buf[:2]))))
+		targets <- target
+		dst, err := net.Dial("tcp", target)
+		if err != nil {
+			_, _ = c.Write([]byte{5, 5, 0, 1, 0, 0, 0, 0, 0, 0})
+			return
+		}
+		defer dst.Close()
+		_, _ = c.Write([]byte{5, 0, 0, 1, 0, 0, 0, 0, 0, 0})
+		go func() { _, _ = io.Copy(dst, c) }()
+		_, _ = io.Copy(c, dst)
+	}
+	go func() {
+		for {
+			c, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go handle(c)
+		}
+	}()
+	return l.Addr().String(), targets
+}
+
+// startEcho starts a TCP server replying with the data received.
+func startEcho(t *testing.T) string {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { l.Close() })
+	go func() {
+		for {
+			c, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer c.Close()
+				_, _ = io.Copy(c, c)
+			}()
+		}
+	}()
+	return l.Addr().String()
+}
+
+func checkEcho(t *testing.T, c net.Conn) {
+	defer c.Close()
+	_, err := c.Write([]byte{1, 2, 3})
+	require.NoError(t, err)
+	var buf = make([]byte, 3)
+	_, err = io.ReadFull(c, buf)
+	require.NoError(t, err)
+	require.Equal(t, []byte{1, 2, 3}, buf)
+}
+
+func TestPeerPolicy_Config(t *testing.T) {
+	_, err := newPeerPolicy(config.P2PPeerPolicy{DisableIPv4: true, DisableIPv6: true}, config.P2PProxy{})
+	require.Error(t, err)
+	_, err = newPeerPolicy(config.P2PPeerPolicy{AllowInbound: []string{"10.0.0.0/33"}}, config.P2PProxy{})
+	require.Error(t, err)
+	_, err = newPeerPolicy(config.P2PPeerPolicy{DenyOutbound: []string{"bad"}}, config.P2PProxy{})
+	require.Error(t, err)
+
+	p, err := newPeerPolicy(config.P2PPeerPolicy{
+		AllowInbound: []string{"10.0.0.0/8", "192.168.1.1"},
+		DenyInbound:  []string{"10.1.0.0/16"},
+		DenyOutbound: []string{"172.16.0.0/12", "fd00::/8"},
+	}, config.P2PProxy{})
+	require.NoError(t, err)
+	require.True(t, p.allowInbound(net.ParseIP("10.2.3.4")))
+	require.True(t, p.allowInbound(net.ParseIP("192.168.1.1")))
+	require.False(t, p.allowInbound(net.ParseIP("192.168.1.2")))
+	require.False(t, p.allowInbound(net.ParseIP("10.1.3.4")))
+	require.True(t, p.allowOutbound(net.ParseIP("8.8.8.8")))
+	require.True(t, p.allowOutbound(net.ParseIP("2001:db8::1")))
+	require.False(t, p.allowOutbound(net.ParseIP("172.17.0.1")))
+	require.False(t, p.allowOutbound(net.ParseIP("fd00::1")))
+
+	p, err = newPeerPolicy(config.P2PPeerPolicy{DisableIPv6: true}, config.P2PProxy{})
+	require.NoError(t, err)
+	require.True(t, p.allowOutbound(net.ParseIP("8.8.8.8")))
+	require.False(t, p.allowOutbound(net.ParseIP("2001:db8::1")))
+
+	require.Equal(t, subnetOf(net.ParseIP("10.0.0.1")), subnetOf(net.ParseIP("10.0.0.254")))
+	require.NotEqual(t, subnetOf(net.ParseIP("10.0.0.1")), subnetOf(net.ParseIP("10.0.1.1")))
+	require.Equal(t, subnetOf(net.ParseIP("2001:db8::1")), subnetOf(net.ParseIP("2001:db8::ffff:1")))
+	require.NotEqual(t, subnetOf(net.ParseIP("2001:db8::1")), subnetOf(net.ParseIP("2001:db8:0:1::1")))
+}
+
+func TestPeerPolicy_Dial(t *testing.T) {
+	echo := startEcho(t)
+	_, port, err := net.SplitHostPort(echo)
+	require.NoError(t, err)
+
+	t.Run("direct", func(t *testing.T) {
+		p, err := newPeerPolicy(config.P2PPeerPolicy{}, config.P2PProxy{})
+		require.NoError(t, err)
+		c, err := p.dial(echo, time.Second, nil)
+		require.NoError(t, err)
+		checkEcho(t, c)
+	})
+	t.Run("denied", func(t *testing.T) {
+		p, err := newPeerPolicy(config.P2PPeerPolicy{DenyOutbound: []string{"127.0.0.0/8"}}, config.P2PProxy{})
+		require.NoError(t, err)
+		_, err = p.dial(echo, time.Second, nil)
+		require.ErrorIs(t, err, errPeerDenied)
+		_, err = p.dial(net.JoinHostPort("localhost", port), time.Second, nil)
+		require.Error(t, err)
+	})
+	t.Run("check", func(t *testing.T) {
+		p, err := newPeerPolicy(config.P2PPeerPolicy{}, config.P2PProxy{})
+		require.NoError(t, err)
+		_, err = p.dial(echo, time.Second, func(net.IP) error { return errSubnetPeersLimit })
+		require.ErrorIs(t, err, errSubnetPeersLimit)
+	})
+	t.Run("proxy", func(t *testing.T) {
+		proxyAddr, targets := startSOCKS5(t, "", "")
+		p, err := newPeerPolicy(config.P2PPeerPolicy{}, config.P2PProxy{Address: proxyAddr})
+		require.NoError(t, err)
+		c, err := p.dial(echo, time.Second, nil)
+		require.NoError(t, err)
+		require.Equal(t, echo, <-targets)
+		checkEcho(t, c)
+
+		// Host names are resolved by proxy if there are no restrictions.
+		c, err = p.dial(net.JoinHostPort("localhost", port), time.Second, nil)
+		require.NoError(t, err)
+		require.Equal(t, net.JoinHostPort("localhost", port), <-targets)
+		c.Close()
+	})
+	t.Run("proxy with auth", func(t *testing.T) {
+		proxyAddr, targets := startSOCKS5(t, "user", "pass")
+		p, err := newPeerPolicy(config.P2PPeerPolicy{}, config.P2PProxy{Address: proxyAddr, Username: "user", Password: "pass"})
+		require.NoError(t, err)
+		c, err := p.dial(echo, time.Second, nil)
+		require.NoError(t, err)
+		require.Equal(t, echo, <-targets)
+		checkEcho(t, c)
+
+		p, err = newPeerPolicy(config.P2PPeerPolicy{}, config.P2PProxy{Address: proxyAddr, Username: "user", Password: "bad"})
+		require.NoError(t, err)
+		_, err = p.dial(echo, time.Second, nil)
+		require.Error(t, err)
+	})
+	t.Run("proxy with restrictions", func(t *testing.T) {
+		proxyAddr, targets := startSOCKS5(t, "", "")
+		p, err := newPeerPolicy(config.P2PPeerPolicy{DisableIPv6: true}, config.P2PProxy{Address: proxyAddr})
+		require.NoError(t, err)
+		c, err := p.dial(net.JoinHostPort("localhost", port), time.Second, nil)
+		require.NoError(t, err)
+		require.Equal(t, echo, <-targets) // Resolved locally.
+		checkEcho(t, c)
+	})
+}
+
+func TestServerMaxPeersPerSubnet(t *testing.T) {
+	s := newTestServer(t, ServerConfig{MaxPeers: 10, PeerPolicy: config.P2PPeerPolicy{MaxPeersPerSubnet: 2}})
+	startWithCleanup(t, s)
+
+	var peers = make([]*localPeer, 4)
+	for i, addr := range []string{"10.0.0.1:20333", "10.0.0.2:20333", "10.0.1.1:20333", "10.0.0.3:20333"} {
+		peers[i] = newLocalPeer(t, s)
+		a, err := net.ResolveTCPAddr("tcp", addr)
+		require.NoError(t, err)
+		peers[i].netaddr = *a
+		s.register <- peers[i]
+		if i < 3 {
+			require.Eventually(t, func() bool { return s.PeerCount() == i+1 }, time.Second, 10*time.Millisecond)
+		}
+	}
+	require.Eventually(t, func() bool { return peers[3].droppedWith.Load() != nil }, time.Second, 10*time.Millisecond)
+	require.ErrorIs(t, peers[3].droppedWith.Load().(error), errSubnetPeersLimit)
+	for _, p := range peers[:3] {
+		require.Nil(t, p.droppedWith.Load())
+	}
+
+	require.ErrorIs(t, s.checkSubnet(net.ParseIP("10.0.0.100"), nil), errSubnetPeersLimit)
+	require.NoError(t, s.checkSubnet(net.ParseIP("10.0.1.100"), nil))
+}
@@ -140,6 +140,12 @@ type (
 
 		stateSync StateSync
 
+		// capture is an optional P2P message capture writer.
+		capture *captureWriter
+
+		// policy restricts peer connections.
+		policy *peerPolicy
+
 		log *zap.Logger
 
 		// started used to Start and Shutdown server only once.
@@ -251,6 +257,11 @@ func newServerFromConstructors(config ServerConfig, chain Ledger, stSync StateSy
 	if len(s.ServerConfig.Addresses) == 0 {
 		return nil, errors.New("no bind addresses configured")
 	}
+	var err error
+	s.policy, err = newPeerPolicy(s.PeerPolicy, s.Proxy)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize peer policy: %w", err)
+	}
 	transports := make([]Transporter, len(s.ServerConfig.Addresses))
 	for i, addr := range s.ServerConfig.Addresses {
 		transports[i] = newTransport(s, addr.Address)
@@ -263,10 +274,77 @@ func newServerFromConstructors(config ServerConfig, chain Ledger, stSync StateSy
 		// dial, and it doesn't matter which one.
 		s.transports[0],
 	)
+	// Capture file is opened last, so that there is nothing to close if
+	// server construction fails.
+	if s.Capture.Enabled {
+		s.capture, err = newCaptureWriter(s.Capture, CaptureHeader{
+			Magic:             s.Net,
+			StateRootInHeader: s.config.StateRootInHeader,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize P2P capture: %w", err)
+		}
+	}
 
 	return s, nil
 }
 
+// capturePacket writes the given P2P packet into the capture file if capturing
+// is enabled.
+func (s *Server) capturePacket(d CaptureDirection, p Peer, data []byte) {
+	if s.capture == nil {
+		return
+	}
+	if err := s.capture.capture(d, p.RemoteAddr().String(), data); err != nil {
+		s.log.Warn("failed to capture P2P packet", zap.Stringer("direction", d), zap.Error(err))
+	}
+}
+
+// peerIP returns the IP address of the peer used for policy checks, it's nil
+// if the address can't be determined (host name connected to via proxy).
+func (s *Server) peerIP(p Peer) net.IP {
+	host, _, err := net.SplitHostPort(p.ConnectionAddr())
+	if err == nil {
+		if ip := net.ParseIP(host); ip != nil {
+			return ip
+		}
+	}
+	if s.policy.proxy != nil {
+		return nil
+	}
+	if addr, ok := p.RemoteAddr().(*net.TCPAddr); ok {
+		return addr.IP
+	}
+	return nil
+}
+
+// checkSubnet returns an error if the number of peers connected from the same
+// subnet as the given IP reached MaxPeersPerSubnet limit. The peer itself (if
+// it's already registered) is not counted.
+func (s *Server) checkSubnet(ip net.IP, self Peer) error {
+	if s.PeerPolicy.MaxPeersPerSubnet <= 0 || ip == nil {
+		return nil
+	}
+	var (
+		subnet = subnetOf(ip)
+		n      int
+	)
+	s.lock.RLock()
+	for p := range s.peers {
+		if p == self {
+			continue
+		}
+		if pip := s.peerIP(p); pip != nil && subnetOf(pip) == subnet {
+			n++
+		}
+	}
+	s.lock.RUnlock()
+	if n >= s.PeerPolicy.MaxPeersPerSubnet {
+		return fmt.Errorf("%w: %s", errSubnetPeersLimit, subnet)
+	}
+	return nil
+}
+
 // ID returns the servers ID.
 func (s *Server) ID() uint32 {
 	return s.id
@@ -285,6 +363,11 @@ func (s *Server) Start() {
 
 	s.tryStartServices()
 	s.initStaleMemPools()
+	if s.MempoolCfg.PersistFile != "" {
+		if err := s.restoreMemPools(); err != nil {
+			s.log.Warn("failed to restore memory pool", zap.Error(err))
+		}
+	}
 
 	var txThreads = optimalNumOfThreads()
 	s.txHandlerLoopWG.Add(txThreads)
@@ -308,6 +391,9 @@ func (s *Server) Start() {
 // once stopped the same instance of the Server can't be started again by calling Start.
 func (s *Server) Shutdown() {
 	if !s.started.CompareAndSwap(true, false) {
+		// Capture file is opened by the constructor, so it needs to be
+		// closed even if the server wasn't started.
+		s.closeCapture()
 		return
 	}
 	s.log.Info("shutting down server", zap.Int("peers", s.PeerCount()))
@@ -333,10 +419,26 @@ func (s *Server) Shutdown() {
 	<-s.relayFin
 	<-s.runFin
 	s.txHandlerLoopWG.Wait()
+	if s.MempoolCfg.PersistFile != "" {
+		if err := s.saveMemPools(); err != nil {
+			s.log.Warn("failed to save memory pool", zap.Error(err))
+		}
+	}
+	s.closeCapture()
 
 	_ = s.log.Sync()
 }
 
+// closeCapture closes the capture file if capturing is enabled, it can be
+// called several times.
+func (s *Server) closeCapture() {
+	if s.capture != nil {
+		if err := s.capture.Close(); err != nil {
+			s.log.Warn("failed to close P2P capture file", zap.Error(err))
+		}
+	}
+}
+
 // AddService allows to add a service to be started/stopped by Server.
 func (s *Server) AddService(svc Service) {
 	s.serviceLock.Lock()
@@ -496,7 +598,9 @@ func (s *Server) run() {
 			s.lock.Unlock()
 			peerCount := s.PeerCount()
 			s.log.Info("new peer connected", zap.Stringer("addr", p.RemoteAddr()), zap.Int("peerCount", peerCount))
-			if peerCount > s.MaxPeers {
+			if err := s.checkSubnet(s.peerIP(p), p); err != nil {
+				go p.Disconnect(err)
+			} else if peerCount > s.MaxPeers {
 				s.lock.RLock()
 				// Pick a random peer and drop connection to it.
 				for peer := range s.peers {
@@ -71,11 +71,23 @@ type (
 		// StateRootCfg is stateroot module configuration.
 		StateRootCfg config.StateRoot
 
+		// MempoolCfg is memory pool configuration.
+		MempoolCfg config.Mempool
+
 		// ExtensiblePoolSize is the size of the pool for extensible payloads from a single sender.
 		ExtensiblePoolSize int
 
 		// BroadcastFactor is the factor (0-100) for fan-out optimization.
 		BroadcastFactor int
+
+		// Capture is P2P message capture configuration.
+		Capture config.P2PCapture
+
+		// PeerPolicy contains restrictions for inbound and outbound connections.
+		PeerPolicy config.P2PPeerPolicy
+
+		// Proxy is SOCKS5 proxy configuration for outbound connections.
+		Proxy config.P2PProxy
 	}
 )
 
@@ -105,8 +117,12 @@ func NewServerConfig(cfg config.Config) (ServerConfig, error) {
 		OracleCfg:          appConfig.Oracle,
 		P2PNotaryCfg:       appConfig.P2PNotary,
 		StateRootCfg:       appConfig.StateRoot,
+		MempoolCfg:         appConfig.Mempool,
 		ExtensiblePoolSize: appConfig.P2P.ExtensiblePoolSize,
 		BroadcastFactor:    appConfig.P2P.BroadcastFactor,
+		Capture:            appConfig.P2P.Capture,
+		PeerPolicy:         appConfig.P2P.PeerPolicy,
+		Proxy:              appConfig.P2P.Proxy,
 	}
 	return c, nil
 }
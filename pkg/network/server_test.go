@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"math/big"
 	"net"
+	"path/filepath"
 	"strconv"
 	"sync"
 	"sync/atomic"
@@ -136,6 +137,15 @@ func TestServerStartAndShutdown(t *testing.T) {
 
 		require.True(t, s.started.Load(), "server should still be marked as started after second Start call")
 	})
+	t.Run("shutdown without start", func(t *testing.T) {
+		s := newTestServer(t, ServerConfig{Capture: config.P2PCapture{
+			Enabled:  true,
+			FilePath: filepath.Join(t.TempDir(), "p2p.cap"),
+		}})
+		require.NotNil(t, s.capture.file)
+		s.Shutdown()
+		require.Nil(t, s.capture.file)
+	})
 	t.Run("double shutdown", func(t *testing.T) {
 		s := newTestServer(t, ServerConfig{})
 		s.Start()
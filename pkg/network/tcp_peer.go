@@ -147,6 +147,9 @@ func (p *TCPPeer) writeMsg(msg *Message) error {
 	}
 
 	_, err = p.conn.Write(b)
+	if err == nil {
+		p.server.capturePacket(CaptureOutbound, p, b)
+	}
 
 	return err
 }
@@ -175,6 +178,9 @@ func (p *TCPPeer) handleConn() {
 			} else if err != nil {
 				break
 			}
+			if p.server.capture != nil {
+				p.server.capturePacket(CaptureInbound, p, msg.receivedBytes())
+			}
 			select {
 			case p.incoming <- msg:
 			case <-p.done:
@@ -252,6 +258,7 @@ func (p *TCPPeer) handleQueues() {
 		if err != nil {
 			break
 		}
+		p.server.capturePacket(CaptureOutbound, p, msg)
 		p2pSkipCounter++
 	}
 	p.Disconnect(err)
@@ -412,8 +419,9 @@ func (p *TCPPeer) RemoteAddr() net.Addr {
 // PeerAddr implements the Peer interface.
 func (p *TCPPeer) PeerAddr() net.Addr {
 	remote := p.conn.RemoteAddr()
+	version := p.Version()
 	// The network can be non-tcp in unit tests.
-	if p.version == nil || remote.Network() != "tcp" {
+	if version == nil || remote.Network() != "tcp" {
 		return p.RemoteAddr()
 	}
 	host, _, err := net.SplitHostPort(remote.String())
@@ -421,7 +429,7 @@ func (p *TCPPeer) PeerAddr() net.Addr {
 		return p.RemoteAddr()
 	}
 	var port uint16
-	for _, cap := range p.version.Capabilities {
+	for _, cap := range version.Capabilities {
 		if cap.Type == capability.TCPServer {
 			port = cap.Data.(*capability.Server).Port
 		}
@@ -448,6 +456,8 @@ func (p *TCPPeer) Disconnect(err error) {
 
 // Version implements the Peer interface.
 func (p *TCPPeer) Version() *payload.Version {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
 	return p.version
 }
 
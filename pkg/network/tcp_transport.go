@@ -46,7 +46,9 @@ func NewTCPTransport(s *Server, bindAddr string, log *zap.Logger) *TCPTransport
 
 // Dial implements the Transporter interface.
 func (t *TCPTransport) Dial(addr string, timeout time.Duration) (AddressablePeer, error) {
-	conn, err := net.DialTimeout("tcp", addr, timeout)
+	conn, err := t.server.policy.dial(addr, timeout, func(ip net.IP) error {
+		return t.server.checkSubnet(ip, nil)
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -86,6 +88,11 @@ func (t *TCPTransport) Accept() {
 			t.log.Warn("TCP accept error", zap.Stringer("address", l.Addr()), zap.Error(err))
 			continue
 		}
+		if addr, ok := conn.RemoteAddr().(*net.TCPAddr); ok && !t.server.policy.allowInbound(addr.IP) {
+			t.log.Debug("inbound connection rejected by policy", zap.Stringer("address", addr))
+			conn.Close()
+			continue
+		}
 		p := NewTCPPeer(conn, "", t.server)
 		go p.handleConn()
 	}
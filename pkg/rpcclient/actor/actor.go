@@ -37,6 +37,17 @@ type RPCActor interface {
 	SendRawTransaction(tx *transaction.Transaction) (util.Uint256, error)
 }
 
+// RPCPriorityFee is an optional interface RPCActor can implement to provide
+// network fee estimations (see EstimatePriorityFee), rpcclient.Client
+// implements it.
+type RPCPriorityFee interface {
+	EstimatePriorityFee(blocks int) (*result.PriorityFee, error)
+}
+
+// ErrPriorityFeeNotSupported is returned from EstimatePriorityFee if RPCActor
+// doesn't implement RPCPriorityFee.
+var ErrPriorityFeeNotSupported = errors.New("priority fee estimation is not supported by RPC actor")
+
 // SignerAccount represents combination of the transaction.Signer and the
 // corresponding wallet.Account. It's used to create and sign transactions, each
 // transaction has a set of signers that must witness the transaction with their
@@ -184,6 +195,19 @@ func (a *Actor) CalculateNetworkFee(tx *transaction.Transaction) (int64, error)
 	return a.client.CalculateNetworkFee(tx)
 }
 
+// EstimatePriorityFee returns network fee per byte value suggested by the RPC
+// node for new transactions based on the memory pool state and fees paid in
+// the given number of latest blocks (node default is used if it's 0). The
+// result can be used with PriorityFeeModifier. ErrPriorityFeeNotSupported is
+// returned if RPCActor doesn't implement RPCPriorityFee.
+func (a *Actor) EstimatePriorityFee(blocks int) (*result.PriorityFee, error) {
+	c, ok := a.client.(RPCPriorityFee)
+	if !ok {
+		return nil, ErrPriorityFeeNotSupported
+	}
+	return c.EstimatePriorityFee(blocks)
+}
+
 // GetBlockCount wraps RPCActor's GetBlockCount, making it available to
 // Actor users directly. It returns current number of blocks in the chain.
 func (a *Actor) GetBlockCount() (uint32, error) {
@@ -5,6 +5,8 @@ import (
 	"fmt"
 
 	"github.com/nspcc-dev/neo-go/pkg/core/transaction"
+	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
+	"github.com/nspcc-dev/neo-go/pkg/io"
 	"github.com/nspcc-dev/neo-go/pkg/neorpc/result"
 	"github.com/nspcc-dev/neo-go/pkg/util"
 	"github.com/nspcc-dev/neo-go/pkg/vm/vmstate"
@@ -47,6 +49,37 @@ func DefaultCheckerModifier(r *result.Invoke, t *transaction.Transaction) error
 	return nil
 }
 
+// PriorityFeeModifier returns a TransactionModifier that increases transaction's
+// NetworkFee (if needed) to make its fee per byte value (NetworkFee divided by
+// the transaction size) not lower than the given one after the transaction is
+// signed by Actor. It can be used with the value suggested by
+// EstimatePriorityFee to make transaction more competitive in the memory pool:
+//
+//	fee, err := a.EstimatePriorityFee(0)
+//	...
+//	tx, err := a.MakeUncheckedRun(script, sysfee, nil, a.PriorityFeeModifier(fee.FeePerByte))
+func (a *Actor) PriorityFeeModifier(feePerByte int64) TransactionModifier {
+	return func(t *transaction.Transaction) error {
+		if len(t.Scripts) != len(a.signers) {
+			return errors.New("incorrect number of witnesses in the transaction")
+		}
+		// Bytes doesn't cache anything, so it's safe to use here.
+		size := len(t.Bytes())
+		for i, signer := range a.signers {
+			if len(t.Scripts[i].InvocationScript) != 0 || signer.Account.Contract.Deployed {
+				continue
+			}
+			// Every signature is pushed with PUSHDATA1.
+			invLen := (2 + keys.SignatureLen) * len(signer.Account.Contract.Parameters)
+			size += io.GetVarSize(invLen) + invLen - io.GetVarSize(0)
+		}
+		if fee := feePerByte * int64(size); t.NetworkFee < fee {
+			t.NetworkFee = fee
+		}
+		return nil
+	}
+}
+
 // MakeCall creates a transaction that calls the given method of the given
 // contract with the given parameters. Test call is performed and filtered through
 // Actor-configured TransactionCheckerModifier. The resulting transaction has
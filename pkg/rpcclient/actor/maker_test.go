@@ -215,3 +215,45 @@ func TestMakeSigned(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, uint32(888), tx.ValidUntilBlock)
 }
+
+type priorityFeeClient struct {
+	*RPCClient
+	fee *result.PriorityFee
+}
+
+func (c *priorityFeeClient) EstimatePriorityFee(blocks int) (*result.PriorityFee, error) {
+	return c.fee, c.err
+}
+
+func TestPriorityFee(t *testing.T) {
+	client, acc := testRPCAndAccount(t)
+	a, err := NewSimple(client, acc)
+	require.NoError(t, err)
+
+	_, err = a.EstimatePriorityFee(0)
+	require.ErrorIs(t, err, ErrPriorityFeeNotSupported)
+
+	pc := &priorityFeeClient{RPCClient: client, fee: &result.PriorityFee{FeePerByte: 2000}}
+	a, err = NewSimple(pc, acc)
+	require.NoError(t, err)
+	fee, err := a.EstimatePriorityFee(10)
+	require.NoError(t, err)
+	require.Equal(t, int64(2000), fee.FeePerByte)
+
+	client.netFee = 1
+	tx, err := a.MakeUnsignedUncheckedRun([]byte{1, 2, 3}, 1, nil)
+	require.NoError(t, err)
+	require.NoError(t, a.PriorityFeeModifier(fee.FeePerByte)(tx))
+	require.NoError(t, a.Sign(tx))
+	require.Equal(t, fee.FeePerByte*int64(tx.Size()), tx.NetworkFee)
+
+	// Higher fee is preserved.
+	tx, err = a.MakeUnsignedUncheckedRun([]byte{1, 2, 3}, 1, nil)
+	require.NoError(t, err)
+	tx.NetworkFee = 1_0000_0000
+	require.NoError(t, a.PriorityFeeModifier(fee.FeePerByte)(tx))
+	require.Equal(t, int64(1_0000_0000), tx.NetworkFee)
+
+	tx.Scripts = nil
+	require.Error(t, a.PriorityFeeModifier(fee.FeePerByte)(tx))
+}
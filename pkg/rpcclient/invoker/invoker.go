@@ -15,6 +15,7 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/nspcc-dev/neo-go/pkg/core/transaction"
+	"github.com/nspcc-dev/neo-go/pkg/neorpc"
 	"github.com/nspcc-dev/neo-go/pkg/neorpc/result"
 	"github.com/nspcc-dev/neo-go/pkg/smartcontract"
 	"github.com/nspcc-dev/neo-go/pkg/util"
@@ -44,6 +45,13 @@ type RPCInvoke interface {
 	InvokeScript(script []byte, signers []transaction.Signer) (*result.Invoke, error)
 }
 
+// RPCInvokeScripts is an optional RPC method that executes several scripts
+// sequentially on the same state. It's a NeoGo extension and it's not
+// available for historic calls.
+type RPCInvokeScripts interface {
+	InvokeScripts(scripts []neorpc.ScriptWithSigners) ([]*result.Invoke, error)
+}
+
 // RPCInvokeHistoric is a set of RPC methods needed to execute things at some
 // fixed point in blockchain's life.
 type RPCInvokeHistoric interface {
@@ -178,6 +186,26 @@ func (v *Invoker) Run(script []byte) (*result.Invoke, error) {
 	return v.client.InvokeScript(script, v.signers)
 }
 
+// RunScripts executes the given scripts one after another on the same state
+// as if they were transactions of the next block, so that changes made by
+// the previous scripts are visible to the subsequent ones (changes made by
+// the faulted ones are dropped). Scripts without signers use Invoker-specific
+// list of signers. It requires RPC client implementing RPCInvokeScripts.
+func (v *Invoker) RunScripts(scripts ...neorpc.ScriptWithSigners) ([]*result.Invoke, error) {
+	c, ok := v.client.(RPCInvokeScripts)
+	if !ok {
+		return nil, errors.New("sequential script invocation is not supported by the RPC client")
+	}
+	var ss = make([]neorpc.ScriptWithSigners, len(scripts))
+	for i := range scripts {
+		ss[i] = scripts[i]
+		if ss[i].Signers == nil {
+			ss[i].Signers = v.signers
+		}
+	}
+	return c.InvokeScripts(ss)
+}
+
 // TerminateSession closes the given session, returning an error if anything
 // goes wrong. It's not strictly required to close the session (it'll expire on
 // the server anyway), but it helps to release server resources earlier.
@@ -6,6 +6,7 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/nspcc-dev/neo-go/pkg/core/transaction"
+	"github.com/nspcc-dev/neo-go/pkg/neorpc"
 	"github.com/nspcc-dev/neo-go/pkg/neorpc/result"
 	"github.com/nspcc-dev/neo-go/pkg/smartcontract"
 	"github.com/nspcc-dev/neo-go/pkg/util"
@@ -158,3 +159,40 @@ func TestInvoker(t *testing.T) {
 		}
 	})
 }
+
+type rpcInvScripts struct {
+	rpcInv
+	scripts []neorpc.ScriptWithSigners
+}
+
+func (r *rpcInvScripts) InvokeScripts(scripts []neorpc.ScriptWithSigners) ([]*result.Invoke, error) {
+	r.scripts = scripts
+	return []*result.Invoke{r.resInv}, r.err
+}
+
+func TestInvokerRunScripts(t *testing.T) {
+	var (
+		resExp  = &result.Invoke{State: "HALT"}
+		signers = []transaction.Signer{{Account: util.Uint160{1, 2, 3}}}
+		own     = []transaction.Signer{{Account: util.Uint160{4, 5, 6}}}
+		ri      = &rpcInvScripts{rpcInv: rpcInv{resExp, true, nil, nil}}
+	)
+	inv := New(ri, signers)
+	res, err := inv.RunScripts(
+		neorpc.ScriptWithSigners{Script: []byte{1}},
+		neorpc.ScriptWithSigners{Script: []byte{2}, Signers: own},
+	)
+	require.NoError(t, err)
+	require.Equal(t, []*result.Invoke{resExp}, res)
+	require.Equal(t, []neorpc.ScriptWithSigners{
+		{Script: []byte{1}, Signers: signers},
+		{Script: []byte{2}, Signers: own},
+	}, ri.scripts)
+
+	t.Run("unsupported", func(t *testing.T) {
+		_, err := New(&ri.rpcInv, signers).RunScripts(neorpc.ScriptWithSigners{Script: []byte{1}})
+		require.Error(t, err)
+		_, err = NewHistoricAtHeight(100500, &ri.rpcInv, signers).RunScripts(neorpc.ScriptWithSigners{Script: []byte{1}})
+		require.Error(t, err)
+	})
+}
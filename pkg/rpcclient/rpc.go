@@ -40,6 +40,36 @@ func (c *Client) CalculateNetworkFee(tx *transaction.Transaction) (int64, error)
 	return resp.Value, nil
 }
 
+// EstimatePriorityFee returns network fee per byte value suggested by the node
+// for new transactions based on the memory pool state and fees paid by
+// transactions in the given number of latest blocks (the node default is used
+// if it's 0). This method is only supported by NeoGo servers.
+func (c *Client) EstimatePriorityFee(blocks int) (*result.PriorityFee, error) {
+	var (
+		params []any
+		resp   = new(result.PriorityFee)
+	)
+	if blocks != 0 {
+		params = []any{blocks}
+	}
+	if err := c.performRequest("estimatepriorityfee", params, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// GenerateBlocks asks the node to create the given number of blocks and returns
+// their hashes. It's only supported by NeoGo consensus nodes running in the
+// instant mining mode.
+func (c *Client) GenerateBlocks(n int) ([]util.Uint256, error) {
+	var resp []util.Uint256
+
+	if err := c.performRequest("generateblocks", []any{n}, &resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
 // GetApplicationLog returns a contract log based on the specified txid.
 func (c *Client) GetApplicationLog(hash util.Uint256, trig *trigger.Type) (*result.ApplicationLog, error) {
 	var (
@@ -223,6 +253,18 @@ func (c *Client) GetConnectionCount() (int, error) {
 	return resp, nil
 }
 
+// GetConsensusState returns the current dBFT state of the consensus node along
+// with the timeline of recent rounds. It's only supported by NeoGo consensus
+// nodes.
+func (c *Client) GetConsensusState() (*result.ConsensusState, error) {
+	var resp = new(result.ConsensusState)
+
+	if err := c.performRequest("getconsensusstate", nil, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
 // GetCommittee returns the current public keys of NEO nodes in the committee.
 func (c *Client) GetCommittee() (keys.PublicKeys, error) {
 	var resp = new(keys.PublicKeys)
@@ -233,6 +275,29 @@ func (c *Client) GetCommittee() (keys.PublicKeys, error) {
 	return *resp, nil
 }
 
+// GetCommitteeAtHeight returns public keys of NEO nodes in the committee at
+// the given blockchain height. It's a NeoGo extension requiring the node to
+// keep all MPT states.
+func (c *Client) GetCommitteeAtHeight(height uint32) (keys.PublicKeys, error) {
+	return c.getCommitteeHistoric(height)
+}
+
+// GetCommitteeWithState returns public keys of NEO nodes in the committee at
+// the blockchain state defined by the specified state root or block hash. It's
+// a NeoGo extension requiring the node to keep all MPT states.
+func (c *Client) GetCommitteeWithState(stateOrBlock util.Uint256) (keys.PublicKeys, error) {
+	return c.getCommitteeHistoric(stateOrBlock.StringLE())
+}
+
+func (c *Client) getCommitteeHistoric(param any) (keys.PublicKeys, error) {
+	var resp = new(keys.PublicKeys)
+
+	if err := c.performRequest("getcommitteehistoric", []any{param}, resp); err != nil {
+		return nil, err
+	}
+	return *resp, nil
+}
+
 // GetContractStateByHash queries contract information according to the contract script hash.
 func (c *Client) GetContractStateByHash(hash util.Uint160) (*state.Contract, error) {
 	return c.getContractState(hash.StringLE())
@@ -407,6 +472,24 @@ func (c *Client) GetRawMemPool() ([]util.Uint256, error) {
 	return *resp, nil
 }
 
+// GetMempoolEntries returns detailed information about transactions in the
+// node's memory pool in their priority order. Transactions can be filtered by
+// sender and/or signer with the given filter (nil means no filtering). This
+// method is only supported by NeoGo servers.
+func (c *Client) GetMempoolEntries(flt *neorpc.TxFilter) ([]result.MempoolEntry, error) {
+	var (
+		params []any
+		resp   []result.MempoolEntry
+	)
+	if flt != nil {
+		params = []any{flt}
+	}
+	if err := c.performRequest("getmempoolentries", params, &resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
 // GetRawTransaction returns a transaction by hash.
 func (c *Client) GetRawTransaction(hash util.Uint256) (*transaction.Transaction, error) {
 	var (
@@ -523,6 +606,28 @@ func (c *Client) getStateRoot(param any) (*state.MPTRoot, error) {
 	return resp, nil
 }
 
+// GetStateDiffByHeight returns contract storage changes made by the block with
+// the specified height. It's a NeoGo extension requiring the node to keep all
+// MPT states.
+func (c *Client) GetStateDiffByHeight(height uint32) (*state.StateDiff, error) {
+	return c.getStateDiff(height)
+}
+
+// GetStateDiffByBlockHash returns contract storage changes made by the block
+// with the specified hash. It's a NeoGo extension requiring the node to keep
+// all MPT states.
+func (c *Client) GetStateDiffByBlockHash(hash util.Uint256) (*state.StateDiff, error) {
+	return c.getStateDiff(hash)
+}
+
+func (c *Client) getStateDiff(param any) (*state.StateDiff, error) {
+	var resp = new(state.StateDiff)
+	if err := c.performRequest("getstatediff", []any{param}, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
 // GetStateHeight returns the current validated and local node state height.
 func (c *Client) GetStateHeight() (*result.StateHeight, error) {
 	var resp = new(result.StateHeight)
@@ -690,6 +795,53 @@ func (c *Client) GetNextBlockValidators() ([]result.Validator, error) {
 	return *resp, nil
 }
 
+// GetCandidatesAtHeight returns the list of NEO candidate nodes with voting
+// data and validator status at the given blockchain height. It's a NeoGo
+// extension requiring the node to keep all MPT states.
+func (c *Client) GetCandidatesAtHeight(height uint32) ([]result.Candidate, error) {
+	return c.getCandidatesHistoric(height)
+}
+
+// GetCandidatesWithState returns the list of NEO candidate nodes with voting
+// data and validator status at the blockchain state defined by the specified
+// state root or block hash. It's a NeoGo extension requiring the node to keep
+// all MPT states.
+func (c *Client) GetCandidatesWithState(stateOrBlock util.Uint256) ([]result.Candidate, error) {
+	return c.getCandidatesHistoric(stateOrBlock.StringLE())
+}
+
+func (c *Client) getCandidatesHistoric(param any) ([]result.Candidate, error) {
+	var resp = new([]result.Candidate)
+
+	if err := c.performRequest("getcandidateshistoric", []any{param}, resp); err != nil {
+		return nil, err
+	}
+	return *resp, nil
+}
+
+// GetNextBlockValidatorsAtHeight returns NEO consensus nodes information and
+// voting data for the block following the one with the given height. It's a
+// NeoGo extension requiring the node to keep all MPT states.
+func (c *Client) GetNextBlockValidatorsAtHeight(height uint32) ([]result.Validator, error) {
+	return c.getNextBlockValidatorsHistoric(height)
+}
+
+// GetNextBlockValidatorsWithState returns NEO consensus nodes information and
+// voting data at the blockchain state defined by the specified state root or
+// block hash. It's a NeoGo extension requiring the node to keep all MPT states.
+func (c *Client) GetNextBlockValidatorsWithState(stateOrBlock util.Uint256) ([]result.Validator, error) {
+	return c.getNextBlockValidatorsHistoric(stateOrBlock.StringLE())
+}
+
+func (c *Client) getNextBlockValidatorsHistoric(param any) ([]result.Validator, error) {
+	var resp = new([]result.Validator)
+
+	if err := c.performRequest("getnextblockvalidatorshistoric", []any{param}, resp); err != nil {
+		return nil, err
+	}
+	return *resp, nil
+}
+
 // GetVersion returns the version information about the queried node.
 func (c *Client) GetVersion() (*result.Version, error) {
 	var resp = &result.Version{}
@@ -725,6 +877,31 @@ func (c *Client) InvokeScriptWithState(stateOrBlock util.Uint256, script []byte,
 	return c.invokeSomething("invokescripthistoric", p, signers)
 }
 
+// InvokeScriptWithOverrides returns the result of the given script after
+// running it true the VM with the given overrides applied to the current
+// chain state.
+// NOTE: This is a test invoke and will not affect the blockchain.
+func (c *Client) InvokeScriptWithOverrides(script []byte, signers []transaction.Signer, overrides *state.Overrides) (*result.Invoke, error) {
+	var p = []any{script}
+	return c.invokeWithOverrides("invokescript", p, signers, overrides)
+}
+
+// InvokeScripts executes the given scripts one after another on the same chain
+// state as if they were transactions of the next block (changes made by the
+// faulted ones are dropped) and returns their results. It's a NeoGo extension
+// not supported by C# nodes.
+// NOTE: This is a test invoke and will not affect the blockchain.
+func (c *Client) InvokeScripts(scripts []neorpc.ScriptWithSigners) ([]*result.Invoke, error) {
+	var (
+		p    = []any{scripts}
+		resp []*result.Invoke
+	)
+	if err := c.performRequest("invokescripts", p, &resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
 // InvokeFunction returns the results after calling the smart contract scripthash
 // with the given operation and parameters.
 // NOTE: this is test invoke and will not affect the blockchain.
@@ -751,6 +928,15 @@ func (c *Client) InvokeFunctionWithState(stateOrBlock util.Uint256, contract uti
 	return c.invokeSomething("invokefunctionhistoric", p, signers)
 }
 
+// InvokeFunctionWithOverrides returns the results after calling the smart
+// contract with the given operation and parameters with the given overrides
+// applied to the current chain state.
+// NOTE: this is test invoke and will not affect the blockchain.
+func (c *Client) InvokeFunctionWithOverrides(contract util.Uint160, operation string, params []smartcontract.Parameter, signers []transaction.Signer, overrides *state.Overrides) (*result.Invoke, error) {
+	var p = []any{contract.StringLE(), operation, params}
+	return c.invokeWithOverrides("invokefunction", p, signers, overrides)
+}
+
 // InvokeContractVerify returns the results after calling `verify` method of the smart contract
 // with the given parameters under verification trigger type.
 // NOTE: this is test invoke and will not affect the blockchain.
@@ -803,6 +989,19 @@ func (c *Client) invokeSomething(method string, p []any, signers []transaction.S
 	return resp, nil
 }
 
+// invokeWithOverrides is an inner wrapper for Invoke*WithOverrides functions.
+func (c *Client) invokeWithOverrides(method string, p []any, signers []transaction.Signer, overrides *state.Overrides) (*result.Invoke, error) {
+	var resp = new(result.Invoke)
+	if signers == nil {
+		signers = []transaction.Signer{}
+	}
+	p = append(p, signers, false, overrides)
+	if err := c.performRequest(method, p, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
 // SendRawTransaction broadcasts the given transaction to the Neo network.
 // It always returns transaction hash, when successful (no error) this is the
 // hash returned from server, when not it's a locally calculated rawTX hash.
@@ -930,6 +1129,21 @@ func (c *Client) TerminateSession(sessionID uuid.UUID) (bool, error) {
 	return resp, nil
 }
 
+// TraceTransaction re-executes the persisted transaction with the given hash
+// and returns its execution trace. Executed instructions are only included if
+// withSteps is set. It's a NeoGo extension requiring the node to keep all MPT
+// states.
+func (c *Client) TraceTransaction(hash util.Uint256, withSteps bool) (*result.TransactionTrace, error) {
+	var (
+		params = []any{hash.StringLE(), withSteps}
+		resp   = new(result.TransactionTrace)
+	)
+	if err := c.performRequest("tracetransaction", params, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
 // GetRawNotaryTransaction  returns main or fallback transaction from the
 // RPC node's notary request pool.
 func (c *Client) GetRawNotaryTransaction(hash util.Uint256) (*transaction.Transaction, error) {
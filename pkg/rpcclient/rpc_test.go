@@ -26,6 +26,7 @@ import (
 	"github.com/nspcc-dev/neo-go/pkg/encoding/bigint"
 	"github.com/nspcc-dev/neo-go/pkg/encoding/fixedn"
 	"github.com/nspcc-dev/neo-go/pkg/io"
+	"github.com/nspcc-dev/neo-go/pkg/neorpc"
 	"github.com/nspcc-dev/neo-go/pkg/neorpc/result"
 	"github.com/nspcc-dev/neo-go/pkg/services/rpcsrv/params"
 	"github.com/nspcc-dev/neo-go/pkg/smartcontract"
@@ -107,10 +108,71 @@ func getTxMoveNeo() *result.TransactionOutputRaw {
 	}
 }
 
+// getResultStateDiff returns data for getstatediff tests.
+func getResultStateDiff() *state.StateDiff {
+	prev, err := util.Uint256DecodeStringLE("65d19151694321e70c6d184b37a2bcf7af4a2c60c099af332a4f7815e3670686")
+	if err != nil {
+		panic(err)
+	}
+	root, err := util.Uint256DecodeStringLE("86fe1061140b2ea791b0739fb9732abc6e5e47de4927228a1ac41de3d93eb7cb")
+	if err != nil {
+		panic(err)
+	}
+	dec := func(s string) []byte {
+		b, err := base64.StdEncoding.DecodeString(s)
+		if err != nil {
+			panic(err)
+		}
+		return b
+	}
+	return &state.StateDiff{
+		Index:    5,
+		PrevRoot: prev,
+		Root:     root,
+		Contracts: []state.ContractStorageDiff{
+			{
+				ID: -6,
+				Changes: []state.StorageChange{
+					{Type: state.StorageModified, Key: dec("FM+Sq2TkoVtWd2ng6eJPFFMjptG7"), OldValue: dec("QAEhBgDodkgXAA=="), NewValue: dec("QAEhBgDIz6o8FwA=")},
+					{Type: state.StorageAdded, Key: dec("FGix8ocY3zgecjHmn3VCGHM4PU29"), NewValue: dec("QAEhBADh9QU=")},
+				},
+			},
+			{
+				ID: 1,
+				Changes: []state.StorageChange{
+					{Type: state.StorageDeleted, Key: dec("dGVzdGtleQ=="), OldValue: dec("dGVzdHZhbHVl")},
+				},
+			},
+		},
+	}
+}
+
 // rpcClientTestCases contains `serverResponse` json data fetched from examples
 // published in the official C# JSON-RPC API v2.10.3 reference
 // (see https://docs.neo.org/docs/en-us/reference/rpc/latest-version/api.html)
 var rpcClientTestCases = map[string][]rpcClientTestCase{
+	"estimatepriorityfee": {
+		{
+			name: "positive",
+			invoke: func(c *Client) (any, error) {
+				return c.EstimatePriorityFee(10)
+			},
+			serverResponse: `{"id":1,"jsonrpc":"2.0","result":{"feeperbyte":"2000","policyfeeperbyte":"1000","mempoolcount":30000,"mempoolcapacity":50000,"mempoolminfeeperbyte":"1500","blocks":10,"blocktransactions":7,"blocksminfeeperbyte":"1000","blocksmedianfeeperbyte":"2000"}}`,
+			result: func(c *Client) any {
+				return &result.PriorityFee{
+					FeePerByte:             2000,
+					PolicyFeePerByte:       1000,
+					MempoolCount:           30000,
+					MempoolCapacity:        50000,
+					MempoolMinFeePerByte:   1500,
+					Blocks:                 10,
+					BlockTransactions:      7,
+					BlocksMinFeePerByte:    1000,
+					BlocksMedianFeePerByte: 2000,
+				}
+			},
+		},
+	},
 	"getapplicationlog": {
 		{
 			name: "positive",
@@ -315,6 +377,36 @@ var rpcClientTestCases = map[string][]rpcClientTestCase{
 			},
 		},
 	},
+	"getcommitteehistoric": {
+		{
+			name: "positive, by height",
+			invoke: func(c *Client) (any, error) {
+				return c.GetCommitteeAtHeight(5)
+			},
+			serverResponse: `{"jsonrpc":"2.0","id":1,"result":["02103a7f7dd016558597f7960d27c516a4394fd968b9e65155eb4b013e4040406e"]}`,
+			result: func(c *Client) any {
+				member, err := keys.NewPublicKeyFromString("02103a7f7dd016558597f7960d27c516a4394fd968b9e65155eb4b013e4040406e")
+				if err != nil {
+					panic(fmt.Errorf("failed to decode public key: %w", err))
+				}
+				return keys.PublicKeys{member}
+			},
+		},
+		{
+			name: "positive, by state",
+			invoke: func(c *Client) (any, error) {
+				return c.GetCommitteeWithState(util.Uint256{1, 2, 3})
+			},
+			serverResponse: `{"jsonrpc":"2.0","id":1,"result":["02103a7f7dd016558597f7960d27c516a4394fd968b9e65155eb4b013e4040406e"]}`,
+			result: func(c *Client) any {
+				member, err := keys.NewPublicKeyFromString("02103a7f7dd016558597f7960d27c516a4394fd968b9e65155eb4b013e4040406e")
+				if err != nil {
+					panic(fmt.Errorf("failed to decode public key: %w", err))
+				}
+				return keys.PublicKeys{member}
+			},
+		},
+	},
 	"getconnectioncount": {
 		{
 			name: "positive",
@@ -327,6 +419,54 @@ var rpcClientTestCases = map[string][]rpcClientTestCase{
 			},
 		},
 	},
+	"generateblocks": {
+		{
+			name: "positive",
+			invoke: func(c *Client) (any, error) {
+				return c.GenerateBlocks(1)
+			},
+			serverResponse: `{"jsonrpc":"2.0","id":1,"result":["0x773dd2dae4a9c9275290f89b56e67d7363ea4826dfd4fc13cc01cf73a44b0d0e"]}`,
+			result: func(c *Client) any {
+				h, err := util.Uint256DecodeStringLE("773dd2dae4a9c9275290f89b56e67d7363ea4826dfd4fc13cc01cf73a44b0d0e")
+				if err != nil {
+					panic(err)
+				}
+				return []util.Uint256{h}
+			},
+		},
+	},
+	"getconsensusstate": {
+		{
+			name: "positive",
+			invoke: func(c *Client) (any, error) {
+				return c.GetConsensusState()
+			},
+			serverResponse: `{"jsonrpc":"2.0","id":1,"result":{"height":5,"view":1,"primary":3,"index":0,"validators":[{"index":0,"publickey":"03b209fd4f53a7170ea4444e0cb0a6bb6a53c2bd016926989cf85f9b0fba17a70c","preparation":true,"commit":false,"changeview":{"newview":2,"reason":"Timeout"},"lastseen":{"height":5,"view":1}}],"rounds":[{"height":5,"view":0,"primary":4,"start":1000,"duration":15000,"result":"changeview","reason":"Timeout"},{"height":5,"view":1,"primary":3,"start":16000,"duration":100}]}}`,
+			result: func(c *Client) any {
+				pub, err := keys.NewPublicKeyFromString("03b209fd4f53a7170ea4444e0cb0a6bb6a53c2bd016926989cf85f9b0fba17a70c")
+				if err != nil {
+					panic(err)
+				}
+				return &result.ConsensusState{
+					Height:  5,
+					View:    1,
+					Primary: 3,
+					Index:   0,
+					Validators: []result.ConsensusValidator{{
+						Index:       0,
+						PublicKey:   pub,
+						Preparation: true,
+						ChangeView:  &result.ConsensusChangeView{NewView: 2, Reason: "Timeout"},
+						LastSeen:    &result.ConsensusHeightView{Height: 5, View: 1},
+					}},
+					Rounds: []result.ConsensusRound{
+						{Height: 5, Primary: 4, Start: 1000, Duration: 15000, Result: result.ConsensusRoundChangeView, Reason: "Timeout"},
+						{Height: 5, View: 1, Primary: 3, Start: 16000, Duration: 100},
+					},
+				}
+			},
+		},
+	},
 	"getcontractstate": {
 		{
 			name: "positive, by hash",
@@ -610,6 +750,33 @@ var rpcClientTestCases = map[string][]rpcClientTestCase{
 			},
 		},
 	},
+	"getmempoolentries": {
+		{
+			name: "positive",
+			invoke: func(c *Client) (any, error) {
+				return c.GetMempoolEntries(&neorpc.TxFilter{Sender: &util.Uint160{1, 2, 3}})
+			},
+			serverResponse: `{"jsonrpc":"2.0","id":1,"result":[{"hash":"0x9786cce0dddb524c40ddbdd5e31a41ed1f6b5c8a683c122f627ca4a007a7cf4e","sender":"0x0000000000000000000000000000000000030201","feeperbyte":"1000","netfee":"250000","sysfee":"1000000","size":250,"validuntilblock":100,"conflicts":[],"position":0,"senderfees":"1250000"}]}`,
+			result: func(c *Client) any {
+				hash, err := util.Uint256DecodeStringLE("9786cce0dddb524c40ddbdd5e31a41ed1f6b5c8a683c122f627ca4a007a7cf4e")
+				if err != nil {
+					panic(err)
+				}
+				return []result.MempoolEntry{{
+					Hash:            hash,
+					Sender:          util.Uint160{1, 2, 3},
+					FeePerByte:      1000,
+					NetworkFee:      250000,
+					SystemFee:       1000000,
+					Size:            250,
+					ValidUntilBlock: 100,
+					Conflicts:       []util.Uint256{},
+					Position:        0,
+					SenderFees:      1250000,
+				}}
+			},
+		},
+	},
 	"getrawtransaction": {
 		{
 			name: "positive",
@@ -646,6 +813,28 @@ var rpcClientTestCases = map[string][]rpcClientTestCase{
 			},
 		},
 	},
+	"getstatediff": {
+		{
+			name: "positive, by height",
+			invoke: func(c *Client) (any, error) {
+				return c.GetStateDiffByHeight(5)
+			},
+			serverResponse: `{"id":1,"jsonrpc":"2.0","result":{"index":5,"prevstateroot":"0x65d19151694321e70c6d184b37a2bcf7af4a2c60c099af332a4f7815e3670686","stateroot":"0x86fe1061140b2ea791b0739fb9732abc6e5e47de4927228a1ac41de3d93eb7cb","contracts":[{"id":-6,"changes":[{"type":"modified","key":"FM+Sq2TkoVtWd2ng6eJPFFMjptG7","oldvalue":"QAEhBgDodkgXAA==","newvalue":"QAEhBgDIz6o8FwA="},{"type":"added","key":"FGix8ocY3zgecjHmn3VCGHM4PU29","newvalue":"QAEhBADh9QU="}]},{"id":1,"changes":[{"type":"deleted","key":"dGVzdGtleQ==","oldvalue":"dGVzdHZhbHVl"}]}]}}`,
+			result:         func(c *Client) any { return getResultStateDiff() },
+		},
+		{
+			name: "positive, by hash",
+			invoke: func(c *Client) (any, error) {
+				hash, err := util.Uint256DecodeStringLE("86fe1061140b2ea791b0739fb9732abc6e5e47de4927228a1ac41de3d93eb7cb")
+				if err != nil {
+					panic(err)
+				}
+				return c.GetStateDiffByBlockHash(hash)
+			},
+			serverResponse: `{"id":1,"jsonrpc":"2.0","result":{"index":5,"prevstateroot":"0x65d19151694321e70c6d184b37a2bcf7af4a2c60c099af332a4f7815e3670686","stateroot":"0x86fe1061140b2ea791b0739fb9732abc6e5e47de4927228a1ac41de3d93eb7cb","contracts":[{"id":-6,"changes":[{"type":"modified","key":"FM+Sq2TkoVtWd2ng6eJPFFMjptG7","oldvalue":"QAEhBgDodkgXAA==","newvalue":"QAEhBgDIz6o8FwA="},{"type":"added","key":"FGix8ocY3zgecjHmn3VCGHM4PU29","newvalue":"QAEhBADh9QU="}]},{"id":1,"changes":[{"type":"deleted","key":"dGVzdGtleQ==","oldvalue":"dGVzdHZhbHVl"}]}]}}`,
+			result:         func(c *Client) any { return getResultStateDiff() },
+		},
+	},
 	"getstateroot": {
 		{
 			name: "positive, by height",
@@ -1003,6 +1192,55 @@ var rpcClientTestCases = map[string][]rpcClientTestCase{
 			},
 		},
 	},
+	"getcandidateshistoric": {
+		{
+			name: "positive, by height",
+			invoke: func(c *Client) (any, error) {
+				return c.GetCandidatesAtHeight(5)
+			},
+			serverResponse: `{"id":1,"jsonrpc":"2.0","result":[{"publickey":"02b3622bf4017bdfe317c58aed5f4c753f206b7db896046fa7d774bbc4bf7f8dc2","votes":"0","active":true},{"publickey":"02103a7f7dd016558597f7960d27c516a4394fd968b9e65155eb4b013e4040406e","votes":"0","active":false}]}`,
+			result:         func(c *Client) any { return []result.Candidate{} },
+			check: func(t *testing.T, c *Client, uns any) {
+				res, ok := uns.([]result.Candidate)
+				require.True(t, ok)
+				require.Equal(t, 2, len(res))
+				assert.True(t, res[0].Active)
+				assert.False(t, res[1].Active)
+			},
+		},
+		{
+			name: "positive, by state",
+			invoke: func(c *Client) (any, error) {
+				return c.GetCandidatesWithState(util.Uint256{1, 2, 3})
+			},
+			serverResponse: `{"id":1,"jsonrpc":"2.0","result":[]}`,
+			result:         func(c *Client) any { return []result.Candidate{} },
+		},
+	},
+	"getnextblockvalidatorshistoric": {
+		{
+			name: "positive, by height",
+			invoke: func(c *Client) (any, error) {
+				return c.GetNextBlockValidatorsAtHeight(5)
+			},
+			serverResponse: `{"id":1,"jsonrpc":"2.0","result":[{"publickey":"02b3622bf4017bdfe317c58aed5f4c753f206b7db896046fa7d774bbc4bf7f8dc2","votes":"100"}]}`,
+			result:         func(c *Client) any { return []result.Validator{} },
+			check: func(t *testing.T, c *Client, uns any) {
+				res, ok := uns.([]result.Validator)
+				require.True(t, ok)
+				require.Equal(t, 1, len(res))
+				assert.Equal(t, int64(100), res[0].Votes)
+			},
+		},
+		{
+			name: "positive, by state",
+			invoke: func(c *Client) (any, error) {
+				return c.GetNextBlockValidatorsWithState(util.Uint256{1, 2, 3})
+			},
+			serverResponse: `{"id":1,"jsonrpc":"2.0","result":[]}`,
+			result:         func(c *Client) any { return []result.Validator{} },
+		},
+	},
 	"getvalidators": {
 		{
 			name: "positive",
@@ -1159,6 +1397,52 @@ var rpcClientTestCases = map[string][]rpcClientTestCase{
 				}
 			},
 		},
+		{
+			name: "positive, with overrides",
+			invoke: func(c *Client) (any, error) {
+				var ts uint64 = 42
+				return c.InvokeScriptWithOverrides([]byte{byte(opcode.SYSCALL), 0xb7, 0xc3, 0x88, 0x03}, nil, &state.Overrides{
+					Block: &state.BlockOverride{Timestamp: &ts},
+				})
+			},
+			serverResponse: `{"jsonrpc":"2.0","id":1,"result":{"script":"QbfDiAM=","state":"HALT","gasconsumed":"240","stack":[{"type":"Integer","value":"42"}]}}`,
+			result: func(c *Client) any {
+				return &result.Invoke{
+					State:       "HALT",
+					GasConsumed: 240,
+					Script:      []byte{byte(opcode.SYSCALL), 0xb7, 0xc3, 0x88, 0x03},
+					Stack:       []stackitem.Item{stackitem.Make(42)},
+				}
+			},
+		},
+	},
+	"invokescripts": {
+		{
+			name: "positive",
+			invoke: func(c *Client) (any, error) {
+				return c.InvokeScripts([]neorpc.ScriptWithSigners{
+					{Script: []byte{byte(opcode.PUSH1)}, Signers: []transaction.Signer{{Account: util.Uint160{1, 2, 3}}}},
+					{Script: []byte{byte(opcode.ABORT)}},
+				})
+			},
+			serverResponse: `{"jsonrpc":"2.0","id":1,"result":[{"script":"EQ==","state":"HALT","gasconsumed":"30","stack":[{"type":"Integer","value":"1"}],"notifications":[]},{"script":"OA==","state":"FAULT","gasconsumed":"0","exception":"at instruction 0 (ABORT): ABORT","stack":[],"notifications":[]}]}`,
+			result: func(c *Client) any {
+				return []*result.Invoke{{
+					State:         "HALT",
+					GasConsumed:   30,
+					Script:        []byte{byte(opcode.PUSH1)},
+					Stack:         []stackitem.Item{stackitem.Make(1)},
+					Notifications: []state.NotificationEvent{},
+				}, {
+					State:          "FAULT",
+					GasConsumed:    0,
+					Script:         []byte{byte(opcode.ABORT)},
+					Stack:          []stackitem.Item{},
+					FaultException: "at instruction 0 (ABORT): ABORT",
+					Notifications:  []state.NotificationEvent{},
+				}}
+			},
+		},
 	},
 	"invokecontractverify": {
 		{
@@ -1236,6 +1520,77 @@ var rpcClientTestCases = map[string][]rpcClientTestCase{
 			},
 		},
 	},
+	"tracetransaction": {
+		{
+			name: "positive",
+			invoke: func(c *Client) (any, error) {
+				h, err := util.Uint256DecodeStringLE("5c5ed3a2b5e5b0a2d1e0bc55e19d3f3c65e98e1ba2b7a4b86c2b3e0c3fd2c0a8")
+				if err != nil {
+					panic(err)
+				}
+				return c.TraceTransaction(h, true)
+			},
+			serverResponse: `{"jsonrpc":"2.0","id":1,"result":{"txid":"0x5c5ed3a2b5e5b0a2d1e0bc55e19d3f3c65e98e1ba2b7a4b86c2b3e0c3fd2c0a8","blockindex":12,"vmstate":"HALT","gasconsumed":"1065200","stack":[{"type":"Boolean","value":true}],"call":{"contract":"0xe5c8da8c8c8c4f5c2e47d1c57b81d0fa3b4d1ea5","state":"HALT","gasconsumed":"1065200","calls":[{"contract":"0xd2a4cff31913016155e38e474a2c06d08be276cf","method":"balanceOf","arguments":[{"type":"ByteString","value":"z5KrZOShW1Z3aeDp4k8UUyOm0bs="}],"result":{"type":"Integer","value":"100000000"},"state":"HALT","gasconsumed":"1049820","storage":[{"type":"get","id":-6,"key":"FM+Sq2TkoVtWd2ng6eJPFFMjptG7","value":"QAEhBADh9QU="}]}]},"storagechanges":[],"steps":[{"contract":"0xe5c8da8c8c8c4f5c2e47d1c57b81d0fa3b4d1ea5","offset":0,"opcode":"PUSHT","depth":1,"gasconsumed":"0"}]}}`,
+			result: func(c *Client) any {
+				txH, err := util.Uint256DecodeStringLE("5c5ed3a2b5e5b0a2d1e0bc55e19d3f3c65e98e1ba2b7a4b86c2b3e0c3fd2c0a8")
+				if err != nil {
+					panic(err)
+				}
+				script, err := util.Uint160DecodeStringLE("e5c8da8c8c8c4f5c2e47d1c57b81d0fa3b4d1ea5")
+				if err != nil {
+					panic(err)
+				}
+				gas, err := util.Uint160DecodeStringLE("d2a4cff31913016155e38e474a2c06d08be276cf")
+				if err != nil {
+					panic(err)
+				}
+				acc, err := base64.StdEncoding.DecodeString("z5KrZOShW1Z3aeDp4k8UUyOm0bs=")
+				if err != nil {
+					panic(err)
+				}
+				key, err := base64.StdEncoding.DecodeString("FM+Sq2TkoVtWd2ng6eJPFFMjptG7")
+				if err != nil {
+					panic(err)
+				}
+				val, err := base64.StdEncoding.DecodeString("QAEhBADh9QU=")
+				if err != nil {
+					panic(err)
+				}
+				return &result.TransactionTrace{
+					TxHash:      txH,
+					BlockIndex:  12,
+					VMState:     "HALT",
+					GasConsumed: 1065200,
+					Stack:       []stackitem.Item{stackitem.NewBool(true)},
+					Call: &result.TraceCall{
+						Contract:    script,
+						State:       "HALT",
+						GasConsumed: 1065200,
+						Calls: []*result.TraceCall{{
+							Contract:    gas,
+							Method:      "balanceOf",
+							Arguments:   []stackitem.Item{stackitem.NewByteArray(acc)},
+							Result:      stackitem.NewBigInteger(big.NewInt(100000000)),
+							State:       "HALT",
+							GasConsumed: 1049820,
+							Storage: []result.TraceStorageOp{{
+								Type:  result.TraceStorageGet,
+								ID:    -6,
+								Key:   key,
+								Value: val,
+							}},
+						}},
+					},
+					StorageChanges: []result.TraceStorageChange{},
+					Steps: []result.TraceStep{{
+						Contract: script,
+						Opcode:   "PUSHT",
+						Depth:    1,
+					}},
+				}
+			},
+		},
+	},
 	"validateaddress": {
 		{
 			name: "positive",
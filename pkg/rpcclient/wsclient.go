@@ -396,9 +396,57 @@ func (r *notaryRequestReceiver) Close() {
 	close(r.ch)
 }
 
+// mempoolEventReceiver stores information about memory pool events subscriber.
+type mempoolEventReceiver struct {
+	filter *neorpc.MempoolEventFilter
+	ch     chan<- *result.MempoolEvent
+}
+
+// EventID implements neorpc.Comparator interface.
+func (r *mempoolEventReceiver) EventID() neorpc.EventID {
+	return neorpc.MempoolEventID
+}
+
+// Filter implements neorpc.Comparator interface.
+func (r *mempoolEventReceiver) Filter() neorpc.SubscriptionFilter {
+	if r.filter == nil {
+		return nil
+	}
+	return *r.filter
+}
+
+// Receiver implements notificationReceiver interface.
+func (r *mempoolEventReceiver) Receiver() any {
+	return r.ch
+}
+
+// TrySend implements notificationReceiver interface.
+func (r *mempoolEventReceiver) TrySend(ntf Notification, nonBlocking bool) (bool, bool) {
+	if rpcevent.Matches(r, ntf) {
+		if nonBlocking {
+			select {
+			case r.ch <- ntf.Value.(*result.MempoolEvent):
+			default:
+				return true, true
+			}
+		} else {
+			r.ch <- ntf.Value.(*result.MempoolEvent)
+		}
+
+		return true, false
+	}
+	return false, false
+}
+
+// Close implements notificationReceiver interface.
+func (r *mempoolEventReceiver) Close() {
+	close(r.ch)
+}
+
 // Notification represents a server-generated notification for client subscriptions.
 // Value can be one of *block.Block, *state.AppExecResult, *state.ContainedNotificationEvent
-// *transaction.Transaction or *subscriptions.NotaryRequestEvent based on Type.
+// *transaction.Transaction, *subscriptions.NotaryRequestEvent or
+// *result.MempoolEvent based on Type.
 type Notification struct {
 	Type  neorpc.EventID
 	Value any
@@ -566,6 +614,8 @@ readloop:
 				ntf.Value = new(state.AppExecResult)
 			case neorpc.NotaryRequestEventID:
 				ntf.Value = new(result.NotaryRequestEvent)
+			case neorpc.MempoolEventID:
+				ntf.Value = new(result.MempoolEvent)
 			case neorpc.HeaderOfAddedBlockEventID:
 				sr, err := c.stateRootInHeader()
 				if err != nil {
@@ -910,6 +960,29 @@ func (c *WSClient) ReceiveNotaryRequests(flt *neorpc.NotaryRequestFilter, rcvr c
 	return c.performSubscription(params, r)
 }
 
+// ReceiveMempoolEvents registers provided channel as a receiver for the main
+// memory pool events (transaction addition or removal). Events can be filtered
+// by the given MempoolEventFilter where sender and signer correspond to the
+// transaction sender and signers, type corresponds to the [mempoolevent.Type]
+// and reason corresponds to the [mempoolevent.Reason] of transaction removal.
+// nil value doesn't add any filter. See WSClient comments for generic Receive*
+// behaviour details. This method is only supported by NeoGo servers.
+func (c *WSClient) ReceiveMempoolEvents(flt *neorpc.MempoolEventFilter, rcvr chan<- *result.MempoolEvent) (string, error) {
+	if rcvr == nil {
+		return "", ErrNilNotificationReceiver
+	}
+	params := []any{"mempool_event"}
+	if flt != nil {
+		flt = flt.Copy()
+		params = append(params, *flt)
+	}
+	r := &mempoolEventReceiver{
+		filter: flt,
+		ch:     rcvr,
+	}
+	return c.performSubscription(params, r)
+}
+
 // Unsubscribe removes subscription for the given event stream. It will return an
 // error in case if there's no subscription with the provided ID. Call to Unsubscribe
 // doesn't block notifications receive process for given subscriber, thus, ensure
@@ -51,6 +51,7 @@ func TestWSClientSubscription(t *testing.T) {
 	aerCh := make(chan *state.AppExecResult)
 	ntfCh := make(chan *state.ContainedNotificationEvent)
 	ntrCh := make(chan *result.NotaryRequestEvent)
+	mpCh := make(chan *result.MempoolEvent)
 	var cases = map[string]func(*WSClient) (string, error){
 		"blocks": func(wsc *WSClient) (string, error) {
 			return wsc.ReceiveBlocks(nil, bCh)
@@ -67,6 +68,9 @@ func TestWSClientSubscription(t *testing.T) {
 		"notary requests": func(wsc *WSClient) (string, error) {
 			return wsc.ReceiveNotaryRequests(nil, ntrCh)
 		},
+		"mempool events": func(wsc *WSClient) (string, error) {
+			return wsc.ReceiveMempoolEvents(nil, mpCh)
+		},
 	}
 	t.Run("good", func(t *testing.T) {
 		for name, f := range cases {
@@ -730,6 +734,25 @@ func TestWSFilteredSubscriptions(t *testing.T) {
 				require.Equal(t, mempoolevent.TransactionAdded, *filt.Type)
 			},
 		},
+		{"mempool event sender, signer, type and reason",
+			func(t *testing.T, wsc *WSClient) {
+				sender := util.Uint160{1, 2, 3, 4, 5}
+				signer := util.Uint160{0, 42}
+				mempoolType := mempoolevent.TransactionRemoved
+				reason := mempoolevent.ReasonIncluded
+				_, err := wsc.ReceiveMempoolEvents(&neorpc.MempoolEventFilter{Type: &mempoolType, Reason: &reason, Signer: &signer, Sender: &sender}, make(chan *result.MempoolEvent))
+				require.NoError(t, err)
+			},
+			func(t *testing.T, p *params.Params) {
+				param := p.Value(1)
+				filt := new(neorpc.MempoolEventFilter)
+				require.NoError(t, json.Unmarshal(param.RawMessage, filt))
+				require.Equal(t, util.Uint160{1, 2, 3, 4, 5}, *filt.Sender)
+				require.Equal(t, util.Uint160{0, 42}, *filt.Signer)
+				require.Equal(t, mempoolevent.TransactionRemoved, *filt.Type)
+				require.Equal(t, mempoolevent.ReasonIncluded, *filt.Reason)
+			},
+		},
 	}
 	for _, c := range cases {
 		t.Run(c.name, func(t *testing.T) {
@@ -0,0 +1,217 @@
+/*
+Package ledgersink implements the service delivering processed blocks along
+with their execution results and token transfers to external systems (like
+indexers).
+
+Events are delivered to Sink in batches starting from the height stored in the
+checkpoint file which is updated after every successful delivery, so every
+block is delivered at least once (but it can be delivered more than once if
+the node is stopped between delivery and checkpoint update). File and HTTP
+sinks writing events in NDJSON format are available out of the box, custom
+ones can be used via NewWithSink.
+*/
+package ledgersink
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+
+	"github.com/nspcc-dev/neo-go/pkg/config/limits"
+	"github.com/nspcc-dev/neo-go/pkg/core/block"
+	"github.com/nspcc-dev/neo-go/pkg/core/state"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract/manifest"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract/trigger"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/nspcc-dev/neo-go/pkg/vm/stackitem"
+	"github.com/nspcc-dev/neo-go/pkg/vm/vmstate"
+)
+
+// Event contains all data related to a single processed block.
+type Event struct {
+	Block *block.Block `json:"block"`
+	// Executions contains block's OnPersist execution result followed by
+	// transaction execution results (in the block order) and PostPersist
+	// execution result.
+	Executions []state.AppExecResult `json:"executions"`
+	// Transfers contains all NEP-17 and NEP-11 token transfers made in the
+	// block (in the order of executions).
+	Transfers []Transfer `json:"transfers"`
+}
+
+// Transfer is a single token transfer made in some block.
+type Transfer struct {
+	// Container is the hash of the transaction (or block for OnPersist and
+	// PostPersist executions) this transfer was made by.
+	Container util.Uint256
+	// Contract is the token contract hash.
+	Contract util.Uint160
+	// Standard is the token standard (NEP-17 or NEP-11).
+	Standard string
+	// From is the sender, it's zero for minting.
+	From util.Uint160
+	// To is the receiver, it's zero for burning.
+	To     util.Uint160
+	Amount *big.Int
+	// ID is the NEP-11 token ID, it's nil for NEP-17 transfers.
+	ID []byte
+}
+
+// transferAux is an auxiliary struct for Transfer JSON marshalling.
+type transferAux struct {
+	Container util.Uint256  `json:"container"`
+	Contract  util.Uint160  `json:"contract"`
+	Standard  string        `json:"standard"`
+	From      *util.Uint160 `json:"from"`
+	To        *util.Uint160 `json:"to"`
+	Amount    string        `json:"amount"`
+	ID        string        `json:"tokenid,omitempty"`
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (t Transfer) MarshalJSON() ([]byte, error) {
+	aux := transferAux{
+		Container: t.Container,
+		Contract:  t.Contract,
+		Standard:  t.Standard,
+		Amount:    t.Amount.String(),
+		ID:        hex.EncodeToString(t.ID),
+	}
+	if !t.From.Equals(util.Uint160{}) {
+		aux.From = &t.From
+	}
+	if !t.To.Equals(util.Uint160{}) {
+		aux.To = &t.To
+	}
+	return json.Marshal(aux)
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (t *Transfer) UnmarshalJSON(data []byte) error {
+	aux := new(transferAux)
+	if err := json.Unmarshal(data, aux); err != nil {
+		return err
+	}
+	amount, ok := new(big.Int).SetString(aux.Amount, 10)
+	if !ok {
+		return fmt.Errorf("invalid amount: %s", aux.Amount)
+	}
+	id, err := hex.DecodeString(aux.ID)
+	if err != nil {
+		return fmt.Errorf("invalid token ID: %w", err)
+	}
+	*t = Transfer{
+		Container: aux.Container,
+		Contract:  aux.Contract,
+		Standard:  aux.Standard,
+		Amount:    amount,
+	}
+	if aux.From != nil {
+		t.From = *aux.From
+	}
+	if aux.To != nil {
+		t.To = *aux.To
+	}
+	if t.Standard == manifest.NEP11StandardName {
+		t.ID = id
+	}
+	return nil
+}
+
+// newEvent collects event data for the block at the given height.
+func newEvent(chain Ledger, height uint32) (Event, error) {
+	b, err := chain.GetBlock(chain.GetHeaderHash(height))
+	if err != nil {
+		return Event{}, fmt.Errorf("failed to get block %d: %w", height, err)
+	}
+	aers, err := chain.GetAppExecResults(b.Hash(), trigger.All)
+	if err != nil {
+		return Event{}, fmt.Errorf("failed to get block %d execution results: %w", height, err)
+	}
+	if len(aers) != 2 {
+		return Event{}, fmt.Errorf("unexpected number of block %d execution results: %d", height, len(aers))
+	}
+	var ev = Event{
+		Block:      b,
+		Executions: make([]state.AppExecResult, 0, len(b.Transactions)+2),
+		Transfers:  []Transfer{},
+	}
+	ev.Executions = append(ev.Executions, aers[0])
+	for _, tx := range b.Transactions {
+		txAERs, err := chain.GetAppExecResults(tx.Hash(), trigger.Application)
+		if err != nil {
+			return Event{}, fmt.Errorf("failed to get transaction %s execution result: %w", tx.Hash().StringLE(), err)
+		}
+		ev.Executions = append(ev.Executions, txAERs[0])
+	}
+	ev.Executions = append(ev.Executions, aers[1])
+	for i := range ev.Executions {
+		ev.Transfers = appendTransfers(chain, ev.Transfers, &ev.Executions[i])
+	}
+	return ev, nil
+}
+
+// appendTransfers appends transfers made by the successful execution to the
+// list. Transfer notifications are parsed the same way Blockchain does it
+// for transfer logs, but only the ones emitted by contracts declaring the
+// support of the corresponding standard (NEP-17 or NEP-11) are taken into
+// account.
+func appendTransfers(chain Ledger, ts []Transfer, aer *state.AppExecResult) []Transfer {
+	if aer.VMState != vmstate.Halt {
+		return ts
+	}
+	for _, note := range aer.Events {
+		if note.Name != "Transfer" {
+			continue
+		}
+		arr, ok := note.Item.Value().([]stackitem.Item)
+		if !ok || !(len(arr) == 3 || len(arr) == 4) {
+			continue
+		}
+		from, err := parseUint160(arr[0])
+		if err != nil {
+			continue
+		}
+		to, err := parseUint160(arr[1])
+		if err != nil {
+			continue
+		}
+		amount, err := arr[2].TryInteger()
+		if err != nil {
+			continue
+		}
+		var t = Transfer{
+			Container: aer.Container,
+			Contract:  note.ScriptHash,
+			Standard:  manifest.NEP17StandardName,
+			From:      from,
+			To:        to,
+			Amount:    amount,
+		}
+		if len(arr) == 4 {
+			t.Standard = manifest.NEP11StandardName
+			t.ID, err = arr[3].TryBytes()
+			if err != nil || len(t.ID) > limits.MaxStorageKeyLen {
+				continue
+			}
+		}
+		cs := chain.GetContractState(note.ScriptHash)
+		if cs == nil || !cs.Manifest.IsStandardSupported(t.Standard) {
+			continue
+		}
+		ts = append(ts, t)
+	}
+	return ts
+}
+
+func parseUint160(itm stackitem.Item) (util.Uint160, error) {
+	if _, ok := itm.(stackitem.Null); ok { // Minting or burning.
+		return util.Uint160{}, nil
+	}
+	b, err := itm.TryBytes()
+	if err != nil {
+		return util.Uint160{}, err
+	}
+	return util.Uint160DecodeBytesBE(b)
+}
@@ -0,0 +1,211 @@
+package ledgersink
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/nspcc-dev/neo-go/internal/random"
+	"github.com/nspcc-dev/neo-go/pkg/config"
+	"github.com/nspcc-dev/neo-go/pkg/core/native/nativenames"
+	"github.com/nspcc-dev/neo-go/pkg/core/state"
+	"github.com/nspcc-dev/neo-go/pkg/neotest"
+	"github.com/nspcc-dev/neo-go/pkg/neotest/chain"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract/manifest"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract/trigger"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/nspcc-dev/neo-go/pkg/vm/stackitem"
+	"github.com/nspcc-dev/neo-go/pkg/vm/vmstate"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+)
+
+func readEvents(t *testing.T, r io.Reader) []Event {
+	var res []Event
+	sc := bufio.NewScanner(r)
+	sc.Buffer(nil, 1024*1024)
+	for sc.Scan() {
+		var ev Event
+		require.NoError(t, json.Unmarshal(sc.Bytes(), &ev))
+		res = append(res, ev)
+	}
+	require.NoError(t, sc.Err())
+	return res
+}
+
+func TestService_File(t *testing.T) {
+	bc, acc := chain.NewSingle(t)
+	e := neotest.NewExecutor(t, bc, acc, acc)
+	gas := e.ValidatorInvoker(e.NativeHash(t, nativenames.Gas))
+
+	to := random.Uint160()
+	txH := gas.Invoke(t, true, "transfer", acc.ScriptHash(), to, 1_0000_0000, nil)
+
+	var (
+		dir = t.TempDir()
+		cfg = config.LedgerSink{
+			Enabled:        true,
+			Type:           TypeFile,
+			Path:           filepath.Join(dir, "events.ndjson"),
+			CheckpointFile: filepath.Join(dir, "checkpoint"),
+			BatchSize:      1,
+		}
+	)
+	s, err := New(cfg, bc, zaptest.NewLogger(t))
+	require.NoError(t, err)
+	s.Start()
+	require.Eventually(t, func() bool {
+		h, ok, err := s.loadCheckpoint()
+		return err == nil && ok && h == bc.BlockHeight()
+	}, 5*time.Second, 10*time.Millisecond)
+	s.Shutdown()
+
+	f, err := os.Open(cfg.Path)
+	require.NoError(t, err)
+	events := readEvents(t, f)
+	require.NoError(t, f.Close())
+	require.Equal(t, int(bc.BlockHeight())+1, len(events))
+	for i, ev := range events {
+		require.Equal(t, uint32(i), ev.Block.Index)
+		require.Equal(t, bc.GetHeaderHash(uint32(i)), ev.Block.Hash())
+		require.Equal(t, len(ev.Block.Transactions)+2, len(ev.Executions))
+		require.Equal(t, trigger.OnPersist, ev.Executions[0].Trigger)
+		require.Equal(t, trigger.PostPersist, ev.Executions[len(ev.Executions)-1].Trigger)
+	}
+	last := events[len(events)-1]
+	require.Equal(t, txH, last.Block.Transactions[0].Hash())
+	aers, err := bc.GetAppExecResults(txH, trigger.Application)
+	require.NoError(t, err)
+	require.Equal(t, aers[0], last.Executions[1])
+	var found bool
+	for _, tr := range last.Transfers {
+		if tr.Container == txH && tr.To == to {
+			found = true
+			require.Equal(t, e.NativeHash(t, nativenames.Gas), tr.Contract)
+			require.Equal(t, manifest.NEP17StandardName, tr.Standard)
+			require.Equal(t, acc.ScriptHash(), tr.From)
+			require.Equal(t, int64(1_0000_0000), tr.Amount.Int64())
+			require.Nil(t, tr.ID)
+		}
+	}
+	require.True(t, found)
+
+	// Restarted service continues from the checkpoint.
+	gas.Invoke(t, true, "transfer", acc.ScriptHash(), to, 1, nil)
+	s, err = New(cfg, bc, zaptest.NewLogger(t))
+	require.NoError(t, err)
+	s.Start()
+	require.Eventually(t, func() bool {
+		h, ok, err := s.loadCheckpoint()
+		return err == nil && ok && h == bc.BlockHeight()
+	}, 5*time.Second, 10*time.Millisecond)
+	s.Shutdown()
+
+	f, err = os.Open(cfg.Path)
+	require.NoError(t, err)
+	events = readEvents(t, f)
+	require.NoError(t, f.Close())
+	require.Equal(t, int(bc.BlockHeight())+1, len(events))
+	require.Equal(t, bc.CurrentBlockHash(), events[len(events)-1].Block.Hash())
+}
+
+func TestService_HTTP(t *testing.T) {
+	bc, acc := chain.NewSingle(t)
+	e := neotest.NewExecutor(t, bc, acc, acc)
+
+	var (
+		lock     sync.Mutex
+		attempts int
+		received []Event
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lock.Lock()
+		defer lock.Unlock()
+		attempts++
+		if attempts == 1 {
+			http.Error(w, "not now", http.StatusServiceUnavailable)
+			return
+		}
+		require.Equal(t, ndjsonContentType, r.Header.Get("Content-Type"))
+		received = append(received, readEvents(t, r.Body)...)
+	}))
+	defer srv.Close()
+
+	s, err := New(config.LedgerSink{
+		Enabled:        true,
+		Type:           TypeHTTP,
+		URL:            srv.URL,
+		CheckpointFile: filepath.Join(t.TempDir(), "checkpoint"),
+		RetryInterval:  10 * time.Millisecond,
+	}, bc, zaptest.NewLogger(t))
+	require.NoError(t, err)
+	s.Start()
+	defer s.Shutdown()
+
+	e.AddNewBlock(t)
+	e.AddNewBlock(t)
+	require.Eventually(t, func() bool {
+		lock.Lock()
+		defer lock.Unlock()
+		return len(received) > 0 && received[len(received)-1].Block.Index == bc.BlockHeight()
+	}, 5*time.Second, 10*time.Millisecond)
+
+	lock.Lock()
+	defer lock.Unlock()
+	require.True(t, attempts > 1)
+	for i, ev := range received {
+		require.Equal(t, uint32(i), ev.Block.Index)
+	}
+}
+
+func TestNew(t *testing.T) {
+	bc, _ := chain.NewSingle(t)
+	dir := t.TempDir()
+
+	_, err := New(config.LedgerSink{Type: "kafka", CheckpointFile: filepath.Join(dir, "checkpoint")}, bc, zaptest.NewLogger(t))
+	require.Error(t, err)
+	_, err = New(config.LedgerSink{Type: TypeHTTP}, bc, zaptest.NewLogger(t))
+	require.Error(t, err)
+	_, err = New(config.LedgerSink{Type: TypeFile, CheckpointFile: filepath.Join(dir, "checkpoint")}, bc, zaptest.NewLogger(t))
+	require.Error(t, err)
+
+	cp := filepath.Join(dir, "bad")
+	require.NoError(t, os.WriteFile(cp, []byte("height"), 0644))
+	_, err = New(config.LedgerSink{Type: TypeHTTP, URL: "http://localhost", CheckpointFile: cp}, bc, zaptest.NewLogger(t))
+	require.Error(t, err)
+}
+
+func TestAppendTransfers(t *testing.T) {
+	bc, _ := chain.NewSingle(t)
+	gasHash, err := bc.GetNativeContractScriptHash(nativenames.Gas)
+	require.NoError(t, err)
+	neoHash, err := bc.GetNativeContractScriptHash(nativenames.Neo)
+	require.NoError(t, err)
+	from, to := random.Uint160(), random.Uint160()
+	transfer := func(h util.Uint160, withID bool) state.NotificationEvent {
+		args := []stackitem.Item{stackitem.Make(from.BytesBE()), stackitem.Make(to.BytesBE()), stackitem.Make(1)}
+		if withID {
+			args = append(args, stackitem.Make([]byte{1}))
+		}
+		return state.NotificationEvent{ScriptHash: h, Name: "Transfer", Item: stackitem.NewArray(args)}
+	}
+	aer := &state.AppExecResult{Execution: state.Execution{
+		VMState: vmstate.Halt,
+		Events: []state.NotificationEvent{
+			transfer(gasHash, false),
+			transfer(neoHash, true),           // NEO is not NEP-11.
+			transfer(random.Uint160(), false), // Unknown contract.
+		},
+	}}
+	ts := appendTransfers(bc, nil, aer)
+	require.Equal(t, 1, len(ts))
+	require.Equal(t, gasHash, ts[0].Contract)
+	require.Equal(t, manifest.NEP17StandardName, ts[0].Standard)
+}
@@ -0,0 +1,245 @@
+package ledgersink
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/nspcc-dev/neo-go/pkg/config"
+	"github.com/nspcc-dev/neo-go/pkg/core/block"
+	"github.com/nspcc-dev/neo-go/pkg/core/state"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract/trigger"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"go.uber.org/zap"
+)
+
+const (
+	// DefaultBatchSize is the default maximum number of blocks delivered at
+	// once.
+	DefaultBatchSize = 100
+	// DefaultRetryInterval is the default interval between delivery attempts.
+	DefaultRetryInterval = 5 * time.Second
+)
+
+// Ledger is an interface to Blockchain sufficient for Service.
+type Ledger interface {
+	BlockHeight() uint32
+	GetAppExecResults(util.Uint256, trigger.Type) ([]state.AppExecResult, error)
+	GetBlock(hash util.Uint256) (*block.Block, error)
+	GetContractState(hash util.Uint160) *state.Contract
+	GetHeaderHash(uint32) util.Uint256
+	SubscribeForBlocks(ch chan *block.Block)
+	UnsubscribeFromBlocks(ch chan *block.Block)
+}
+
+// Service delivers events for every processed block to Sink.
+type Service struct {
+	cfg   config.LedgerSink
+	chain Ledger
+	sink  Sink
+	log   *zap.Logger
+
+	// next is the height of the next block to be delivered.
+	next    uint32
+	started atomic.Bool
+	// blockCh is used to receive block notifications from the Blockchain,
+	// they're only used to wake the delivery loop up via wakeCh, so that
+	// slow sinks don't block the Blockchain.
+	blockCh chan *block.Block
+	wakeCh  chan struct{}
+	stopCh  chan struct{}
+	wg      sync.WaitGroup
+}
+
+// New creates a new Service with one of the built-in sinks specified in the
+// configuration.
+func New(cfg config.LedgerSink, chain Ledger, log *zap.Logger) (*Service, error) {
+	sink, err := newSink(cfg)
+	if err != nil {
+		return nil, err
+	}
+	s, err := NewWithSink(cfg, chain, sink, log)
+	if err != nil {
+		_ = sink.Close()
+		return nil, err
+	}
+	return s, nil
+}
+
+// NewWithSink creates a new Service delivering events to the given Sink,
+// Type, Path, URL and Timeout configuration fields are ignored. The sink is
+// closed by the Service on shutdown.
+func NewWithSink(cfg config.LedgerSink, chain Ledger, sink Sink, log *zap.Logger) (*Service, error) {
+	if cfg.CheckpointFile == "" {
+		return nil, errors.New("checkpoint file is not set")
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = DefaultBatchSize
+	}
+	if cfg.RetryInterval <= 0 {
+		cfg.RetryInterval = DefaultRetryInterval
+	}
+	s := &Service{
+		cfg:     cfg,
+		chain:   chain,
+		sink:    sink,
+		log:     log.With(zap.String("service", "LedgerSink"), zap.String("checkpoint", cfg.CheckpointFile)),
+		blockCh: make(chan *block.Block, 1),
+		wakeCh:  make(chan struct{}, 1),
+		stopCh:  make(chan struct{}),
+	}
+	h, ok, err := s.loadCheckpoint()
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		s.next = h + 1
+	}
+	return s, nil
+}
+
+// Name returns service name.
+func (s *Service) Name() string {
+	return "ledgersink"
+}
+
+// Start runs service instance in a separate goroutine.
+// The service only starts once, subsequent calls to Start are no-op.
+func (s *Service) Start() {
+	if !s.started.CompareAndSwap(false, true) {
+		return
+	}
+	s.log.Info("starting ledger sink service", zap.Uint32("next", s.next))
+	s.chain.SubscribeForBlocks(s.blockCh)
+	s.wg.Add(2)
+	go s.notify()
+	go s.run()
+}
+
+// Shutdown stops the service and closes its sink. It can only be called
+// once, subsequent calls to Shutdown on the same instance are no-op. The
+// instance that was stopped can not be started again by calling Start (use
+// a new instance if needed).
+func (s *Service) Shutdown() {
+	if !s.started.CompareAndSwap(true, false) {
+		return
+	}
+	s.log.Info("stopping ledger sink service")
+	close(s.stopCh)
+	s.wg.Wait()
+	if err := s.sink.Close(); err != nil {
+		s.log.Warn("failed to close sink", zap.Error(err))
+	}
+	_ = s.log.Sync()
+}
+
+// notify converts block notifications into non-blocking wake-ups of the
+// delivery loop.
+func (s *Service) notify() {
+	defer s.wg.Done()
+runloop:
+	for {
+		select {
+		case <-s.blockCh:
+			select {
+			case s.wakeCh <- struct{}{}:
+			default:
+			}
+		case <-s.stopCh:
+			break runloop
+		}
+	}
+	s.chain.UnsubscribeFromBlocks(s.blockCh)
+drainloop:
+	for {
+		select {
+		case <-s.blockCh:
+		default:
+			break drainloop
+		}
+	}
+	close(s.blockCh)
+}
+
+func (s *Service) run() {
+	defer s.wg.Done()
+	for {
+		for s.next <= s.chain.BlockHeight() {
+			err := s.deliver()
+			if err == nil {
+				continue
+			}
+			s.log.Warn("failed to deliver events", zap.Uint32("height", s.next), zap.Error(err),
+				zap.Duration("retry in", s.cfg.RetryInterval))
+			select {
+			case <-time.After(s.cfg.RetryInterval):
+			case <-s.stopCh:
+				return
+			}
+		}
+		select {
+		case <-s.wakeCh:
+		case <-s.stopCh:
+			return
+		}
+	}
+}
+
+// deliver sends the next batch of events to the sink and updates the
+// checkpoint.
+func (s *Service) deliver() error {
+	var (
+		last   = s.chain.BlockHeight()
+		events = make([]Event, 0, s.cfg.BatchSize)
+	)
+	for h := s.next; h <= last && len(events) < s.cfg.BatchSize; h++ {
+		ev, err := newEvent(s.chain, h)
+		if err != nil {
+			return err
+		}
+		events = append(events, ev)
+	}
+	err := s.sink.Send(events)
+	if err != nil {
+		return err
+	}
+	s.next += uint32(len(events))
+	// Failed checkpoint update only leads to duplicate delivery after
+	// restart, so it's not retried.
+	if err = s.saveCheckpoint(s.next - 1); err != nil {
+		s.log.Warn("failed to save checkpoint", zap.Error(err))
+	}
+	return nil
+}
+
+// loadCheckpoint returns the height of the last delivered block (if any).
+func (s *Service) loadCheckpoint() (uint32, bool, error) {
+	data, err := os.ReadFile(s.cfg.CheckpointFile)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return 0, false, nil
+		}
+		return 0, false, fmt.Errorf("failed to read checkpoint: %w", err)
+	}
+	h, err := strconv.ParseUint(strings.TrimSpace(string(data)), 10, 32)
+	if err != nil {
+		return 0, false, fmt.Errorf("invalid checkpoint: %w", err)
+	}
+	return uint32(h), true, nil
+}
+
+// saveCheckpoint atomically replaces the checkpoint file contents with the
+// given height.
+func (s *Service) saveCheckpoint(h uint32) error {
+	tmp := s.cfg.CheckpointFile + ".tmp"
+	err := os.WriteFile(tmp, []byte(strconv.FormatUint(uint64(h), 10)+"\n"), 0644)
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmp, s.cfg.CheckpointFile)
+}
@@ -0,0 +1,164 @@
+package ledgersink
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/nspcc-dev/neo-go/pkg/config"
+)
+
+// Sink receives ledger events.
+type Sink interface {
+	// Send delivers the batch of events to the sink. Events are always
+	// passed in the block order, but the same events can be passed again
+	// if the previous attempt has failed or the node was restarted before
+	// the checkpoint update.
+	Send(events []Event) error
+	// Close releases sink resources, Send is never called after it.
+	Close() error
+}
+
+const (
+	// TypeFile is the type of the sink appending events to a file.
+	TypeFile = "file"
+	// TypeHTTP is the type of the sink POSTing events to an HTTP endpoint.
+	TypeHTTP = "http"
+
+	// DefaultHTTPTimeout is the default timeout for a single HTTP request.
+	DefaultHTTPTimeout = 10 * time.Second
+
+	// ndjsonContentType is the content type of HTTP sink requests.
+	ndjsonContentType = "application/x-ndjson"
+)
+
+// newSink creates one of the built-in sinks according to the configuration.
+func newSink(cfg config.LedgerSink) (Sink, error) {
+	switch cfg.Type {
+	case TypeFile:
+		return NewFileSink(cfg.Path)
+	case TypeHTTP:
+		return NewHTTPSink(cfg.URL, cfg.Timeout)
+	default:
+		return nil, fmt.Errorf("unknown sink type %q", cfg.Type)
+	}
+}
+
+// writeNDJSON writes events to w, one JSON object per line.
+func writeNDJSON(w io.Writer, events []Event) error {
+	enc := json.NewEncoder(w)
+	for i := range events {
+		if err := enc.Encode(&events[i]); err != nil {
+			return fmt.Errorf("failed to marshal block %d event: %w", events[i].Block.Index, err)
+		}
+	}
+	return nil
+}
+
+// FileSink appends events to a file in NDJSON format.
+type FileSink struct {
+	f *os.File
+}
+
+// NewFileSink creates a FileSink appending events to the file at the given
+// path (it's created if not exists).
+func NewFileSink(path string) (*FileSink, error) {
+	if path == "" {
+		return nil, errors.New("file sink path is not set")
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open sink file: %w", err)
+	}
+	return &FileSink{f: f}, nil
+}
+
+// Send implements the Sink interface. Events are synced to disk before it
+// returns. If they can't be written completely, the file is truncated back to
+// its previous size, so that no partial records are left there.
+func (s *FileSink) Send(events []Event) error {
+	var buf bytes.Buffer
+	if err := writeNDJSON(&buf, events); err != nil {
+		return err
+	}
+	off, err := s.f.Seek(0, io.SeekEnd)
+	if err != nil {
+		return err
+	}
+	_, err = s.f.Write(buf.Bytes())
+	if err == nil {
+		err = s.f.Sync()
+	}
+	if err != nil {
+		if terr := s.f.Truncate(off); terr != nil {
+			return errors.Join(err, fmt.Errorf("failed to truncate sink file: %w", terr))
+		}
+	}
+	return err
+}
+
+// Close implements the Sink interface.
+func (s *FileSink) Close() error {
+	return s.f.Close()
+}
+
+// HTTPSink POSTs event batches to an HTTP endpoint in NDJSON format, any
+// 2xx response status code is treated as a successful delivery.
+type HTTPSink struct {
+	url    string
+	client http.Client
+}
+
+// NewHTTPSink creates an HTTPSink sending events to the given URL, the
+// timeout is applied to every request (DefaultHTTPTimeout is used if it's
+// not positive).
+func NewHTTPSink(url string, timeout time.Duration) (*HTTPSink, error) {
+	if url == "" {
+		return nil, errors.New("HTTP sink URL is not set")
+	}
+	if timeout <= 0 {
+		timeout = DefaultHTTPTimeout
+	}
+	return &HTTPSink{
+		url:    url,
+		client: http.Client{Timeout: timeout},
+	}, nil
+}
+
+// Send implements the Sink interface.
+func (s *HTTPSink) Send(events []Event) error {
+	var buf bytes.Buffer
+	if err := writeNDJSON(&buf, events); err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, s.url, &buf)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", ndjsonContentType)
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		var msg = make([]byte, 1024)
+		n, _ := resp.Body.Read(msg)
+		return fmt.Errorf("sink endpoint responded with %s: %s", resp.Status, strings.TrimSpace(string(msg[:n])))
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	return nil
+}
+
+// Close implements the Sink interface.
+func (s *HTTPSink) Close() error {
+	s.client.CloseIdleConnections()
+	return nil
+}
@@ -0,0 +1,285 @@
+package plugins
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/nspcc-dev/neo-go/pkg/config"
+	"github.com/nspcc-dev/neo-go/pkg/core/block"
+	"github.com/nspcc-dev/neo-go/pkg/core/state"
+	"github.com/nspcc-dev/neo-go/pkg/core/transaction"
+	"github.com/nspcc-dev/neo-go/pkg/neorpc"
+	"github.com/nspcc-dev/neo-go/pkg/network"
+	"github.com/nspcc-dev/neo-go/pkg/network/payload"
+	"github.com/nspcc-dev/neo-go/pkg/services/rpcsrv/params"
+	"github.com/nspcc-dev/neo-go/pkg/services/stateroot"
+	"go.uber.org/zap"
+)
+
+const (
+	// DefaultCallTimeout is the default timeout for RPC method calls handled
+	// by plugins.
+	DefaultCallTimeout = 10 * time.Second
+	// DefaultRetryInterval is the default interval between reconnection
+	// attempts.
+	DefaultRetryInterval = 5 * time.Second
+
+	// queueSize is the size of the outgoing message queue of every plugin,
+	// the plugin is disconnected when it overflows.
+	queueSize = 1024
+)
+
+type (
+	// Ledger is an interface to Blockchain sufficient for Host.
+	Ledger interface {
+		SubscribeForBlocks(ch chan *block.Block)
+		SubscribeForNotifications(ch chan *state.ContainedNotificationEvent)
+		SubscribeForTransactions(ch chan *transaction.Transaction)
+		UnsubscribeFromBlocks(ch chan *block.Block)
+		UnsubscribeFromNotifications(ch chan *state.ContainedNotificationEvent)
+		UnsubscribeFromTransactions(ch chan *transaction.Transaction)
+	}
+
+	// Node is an interface to the network server sufficient for Host.
+	Node interface {
+		AddExtensibleService(svc network.Service, category string, handler func(*payload.Extensible) error)
+		BroadcastExtensible(p *payload.Extensible)
+		DelExtensibleService(svc network.Service, category string)
+		RelayTxn(t *transaction.Transaction) error
+	}
+
+	// Host connects to configured plugins and serves them.
+	Host struct {
+		chain   Ledger
+		node    Node
+		log     *zap.Logger
+		plugins []*plugin
+
+		lock       sync.RWMutex
+		methods    map[string]*plugin
+		categories map[string]*plugin
+
+		started atomic.Bool
+		blockCh chan *block.Block
+		txCh    chan *transaction.Transaction
+		ntfCh   chan *state.ContainedNotificationEvent
+		stopCh  chan struct{}
+		done    chan struct{}
+	}
+)
+
+// reservedCategories can't be registered by plugins.
+var reservedCategories = map[string]bool{
+	payload.ConsensusCategory: true,
+	stateroot.Category:        true,
+}
+
+// NewHost creates a new Host for all enabled plugins from the list.
+func NewHost(cfgs []config.Plugin, chain Ledger, node Node, log *zap.Logger) (*Host, error) {
+	h := &Host{
+		chain:      chain,
+		node:       node,
+		log:        log.With(zap.String("service", "Plugins")),
+		methods:    make(map[string]*plugin),
+		categories: make(map[string]*plugin),
+		blockCh:    make(chan *block.Block),
+		txCh:       make(chan *transaction.Transaction),
+		ntfCh:      make(chan *state.ContainedNotificationEvent),
+		stopCh:     make(chan struct{}),
+		done:       make(chan struct{}),
+	}
+	for i, cfg := range cfgs {
+		if !cfg.Enabled {
+			continue
+		}
+		if cfg.Address == "" {
+			return nil, fmt.Errorf("plugin #%d address is not set", i)
+		}
+		h.plugins = append(h.plugins, newPlugin(h, cfg))
+	}
+	return h, nil
+}
+
+// Name returns service name.
+func (h *Host) Name() string {
+	return "plugins"
+}
+
+// Start launches plugin processes (if configured) and connects to plugins.
+// The service only starts once, subsequent calls to Start are no-op.
+func (h *Host) Start() error {
+	if !h.started.CompareAndSwap(false, true) {
+		return nil
+	}
+	h.log.Info("starting plugin host", zap.Int("plugins", len(h.plugins)))
+	for i, p := range h.plugins {
+		if err := p.launch(); err != nil {
+			for _, launched := range h.plugins[:i] {
+				launched.kill()
+			}
+			return fmt.Errorf("failed to launch plugin at %s: %w", p.cfg.Address, err)
+		}
+	}
+	h.chain.SubscribeForBlocks(h.blockCh)
+	h.chain.SubscribeForTransactions(h.txCh)
+	h.chain.SubscribeForNotifications(h.ntfCh)
+	go h.run()
+	for _, p := range h.plugins {
+		go p.run()
+	}
+	return nil
+}
+
+// Shutdown disconnects from plugins and stops launched plugin processes. It
+// can only be called once, subsequent calls to Shutdown on the same instance
+// are no-op. The instance that was stopped can not be started again by
+// calling Start (use a new instance if needed).
+func (h *Host) Shutdown() {
+	if !h.started.CompareAndSwap(true, false) {
+		return
+	}
+	h.log.Info("stopping plugin host")
+	for _, p := range h.plugins {
+		p.stop()
+	}
+	close(h.stopCh)
+	<-h.done
+	_ = h.log.Sync()
+}
+
+// HandleRPC handles RPC methods registered by plugins, it returns false if
+// the method is not registered by any of them.
+func (h *Host) HandleRPC(method string, reqParams params.Params) (any, *neorpc.Error, bool) {
+	h.lock.RLock()
+	p := h.methods[method]
+	h.lock.RUnlock()
+	if p == nil {
+		return nil, nil, false
+	}
+	if reqParams == nil {
+		reqParams = params.Params{}
+	}
+	ps, err := json.Marshal(reqParams)
+	if err != nil {
+		return nil, neorpc.NewInvalidParamsError(err.Error()), true
+	}
+	res, rpcErr := p.call(method, ps)
+	return res, rpcErr, true
+}
+
+// register registers methods and categories of the plugin.
+func (h *Host) register(p *plugin, r *Registration) error {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	for _, other := range h.plugins {
+		if other != p && other.name() == r.Name {
+			return fmt.Errorf("plugin %q is already connected", r.Name)
+		}
+	}
+	for _, m := range r.Methods {
+		if h.methods[m] != nil {
+			return fmt.Errorf("method %q is already registered", m)
+		}
+	}
+	for _, c := range r.Categories {
+		if reservedCategories[c] || h.categories[c] != nil {
+			return fmt.Errorf("category %q can't be registered", c)
+		}
+	}
+	for _, m := range r.Methods {
+		h.methods[m] = p
+	}
+	for _, c := range r.Categories {
+		h.categories[c] = p
+		h.node.AddExtensibleService(p.svc, c, p.onExtensible)
+	}
+	return nil
+}
+
+// unregister drops all methods and categories registered by the plugin.
+func (h *Host) unregister(p *plugin) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	for m, owner := range h.methods {
+		if owner == p {
+			delete(h.methods, m)
+		}
+	}
+	for c, owner := range h.categories {
+		if owner == p {
+			delete(h.categories, c)
+			h.node.DelExtensibleService(p.svc, c)
+		}
+	}
+}
+
+// handleRequest executes the plugin request.
+func (h *Host) handleRequest(r *Request) *Result {
+	var (
+		err error
+		res = &Result{ID: r.ID}
+	)
+	switch {
+	case r.Transaction != nil && r.Extensible == nil:
+		var tx *transaction.Transaction
+		tx, err = transaction.NewTransactionFromBytes(r.Transaction)
+		if err == nil {
+			err = h.node.RelayTxn(tx)
+		}
+	case r.Extensible != nil && r.Transaction == nil:
+		var e *payload.Extensible
+		e, err = decodeExtensible(r.Extensible)
+		if err == nil {
+			h.node.BroadcastExtensible(e)
+		}
+	default:
+		err = errors.New("exactly one of transaction and extensible must be set")
+	}
+	if err != nil {
+		res.Error = err.Error()
+	}
+	return res
+}
+
+func (h *Host) run() {
+runloop:
+	for {
+		var m *HostMessage
+		select {
+		case b := <-h.blockCh:
+			m = &HostMessage{Block: b}
+		case tx := <-h.txCh:
+			m = &HostMessage{Transaction: tx}
+		case ntf := <-h.ntfCh:
+			m = &HostMessage{Notification: ntf}
+		case <-h.stopCh:
+			break runloop
+		}
+		for _, p := range h.plugins {
+			p.send(m)
+		}
+	}
+	h.chain.UnsubscribeFromBlocks(h.blockCh)
+	h.chain.UnsubscribeFromTransactions(h.txCh)
+	h.chain.UnsubscribeFromNotifications(h.ntfCh)
+drainloop:
+	for {
+		select {
+		case <-h.blockCh:
+		case <-h.txCh:
+		case <-h.ntfCh:
+		default:
+			break drainloop
+		}
+	}
+	close(h.blockCh)
+	close(h.txCh)
+	close(h.ntfCh)
+	close(h.done)
+}
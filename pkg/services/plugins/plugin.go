@@ -0,0 +1,329 @@
+package plugins
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"sync"
+	"time"
+
+	"github.com/nspcc-dev/neo-go/pkg/config"
+	"github.com/nspcc-dev/neo-go/pkg/io"
+	"github.com/nspcc-dev/neo-go/pkg/neorpc"
+	"github.com/nspcc-dev/neo-go/pkg/network/payload"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// processStopTimeout is the time launched plugin process has to exit after
+// interruption before being killed.
+const processStopTimeout = 5 * time.Second
+
+type (
+	// plugin is the node side of a single plugin connection.
+	plugin struct {
+		host *Host
+		cfg  config.Plugin
+		log  *zap.Logger
+		svc  *extensibleService
+		cmd  *exec.Cmd
+
+		ctx    context.Context
+		cancel context.CancelFunc
+		done   chan struct{}
+
+		lock sync.Mutex
+		// reg is the current plugin registration, it's nil when the plugin
+		// is not connected.
+		reg *Registration
+		// out is the outgoing message queue of the current connection.
+		out chan *HostMessage
+		// drop closes the current connection.
+		drop   context.CancelFunc
+		calls  map[uint64]chan *Reply
+		nextID uint64
+	}
+
+	// extensibleService is a stub network.Service used to register plugin
+	// extensible payload handlers, the plugin lifecycle is managed by Host.
+	extensibleService struct {
+		name string
+	}
+)
+
+// Name implements the network.Service interface.
+func (s *extensibleService) Name() string { return s.name }
+
+// Start implements the network.Service interface.
+func (s *extensibleService) Start() {}
+
+// Shutdown implements the network.Service interface.
+func (s *extensibleService) Shutdown() {}
+
+func newPlugin(h *Host, cfg config.Plugin) *plugin {
+	if cfg.CallTimeout <= 0 {
+		cfg.CallTimeout = DefaultCallTimeout
+	}
+	if cfg.RetryInterval <= 0 {
+		cfg.RetryInterval = DefaultRetryInterval
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	return &plugin{
+		host:   h,
+		cfg:    cfg,
+		log:    h.log.With(zap.String("address", cfg.Address)),
+		svc:    &extensibleService{name: "plugin:" + cfg.Address},
+		ctx:    ctx,
+		cancel: cancel,
+		done:   make(chan struct{}),
+	}
+}
+
+// name returns the name of the connected plugin.
+func (p *plugin) name() string {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if p.reg == nil {
+		return ""
+	}
+	return p.reg.Name
+}
+
+// launch starts the plugin process if it's configured.
+func (p *plugin) launch() error {
+	if len(p.cfg.Command) == 0 {
+		return nil
+	}
+	p.cmd = exec.Command(p.cfg.Command[0], p.cfg.Command[1:]...)
+	p.cmd.Stdout = os.Stdout
+	p.cmd.Stderr = os.Stderr
+	if err := p.cmd.Start(); err != nil {
+		p.cmd = nil
+		return err
+	}
+	p.log.Info("plugin process started", zap.Int("pid", p.cmd.Process.Pid))
+	return nil
+}
+
+// stop disconnects from the plugin and stops its process.
+func (p *plugin) stop() {
+	p.cancel()
+	<-p.done
+	p.kill()
+}
+
+// kill stops the launched plugin process (if any).
+func (p *plugin) kill() {
+	if p.cmd == nil {
+		return
+	}
+	var exited = make(chan struct{})
+	go func() {
+		_ = p.cmd.Wait()
+		close(exited)
+	}()
+	if err := p.cmd.Process.Signal(os.Interrupt); err == nil {
+		select {
+		case <-exited:
+			return
+		case <-time.After(processStopTimeout):
+		}
+	}
+	_ = p.cmd.Process.Kill()
+	<-exited
+}
+
+func (p *plugin) run() {
+	defer close(p.done)
+	for {
+		err := p.serve()
+		if p.ctx.Err() != nil {
+			return
+		}
+		p.log.Warn("plugin connection failed", zap.Error(err),
+			zap.Duration("retry in", p.cfg.RetryInterval))
+		select {
+		case <-time.After(p.cfg.RetryInterval):
+		case <-p.ctx.Done():
+			return
+		}
+	}
+}
+
+// serve connects to the plugin and serves it until the connection is closed.
+func (p *plugin) serve() error {
+	ctx, cancel := context.WithCancel(p.ctx)
+	defer cancel()
+
+	conn, err := grpc.DialContext(ctx, p.cfg.Address,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithDefaultCallOptions(grpc.ForceCodec(codec{})))
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	stream, err := conn.NewStream(ctx, &streamDesc, MethodName)
+	if err != nil {
+		return err
+	}
+	var m PluginMessage
+	if err = stream.RecvMsg(&m); err != nil {
+		return fmt.Errorf("failed to receive registration: %w", err)
+	}
+	if m.Register == nil || m.Register.Name == "" {
+		return errors.New("plugin didn't register")
+	}
+	if err = p.host.register(p, m.Register); err != nil {
+		return err
+	}
+	defer p.host.unregister(p)
+
+	var out = make(chan *HostMessage, queueSize)
+	p.lock.Lock()
+	p.reg = m.Register
+	p.out = out
+	p.drop = cancel
+	p.calls = make(map[uint64]chan *Reply)
+	p.lock.Unlock()
+	defer p.disconnect()
+	p.log.Info("plugin connected", zap.String("name", m.Register.Name),
+		zap.Strings("categories", m.Register.Categories), zap.Strings("methods", m.Register.Methods))
+
+	go func() {
+		for {
+			select {
+			case msg := <-out:
+				if err := stream.SendMsg(msg); err != nil {
+					cancel()
+					return
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	for {
+		var m PluginMessage
+		if err := stream.RecvMsg(&m); err != nil {
+			return err
+		}
+		switch {
+		case m.Reply != nil:
+			p.lock.Lock()
+			ch := p.calls[m.Reply.ID]
+			delete(p.calls, m.Reply.ID)
+			p.lock.Unlock()
+			if ch != nil {
+				ch <- m.Reply
+			}
+		case m.Request != nil:
+			p.send(&HostMessage{Result: p.host.handleRequest(m.Request)})
+		default:
+			return errors.New("unexpected plugin message")
+		}
+	}
+}
+
+// disconnect resets the connection state failing all pending calls.
+func (p *plugin) disconnect() {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	for _, ch := range p.calls {
+		close(ch)
+	}
+	p.reg = nil
+	p.out = nil
+	p.drop = nil
+	p.calls = nil
+	p.log.Info("plugin disconnected")
+}
+
+// send enqueues the message for the connected plugin, it returns false if
+// the plugin is not connected or the message can't be enqueued (the
+// connection is closed in this case).
+func (p *plugin) send(m *HostMessage) bool {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	return p.sendLocked(m)
+}
+
+// sendLocked is an unlocked version of send.
+func (p *plugin) sendLocked(m *HostMessage) bool {
+	if p.out == nil {
+		return false
+	}
+	select {
+	case p.out <- m:
+		return true
+	default:
+		p.log.Warn("plugin message queue overflow, dropping connection")
+		p.drop()
+		p.out = nil
+		return false
+	}
+}
+
+// call calls the RPC method implemented by the plugin.
+func (p *plugin) call(method string, ps json.RawMessage) (any, *neorpc.Error) {
+	var ch = make(chan *Reply, 1)
+
+	p.lock.Lock()
+	id := p.nextID
+	p.nextID++
+	if p.calls != nil {
+		p.calls[id] = ch
+	}
+	ok := p.calls != nil && p.sendLocked(&HostMessage{Call: &Call{ID: id, Method: method, Params: ps}})
+	p.lock.Unlock()
+	if !ok {
+		return nil, neorpc.NewInternalServerError("plugin is not connected")
+	}
+
+	var timer = time.NewTimer(p.cfg.CallTimeout)
+	defer timer.Stop()
+	select {
+	case r, ok := <-ch:
+		if !ok {
+			return nil, neorpc.NewInternalServerError("plugin disconnected")
+		}
+		if r.Error != nil {
+			return nil, r.Error
+		}
+		return r.Result, nil
+	case <-timer.C:
+		p.lock.Lock()
+		if p.calls != nil {
+			delete(p.calls, id)
+		}
+		p.lock.Unlock()
+		return nil, neorpc.NewInternalServerError("plugin call timeout")
+	}
+}
+
+// onExtensible passes extensible payloads of the registered categories to
+// the plugin.
+func (p *plugin) onExtensible(e *payload.Extensible) error {
+	w := io.NewBufBinWriter()
+	e.EncodeBinary(w.BinWriter)
+	if w.Err != nil {
+		return w.Err
+	}
+	p.send(&HostMessage{Extensible: w.Bytes()})
+	return nil
+}
+
+func decodeExtensible(b []byte) (*payload.Extensible, error) {
+	var (
+		e = payload.NewExtensible()
+		r = io.NewBinReaderFromBuf(b)
+	)
+	e.DecodeBinary(r)
+	if r.Err != nil {
+		return nil, fmt.Errorf("invalid extensible payload: %w", r.Err)
+	}
+	return e, nil
+}
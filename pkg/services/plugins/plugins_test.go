@@ -0,0 +1,266 @@
+package plugins
+
+import (
+	"encoding/json"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/nspcc-dev/neo-go/internal/random"
+	"github.com/nspcc-dev/neo-go/pkg/config"
+	"github.com/nspcc-dev/neo-go/pkg/core"
+	"github.com/nspcc-dev/neo-go/pkg/core/native/nativenames"
+	"github.com/nspcc-dev/neo-go/pkg/core/transaction"
+	"github.com/nspcc-dev/neo-go/pkg/io"
+	"github.com/nspcc-dev/neo-go/pkg/neorpc"
+	"github.com/nspcc-dev/neo-go/pkg/neotest"
+	"github.com/nspcc-dev/neo-go/pkg/neotest/chain"
+	"github.com/nspcc-dev/neo-go/pkg/network"
+	"github.com/nspcc-dev/neo-go/pkg/network/payload"
+	"github.com/nspcc-dev/neo-go/pkg/services/rpcsrv/params"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+	"google.golang.org/grpc"
+)
+
+type fakeNode struct {
+	chain *core.Blockchain
+
+	lock      sync.Mutex
+	handlers  map[string]func(*payload.Extensible) error
+	broadcast []*payload.Extensible
+}
+
+func (n *fakeNode) AddExtensibleService(_ network.Service, category string, handler func(*payload.Extensible) error) {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	n.handlers[category] = handler
+}
+
+func (n *fakeNode) BroadcastExtensible(p *payload.Extensible) {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	n.broadcast = append(n.broadcast, p)
+}
+
+func (n *fakeNode) DelExtensibleService(_ network.Service, category string) {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	delete(n.handlers, category)
+}
+
+func (n *fakeNode) RelayTxn(t *transaction.Transaction) error {
+	return n.chain.PoolTx(t)
+}
+
+func (n *fakeNode) handler(category string) func(*payload.Extensible) error {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	return n.handlers[category]
+}
+
+// testPlugin is an in-process plugin echoing all RPC calls and passing all
+// other node messages to the test.
+type testPlugin struct {
+	srv  *grpc.Server
+	addr string
+	msgs chan *HostMessage
+	conn chan *Conn
+}
+
+func newTestPlugin(t *testing.T, reg Registration) *testPlugin {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	p := &testPlugin{
+		addr: l.Addr().String(),
+		msgs: make(chan *HostMessage, 100),
+		conn: make(chan *Conn, 1),
+	}
+	p.srv = NewServer(func(c *Conn) error {
+		if err := c.Register(reg); err != nil {
+			return err
+		}
+		p.conn <- c
+		for {
+			m, err := c.Receive()
+			if err != nil {
+				return err
+			}
+			if m.Call != nil {
+				var res = &Reply{ID: m.Call.ID, Result: m.Call.Params}
+				if m.Call.Method == "fail" {
+					res = &Reply{ID: m.Call.ID, Error: neorpc.NewInvalidParamsError("bad")}
+				}
+				if err := c.Send(&PluginMessage{Reply: res}); err != nil {
+					return err
+				}
+				continue
+			}
+			p.msgs <- m
+		}
+	})
+	go func() { _ = p.srv.Serve(l) }()
+	t.Cleanup(p.srv.Stop)
+	return p
+}
+
+func (p *testPlugin) receive(t *testing.T) *HostMessage {
+	select {
+	case m := <-p.msgs:
+		return m
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "no message from the node")
+		return nil
+	}
+}
+
+func encodeExtensible(t *testing.T, e *payload.Extensible) []byte {
+	w := io.NewBufBinWriter()
+	e.EncodeBinary(w.BinWriter)
+	require.NoError(t, w.Err)
+	return w.Bytes()
+}
+
+func TestHost(t *testing.T) {
+	bc, acc := chain.NewSingle(t)
+	e := neotest.NewExecutor(t, bc, acc, acc)
+	gas := e.ValidatorInvoker(e.NativeHash(t, nativenames.Gas))
+	node := &fakeNode{chain: bc, handlers: make(map[string]func(*payload.Extensible) error)}
+
+	p := newTestPlugin(t, Registration{
+		Name:       "test",
+		Categories: []string{"custom"},
+		Methods:    []string{"echo", "fail"},
+	})
+	h, err := NewHost([]config.Plugin{
+		{Address: "127.0.0.1:1"}, // Disabled.
+		{Enabled: true, Address: p.addr, RetryInterval: 10 * time.Millisecond},
+	}, bc, node, zaptest.NewLogger(t))
+	require.NoError(t, err)
+	require.Equal(t, 1, len(h.plugins))
+	require.NoError(t, h.Start())
+	defer h.Shutdown()
+
+	var conn *Conn
+	select {
+	case conn = <-p.conn:
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "plugin is not connected")
+	}
+	require.Eventually(t, func() bool { return h.plugins[0].name() == "test" }, 5*time.Second, 10*time.Millisecond)
+
+	t.Run("rpc", func(t *testing.T) {
+		res, rpcErr, ok := h.HandleRPC("echo", params.Params{{RawMessage: json.RawMessage(`"hello"`)}})
+		require.True(t, ok)
+		require.Nil(t, rpcErr)
+		require.JSONEq(t, `["hello"]`, string(res.(json.RawMessage)))
+
+		_, rpcErr, ok = h.HandleRPC("fail", nil)
+		require.True(t, ok)
+		require.Equal(t, int64(neorpc.InvalidParamsCode), rpcErr.Code)
+
+		_, _, ok = h.HandleRPC("getversion", nil)
+		require.False(t, ok)
+	})
+
+	t.Run("ledger", func(t *testing.T) {
+		txH := gas.Invoke(t, true, "transfer", acc.ScriptHash(), random.Uint160(), 1, nil)
+		var gotTx, gotNtf bool
+		for {
+			m := p.receive(t)
+			if m.Block != nil {
+				require.Equal(t, bc.CurrentBlockHash(), m.Block.Hash())
+				break
+			}
+			if m.Transaction != nil {
+				require.Equal(t, txH, m.Transaction.Hash())
+				gotTx = true
+			}
+			if m.Notification != nil && m.Notification.Container == txH {
+				require.Equal(t, "Transfer", m.Notification.Name)
+				gotNtf = true
+			}
+		}
+		require.True(t, gotTx)
+		require.True(t, gotNtf)
+	})
+
+	t.Run("extensible", func(t *testing.T) {
+		ext := &payload.Extensible{
+			Category:      "custom",
+			ValidBlockEnd: 100,
+			Sender:        random.Uint160(),
+			Data:          []byte{1, 2, 3},
+			Witness:       transaction.Witness{InvocationScript: []byte{}, VerificationScript: []byte{}},
+		}
+		handler := node.handler("custom")
+		require.NotNil(t, handler)
+		require.NoError(t, handler(ext))
+		m := p.receive(t)
+		require.Equal(t, encodeExtensible(t, ext), m.Extensible)
+
+		require.NoError(t, conn.Send(&PluginMessage{Request: &Request{ID: 1, Extensible: m.Extensible}}))
+		m = p.receive(t)
+		require.Equal(t, &Result{ID: 1}, m.Result)
+		node.lock.Lock()
+		require.Equal(t, 1, len(node.broadcast))
+		require.Equal(t, ext.Data, node.broadcast[0].Data)
+		node.lock.Unlock()
+	})
+
+	t.Run("transaction", func(t *testing.T) {
+		tx := gas.PrepareInvoke(t, "transfer", acc.ScriptHash(), random.Uint160(), 1, nil)
+		require.NoError(t, conn.Send(&PluginMessage{Request: &Request{ID: 2, Transaction: tx.Bytes()}}))
+		m := p.receive(t)
+		require.Equal(t, &Result{ID: 2}, m.Result)
+		require.True(t, bc.GetMemPool().ContainsKey(tx.Hash()))
+
+		require.NoError(t, conn.Send(&PluginMessage{Request: &Request{ID: 3, Transaction: []byte{1, 2, 3}}}))
+		m = p.receive(t)
+		require.Equal(t, uint64(3), m.Result.ID)
+		require.NotEmpty(t, m.Result.Error)
+
+		require.NoError(t, conn.Send(&PluginMessage{Request: &Request{ID: 4}}))
+		m = p.receive(t)
+		require.Equal(t, uint64(4), m.Result.ID)
+		require.NotEmpty(t, m.Result.Error)
+	})
+
+	t.Run("disconnect", func(t *testing.T) {
+		p.srv.Stop()
+		require.Eventually(t, func() bool {
+			_, _, ok := h.HandleRPC("echo", nil)
+			return !ok && node.handler("custom") == nil
+		}, 5*time.Second, 10*time.Millisecond)
+	})
+}
+
+func TestHost_Register(t *testing.T) {
+	bc, _ := chain.NewSingle(t)
+	node := &fakeNode{chain: bc, handlers: make(map[string]func(*payload.Extensible) error)}
+
+	_, err := NewHost([]config.Plugin{{Enabled: true}}, bc, node, zaptest.NewLogger(t))
+	require.Error(t, err)
+
+	h, err := NewHost([]config.Plugin{
+		{Enabled: true, Address: "127.0.0.1:1"},
+		{Enabled: true, Address: "127.0.0.1:2"},
+	}, bc, node, zaptest.NewLogger(t))
+	require.NoError(t, err)
+	p1, p2 := h.plugins[0], h.plugins[1]
+
+	require.Error(t, h.register(p1, &Registration{Name: "a", Categories: []string{payload.ConsensusCategory}}))
+	require.Error(t, h.register(p1, &Registration{Name: "a", Categories: []string{"StateService"}}))
+	require.NoError(t, h.register(p1, &Registration{Name: "a", Categories: []string{"custom"}, Methods: []string{"m"}}))
+	p1.reg = &Registration{Name: "a"}
+	require.NotNil(t, node.handler("custom"))
+
+	require.Error(t, h.register(p2, &Registration{Name: "a"}))
+	require.Error(t, h.register(p2, &Registration{Name: "b", Methods: []string{"m"}}))
+	require.Error(t, h.register(p2, &Registration{Name: "b", Categories: []string{"custom"}}))
+
+	h.unregister(p1)
+	require.Nil(t, node.handler("custom"))
+	require.NoError(t, h.register(p2, &Registration{Name: "b", Categories: []string{"custom"}, Methods: []string{"m"}}))
+}
@@ -0,0 +1,140 @@
+/*
+Package plugins implements out-of-process service plugins connected to the
+node via gRPC.
+
+Every plugin is a gRPC server implementing a single bidirectional streaming
+method (see MethodName) and the node (Host) is its client. Messages are
+JSON-encoded HostMessage (node to plugin) and PluginMessage (plugin to node)
+structures. The first message sent by the plugin must be a Registration
+containing the plugin name, the extensible payload categories it handles and
+the RPC methods it implements. After that the node streams new blocks,
+transactions from these blocks, notifications, extensible payloads of the
+registered categories and RPC calls of the registered methods to the plugin,
+while the plugin can reply to calls, submit transactions and broadcast
+extensible payloads.
+
+Go plugins can use NewServer to create a gRPC server with proper settings.
+*/
+package plugins
+
+import (
+	"encoding/json"
+
+	"github.com/nspcc-dev/neo-go/pkg/core/block"
+	"github.com/nspcc-dev/neo-go/pkg/core/state"
+	"github.com/nspcc-dev/neo-go/pkg/core/transaction"
+	"github.com/nspcc-dev/neo-go/pkg/neorpc"
+	"google.golang.org/grpc"
+)
+
+const (
+	// ServiceName is the gRPC service name plugins implement.
+	ServiceName = "neogo.plugins.Plugin"
+	// MethodName is the full name of the bidirectional streaming gRPC method
+	// plugins implement.
+	MethodName = "/" + ServiceName + "/Connect"
+)
+
+type (
+	// HostMessage is a message sent by the node to the plugin, exactly one
+	// field is set in every message.
+	HostMessage struct {
+		// Block is a new block added to the chain, it's sent after all
+		// transactions and notifications of this block.
+		Block *block.Block `json:"block,omitempty"`
+		// Transaction is a transaction from the new block.
+		Transaction *transaction.Transaction `json:"transaction,omitempty"`
+		// Notification is a notification emitted by some successful
+		// execution in the new block.
+		Notification *state.ContainedNotificationEvent `json:"notification,omitempty"`
+		// Extensible is a serialized extensible payload of one of the
+		// registered categories received from the network.
+		Extensible []byte `json:"extensible,omitempty"`
+		// Call is an RPC method call the plugin must reply to.
+		Call *Call `json:"call,omitempty"`
+		// Result is the result of the plugin request.
+		Result *Result `json:"result,omitempty"`
+	}
+
+	// PluginMessage is a message sent by the plugin to the node, exactly one
+	// field is set in every message.
+	PluginMessage struct {
+		// Register must be set in the first plugin message (and only there).
+		Register *Registration `json:"register,omitempty"`
+		// Reply is the reply to the RPC method call.
+		Reply *Reply `json:"reply,omitempty"`
+		// Request is the plugin request to the node.
+		Request *Request `json:"request,omitempty"`
+	}
+
+	// Registration describes the plugin.
+	Registration struct {
+		// Name is the unique plugin name.
+		Name string `json:"name"`
+		// Categories is a list of extensible payload categories handled
+		// by the plugin.
+		Categories []string `json:"categories,omitempty"`
+		// Methods is a list of RPC methods handled by the plugin, they
+		// can't override methods implemented by the node.
+		Methods []string `json:"methods,omitempty"`
+	}
+
+	// Call is an RPC method call.
+	Call struct {
+		ID     uint64          `json:"id"`
+		Method string          `json:"method"`
+		Params json.RawMessage `json:"params"`
+	}
+
+	// Reply is the reply to the Call with the same ID.
+	Reply struct {
+		ID     uint64          `json:"id"`
+		Result json.RawMessage `json:"result,omitempty"`
+		Error  *neorpc.Error   `json:"error,omitempty"`
+	}
+
+	// Request is the plugin request to the node, exactly one of Transaction
+	// and Extensible must be set.
+	Request struct {
+		// ID is an arbitrary number that is returned in the Result.
+		ID uint64 `json:"id"`
+		// Transaction is a serialized transaction to be added to the
+		// memory pool and relayed.
+		Transaction []byte `json:"transaction,omitempty"`
+		// Extensible is a serialized signed extensible payload to be
+		// broadcasted.
+		Extensible []byte `json:"extensible,omitempty"`
+	}
+
+	// Result is the result of the Request with the same ID.
+	Result struct {
+		ID uint64 `json:"id"`
+		// Error is empty for successful requests.
+		Error string `json:"error,omitempty"`
+	}
+)
+
+// codec is the gRPC codec used for plugin messages.
+type codec struct{}
+
+// Marshal implements the encoding.Codec interface.
+func (codec) Marshal(v any) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+// Unmarshal implements the encoding.Codec interface.
+func (codec) Unmarshal(data []byte, v any) error {
+	return json.Unmarshal(data, v)
+}
+
+// Name implements the encoding.Codec interface.
+func (codec) Name() string {
+	return "json"
+}
+
+// streamDesc describes the plugin gRPC method.
+var streamDesc = grpc.StreamDesc{
+	StreamName:    "Connect",
+	ServerStreams: true,
+	ClientStreams: true,
+}
@@ -0,0 +1,61 @@
+package plugins
+
+import (
+	"context"
+	"sync"
+
+	"google.golang.org/grpc"
+)
+
+// Conn is the plugin side of the connection to the node.
+type Conn struct {
+	stream grpc.ServerStream
+	lock   sync.Mutex
+}
+
+// Handler handles a single node connection on the plugin side, the connection
+// is closed when it returns.
+type Handler func(c *Conn) error
+
+// NewServer creates a gRPC server handling node connections with the given
+// handler. The server is not started, use its Serve method to do that.
+func NewServer(h Handler, opts ...grpc.ServerOption) *grpc.Server {
+	s := grpc.NewServer(append(opts, grpc.ForceServerCodec(codec{}))...)
+	desc := streamDesc
+	desc.Handler = func(_ any, stream grpc.ServerStream) error {
+		return h(&Conn{stream: stream})
+	}
+	s.RegisterService(&grpc.ServiceDesc{
+		ServiceName: ServiceName,
+		Streams:     []grpc.StreamDesc{desc},
+	}, nil)
+	return s
+}
+
+// Register sends the registration message, it must be the first message sent.
+func (c *Conn) Register(r Registration) error {
+	return c.Send(&PluginMessage{Register: &r})
+}
+
+// Send sends the message to the node, it can be called concurrently.
+func (c *Conn) Send(m *PluginMessage) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.stream.SendMsg(m)
+}
+
+// Receive returns the next message from the node, it must not be called
+// concurrently.
+func (c *Conn) Receive() (*HostMessage, error) {
+	var m = new(HostMessage)
+	if err := c.stream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// Context returns the connection context that is canceled when the node
+// disconnects.
+func (c *Conn) Context() context.Context {
+	return c.stream.Context()
+}
@@ -0,0 +1,81 @@
+/*
+Package replication implements DB change sets streaming from the primary node
+to the read-only RPC replicas.
+
+The primary node wraps its Store into the replication Store that keeps the
+latest change sets persisted into the DB in memory and Service streams them to
+replicas via HTTP. Replica starts with a copy of the primary node DB and
+applies all change sets made after the copy to it, so it follows the primary
+node without P2P, consensus and block processing.
+*/
+package replication
+
+import (
+	"github.com/nspcc-dev/neo-go/pkg/io"
+)
+
+// ChangeSet is a set of DB changes made by a single persist operation along
+// with the chain position it leads to.
+type ChangeSet struct {
+	// BlockHeight is the persisted block height after the change.
+	BlockHeight uint32
+	// HeaderHeight is the persisted header height after the change.
+	HeaderHeight uint32
+	// Changes maps changed keys to their new values, nil value denotes
+	// deleted key.
+	Changes map[string][]byte
+	// StorageChanges is the same as Changes, but for contract storage items
+	// (see storage.Store.PutChangeSet).
+	StorageChanges map[string][]byte
+}
+
+// position is a chain position after some change.
+type position struct {
+	blockHeight  uint32
+	headerHeight uint32
+}
+
+func (c *ChangeSet) position() position {
+	return position{blockHeight: c.BlockHeight, headerHeight: c.HeaderHeight}
+}
+
+// EncodeBinary implements the io.Serializable interface.
+func (c *ChangeSet) EncodeBinary(w *io.BinWriter) {
+	w.WriteU32LE(c.BlockHeight)
+	w.WriteU32LE(c.HeaderHeight)
+	encodeChanges(w, c.Changes)
+	encodeChanges(w, c.StorageChanges)
+}
+
+func encodeChanges(w *io.BinWriter, m map[string][]byte) {
+	w.WriteVarUint(uint64(len(m)))
+	for k, v := range m {
+		w.WriteVarBytes([]byte(k))
+		w.WriteBool(v != nil)
+		if v != nil {
+			w.WriteVarBytes(v)
+		}
+	}
+}
+
+// DecodeBinary implements the io.Serializable interface.
+func (c *ChangeSet) DecodeBinary(r *io.BinReader) {
+	c.BlockHeight = r.ReadU32LE()
+	c.HeaderHeight = r.ReadU32LE()
+	c.Changes = decodeChanges(r)
+	c.StorageChanges = decodeChanges(r)
+}
+
+func decodeChanges(r *io.BinReader) map[string][]byte {
+	n := r.ReadVarUint()
+	m := make(map[string][]byte)
+	for i := uint64(0); i < n && r.Err == nil; i++ {
+		k := r.ReadVarBytes()
+		var v []byte
+		if r.ReadBool() {
+			v = r.ReadVarBytes()
+		}
+		m[string(k)] = v
+	}
+	return m
+}
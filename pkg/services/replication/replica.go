@@ -0,0 +1,129 @@
+package replication
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nspcc-dev/neo-go/pkg/config"
+	"github.com/nspcc-dev/neo-go/pkg/io"
+	"go.uber.org/zap"
+)
+
+// defaultRetryInterval is the default interval between reconnection attempts.
+const defaultRetryInterval = 5 * time.Second
+
+// Ledger is the interface to the replica chain.
+type Ledger interface {
+	BlockHeight() uint32
+	HeaderHeight() uint32
+	ApplyChangeSet(changes map[string][]byte, storageChanges map[string][]byte) error
+}
+
+// Replica follows the primary node applying change sets streamed by its
+// Service to the local chain.
+type Replica struct {
+	cfg   config.Replica
+	chain Ledger
+	log   *zap.Logger
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewReplica creates a new Replica for the given chain.
+func NewReplica(cfg config.Replica, chain Ledger, log *zap.Logger) (*Replica, error) {
+	if cfg.Primary == "" {
+		return nil, errors.New("primary node address is not set")
+	}
+	if cfg.RetryInterval <= 0 {
+		cfg.RetryInterval = defaultRetryInterval
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Replica{
+		cfg:    cfg,
+		chain:  chain,
+		log:    log.With(zap.String("service", "Replica")),
+		ctx:    ctx,
+		cancel: cancel,
+		done:   make(chan struct{}),
+	}, nil
+}
+
+// Start runs the replica following the primary node in a separate goroutine.
+func (r *Replica) Start() {
+	r.log.Info("starting replica", zap.String("primary", r.cfg.Primary))
+	go r.run()
+}
+
+// Shutdown stops the replica, it can't be started again.
+func (r *Replica) Shutdown() {
+	r.cancel()
+	<-r.done
+}
+
+func (r *Replica) run() {
+	defer close(r.done)
+	for {
+		err := r.follow()
+		if r.ctx.Err() != nil {
+			return
+		}
+		r.log.Warn("failed to follow primary node", zap.Error(err),
+			zap.Duration("retry in", r.cfg.RetryInterval))
+		select {
+		case <-time.After(r.cfg.RetryInterval):
+		case <-r.ctx.Done():
+			return
+		}
+	}
+}
+
+// follow connects to the primary node and applies change sets received from
+// it until an error occurs.
+func (r *Replica) follow() error {
+	var (
+		q = url.Values{
+			"height":       []string{strconv.FormatUint(uint64(r.chain.BlockHeight()), 10)},
+			"headerHeight": []string{strconv.FormatUint(uint64(r.chain.HeaderHeight()), 10)},
+		}
+		u = "http://" + r.cfg.Primary + changesPath + "?" + q.Encode()
+	)
+	req, err := http.NewRequestWithContext(r.ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		var msg = make([]byte, 1024)
+		n, _ := resp.Body.Read(msg)
+		return fmt.Errorf("primary node responded with %s: %s", resp.Status, strings.TrimSpace(string(msg[:n])))
+	}
+	r.log.Info("following primary node", zap.Uint32("height", r.chain.BlockHeight()))
+	br := io.NewBinReaderFromIO(bufio.NewReader(resp.Body))
+	for {
+		var cs ChangeSet
+		cs.DecodeBinary(br)
+		if br.Err != nil {
+			return fmt.Errorf("failed to read change set: %w", br.Err)
+		}
+		err = r.chain.ApplyChangeSet(cs.Changes, cs.StorageChanges)
+		if err != nil {
+			return fmt.Errorf("failed to apply change set: %w", err)
+		}
+		if h := r.chain.BlockHeight(); h != cs.BlockHeight {
+			return fmt.Errorf("replica height %d doesn't match primary height %d", h, cs.BlockHeight)
+		}
+	}
+}
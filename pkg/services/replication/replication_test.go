@@ -0,0 +1,182 @@
+package replication
+
+import (
+	"encoding/binary"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nspcc-dev/neo-go/internal/random"
+	"github.com/nspcc-dev/neo-go/internal/testserdes"
+	"github.com/nspcc-dev/neo-go/pkg/config"
+	"github.com/nspcc-dev/neo-go/pkg/core"
+	"github.com/nspcc-dev/neo-go/pkg/core/block"
+	"github.com/nspcc-dev/neo-go/pkg/core/native/nativenames"
+	"github.com/nspcc-dev/neo-go/pkg/core/storage"
+	"github.com/nspcc-dev/neo-go/pkg/neotest"
+	"github.com/nspcc-dev/neo-go/pkg/neotest/chain"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract/trigger"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestChangeSet_EncodeDecodeBinary(t *testing.T) {
+	testserdes.EncodeDecodeBinary(t, &ChangeSet{
+		BlockHeight:  1,
+		HeaderHeight: 2,
+		Changes: map[string][]byte{
+			"key":     []byte("value"),
+			"empty":   {},
+			"deleted": nil,
+		},
+		StorageChanges: map[string][]byte{
+			"storage": {1, 2, 3},
+		},
+	}, new(ChangeSet))
+}
+
+func TestReplica(t *testing.T) {
+	primaryStore := NewStore(storage.NewMemoryStore(), 0)
+	bc, acc := chain.NewSingleWithCustomConfigAndStore(t, nil, primaryStore, true)
+	e := neotest.NewExecutor(t, bc, acc, acc)
+	gas := e.ValidatorInvoker(e.NativeHash(t, nativenames.Gas))
+
+	to := random.Uint160()
+	gas.Invoke(t, true, "transfer", acc.ScriptHash(), to, 1_0000_0000, nil)
+	waitPersisted(t, primaryStore, bc)
+
+	// Copy the primary DB.
+	replicaStore := storage.NewMemoryStore()
+	primaryStore.lock.RLock()
+	var data, stor = make(map[string][]byte), make(map[string][]byte)
+	for i := 0; i < 256; i++ {
+		var m = data
+		if storage.KeyPrefix(i) == storage.STStorage {
+			m = stor
+		}
+		primaryStore.Store.Seek(storage.SeekRange{Prefix: []byte{byte(i)}}, func(k, v []byte) bool {
+			m[string(k)] = v
+			return true
+		})
+	}
+	primaryStore.lock.RUnlock()
+	require.NoError(t, replicaStore.PutChangeSet(data, stor))
+
+	// More changes are made before the replica connects.
+	gas.Invoke(t, true, "transfer", acc.ScriptHash(), to, 1_0000_0000, nil)
+
+	rbc, _ := chain.NewSingleWithCustomConfigAndStore(t, func(c *config.Blockchain) {
+		c.Replica = true
+	}, replicaStore, true)
+	require.Equal(t, uint32(1), rbc.BlockHeight())
+	require.ErrorIs(t, rbc.AddBlock(e.NewUnsignedBlock(t)), core.ErrReadOnly)
+	require.ErrorIs(t, rbc.PoolTx(gas.PrepareInvoke(t, "transfer", acc.ScriptHash(), to, 1, nil)), core.ErrReadOnly)
+
+	blocks := make(chan *block.Block, 10)
+	rbc.SubscribeForBlocks(blocks)
+
+	srv := httptest.NewServer(http.HandlerFunc(NewService(config.Replication{}, primaryStore, zaptest.NewLogger(t)).handleChanges))
+	defer srv.Close()
+
+	t.Run("unavailable position", func(t *testing.T) {
+		resp, err := http.Get(srv.URL + changesPath + "?height=100&headerHeight=100")
+		require.NoError(t, err)
+		require.NoError(t, resp.Body.Close())
+		require.Equal(t, http.StatusGone, resp.StatusCode)
+
+		resp, err = http.Get(srv.URL + changesPath + "?height=1")
+		require.NoError(t, err)
+		require.NoError(t, resp.Body.Close())
+		require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	r, err := NewReplica(config.Replica{
+		Enabled:       true,
+		Primary:       strings.TrimPrefix(srv.URL, "http://"),
+		RetryInterval: 10 * time.Millisecond,
+	}, rbc, zaptest.NewLogger(t))
+	require.NoError(t, err)
+	r.Start()
+	defer r.Shutdown()
+
+	txH := gas.Invoke(t, true, "transfer", acc.ScriptHash(), to, 1_0000_0000, nil)
+	waitPersisted(t, primaryStore, bc)
+	require.Eventually(t, func() bool { return rbc.BlockHeight() == bc.BlockHeight() }, 5*time.Second, 10*time.Millisecond)
+
+	for h := uint32(2); h <= bc.BlockHeight(); h++ {
+		b := <-blocks
+		require.Equal(t, bc.GetHeaderHash(h), b.Hash())
+	}
+	require.Equal(t, bc.HeaderHeight(), rbc.HeaderHeight())
+	require.Equal(t, bc.CurrentBlockHash(), rbc.CurrentBlockHash())
+	require.Equal(t, bc.GetStateModule().CurrentLocalStateRoot(), rbc.GetStateModule().CurrentLocalStateRoot())
+	require.Equal(t, int64(3_0000_0000), rbc.GetUtilityTokenBalance(to).Int64())
+	aers, err := rbc.GetAppExecResults(txH, trigger.Application)
+	require.NoError(t, err)
+	expected, err := bc.GetAppExecResults(txH, trigger.Application)
+	require.NoError(t, err)
+	require.Equal(t, expected, aers)
+	// Native cache is updated too.
+	require.Equal(t, bc.FeePerByte(), rbc.FeePerByte())
+}
+
+func waitPersisted(t *testing.T, s *Store, bc *core.Blockchain) {
+	require.Eventually(t, func() bool {
+		s.lock.RLock()
+		defer s.lock.RUnlock()
+		return s.currentPosition() == position{blockHeight: bc.BlockHeight(), headerHeight: bc.HeaderHeight()}
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestStore_Backlog(t *testing.T) {
+	s := NewStore(storage.NewMemoryStore(), 2)
+	_, err := s.start(position{})
+	require.NoError(t, err)
+
+	for i := 0; i < 3; i++ {
+		require.NoError(t, s.PutChangeSet(map[string][]byte{string(util.Uint160{byte(i)}.BytesBE()): {1}}, nil))
+	}
+	data, _, err := s.get(1)
+	require.NoError(t, err)
+	require.Equal(t, 2, len(data))
+	_, _, err = s.get(0)
+	require.Error(t, err)
+
+	require.NoError(t, s.SeekGC(storage.SeekRange{Prefix: util.Uint160{}.BytesBE()[:1]}, func(k, _ []byte) bool {
+		return k[len(k)-1] != 0
+	}))
+	data, _, err = s.get(3)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(data))
+	var cs ChangeSet
+	require.NoError(t, testserdes.DecodeBinary(data[0], &cs))
+	require.Equal(t, map[string][]byte{string(util.Uint160{0}.BytesBE()): nil}, cs.Changes)
+	require.Equal(t, 0, len(cs.StorageChanges))
+}
+
+func TestStore_Start(t *testing.T) {
+	s := NewStore(storage.NewMemoryStore(), 2)
+	putHeight := func(h uint32) {
+		v := make([]byte, 36)
+		binary.LittleEndian.PutUint32(v[32:], h)
+		require.NoError(t, s.PutChangeSet(map[string][]byte{
+			string([]byte{byte(storage.SYSCurrentBlock)}):  v,
+			string([]byte{byte(storage.SYSCurrentHeader)}): v,
+		}, nil))
+	}
+	for h := uint32(1); h <= 3; h++ {
+		putHeight(h)
+	}
+	// The first change set is dropped, so the replica at its position gets
+	// the remaining ones.
+	for h, seq := range map[uint32]uint64{1: 1, 2: 2, 3: 3} {
+		actual, err := s.start(position{blockHeight: h, headerHeight: h})
+		require.NoError(t, err)
+		require.Equal(t, seq, actual, h)
+	}
+	_, err := s.start(position{})
+	require.Error(t, err)
+}
@@ -0,0 +1,108 @@
+package replication
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+
+	"github.com/nspcc-dev/neo-go/pkg/config"
+	"github.com/nspcc-dev/neo-go/pkg/services/metrics"
+	"go.uber.org/zap"
+)
+
+// changesPath is the HTTP path change sets are served at.
+const changesPath = "/changes"
+
+// Service streams change sets kept by Store to replicas via HTTP.
+type Service struct {
+	store    *Store
+	log      *zap.Logger
+	http     *metrics.Service
+	quit     chan struct{}
+	quitOnce sync.Once
+}
+
+// NewService creates a new Service serving change sets of the given Store.
+func NewService(cfg config.Replication, st *Store, log *zap.Logger) *Service {
+	s := &Service{
+		store: st,
+		log:   log.With(zap.String("service", "Replication")),
+		quit:  make(chan struct{}),
+	}
+	handler := http.NewServeMux()
+	handler.HandleFunc(changesPath, s.handleChanges)
+
+	srvs := make([]*http.Server, len(cfg.Addresses))
+	for i, addr := range cfg.Addresses {
+		srvs[i] = &http.Server{
+			Addr:    addr,
+			Handler: handler,
+		}
+	}
+	s.http = metrics.NewService("Replication", srvs, cfg.BasicService, log)
+	return s
+}
+
+// Start runs the service.
+func (s *Service) Start() error {
+	return s.http.Start()
+}
+
+// ShutDown stops the service dropping all replica connections.
+func (s *Service) ShutDown() {
+	s.quitOnce.Do(func() { close(s.quit) })
+	s.http.ShutDown()
+}
+
+// handleChanges streams change sets starting from the position given in
+// the request.
+func (s *Service) handleChanges(w http.ResponseWriter, r *http.Request) {
+	var params = []string{"height", "headerHeight"}
+	var values = make([]uint32, len(params))
+	for i, param := range params {
+		v, err := strconv.ParseUint(r.URL.Query().Get(param), 10, 32)
+		if err != nil {
+			http.Error(w, "invalid "+param, http.StatusBadRequest)
+			return
+		}
+		values[i] = uint32(v)
+	}
+	seq, err := s.store.start(position{blockHeight: values[0], headerHeight: values[1]})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusGone)
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming is not supported", http.StatusInternalServerError)
+		return
+	}
+	s.log.Info("replica connected", zap.String("addr", r.RemoteAddr),
+		zap.Uint32("height", values[0]), zap.Uint32("headerHeight", values[1]))
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+	for {
+		data, updated, err := s.store.get(seq)
+		if err != nil {
+			s.log.Warn("replica is too slow", zap.String("addr", r.RemoteAddr), zap.Error(err))
+			return
+		}
+		for _, d := range data {
+			if _, err = w.Write(d); err != nil {
+				s.log.Info("replica disconnected", zap.String("addr", r.RemoteAddr), zap.Error(err))
+				return
+			}
+		}
+		seq += uint64(len(data))
+		flusher.Flush()
+		select {
+		case <-updated:
+		case <-s.quit:
+			return
+		case <-r.Context().Done():
+			s.log.Info("replica disconnected", zap.String("addr", r.RemoteAddr))
+			return
+		}
+	}
+}
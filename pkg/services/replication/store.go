@@ -0,0 +1,162 @@
+package replication
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/nspcc-dev/neo-go/pkg/core/dao"
+	"github.com/nspcc-dev/neo-go/pkg/core/storage"
+	"github.com/nspcc-dev/neo-go/pkg/io"
+)
+
+// DefaultBacklogSize is the default number of the latest change sets kept by
+// Store.
+const DefaultBacklogSize = 64
+
+// Store wraps the primary node Store and keeps the latest change sets
+// persisted into it (including the ones made by garbage collection) for
+// replicas.
+type Store struct {
+	storage.Store
+
+	dao *dao.Simple
+
+	lock sync.RWMutex
+	// base is the position before the first backlog change set.
+	base    position
+	backlog []change
+	// first is the sequence number of the first backlog change set.
+	first       uint64
+	backlogSize int
+	// updated is closed (and replaced) when a new change set is added.
+	updated chan struct{}
+}
+
+// change is a serialized ChangeSet with its position.
+type change struct {
+	pos  position
+	data []byte
+}
+
+// NewStore wraps the given Store keeping up to backlogSize change sets (or
+// DefaultBacklogSize if it's not positive).
+func NewStore(ps storage.Store, backlogSize int) *Store {
+	if backlogSize <= 0 {
+		backlogSize = DefaultBacklogSize
+	}
+	s := &Store{
+		Store:       ps,
+		dao:         dao.NewSimple(ps, false),
+		backlogSize: backlogSize,
+		updated:     make(chan struct{}),
+	}
+	s.base = s.currentPosition()
+	return s
+}
+
+// currentPosition returns the chain position of the underlying Store, an
+// empty one is returned for the empty Store.
+func (s *Store) currentPosition() position {
+	var p position
+	p.blockHeight, _ = s.dao.GetCurrentBlockHeight()
+	p.headerHeight, _, _ = s.dao.GetCurrentHeaderHeight()
+	return p
+}
+
+// PutChangeSet implements the Store interface.
+func (s *Store) PutChangeSet(puts map[string][]byte, stor map[string][]byte) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	err := s.Store.PutChangeSet(puts, stor)
+	if err != nil {
+		return err
+	}
+	s.add(puts, stor)
+	return nil
+}
+
+// SeekGC implements the Store interface.
+func (s *Store) SeekGC(rng storage.SeekRange, keep func(k, v []byte) bool) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	var (
+		deleted    = make(map[string][]byte)
+		delStorage = make(map[string][]byte)
+	)
+	err := s.Store.SeekGC(rng, func(k, v []byte) bool {
+		if keep(k, v) {
+			return true
+		}
+		switch storage.KeyPrefix(k[0]) {
+		case storage.STStorage, storage.STTempStorage:
+			delStorage[string(k)] = nil
+		default:
+			deleted[string(k)] = nil
+		}
+		return false
+	})
+	if len(deleted) != 0 || len(delStorage) != 0 {
+		s.add(deleted, delStorage)
+	}
+	return err
+}
+
+// add adds a new change set to the backlog, it must be called under the lock.
+func (s *Store) add(changes map[string][]byte, storageChanges map[string][]byte) {
+	var (
+		pos = s.currentPosition()
+		cs  = ChangeSet{
+			BlockHeight:    pos.blockHeight,
+			HeaderHeight:   pos.headerHeight,
+			Changes:        changes,
+			StorageChanges: storageChanges,
+		}
+		w = io.NewBufBinWriter()
+	)
+	cs.EncodeBinary(w.BinWriter)
+	s.backlog = append(s.backlog, change{pos: pos, data: w.Bytes()})
+	if len(s.backlog) > s.backlogSize {
+		s.base = s.backlog[0].pos
+		s.backlog[0] = change{}
+		s.backlog = s.backlog[1:]
+		s.first++
+	}
+	close(s.updated)
+	s.updated = make(chan struct{})
+}
+
+// start returns the sequence number of the first change set to be applied to
+// the replica at the given position.
+func (s *Store) start(p position) (uint64, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	if p == s.base {
+		return s.first, nil
+	}
+	for i := range s.backlog {
+		if s.backlog[i].pos == p {
+			// The replica is at the position after this change set.
+			return s.first + uint64(i+1), nil
+		}
+	}
+	return 0, fmt.Errorf("changes for block %d (header %d) are not available", p.blockHeight, p.headerHeight)
+}
+
+// get returns serialized change sets starting from the given sequence number
+// and the channel that is closed when new change sets are added.
+func (s *Store) get(seq uint64) ([][]byte, <-chan struct{}, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	if seq < s.first {
+		return nil, nil, fmt.Errorf("change set %d is already dropped", seq)
+	}
+	var res = make([][]byte, 0, len(s.backlog))
+	for i := seq - s.first; i < uint64(len(s.backlog)); i++ {
+		res = append(res, s.backlog[i].data)
+	}
+	return res, s.updated, nil
+}
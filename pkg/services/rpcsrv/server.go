@@ -13,6 +13,7 @@ import (
 	"math/big"
 	"net"
 	"net/http"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
@@ -73,7 +74,9 @@ type (
 		GetAppExecResults(util.Uint256, trigger.Type) ([]state.AppExecResult, error)
 		GetBaseExecFee() int64
 		GetBlock(hash util.Uint256) (*block.Block, error)
+		GetCandidatesHistoric(height uint32) ([]*keys.PublicKey, []state.Validator, error)
 		GetCommittee() (keys.PublicKeys, error)
+		GetCommitteeHistoric(height uint32) (keys.PublicKeys, error)
 		GetConfig() config.Blockchain
 		GetContractScriptHash(id int32) (util.Uint160, error)
 		GetContractState(hash util.Uint160) *state.Contract
@@ -89,9 +92,12 @@ type (
 		GetNatives() []state.Contract
 		GetNextBlockValidators() ([]*keys.PublicKey, error)
 		GetNotaryContractScriptHash() util.Uint160
+		GetStateDiff(index uint32) (*state.StateDiff, error)
+		ApplyStateOverrides(ic *interop.Context, o *state.Overrides) error
 		GetStateModule() core.StateRoot
 		GetStorageItem(id int32, key []byte) state.StorageItem
 		GetTestHistoricVM(t trigger.Type, tx *transaction.Transaction, nextBlockHeight uint32) (*interop.Context, error)
+		GetTestHistoricTxVM(h util.Uint256) (*interop.Context, error)
 		GetTestVM(t trigger.Type, tx *transaction.Transaction, b *block.Block) (*interop.Context, error)
 		GetTokenLastUpdated(acc util.Uint160) (map[int32]uint32, error)
 		GetTransaction(util.Uint256) (*transaction.Transaction, uint32, error)
@@ -100,11 +106,13 @@ type (
 		P2PSigExtensionsEnabled() bool
 		SubscribeForBlocks(ch chan *block.Block)
 		SubscribeForHeadersOfAddedBlocks(ch chan *block.Header)
+		SubscribeForMempoolEvents(ch chan mempoolevent.Event)
 		SubscribeForExecutions(ch chan *state.AppExecResult)
 		SubscribeForNotifications(ch chan *state.ContainedNotificationEvent)
 		SubscribeForTransactions(ch chan *transaction.Transaction)
 		UnsubscribeFromBlocks(ch chan *block.Block)
 		UnsubscribeFromHeadersOfAddedBlocks(ch chan *block.Header)
+		UnsubscribeFromMempoolEvents(ch chan mempoolevent.Event)
 		UnsubscribeFromExecutions(ch chan *state.AppExecResult)
 		UnsubscribeFromNotifications(ch chan *state.ContainedNotificationEvent)
 		UnsubscribeFromTransactions(ch chan *transaction.Transaction)
@@ -125,6 +133,32 @@ type (
 		AddResponse(pub *keys.PublicKey, reqID uint64, txSig []byte)
 	}
 
+	// ConsensusHandler is the consensus service used by the Server. It can
+	// implement ConsensusStateProvider and BlockGenerator interfaces to enable
+	// the corresponding RPC methods.
+	ConsensusHandler any
+
+	// ConsensusStateProvider is the interface consensus service provides to
+	// expose its state, it's used by getconsensusstate.
+	ConsensusStateProvider interface {
+		GetState() (*result.ConsensusState, error)
+	}
+
+	// ExtensionHandler is the interface for external RPC method providers
+	// (like plugins). Methods implemented by the Server itself can't be
+	// overridden.
+	ExtensionHandler interface {
+		// HandleRPC handles the given method call, it returns false if the
+		// method is not known to the handler.
+		HandleRPC(method string, reqParams params.Params) (any, *neorpc.Error, bool)
+	}
+
+	// BlockGenerator is the interface consensus service running in instant
+	// mining mode provides, it's used by generateblocks.
+	BlockGenerator interface {
+		GenerateBlocks(n int) ([]util.Uint256, error)
+	}
+
 	// Server represents the JSON-RPC 2.0 server.
 	Server struct {
 		http  []*http.Server
@@ -139,6 +173,8 @@ type (
 		stateRootEnabled bool
 		coreServer       *network.Server
 		oracle           *atomic.Value
+		consensus        *atomic.Pointer[ConsensusHandler]
+		extension        *atomic.Pointer[ExtensionHandler]
 		log              *zap.Logger
 		shutdown         chan struct{}
 		started          atomic.Bool
@@ -157,6 +193,7 @@ type (
 		notificationSubs  int
 		transactionSubs   int
 		notaryRequestSubs int
+		mempoolSubs       int
 
 		blockCh           chan *block.Block
 		blockHeaderCh     chan *block.Header
@@ -164,6 +201,7 @@ type (
 		notificationCh    chan *state.ContainedNotificationEvent
 		transactionCh     chan *transaction.Transaction
 		notaryRequestCh   chan mempoolevent.Event
+		mempoolCh         chan mempoolevent.Event
 		subEventsToExitCh chan struct{}
 	}
 
@@ -205,60 +243,83 @@ const (
 
 	// defaultSessionPoolSize is the number of concurrently running iterator sessions.
 	defaultSessionPoolSize = 20
+
+	// defaultPriorityFeeBlocks is the default number of blocks analyzed by
+	// estimatepriorityfee.
+	defaultPriorityFeeBlocks = 20
+	// maxPriorityFeeBlocks is the maximum number of blocks analyzed by
+	// estimatepriorityfee.
+	maxPriorityFeeBlocks = 1000
+	// maxGeneratedBlocks is the maximum number of blocks that can be created
+	// with a single generateblocks call.
+	maxGeneratedBlocks = 1000
+	// maxInvokedScripts is the maximum number of scripts that can be executed
+	// with a single invokescripts call.
+	maxInvokedScripts = 32
 )
 
 var rpcHandlers = map[string]func(*Server, params.Params) (any, *neorpc.Error){
-	"calculatenetworkfee":          (*Server).calculateNetworkFee,
-	"findstates":                   (*Server).findStates,
-	"findstorage":                  (*Server).findStorage,
-	"findstoragehistoric":          (*Server).findStorageHistoric,
-	"getapplicationlog":            (*Server).getApplicationLog,
-	"getbestblockhash":             (*Server).getBestBlockHash,
-	"getblock":                     (*Server).getBlock,
-	"getblockcount":                (*Server).getBlockCount,
-	"getblockhash":                 (*Server).getBlockHash,
-	"getblockheader":               (*Server).getBlockHeader,
-	"getblockheadercount":          (*Server).getBlockHeaderCount,
-	"getblocksysfee":               (*Server).getBlockSysFee,
-	"getcandidates":                (*Server).getCandidates,
-	"getcommittee":                 (*Server).getCommittee,
-	"getconnectioncount":           (*Server).getConnectionCount,
-	"getcontractstate":             (*Server).getContractState,
-	"getnativecontracts":           (*Server).getNativeContracts,
-	"getnep11balances":             (*Server).getNEP11Balances,
-	"getnep11properties":           (*Server).getNEP11Properties,
-	"getnep11transfers":            (*Server).getNEP11Transfers,
-	"getnep17balances":             (*Server).getNEP17Balances,
-	"getnep17transfers":            (*Server).getNEP17Transfers,
-	"getpeers":                     (*Server).getPeers,
-	"getproof":                     (*Server).getProof,
-	"getrawmempool":                (*Server).getRawMempool,
-	"getrawnotarypool":             (*Server).getRawNotaryPool,
-	"getrawnotarytransaction":      (*Server).getRawNotaryTransaction,
-	"getrawtransaction":            (*Server).getrawtransaction,
-	"getstate":                     (*Server).getState,
-	"getstateheight":               (*Server).getStateHeight,
-	"getstateroot":                 (*Server).getStateRoot,
-	"getstorage":                   (*Server).getStorage,
-	"getstoragehistoric":           (*Server).getStorageHistoric,
-	"gettransactionheight":         (*Server).getTransactionHeight,
-	"getunclaimedgas":              (*Server).getUnclaimedGas,
-	"getnextblockvalidators":       (*Server).getNextBlockValidators,
-	"getversion":                   (*Server).getVersion,
-	"invokefunction":               (*Server).invokeFunction,
-	"invokefunctionhistoric":       (*Server).invokeFunctionHistoric,
-	"invokescript":                 (*Server).invokescript,
-	"invokescripthistoric":         (*Server).invokescripthistoric,
-	"invokecontractverify":         (*Server).invokeContractVerify,
-	"invokecontractverifyhistoric": (*Server).invokeContractVerifyHistoric,
-	"sendrawtransaction":           (*Server).sendrawtransaction,
-	"submitblock":                  (*Server).submitBlock,
-	"submitnotaryrequest":          (*Server).submitNotaryRequest,
-	"submitoracleresponse":         (*Server).submitOracleResponse,
-	"terminatesession":             (*Server).terminateSession,
-	"traverseiterator":             (*Server).traverseIterator,
-	"validateaddress":              (*Server).validateAddress,
-	"verifyproof":                  (*Server).verifyProof,
+	"calculatenetworkfee":            (*Server).calculateNetworkFee,
+	"estimatepriorityfee":            (*Server).estimatePriorityFee,
+	"findstates":                     (*Server).findStates,
+	"findstorage":                    (*Server).findStorage,
+	"findstoragehistoric":            (*Server).findStorageHistoric,
+	"getapplicationlog":              (*Server).getApplicationLog,
+	"getbestblockhash":               (*Server).getBestBlockHash,
+	"getblock":                       (*Server).getBlock,
+	"getblockcount":                  (*Server).getBlockCount,
+	"getblockhash":                   (*Server).getBlockHash,
+	"getblockheader":                 (*Server).getBlockHeader,
+	"getblockheadercount":            (*Server).getBlockHeaderCount,
+	"getblocksysfee":                 (*Server).getBlockSysFee,
+	"getcandidates":                  (*Server).getCandidates,
+	"getcandidateshistoric":          (*Server).getCandidatesHistoric,
+	"getcommittee":                   (*Server).getCommittee,
+	"getcommitteehistoric":           (*Server).getCommitteeHistoric,
+	"getconnectioncount":             (*Server).getConnectionCount,
+	"generateblocks":                 (*Server).generateBlocks,
+	"getconsensusstate":              (*Server).getConsensusState,
+	"getcontractstate":               (*Server).getContractState,
+	"getmempoolentries":              (*Server).getMempoolEntries,
+	"getnativecontracts":             (*Server).getNativeContracts,
+	"getnep11balances":               (*Server).getNEP11Balances,
+	"getnep11properties":             (*Server).getNEP11Properties,
+	"getnep11transfers":              (*Server).getNEP11Transfers,
+	"getnep17balances":               (*Server).getNEP17Balances,
+	"getnep17transfers":              (*Server).getNEP17Transfers,
+	"getpeers":                       (*Server).getPeers,
+	"getproof":                       (*Server).getProof,
+	"getrawmempool":                  (*Server).getRawMempool,
+	"getrawnotarypool":               (*Server).getRawNotaryPool,
+	"getrawnotarytransaction":        (*Server).getRawNotaryTransaction,
+	"getrawtransaction":              (*Server).getrawtransaction,
+	"getstate":                       (*Server).getState,
+	"getstatediff":                   (*Server).getStateDiff,
+	"getstateheight":                 (*Server).getStateHeight,
+	"getstateroot":                   (*Server).getStateRoot,
+	"getstorage":                     (*Server).getStorage,
+	"getstoragehistoric":             (*Server).getStorageHistoric,
+	"gettransactionheight":           (*Server).getTransactionHeight,
+	"getunclaimedgas":                (*Server).getUnclaimedGas,
+	"getnextblockvalidators":         (*Server).getNextBlockValidators,
+	"getnextblockvalidatorshistoric": (*Server).getNextBlockValidatorsHistoric,
+	"getversion":                     (*Server).getVersion,
+	"invokefunction":                 (*Server).invokeFunction,
+	"invokefunctionhistoric":         (*Server).invokeFunctionHistoric,
+	"invokescript":                   (*Server).invokescript,
+	"invokescripthistoric":           (*Server).invokescripthistoric,
+	"invokescripts":                  (*Server).invokescripts,
+	"invokecontractverify":           (*Server).invokeContractVerify,
+	"invokecontractverifyhistoric":   (*Server).invokeContractVerifyHistoric,
+	"sendrawtransaction":             (*Server).sendrawtransaction,
+	"submitblock":                    (*Server).submitBlock,
+	"submitnotaryrequest":            (*Server).submitNotaryRequest,
+	"submitoracleresponse":           (*Server).submitOracleResponse,
+	"terminatesession":               (*Server).terminateSession,
+	"tracetransaction":               (*Server).traceTransaction,
+	"traverseiterator":               (*Server).traverseIterator,
+	"validateaddress":                (*Server).validateAddress,
+	"verifyproof":                    (*Server).verifyProof,
 }
 
 var rpcWsHandlers = map[string]func(*Server, params.Params, *subscriber) (any, *neorpc.Error){
@@ -352,6 +413,8 @@ func New(chain Ledger, conf config.RPC, coreServer *network.Server,
 		coreServer:       coreServer,
 		log:              log,
 		oracle:           oracleWrapped,
+		consensus:        new(atomic.Pointer[ConsensusHandler]),
+		extension:        new(atomic.Pointer[ExtensionHandler]),
 		shutdown:         make(chan struct{}),
 		errChan:          errChan,
 
@@ -364,6 +427,7 @@ func New(chain Ledger, conf config.RPC, coreServer *network.Server,
 		notificationCh:    make(chan *state.ContainedNotificationEvent),
 		transactionCh:     make(chan *transaction.Transaction),
 		notaryRequestCh:   make(chan mempoolevent.Event),
+		mempoolCh:         make(chan mempoolevent.Event, notificationBufSize), // Buffered since memory pool doesn't wait for this subscriber.
 		blockHeaderCh:     make(chan *block.Header),
 		subEventsToExitCh: make(chan struct{}),
 	}
@@ -489,6 +553,28 @@ func (s *Server) SetOracleHandler(orc OracleHandler) {
 	s.oracle.Store(orc)
 }
 
+// SetExtensionHandler allows to update the handler of methods not implemented
+// by the Server itself, they're not available if it's nil.
+func (s *Server) SetExtensionHandler(h ExtensionHandler) {
+	s.extension.Store(&h)
+}
+
+// handleExtension handles the method call with the extension handler (if
+// any), it returns false if the method is not handled.
+func (s *Server) handleExtension(method string, reqParams params.Params) (any, *neorpc.Error, bool) {
+	h := s.extension.Load()
+	if h == nil || *h == nil {
+		return nil, nil, false
+	}
+	return (*h).HandleRPC(method, reqParams)
+}
+
+// SetConsensusHandler allows to update consensus handler used by the Server,
+// getconsensusstate and generateblocks are not available if it's nil.
+func (s *Server) SetConsensusHandler(h ConsensusHandler) {
+	s.consensus.Store(&h)
+}
+
 func (s *Server) handleHTTPRequest(w http.ResponseWriter, httpRequest *http.Request) {
 	// Restrict request body before further processing.
 	httpRequest.Body = http.MaxBytesReader(w, httpRequest.Body, int64(s.config.MaxRequestBodyBytes))
@@ -607,11 +693,12 @@ func (s *Server) handleInternal(req *neorpc.Request, sub *subscriber) (*neorpc.R
 	handler, ok := rpcHandlers[req.Method]
 	if ok {
 		res, rpcRes.Error = handler(s, reqParams)
-	} else if sub != nil {
-		handler, ok := rpcWsHandlers[req.Method]
-		if ok {
-			res, rpcRes.Error = handler(s, reqParams, sub)
+	} else if wsHandler, ok := rpcWsHandlers[req.Method]; ok {
+		if sub != nil {
+			res, rpcRes.Error = wsHandler(s, reqParams, sub)
 		}
+	} else if extRes, extErr, ok := s.handleExtension(req.Method, reqParams); ok {
+		res, rpcRes.Error = extRes, extErr
 	}
 	if res != nil {
 		b, err := json.Marshal(res)
@@ -643,11 +730,12 @@ func (s *Server) handleIn(req *params.In, sub *subscriber) abstract {
 	handler, ok := rpcHandlers[req.Method]
 	if ok {
 		res, resErr = handler(s, reqParams)
-	} else if sub != nil {
-		handler, ok := rpcWsHandlers[req.Method]
-		if ok {
-			res, resErr = handler(s, reqParams, sub)
+	} else if wsHandler, ok := rpcWsHandlers[req.Method]; ok {
+		if sub != nil {
+			res, resErr = wsHandler(s, reqParams, sub)
 		}
+	} else if extRes, extErr, ok := s.handleExtension(req.Method, reqParams); ok {
+		res, resErr = extRes, extErr
 	}
 	return s.packResponse(req, res, resErr)
 }
@@ -784,6 +872,48 @@ func (s *Server) getConnectionCount(_ params.Params) (any, *neorpc.Error) {
 	return s.coreServer.PeerCount(), nil
 }
 
+func (s *Server) getConsensusState(_ params.Params) (any, *neorpc.Error) {
+	h := s.consensus.Load()
+	if h == nil || *h == nil {
+		return nil, neorpc.ErrConsensusDisabled
+	}
+	sp, ok := (*h).(ConsensusStateProvider)
+	if !ok {
+		return nil, neorpc.ErrConsensusDisabled
+	}
+	st, err := sp.GetState()
+	if err != nil {
+		return nil, neorpc.WrapErrorWithData(neorpc.ErrConsensusDisabled, err.Error())
+	}
+	return st, nil
+}
+
+// generateBlocks creates the requested number of blocks (one by default) if
+// consensus service runs in instant mining mode.
+func (s *Server) generateBlocks(reqParams params.Params) (any, *neorpc.Error) {
+	var n = 1
+	if len(reqParams) > 0 {
+		var err error
+		n, err = reqParams[0].GetInt()
+		if err != nil || n <= 0 || n > maxGeneratedBlocks {
+			return nil, neorpc.WrapErrorWithData(neorpc.ErrInvalidParams, fmt.Sprintf("number of blocks should be in [1, %d] range", maxGeneratedBlocks))
+		}
+	}
+	h := s.consensus.Load()
+	if h == nil || *h == nil {
+		return nil, neorpc.ErrConsensusDisabled
+	}
+	gen, ok := (*h).(BlockGenerator)
+	if !ok {
+		return nil, neorpc.ErrInstantMiningDisabled
+	}
+	hashes, err := gen.GenerateBlocks(n)
+	if err != nil {
+		return nil, neorpc.NewInternalServerError(fmt.Sprintf("can't generate blocks: %s", err))
+	}
+	return hashes, nil
+}
+
 func (s *Server) blockHashFromParam(param *params.Param) (util.Uint256, *neorpc.Error) {
 	var (
 		hash util.Uint256
@@ -917,6 +1047,53 @@ func (s *Server) getRawMempool(reqParams params.Params) (any, *neorpc.Error) {
 	}, nil
 }
 
+// getMempoolEntries returns detailed information about memory pool transactions
+// optionally filtered by sender and/or signer.
+func (s *Server) getMempoolEntries(reqParams params.Params) (any, *neorpc.Error) {
+	var flt neorpc.TxFilter
+	if p := reqParams.Value(0); p != nil {
+		jd := json.NewDecoder(bytes.NewReader(p.RawMessage))
+		jd.DisallowUnknownFields()
+		if err := jd.Decode(&flt); err != nil {
+			return nil, neorpc.WrapErrorWithData(neorpc.ErrInvalidParams, fmt.Sprintf("invalid filter: %s", err))
+		}
+	}
+	var (
+		txs        = s.chain.GetMemPool().GetVerifiedTransactions()
+		senderFees = make(map[util.Uint160]int64)
+		res        = make([]result.MempoolEntry, 0)
+	)
+	for _, tx := range txs {
+		senderFees[tx.Sender()] += tx.SystemFee + tx.NetworkFee
+	}
+	for i, tx := range txs {
+		if flt.Sender != nil && !tx.Sender().Equals(*flt.Sender) {
+			continue
+		}
+		if flt.Signer != nil && !tx.HasSigner(*flt.Signer) {
+			continue
+		}
+		var conflicts = make([]util.Uint256, 0)
+		for _, attr := range tx.GetAttributes(transaction.ConflictsT) {
+			conflicts = append(conflicts, attr.Value.(*transaction.Conflicts).Hash)
+		}
+		res = append(res, result.MempoolEntry{
+			Hash:            tx.Hash(),
+			Sender:          tx.Sender(),
+			FeePerByte:      tx.FeePerByte(),
+			NetworkFee:      tx.NetworkFee,
+			SystemFee:       tx.SystemFee,
+			Size:            tx.Size(),
+			ValidUntilBlock: tx.ValidUntilBlock,
+			HighPriority:    tx.HasAttribute(transaction.HighPriority),
+			Conflicts:       conflicts,
+			Position:        i,
+			SenderFees:      senderFees[tx.Sender()],
+		})
+	}
+	return res, nil
+}
+
 func (s *Server) validateAddress(reqParams params.Params) (any, *neorpc.Error) {
 	param, err := reqParams.Value(0).GetString()
 	if err != nil {
@@ -1001,6 +1178,78 @@ func (s *Server) calculateNetworkFee(reqParams params.Params) (any, *neorpc.Erro
 	return result.NetworkFee{Value: netFee}, nil
 }
 
+// estimatePriorityFee suggests network fee per byte value for new transactions
+// based on the memory pool state and fees of transactions in the latest blocks.
+func (s *Server) estimatePriorityFee(reqParams params.Params) (any, *neorpc.Error) {
+	var blocks = defaultPriorityFeeBlocks
+	if len(reqParams) > 0 {
+		var err error
+		blocks, err = reqParams[0].GetInt()
+		if err != nil || blocks <= 0 || blocks > maxPriorityFeeBlocks {
+			return nil, neorpc.WrapErrorWithData(neorpc.ErrInvalidParams, fmt.Sprintf("number of blocks should be in [1, %d] range", maxPriorityFeeBlocks))
+		}
+	}
+	var (
+		mp  = s.chain.GetMemPool()
+		res = result.PriorityFee{
+			PolicyFeePerByte: s.chain.FeePerByte(),
+			MempoolCapacity:  mp.Capacity(),
+		}
+		fees   []int64
+		height = s.chain.BlockHeight()
+	)
+	mp.IterateVerifiedTransactions(func(tx *transaction.Transaction, _ any) bool {
+		res.MempoolCount++
+		if !tx.HasAttribute(transaction.HighPriority) {
+			if fpb := tx.FeePerByte(); res.MempoolMinFeePerByte == 0 || fpb < res.MempoolMinFeePerByte {
+				res.MempoolMinFeePerByte = fpb
+			}
+		}
+		return true
+	})
+	for i := uint32(0); i < uint32(blocks) && i <= height; i++ {
+		b, err := s.chain.GetBlock(s.chain.GetHeaderHash(height - i))
+		if err != nil {
+			// Block can be unavailable (removed or not yet stored),
+			// it's not critical for statistics.
+			continue
+		}
+		res.Blocks++
+		for _, tx := range b.Transactions {
+			fees = append(fees, tx.FeePerByte())
+		}
+	}
+	if len(fees) != 0 {
+		sort.Slice(fees, func(i, j int) bool { return fees[i] < fees[j] })
+		res.BlockTransactions = len(fees)
+		res.BlocksMinFeePerByte = fees[0]
+		res.BlocksMedianFeePerByte = fees[len(fees)/2]
+	}
+
+	// Low pressure, minimum fee is enough.
+	res.FeePerByte = res.PolicyFeePerByte
+	if 2*res.MempoolCount >= res.MempoolCapacity {
+		// Significant pressure, pay at least as much as others do.
+		res.FeePerByte = max64(res.FeePerByte, res.BlocksMedianFeePerByte)
+		if res.MempoolCount >= res.MempoolCapacity {
+			// The pool is full, transaction must be better than the
+			// worst one to get in.
+			res.FeePerByte = max64(res.FeePerByte, res.MempoolMinFeePerByte+1)
+		} else {
+			res.FeePerByte = max64(res.FeePerByte, res.MempoolMinFeePerByte)
+		}
+	}
+	return res, nil
+}
+
+// max64 returns the maximum of two int64 values.
+func max64(a, b int64) int64 {
+	if a > b {
+		return a
+	}
+	return b
+}
+
 // getApplicationLog returns the contract log based on the specified txid or blockid.
 func (s *Server) getApplicationLog(reqParams params.Params) (any, *neorpc.Error) {
 	hash, err := reqParams.Value(0).GetUint256()
@@ -1027,6 +1276,38 @@ func (s *Server) getApplicationLog(reqParams params.Params) (any, *neorpc.Error)
 	return result.NewApplicationLog(hash, appExecResults, trig), nil
 }
 
+// traceTransaction re-executes persisted transaction on the historic state and
+// returns its execution trace.
+func (s *Server) traceTransaction(reqParams params.Params) (any, *neorpc.Error) {
+	hash, err := reqParams.Value(0).GetUint256()
+	if err != nil {
+		return nil, neorpc.ErrInvalidParams
+	}
+	var withSteps bool
+	if len(reqParams) > 1 {
+		withSteps, err = reqParams[1].GetBoolean()
+		if err != nil {
+			return nil, neorpc.ErrInvalidParams
+		}
+	}
+	if s.chain.GetConfig().Ledger.KeepOnlyLatestState {
+		return nil, neorpc.WrapErrorWithData(neorpc.ErrUnsupportedState, fmt.Sprintf("'tracetransaction' is not supported: %s", errKeepOnlyLatestState))
+	}
+	_, height, err := s.chain.GetTransaction(hash)
+	if err != nil || s.chain.GetMemPool().ContainsKey(hash) {
+		return nil, neorpc.ErrUnknownTransaction
+	}
+	ic, err := s.chain.GetTestHistoricTxVM(hash)
+	if err != nil {
+		return nil, neorpc.NewInternalServerError(fmt.Sprintf("can't prepare transaction execution: %s", err))
+	}
+	defer ic.Finalize()
+	trace := newTracer(ic, withSteps).run()
+	trace.TxHash = hash
+	trace.BlockIndex = height
+	return trace, nil
+}
+
 func (s *Server) getNEP11Tokens(h util.Uint160, acc util.Uint160, bw *io.BufBinWriter) ([]stackitem.Item, string, int, error) {
 	items, finalize, err := s.invokeReadOnlyMulti(bw, h, []string{"tokensOf", "symbol", "decimals"}, [][]any{{acc}, nil, nil})
 	if err != nil {
@@ -1845,6 +2126,25 @@ func (s *Server) getHistoricalContractState(root util.Uint256, csHash util.Uint1
 	return contract, nil
 }
 
+func (s *Server) getStateDiff(ps params.Params) (any, *neorpc.Error) {
+	if s.chain.GetConfig().Ledger.KeepOnlyLatestState {
+		return nil, neorpc.WrapErrorWithData(neorpc.ErrUnsupportedState, fmt.Sprintf("'getstatediff' is not supported: %s", errKeepOnlyLatestState))
+	}
+	hash, respErr := s.blockHashFromParam(ps.Value(0))
+	if respErr != nil {
+		return nil, respErr
+	}
+	hdr, err := s.chain.GetHeader(hash)
+	if err != nil {
+		return nil, neorpc.ErrUnknownBlock
+	}
+	diff, err := s.chain.GetStateDiff(hdr.Index)
+	if err != nil {
+		return nil, neorpc.NewInternalServerError(fmt.Sprintf("failed to get state diff: %s", err))
+	}
+	return diff, nil
+}
+
 func (s *Server) getStateHeight(_ params.Params) (any, *neorpc.Error) {
 	var height = s.chain.BlockHeight()
 	var stateHeight = s.chain.GetStateModule().CurrentValidatedHeight()
@@ -2079,8 +2379,6 @@ func (s *Server) getUnclaimedGas(ps params.Params) (any, *neorpc.Error) {
 
 // getCandidates returns the current list of candidates with their active/inactive voting status.
 func (s *Server) getCandidates(_ params.Params) (any, *neorpc.Error) {
-	var validators keys.PublicKeys
-
 	validators, err := s.chain.GetNextBlockValidators()
 	if err != nil {
 		return nil, neorpc.NewInternalServerError(fmt.Sprintf("Can't get next block validators: %s", err.Error()))
@@ -2089,6 +2387,24 @@ func (s *Server) getCandidates(_ params.Params) (any, *neorpc.Error) {
 	if err != nil {
 		return nil, neorpc.NewInternalServerError(fmt.Sprintf("Can't get enrollments: %s", err.Error()))
 	}
+	return makeCandidates(validators, enrollments), nil
+}
+
+// getCandidatesHistoric returns the list of candidates with their active/inactive
+// voting status at the specified height.
+func (s *Server) getCandidatesHistoric(reqParams params.Params) (any, *neorpc.Error) {
+	nextH, respErr := s.getHistoricParams(reqParams)
+	if respErr != nil {
+		return nil, respErr
+	}
+	validators, enrollments, err := s.chain.GetCandidatesHistoric(nextH - 1)
+	if err != nil {
+		return nil, neorpc.NewInternalServerError(fmt.Sprintf("Can't get historic candidates: %s", err.Error()))
+	}
+	return makeCandidates(validators, enrollments), nil
+}
+
+func makeCandidates(validators keys.PublicKeys, enrollments []state.Validator) []result.Candidate {
 	var res = make([]result.Candidate, 0)
 	for _, v := range enrollments {
 		res = append(res, result.Candidate{
@@ -2097,13 +2413,11 @@ func (s *Server) getCandidates(_ params.Params) (any, *neorpc.Error) {
 			Active:    validators.Contains(v.Key),
 		})
 	}
-	return res, nil
+	return res
 }
 
 // getNextBlockValidators returns validators for the next block with voting status.
 func (s *Server) getNextBlockValidators(_ params.Params) (any, *neorpc.Error) {
-	var validators keys.PublicKeys
-
 	validators, err := s.chain.GetNextBlockValidators()
 	if err != nil {
 		return nil, neorpc.NewInternalServerError(fmt.Sprintf("Can't get next block validators: %s", err.Error()))
@@ -2112,6 +2426,24 @@ func (s *Server) getNextBlockValidators(_ params.Params) (any, *neorpc.Error) {
 	if err != nil {
 		return nil, neorpc.NewInternalServerError(fmt.Sprintf("Can't get enrollments: %s", err.Error()))
 	}
+	return makeValidators(validators, enrollments), nil
+}
+
+// getNextBlockValidatorsHistoric returns validators for the block following
+// the specified one with voting status.
+func (s *Server) getNextBlockValidatorsHistoric(reqParams params.Params) (any, *neorpc.Error) {
+	nextH, respErr := s.getHistoricParams(reqParams)
+	if respErr != nil {
+		return nil, respErr
+	}
+	validators, enrollments, err := s.chain.GetCandidatesHistoric(nextH - 1)
+	if err != nil {
+		return nil, neorpc.NewInternalServerError(fmt.Sprintf("Can't get historic candidates: %s", err.Error()))
+	}
+	return makeValidators(validators, enrollments), nil
+}
+
+func makeValidators(validators keys.PublicKeys, enrollments []state.Validator) []result.Validator {
 	var res = make([]result.Validator, 0)
 	for _, v := range enrollments {
 		if !validators.Contains(v.Key) {
@@ -2122,7 +2454,7 @@ func (s *Server) getNextBlockValidators(_ params.Params) (any, *neorpc.Error) {
 			Votes:     v.Votes.Int64(),
 		})
 	}
-	return res, nil
+	return res
 }
 
 // getCommittee returns the current list of NEO committee members.
@@ -2134,13 +2466,27 @@ func (s *Server) getCommittee(_ params.Params) (any, *neorpc.Error) {
 	return keys, nil
 }
 
+// getCommitteeHistoric returns the list of NEO committee members at the
+// specified height.
+func (s *Server) getCommitteeHistoric(reqParams params.Params) (any, *neorpc.Error) {
+	nextH, respErr := s.getHistoricParams(reqParams)
+	if respErr != nil {
+		return nil, respErr
+	}
+	keys, err := s.chain.GetCommitteeHistoric(nextH - 1)
+	if err != nil {
+		return nil, neorpc.NewInternalServerError(fmt.Sprintf("can't get committee members: %s", err))
+	}
+	return keys, nil
+}
+
 // invokeFunction implements the `invokeFunction` RPC call.
 func (s *Server) invokeFunction(reqParams params.Params) (any, *neorpc.Error) {
-	tx, verbose, respErr := s.getInvokeFunctionParams(reqParams)
+	tx, verbose, overrides, respErr := s.getInvokeFunctionParams(reqParams)
 	if respErr != nil {
 		return nil, respErr
 	}
-	return s.runScriptInVM(trigger.Application, tx.Script, util.Uint160{}, tx, nil, verbose)
+	return s.runScriptInVM(trigger.Application, tx.Script, util.Uint160{}, tx, nil, verbose, overrides)
 }
 
 // invokeFunctionHistoric implements the `invokeFunctionHistoric` RPC call.
@@ -2152,24 +2498,24 @@ func (s *Server) invokeFunctionHistoric(reqParams params.Params) (any, *neorpc.E
 	if len(reqParams) < 2 {
 		return nil, neorpc.ErrInvalidParams
 	}
-	tx, verbose, respErr := s.getInvokeFunctionParams(reqParams[1:])
+	tx, verbose, overrides, respErr := s.getInvokeFunctionParams(reqParams[1:])
 	if respErr != nil {
 		return nil, respErr
 	}
-	return s.runScriptInVM(trigger.Application, tx.Script, util.Uint160{}, tx, &nextH, verbose)
+	return s.runScriptInVM(trigger.Application, tx.Script, util.Uint160{}, tx, &nextH, verbose, overrides)
 }
 
-func (s *Server) getInvokeFunctionParams(reqParams params.Params) (*transaction.Transaction, bool, *neorpc.Error) {
+func (s *Server) getInvokeFunctionParams(reqParams params.Params) (*transaction.Transaction, bool, *state.Overrides, *neorpc.Error) {
 	if len(reqParams) < 2 {
-		return nil, false, neorpc.ErrInvalidParams
+		return nil, false, nil, neorpc.ErrInvalidParams
 	}
 	scriptHash, responseErr := s.contractScriptHashFromParam(reqParams.Value(0))
 	if responseErr != nil {
-		return nil, false, responseErr
+		return nil, false, nil, responseErr
 	}
 	method, err := reqParams[1].GetString()
 	if err != nil {
-		return nil, false, neorpc.ErrInvalidParams
+		return nil, false, nil, neorpc.ErrInvalidParams
 	}
 	var invparams *params.Param
 	if len(reqParams) > 2 {
@@ -2179,7 +2525,7 @@ func (s *Server) getInvokeFunctionParams(reqParams params.Params) (*transaction.
 	if len(reqParams) > 3 {
 		signers, _, err := reqParams[3].GetSignersWithWitnesses()
 		if err != nil {
-			return nil, false, neorpc.ErrInvalidParams
+			return nil, false, nil, neorpc.ErrInvalidParams
 		}
 		tx.Signers = signers
 	}
@@ -2187,27 +2533,31 @@ func (s *Server) getInvokeFunctionParams(reqParams params.Params) (*transaction.
 	if len(reqParams) > 4 {
 		verbose, err = reqParams[4].GetBoolean()
 		if err != nil {
-			return nil, false, neorpc.ErrInvalidParams
+			return nil, false, nil, neorpc.ErrInvalidParams
 		}
 	}
+	overrides, respErr := getOverridesParam(reqParams.Value(5))
+	if respErr != nil {
+		return nil, false, nil, respErr
+	}
 	if len(tx.Signers) == 0 {
 		tx.Signers = []transaction.Signer{{Account: util.Uint160{}, Scopes: transaction.None}}
 	}
 	script, err := params.CreateFunctionInvocationScript(scriptHash, method, invparams)
 	if err != nil {
-		return nil, false, neorpc.WrapErrorWithData(neorpc.ErrInvalidParams, fmt.Sprintf("can't create invocation script: %s", err))
+		return nil, false, nil, neorpc.WrapErrorWithData(neorpc.ErrInvalidParams, fmt.Sprintf("can't create invocation script: %s", err))
 	}
 	tx.Script = script
-	return tx, verbose, nil
+	return tx, verbose, overrides, nil
 }
 
 // invokescript implements the `invokescript` RPC call.
 func (s *Server) invokescript(reqParams params.Params) (any, *neorpc.Error) {
-	tx, verbose, respErr := s.getInvokeScriptParams(reqParams)
+	tx, verbose, overrides, respErr := s.getInvokeScriptParams(reqParams)
 	if respErr != nil {
 		return nil, respErr
 	}
-	return s.runScriptInVM(trigger.Application, tx.Script, util.Uint160{}, tx, nil, verbose)
+	return s.runScriptInVM(trigger.Application, tx.Script, util.Uint160{}, tx, nil, verbose, overrides)
 }
 
 // invokescripthistoric implements the `invokescripthistoric` RPC call.
@@ -2219,24 +2569,24 @@ func (s *Server) invokescripthistoric(reqParams params.Params) (any, *neorpc.Err
 	if len(reqParams) < 2 {
 		return nil, neorpc.ErrInvalidParams
 	}
-	tx, verbose, respErr := s.getInvokeScriptParams(reqParams[1:])
+	tx, verbose, overrides, respErr := s.getInvokeScriptParams(reqParams[1:])
 	if respErr != nil {
 		return nil, respErr
 	}
-	return s.runScriptInVM(trigger.Application, tx.Script, util.Uint160{}, tx, &nextH, verbose)
+	return s.runScriptInVM(trigger.Application, tx.Script, util.Uint160{}, tx, &nextH, verbose, overrides)
 }
 
-func (s *Server) getInvokeScriptParams(reqParams params.Params) (*transaction.Transaction, bool, *neorpc.Error) {
+func (s *Server) getInvokeScriptParams(reqParams params.Params) (*transaction.Transaction, bool, *state.Overrides, *neorpc.Error) {
 	script, err := reqParams.Value(0).GetBytesBase64()
 	if err != nil {
-		return nil, false, neorpc.ErrInvalidParams
+		return nil, false, nil, neorpc.ErrInvalidParams
 	}
 
 	tx := &transaction.Transaction{}
 	if len(reqParams) > 1 {
 		signers, witnesses, err := reqParams[1].GetSignersWithWitnesses()
 		if err != nil {
-			return nil, false, neorpc.WrapErrorWithData(neorpc.ErrInvalidParams, err.Error())
+			return nil, false, nil, neorpc.WrapErrorWithData(neorpc.ErrInvalidParams, err.Error())
 		}
 		tx.Signers = signers
 		tx.Scripts = witnesses
@@ -2245,14 +2595,150 @@ func (s *Server) getInvokeScriptParams(reqParams params.Params) (*transaction.Tr
 	if len(reqParams) > 2 {
 		verbose, err = reqParams[2].GetBoolean()
 		if err != nil {
-			return nil, false, neorpc.WrapErrorWithData(neorpc.ErrInvalidParams, err.Error())
+			return nil, false, nil, neorpc.WrapErrorWithData(neorpc.ErrInvalidParams, err.Error())
 		}
 	}
+	overrides, respErr := getOverridesParam(reqParams.Value(3))
+	if respErr != nil {
+		return nil, false, nil, respErr
+	}
 	if len(tx.Signers) == 0 {
 		tx.Signers = []transaction.Signer{{Account: util.Uint160{}, Scopes: transaction.None}}
 	}
 	tx.Script = script
-	return tx, verbose, nil
+	return tx, verbose, overrides, nil
+}
+
+// invokescripts implements the `invokescripts` RPC call. Scripts are executed
+// one after another on the same state as if they were transactions of the next
+// block, changes made by the faulted ones are dropped. All scripts share the
+// same MaxGasInvoke limit.
+func (s *Server) invokescripts(reqParams params.Params) (any, *neorpc.Error) {
+	txs, respErr := getInvokeScriptsParams(reqParams)
+	if respErr != nil {
+		return nil, respErr
+	}
+	overrides, respErr := getOverridesParam(reqParams.Value(1))
+	if respErr != nil {
+		return nil, respErr
+	}
+	base, err := s.chain.GetTestVM(trigger.Application, txs[0], nil)
+	if err != nil {
+		return nil, neorpc.NewInternalServerError(fmt.Sprintf("failed to create test VM: %s", err))
+	}
+	defer base.Finalize()
+	if overrides != nil {
+		if err = s.chain.ApplyStateOverrides(base, overrides); err != nil {
+			return nil, neorpc.WrapErrorWithData(neorpc.ErrInvalidParams, fmt.Sprintf("can't apply overrides: %s", err))
+		}
+	}
+	var (
+		res     = make([]*result.Invoke, 0, len(txs))
+		gasLeft = int64(s.config.MaxGasInvoke)
+	)
+	for _, tx := range txs {
+		ic, err := s.chain.GetTestVM(trigger.Application, tx, base.Block)
+		if err != nil {
+			return nil, neorpc.NewInternalServerError(fmt.Sprintf("failed to create test VM: %s", err))
+		}
+		ic.DAO = base.DAO.GetPrivate()
+		ic.VM.GasLimit = gasLeft
+		ic.VM.LoadScriptWithFlags(tx.Script, callflag.All)
+		err = ic.VM.Run()
+		// Negative limit means no limit at all, so it never drops below zero.
+		gasLeft -= ic.VM.GasConsumed()
+		if gasLeft < 0 {
+			gasLeft = 0
+		}
+		var faultException string
+		if err != nil {
+			faultException = err.Error()
+		}
+		// Sessions can't be used here since the state is changed by the
+		// subsequent scripts, so iterators are always traversed.
+		items := ic.VM.Estack().ToArray()
+		for i := range items {
+			if items[i].Type() == stackitem.InteropT && iterator.IsIterator(items[i]) {
+				var it result.Iterator
+				it.Values, it.Truncated = iterator.ValuesTruncated(items[i], s.config.MaxIteratorResultItems)
+				items[i] = stackitem.NewInterop(it)
+			}
+		}
+		ic.Finalize()
+		if err == nil {
+			if _, err = ic.DAO.Persist(); err != nil {
+				return nil, neorpc.NewInternalServerError(fmt.Sprintf("failed to persist invocation results: %s", err))
+			}
+		}
+		notifications := ic.Notifications
+		if notifications == nil {
+			notifications = make([]state.NotificationEvent, 0)
+		}
+		res = append(res, &result.Invoke{
+			State:          ic.VM.State().String(),
+			GasConsumed:    ic.VM.GasConsumed(),
+			Script:         tx.Script,
+			Stack:          items,
+			FaultException: faultException,
+			Notifications:  notifications,
+		})
+	}
+	return res, nil
+}
+
+func getInvokeScriptsParams(reqParams params.Params) ([]*transaction.Transaction, *neorpc.Error) {
+	scripts, err := reqParams.Value(0).GetArray()
+	if err != nil {
+		return nil, neorpc.WrapErrorWithData(neorpc.ErrInvalidParams, err.Error())
+	}
+	if len(scripts) == 0 || len(scripts) > maxInvokedScripts {
+		return nil, neorpc.WrapErrorWithData(neorpc.ErrInvalidParams, fmt.Sprintf("expected 1 to %d scripts, got %d", maxInvokedScripts, len(scripts)))
+	}
+	txs := make([]*transaction.Transaction, len(scripts))
+	for i := range scripts {
+		var item struct {
+			Script  []byte       `json:"script"`
+			Signers params.Param `json:"signers"`
+		}
+		jd := json.NewDecoder(bytes.NewReader(scripts[i].RawMessage))
+		jd.DisallowUnknownFields()
+		if err := jd.Decode(&item); err != nil {
+			return nil, neorpc.WrapErrorWithData(neorpc.ErrInvalidParams, fmt.Sprintf("script %d: %s", i, err))
+		}
+		if len(item.Script) == 0 {
+			return nil, neorpc.WrapErrorWithData(neorpc.ErrInvalidParams, fmt.Sprintf("script %d: empty script", i))
+		}
+		// Different nonces make script containers distinguishable.
+		tx := &transaction.Transaction{Script: item.Script, Nonce: uint32(i)}
+		if len(item.Signers.RawMessage) != 0 && !item.Signers.IsNull() {
+			signers, witnesses, err := item.Signers.GetSignersWithWitnesses()
+			if err != nil {
+				return nil, neorpc.WrapErrorWithData(neorpc.ErrInvalidParams, fmt.Sprintf("script %d: %s", i, err))
+			}
+			tx.Signers = signers
+			tx.Scripts = witnesses
+		}
+		if len(tx.Signers) == 0 {
+			tx.Signers = []transaction.Signer{{Account: util.Uint160{}, Scopes: transaction.None}}
+		}
+		txs[i] = tx
+	}
+	return txs, nil
+}
+
+// getOverridesParam decodes optional state overrides parameter of invocation
+// calls.
+func getOverridesParam(p *params.Param) (*state.Overrides, *neorpc.Error) {
+	if p == nil {
+		return nil, nil
+	}
+	var o = new(state.Overrides)
+	jd := json.NewDecoder(bytes.NewReader(p.RawMessage))
+	jd.DisallowUnknownFields()
+	if err := jd.Decode(o); err != nil {
+		return nil, neorpc.WrapErrorWithData(neorpc.ErrInvalidParams, fmt.Sprintf("invalid overrides: %s", err))
+	}
+	return o, nil
 }
 
 // invokeContractVerify implements the `invokecontractverify` RPC call.
@@ -2261,7 +2747,7 @@ func (s *Server) invokeContractVerify(reqParams params.Params) (any, *neorpc.Err
 	if respErr != nil {
 		return nil, respErr
 	}
-	return s.runScriptInVM(trigger.Verification, invocationScript, scriptHash, tx, nil, false)
+	return s.runScriptInVM(trigger.Verification, invocationScript, scriptHash, tx, nil, false, nil)
 }
 
 // invokeContractVerifyHistoric implements the `invokecontractverifyhistoric` RPC call.
@@ -2277,7 +2763,7 @@ func (s *Server) invokeContractVerifyHistoric(reqParams params.Params) (any, *ne
 	if respErr != nil {
 		return nil, respErr
 	}
-	return s.runScriptInVM(trigger.Verification, invocationScript, scriptHash, tx, &nextH, false)
+	return s.runScriptInVM(trigger.Verification, invocationScript, scriptHash, tx, &nextH, false, nil)
 }
 
 func (s *Server) getInvokeContractVerifyParams(reqParams params.Params) (util.Uint160, *transaction.Transaction, []byte, *neorpc.Error) {
@@ -2396,12 +2882,21 @@ func (s *Server) prepareInvocationContext(t trigger.Type, script []byte, contrac
 // result. The script is either a simple script in case of `application` trigger,
 // witness invocation script in case of `verification` trigger (it pushes `verify`
 // arguments on stack before verification). In case of contract verification
-// contractScriptHash should be specified.
-func (s *Server) runScriptInVM(t trigger.Type, script []byte, contractScriptHash util.Uint160, tx *transaction.Transaction, nextH *uint32, verbose bool) (*result.Invoke, *neorpc.Error) {
+// contractScriptHash should be specified. Optional overrides are applied to
+// the state before the script execution.
+func (s *Server) runScriptInVM(t trigger.Type, script []byte, contractScriptHash util.Uint160, tx *transaction.Transaction, nextH *uint32, verbose bool, overrides *state.Overrides) (*result.Invoke, *neorpc.Error) {
 	ic, respErr := s.prepareInvocationContext(t, script, contractScriptHash, tx, nextH, verbose)
 	if respErr != nil {
 		return nil, respErr
 	}
+	// Block index can be overridden, so remember the real one for the rerun.
+	nextIndex := ic.Block.Index
+	if overrides != nil {
+		if err := s.chain.ApplyStateOverrides(ic, overrides); err != nil {
+			ic.Finalize()
+			return nil, neorpc.WrapErrorWithData(neorpc.ErrInvalidParams, fmt.Sprintf("can't apply overrides: %s", err))
+		}
+	}
 	err := ic.VM.Run()
 	var faultException string
 	if err != nil {
@@ -2417,7 +2912,7 @@ func (s *Server) runScriptInVM(t trigger.Type, script []byte, contractScriptHash
 		if s.config.SessionBackedByMPT && nextH == nil {
 			ic.Finalize()
 			// Rerun with MPT-backed storage.
-			return s.runScriptInVM(t, script, contractScriptHash, tx, &ic.Block.Index, verbose)
+			return s.runScriptInVM(t, script, contractScriptHash, tx, &nextIndex, verbose, overrides)
 		}
 		id = uuid.New()
 		sessionID := id.String()
@@ -2752,6 +3247,10 @@ func (s *Server) subscribe(reqParams params.Params, sub *subscriber) (any, *neor
 			flt := new(neorpc.NotaryRequestFilter)
 			err = jd.Decode(flt)
 			filter = *flt
+		case neorpc.MempoolEventID:
+			flt := new(neorpc.MempoolEventFilter)
+			err = jd.Decode(flt)
+			filter = *flt
 		case neorpc.NotificationEventID:
 			flt := new(neorpc.NotificationFilter)
 			err = jd.Decode(flt)
@@ -2829,6 +3328,11 @@ func (s *Server) subscribeToChannel(event neorpc.EventID) {
 			s.coreServer.SubscribeForNotaryRequests(s.notaryRequestCh)
 		}
 		s.notaryRequestSubs++
+	case neorpc.MempoolEventID:
+		if s.mempoolSubs == 0 {
+			s.chain.SubscribeForMempoolEvents(s.mempoolCh)
+		}
+		s.mempoolSubs++
 	case neorpc.HeaderOfAddedBlockEventID:
 		if s.blockHeaderSubs == 0 {
 			s.chain.SubscribeForHeadersOfAddedBlocks(s.blockHeaderCh)
@@ -2889,6 +3393,11 @@ func (s *Server) unsubscribeFromChannel(event neorpc.EventID) {
 		if s.notaryRequestSubs == 0 {
 			s.coreServer.UnsubscribeFromNotaryRequests(s.notaryRequestCh)
 		}
+	case neorpc.MempoolEventID:
+		s.mempoolSubs--
+		if s.mempoolSubs == 0 {
+			s.chain.UnsubscribeFromMempoolEvents(s.mempoolCh)
+		}
 	case neorpc.HeaderOfAddedBlockEventID:
 		s.blockHeaderSubs--
 		if s.blockHeaderSubs == 0 {
@@ -2943,6 +3452,13 @@ chloop:
 				Type:          e.Type,
 				NotaryRequest: e.Data.(*payload.P2PNotaryRequest),
 			}
+		case e := <-s.mempoolCh:
+			resp.Event = neorpc.MempoolEventID
+			resp.Payload[0] = &result.MempoolEvent{
+				Type:        e.Type,
+				Reason:      e.Reason,
+				Transaction: e.Tx,
+			}
 		case header := <-s.blockHeaderCh:
 			resp.Event = neorpc.HeaderOfAddedBlockEventID
 			resp.Payload[0] = header
@@ -2999,6 +3515,7 @@ chloop:
 	s.chain.UnsubscribeFromNotifications(s.notificationCh)
 	s.chain.UnsubscribeFromExecutions(s.executionCh)
 	s.chain.UnsubscribeFromHeadersOfAddedBlocks(s.blockHeaderCh)
+	s.chain.UnsubscribeFromMempoolEvents(s.mempoolCh)
 	if s.chain.P2PSigExtensionsEnabled() {
 		s.coreServer.UnsubscribeFromNotaryRequests(s.notaryRequestCh)
 	}
@@ -3011,6 +3528,7 @@ drainloop:
 		case <-s.notificationCh:
 		case <-s.transactionCh:
 		case <-s.notaryRequestCh:
+		case <-s.mempoolCh:
 		case <-s.blockHeaderCh:
 		default:
 			break drainloop
@@ -3023,6 +3541,7 @@ drainloop:
 	close(s.notificationCh)
 	close(s.executionCh)
 	close(s.notaryRequestCh)
+	close(s.mempoolCh)
 	close(s.blockHeaderCh)
 	// notify Shutdown routine
 	close(s.subEventsToExitCh)
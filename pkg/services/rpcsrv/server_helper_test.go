@@ -16,8 +16,11 @@ import (
 	"github.com/nspcc-dev/neo-go/pkg/core/native"
 	"github.com/nspcc-dev/neo-go/pkg/core/storage"
 	"github.com/nspcc-dev/neo-go/pkg/io"
+	"github.com/nspcc-dev/neo-go/pkg/neorpc"
+	"github.com/nspcc-dev/neo-go/pkg/neorpc/result"
 	"github.com/nspcc-dev/neo-go/pkg/network"
 	"github.com/nspcc-dev/neo-go/pkg/services/oracle"
+	"github.com/nspcc-dev/neo-go/pkg/services/rpcsrv/params"
 	"github.com/nspcc-dev/neo-go/pkg/util"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
@@ -163,6 +166,42 @@ func initServerWithInMemoryChainAndServices(t *testing.T, needOracle bool, needN
 
 type FeerStub struct{}
 
+type consensusStub struct {
+	state *result.ConsensusState
+	err   error
+}
+
+func (c *consensusStub) GetState() (*result.ConsensusState, error) {
+	return c.state, c.err
+}
+
+// generatorStub is a consensus handler running in instant mining mode.
+type generatorStub struct {
+	consensusStub
+	hashes []util.Uint256
+}
+
+func (g *generatorStub) GenerateBlocks(n int) ([]util.Uint256, error) {
+	if g.err != nil {
+		return nil, g.err
+	}
+	return g.hashes[:n], nil
+}
+
+// extensionStub handles a single "echo" method returning its parameters.
+type extensionStub struct{}
+
+func (extensionStub) HandleRPC(method string, reqParams params.Params) (any, *neorpc.Error, bool) {
+	switch method {
+	case "echo":
+		return reqParams, nil, true
+	case "fail":
+		return nil, neorpc.NewInternalServerError("failed"), true
+	default:
+		return nil, nil, false
+	}
+}
+
 func (fs *FeerStub) FeePerByte() int64 {
 	return 0
 }
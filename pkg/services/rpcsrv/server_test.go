@@ -6,6 +6,7 @@ import (
 	"encoding/base64"
 	"encoding/binary"
 	"encoding/json"
+	"errors"
 	"fmt"
 	gio "io"
 	"math"
@@ -31,6 +32,8 @@ import (
 	"github.com/nspcc-dev/neo-go/pkg/core/block"
 	"github.com/nspcc-dev/neo-go/pkg/core/fee"
 	"github.com/nspcc-dev/neo-go/pkg/core/interop/interopnames"
+	"github.com/nspcc-dev/neo-go/pkg/core/native"
+	"github.com/nspcc-dev/neo-go/pkg/core/native/nativehashes"
 	"github.com/nspcc-dev/neo-go/pkg/core/native/nativenames"
 	"github.com/nspcc-dev/neo-go/pkg/core/state"
 	"github.com/nspcc-dev/neo-go/pkg/core/storage/dboper"
@@ -46,6 +49,9 @@ import (
 	rpc2 "github.com/nspcc-dev/neo-go/pkg/services/oracle/broadcaster"
 	"github.com/nspcc-dev/neo-go/pkg/services/rpcsrv/params"
 	"github.com/nspcc-dev/neo-go/pkg/smartcontract"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract/callflag"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract/manifest"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract/nef"
 	"github.com/nspcc-dev/neo-go/pkg/smartcontract/trigger"
 	"github.com/nspcc-dev/neo-go/pkg/util"
 	"github.com/nspcc-dev/neo-go/pkg/vm/emit"
@@ -97,8 +103,38 @@ var (
 	nfsoHash, _           = util.Uint160DecodeStringLE(nfsoContractHash)
 	nfsoToken1ContainerID = util.Uint256{1, 2, 3}
 	nfsoToken1ObjectID    = util.Uint256{4, 5, 6}
+	overridesContractHash = util.Uint160{1, 2, 3}
+	gasBalanceOverrides   = `{"balances":[{"asset":"` + nativehashes.GasToken.StringLE() + `","account":"0x0000000000000000000000000000000000000001","amount":12345}]}`
 )
 
+// contractOverrides returns invocation overrides injecting the contract with
+// the `get` method returning "k" storage item and setting this item to "v".
+func contractOverrides() string {
+	w := io.NewBufBinWriter()
+	emit.Bytes(w.BinWriter, []byte("k"))
+	emit.Syscall(w.BinWriter, interopnames.SystemStorageGetReadOnlyContext)
+	emit.Syscall(w.BinWriter, interopnames.SystemStorageGet)
+	emit.Opcodes(w.BinWriter, opcode.RET)
+	ne, err := nef.NewFile(w.Bytes())
+	if err != nil {
+		panic(err)
+	}
+	nefBytes, err := ne.Bytes()
+	if err != nil {
+		panic(err)
+	}
+	m := manifest.NewManifest("Injected")
+	m.ABI.Methods = []manifest.Method{{Name: "get", ReturnType: smartcontract.ByteArrayType, Safe: true}}
+	b, err := json.Marshal(state.Overrides{
+		Contracts: []state.ContractOverride{{Hash: overridesContractHash, NEF: nefBytes, Manifest: *m}},
+		Storage:   []state.StorageOverride{{Hash: overridesContractHash, Key: []byte("k"), Value: []byte("v")}},
+	})
+	if err != nil {
+		panic(err)
+	}
+	return string(b)
+}
+
 var rpcFunctionsWithUnsupportedStatesTestCases = map[string][]rpcTestCase{
 	"getproof": {
 		{
@@ -557,6 +593,53 @@ var rpcTestCases = map[string][]rpcTestCase{
 			errCode: neorpc.ErrUnknownContractCode,
 		},
 	},
+	"getmempoolentries": {
+		{
+			name:    "invalid filter",
+			params:  `[{"receiver": "0x0000000000000000000000000000000000000000"}]`,
+			fail:    true,
+			errCode: neorpc.InvalidParamsCode,
+		},
+		{
+			name:    "invalid sender",
+			params:  `[{"sender": "notahash"}]`,
+			fail:    true,
+			errCode: neorpc.InvalidParamsCode,
+		},
+	},
+	"estimatepriorityfee": {
+		{
+			name:   "positive",
+			params: `[5]`,
+			result: func(*executor) any { return new(result.PriorityFee) },
+			check: func(t *testing.T, e *executor, resp any) {
+				res, ok := resp.(*result.PriorityFee)
+				require.True(t, ok)
+				require.Equal(t, e.chain.FeePerByte(), res.PolicyFeePerByte)
+				require.Equal(t, e.chain.GetMemPool().Capacity(), res.MempoolCapacity)
+				require.True(t, res.Blocks <= 5)
+				require.True(t, res.FeePerByte >= res.PolicyFeePerByte)
+			},
+		},
+		{
+			name:    "zero blocks",
+			params:  `[0]`,
+			fail:    true,
+			errCode: neorpc.InvalidParamsCode,
+		},
+		{
+			name:    "too many blocks",
+			params:  `[1001]`,
+			fail:    true,
+			errCode: neorpc.InvalidParamsCode,
+		},
+		{
+			name:    "invalid blocks",
+			params:  `["notanumber"]`,
+			fail:    true,
+			errCode: neorpc.InvalidParamsCode,
+		},
+	},
 	"findstates": {
 		{
 			name:    "no params",
@@ -1179,6 +1262,36 @@ var rpcTestCases = map[string][]rpcTestCase{
 			},
 		},
 	},
+	"getcommitteehistoric": {
+		{
+			name:   "positive, by index",
+			params: "[20]",
+			result: func(e *executor) any {
+				expected, _ := e.chain.GetCommitteeHistoric(20)
+				return &expected
+			},
+		},
+		{
+			name:   "positive, by stateroot",
+			params: `["` + block20StateRootLE + `"]`,
+			result: func(e *executor) any {
+				expected, _ := e.chain.GetCommitteeHistoric(20)
+				return &expected
+			},
+		},
+		{
+			name:    "no params",
+			params:  "[]",
+			fail:    true,
+			errCode: neorpc.InvalidParamsCode,
+		},
+		{
+			name:    "unknown block",
+			params:  `["` + random.Uint256().StringLE() + `"]`,
+			fail:    true,
+			errCode: neorpc.InvalidParamsCode,
+		},
+	},
 	"getconnectioncount": {
 		{
 			params: "[]",
@@ -1307,6 +1420,50 @@ var rpcTestCases = map[string][]rpcTestCase{
 			},
 		},
 	},
+	"getcandidateshistoric": {
+		{
+			name:   "positive, by index",
+			params: "[20]",
+			result: func(*executor) any {
+				return &[]result.Candidate{}
+			},
+		},
+		{
+			name:   "positive, by stateroot",
+			params: `["` + block20StateRootLE + `"]`,
+			result: func(*executor) any {
+				return &[]result.Candidate{}
+			},
+		},
+		{
+			name:    "no params",
+			params:  "[]",
+			fail:    true,
+			errCode: neorpc.InvalidParamsCode,
+		},
+	},
+	"getnextblockvalidatorshistoric": {
+		{
+			name:   "positive, by index",
+			params: "[20]",
+			result: func(*executor) any {
+				return &[]result.Validator{}
+			},
+		},
+		{
+			name:   "positive, by stateroot",
+			params: `["` + block20StateRootLE + `"]`,
+			result: func(*executor) any {
+				return &[]result.Validator{}
+			},
+		},
+		{
+			name:    "no params",
+			params:  "[]",
+			fail:    true,
+			errCode: neorpc.InvalidParamsCode,
+		},
+	},
 	"getnextblockvalidators": {
 		{
 			params: "[]",
@@ -1474,6 +1631,43 @@ var rpcTestCases = map[string][]rpcTestCase{
 				}
 			},
 		},
+		{
+			name:   "positive, balance overrides",
+			params: `["` + nativehashes.GasToken.StringLE() + `", "balanceOf", [{"type":"Hash160","value":"0x0000000000000000000000000000000000000001"}], [], true, ` + gasBalanceOverrides + `]`,
+			result: func(e *executor) any { return &result.Invoke{} },
+			check: func(t *testing.T, e *executor, inv any) {
+				res, ok := inv.(*result.Invoke)
+				require.True(t, ok)
+				require.Equal(t, "HALT", res.State)
+				require.Equal(t, []stackitem.Item{stackitem.Make(12345)}, res.Stack)
+				// Overrides are not a part of invocation changes.
+				require.Equal(t, []dboper.Operation{}, res.Diagnostics.Changes)
+			},
+		},
+		{
+			name:   "positive, contract and storage overrides",
+			params: `["` + overridesContractHash.StringLE() + `", "get", [], [], false, ` + contractOverrides() + `]`,
+			result: func(e *executor) any { return &result.Invoke{} },
+			check: func(t *testing.T, e *executor, inv any) {
+				res, ok := inv.(*result.Invoke)
+				require.True(t, ok)
+				require.Equal(t, "HALT", res.State)
+				require.Equal(t, 1, len(res.Stack))
+				require.Equal(t, []byte("v"), res.Stack[0].Value())
+			},
+		},
+		{
+			name:    "invalid overrides",
+			params:  `["` + nativehashes.GasToken.StringLE() + `", "symbol", [], [], false, {"unknown":1}]`,
+			fail:    true,
+			errCode: neorpc.InvalidParamsCode,
+		},
+		{
+			name:    "unsupported asset overrides",
+			params:  `["` + nativehashes.GasToken.StringLE() + `", "symbol", [], [], false, {"balances":[{"asset":"` + nnsContractHash + `","account":"0x0000000000000000000000000000000000000001","amount":1}]}]`,
+			fail:    true,
+			errCode: neorpc.InvalidParamsCode,
+		},
 		{
 			name:    "no params",
 			params:  `[]`,
@@ -1593,6 +1787,17 @@ var rpcTestCases = map[string][]rpcTestCase{
 				}
 			},
 		},
+		{
+			name:   "positive, balance overrides",
+			params: `[20, "` + nativehashes.GasToken.StringLE() + `", "balanceOf", [{"type":"Hash160","value":"0x0000000000000000000000000000000000000001"}], [], false, ` + gasBalanceOverrides + `]`,
+			result: func(e *executor) any { return &result.Invoke{} },
+			check: func(t *testing.T, e *executor, inv any) {
+				res, ok := inv.(*result.Invoke)
+				require.True(t, ok)
+				require.Equal(t, "HALT", res.State)
+				require.Equal(t, []stackitem.Item{stackitem.Make(12345)}, res.Stack)
+			},
+		},
 		{
 			name:    "no params",
 			params:  `[]`,
@@ -1719,6 +1924,21 @@ var rpcTestCases = map[string][]rpcTestCase{
 				assert.Equal(t, big.NewInt(1), res.Stack[0].Value())
 			},
 		},
+		{
+			name: "positive, block overrides",
+			params: func() string {
+				w := io.NewBufBinWriter()
+				emit.Syscall(w.BinWriter, interopnames.SystemRuntimeGetTime)
+				return `["` + base64.StdEncoding.EncodeToString(w.Bytes()) + `", [], false, {"block":{"timestamp":42}}]`
+			}(),
+			result: func(e *executor) any { return &result.Invoke{} },
+			check: func(t *testing.T, e *executor, inv any) {
+				res, ok := inv.(*result.Invoke)
+				require.True(t, ok)
+				require.Equal(t, "HALT", res.State)
+				require.Equal(t, []stackitem.Item{stackitem.Make(42)}, res.Stack)
+			},
+		},
 		{
 			name:    "no params",
 			params:  `[]`,
@@ -1738,6 +1958,113 @@ var rpcTestCases = map[string][]rpcTestCase{
 			errCode: neorpc.InvalidParamsCode,
 		},
 	},
+	"invokescripts": {
+		{
+			name: "positive",
+			params: func() string {
+				var (
+					to    = util.Uint160{1, 2, 3}
+					from  = testchain.MultisigScriptHash()
+					w     = io.NewBufBinWriter()
+					items = make([]neorpc.ScriptWithSigners, 3)
+				)
+				emit.AppCall(w.BinWriter, nativehashes.GasToken, "transfer", callflag.All, from, to, 1, nil)
+				items[0] = neorpc.ScriptWithSigners{Script: bytes.Clone(w.Bytes()), Signers: []transaction.Signer{{Account: from, Scopes: transaction.CalledByEntry}}}
+				items[1] = neorpc.ScriptWithSigners{Script: []byte{byte(opcode.ABORT)}}
+				w.Reset()
+				emit.AppCall(w.BinWriter, nativehashes.GasToken, "balanceOf", callflag.All, to)
+				items[2] = neorpc.ScriptWithSigners{Script: w.Bytes()}
+				b, err := json.Marshal(items)
+				if err != nil {
+					panic(err)
+				}
+				return `[` + string(b) + `]`
+			}(),
+			result: func(e *executor) any { return &[]*result.Invoke{} },
+			check: func(t *testing.T, e *executor, inv any) {
+				res, ok := inv.(*[]*result.Invoke)
+				require.True(t, ok)
+				require.Equal(t, 3, len(*res))
+				transfer, abort, balance := (*res)[0], (*res)[1], (*res)[2]
+				require.Equal(t, "HALT", transfer.State)
+				require.Equal(t, []stackitem.Item{stackitem.Make(true)}, transfer.Stack)
+				require.Equal(t, 1, len(transfer.Notifications))
+				require.Equal(t, "Transfer", transfer.Notifications[0].Name)
+				require.NotEqual(t, int64(0), transfer.GasConsumed)
+				require.Equal(t, "FAULT", abort.State)
+				require.NotEqual(t, "", abort.FaultException)
+				// The balance is changed by the first script.
+				require.Equal(t, "HALT", balance.State)
+				require.Equal(t, []stackitem.Item{stackitem.Make(1)}, balance.Stack)
+				// While the real state is not.
+				require.Equal(t, int64(0), e.chain.GetUtilityTokenBalance(util.Uint160{1, 2, 3}).Int64())
+			},
+		},
+		{
+			name: "positive, shared gas limit",
+			params: func() string {
+				// Infinite loop spending all the GAS available.
+				loop := []byte{byte(opcode.PUSH1), byte(opcode.NEWBUFFER), byte(opcode.DROP), byte(opcode.JMP), 0xfd}
+				return `[[{"script":"` + base64.StdEncoding.EncodeToString(loop) + `"},{"script":"EQ=="}]]`
+			}(),
+			result: func(e *executor) any { return &[]*result.Invoke{} },
+			check: func(t *testing.T, e *executor, inv any) {
+				res, ok := inv.(*[]*result.Invoke)
+				require.True(t, ok)
+				require.Equal(t, 2, len(*res))
+				require.Equal(t, "FAULT", (*res)[0].State)
+				require.NotEqual(t, int64(0), (*res)[0].GasConsumed)
+				// Nothing is left for the second script.
+				require.Equal(t, "FAULT", (*res)[1].State)
+				require.NotEqual(t, "", (*res)[1].FaultException)
+			},
+		},
+		{
+			name: "positive, with overrides",
+			params: func() string {
+				w := io.NewBufBinWriter()
+				emit.AppCall(w.BinWriter, nativehashes.GasToken, "balanceOf", callflag.All, util.Uint160{1})
+				return `[[{"script":"` + base64.StdEncoding.EncodeToString(w.Bytes()) + `"}], ` + gasBalanceOverrides + `]`
+			}(),
+			result: func(e *executor) any { return &[]*result.Invoke{} },
+			check: func(t *testing.T, e *executor, inv any) {
+				res, ok := inv.(*[]*result.Invoke)
+				require.True(t, ok)
+				require.Equal(t, 1, len(*res))
+				require.Equal(t, []stackitem.Item{stackitem.Make(12345)}, (*res)[0].Stack)
+			},
+		},
+		{
+			name:    "no params",
+			params:  `[]`,
+			fail:    true,
+			errCode: neorpc.InvalidParamsCode,
+		},
+		{
+			name:    "no scripts",
+			params:  `[[]]`,
+			fail:    true,
+			errCode: neorpc.InvalidParamsCode,
+		},
+		{
+			name:    "not an array",
+			params:  `["UcVrDUhlbGxvLCB3b3JsZCFoD05lby5SdW50aW1lLkxvZ2FsdWY="]`,
+			fail:    true,
+			errCode: neorpc.InvalidParamsCode,
+		},
+		{
+			name:    "unknown field",
+			params:  `[[{"script":"UQ==","foo":1}]]`,
+			fail:    true,
+			errCode: neorpc.InvalidParamsCode,
+		},
+		{
+			name:    "bad signers",
+			params:  `[[{"script":"UQ==","signers":[42]}]]`,
+			fail:    true,
+			errCode: neorpc.InvalidParamsCode,
+		},
+	},
 	"invokescripthistoric": {
 		{
 			name:   "positive, by index",
@@ -2545,6 +2872,7 @@ func runTestCasesWithExecutor(t *testing.T, e *executor, rpcCall string, method
 // scratch here.
 func testRPCProtocol(t *testing.T, doRPCCall func(string, string, *testing.T) []byte) {
 	chain, rpcSrv, httpSrv := initServerWithInMemoryChain(t)
+	testChainHeight := chain.BlockHeight()
 
 	e := &executor{chain: chain, httpSrv: httpSrv}
 	t.Run("single request", func(t *testing.T) {
@@ -2987,6 +3315,281 @@ func testRPCProtocol(t *testing.T, doRPCCall func(string, string, *testing.T) []
 		assert.ElementsMatch(t, expected, actual)
 	})
 
+	t.Run("estimatepriorityfee", func(t *testing.T) {
+		mp := chain.GetMemPool()
+		rpc := `{"jsonrpc": "2.0", "id": 1, "method": "estimatepriorityfee", "params": []}`
+		body := doRPCCall(rpc, httpSrv.URL, t)
+		res := checkErrGetResult(t, body, false, 0)
+
+		var actual result.PriorityFee
+		require.NoErrorf(t, json.Unmarshal(res, &actual), "could not parse response: %s", res)
+		require.Equal(t, chain.FeePerByte(), actual.PolicyFeePerByte)
+		require.Equal(t, mp.Count(), actual.MempoolCount)
+		require.Equal(t, mp.Capacity(), actual.MempoolCapacity)
+		require.True(t, actual.Blocks > 0 && actual.Blocks <= defaultPriorityFeeBlocks)
+		require.True(t, actual.BlockTransactions > 0)
+		require.True(t, actual.BlocksMinFeePerByte <= actual.BlocksMedianFeePerByte)
+		// The pool is far from being full.
+		require.Equal(t, actual.PolicyFeePerByte, actual.FeePerByte)
+	})
+
+	t.Run("getmempoolentries", func(t *testing.T) {
+		mp := chain.GetMemPool()
+		sender := util.Uint160{4, 5, 6}
+		signer := util.Uint160{7, 8, 9}
+		var hashes []util.Uint256
+		for i := 0; i < 3; i++ {
+			tx := transaction.New([]byte{byte(opcode.PUSH1)}, int64(i+1))
+			tx.NetworkFee = int64(1000 * (i + 1))
+			tx.Signers = []transaction.Signer{{Account: sender}}
+			if i == 2 {
+				tx.Signers = append(tx.Signers, transaction.Signer{Account: signer})
+				tx.Attributes = []transaction.Attribute{{
+					Type:  transaction.ConflictsT,
+					Value: &transaction.Conflicts{Hash: util.Uint256{1, 2, 3}},
+				}}
+			}
+			require.NoError(t, mp.Add(tx, &FeerStub{}))
+			hashes = append(hashes, tx.Hash())
+		}
+
+		getEntries := func(t *testing.T, params string) []result.MempoolEntry {
+			rpc := `{"jsonrpc": "2.0", "id": 1, "method": "getmempoolentries", "params": ` + params + `}`
+			body := doRPCCall(rpc, httpSrv.URL, t)
+			res := checkErrGetResult(t, body, false, 0)
+			var actual []result.MempoolEntry
+			require.NoErrorf(t, json.Unmarshal(res, &actual), "could not parse response: %s", res)
+			return actual
+		}
+
+		all := getEntries(t, `[]`)
+		require.Equal(t, mp.Count(), len(all))
+		for i, entry := range all {
+			require.Equal(t, i, entry.Position)
+			if i > 0 {
+				require.True(t, entry.FeePerByte <= all[i-1].FeePerByte)
+			}
+		}
+
+		byS := getEntries(t, `[{"sender": "`+sender.StringLE()+`"}]`)
+		require.Equal(t, 3, len(byS))
+		for i, entry := range byS {
+			tx, ok := mp.TryGetValue(entry.Hash)
+			require.True(t, ok)
+			require.Equal(t, sender, entry.Sender)
+			require.Equal(t, tx.FeePerByte(), entry.FeePerByte)
+			require.Equal(t, tx.NetworkFee, entry.NetworkFee)
+			require.Equal(t, tx.SystemFee, entry.SystemFee)
+			require.Equal(t, tx.Size(), entry.Size)
+			require.Equal(t, tx.ValidUntilBlock, entry.ValidUntilBlock)
+			require.Equal(t, int64(6+6000), entry.SenderFees)
+			require.Equal(t, all[entry.Position], byS[i])
+		}
+
+		bySigner := getEntries(t, `[{"signer": "`+signer.StringLE()+`"}]`)
+		require.Equal(t, 1, len(bySigner))
+		require.Equal(t, hashes[2], bySigner[0].Hash)
+		require.Equal(t, []util.Uint256{{1, 2, 3}}, bySigner[0].Conflicts)
+
+		none := getEntries(t, `[{"sender": "`+sender.StringLE()+`", "signer": "`+util.Uint160{}.StringLE()+`"}]`)
+		require.Equal(t, 0, len(none))
+	})
+
+	t.Run("getconsensusstate", func(t *testing.T) {
+		rpc := `{"jsonrpc": "2.0", "id": 1, "method": "getconsensusstate", "params": []}`
+		body := doRPCCall(rpc, httpSrv.URL, t)
+		checkErrGetResult(t, body, true, neorpc.ErrConsensusDisabledCode)
+
+		t.Cleanup(func() { rpcSrv.SetConsensusHandler(nil) })
+		rpcSrv.SetConsensusHandler(struct{}{}) // No state provided.
+		body = doRPCCall(rpc, httpSrv.URL, t)
+		checkErrGetResult(t, body, true, neorpc.ErrConsensusDisabledCode)
+
+		rpcSrv.SetConsensusHandler(&consensusStub{err: errors.New("not running")})
+		body = doRPCCall(rpc, httpSrv.URL, t)
+		checkErrGetResult(t, body, true, neorpc.ErrConsensusDisabledCode, "not running")
+
+		expected := &result.ConsensusState{
+			Height:  5,
+			View:    1,
+			Primary: 3,
+			Index:   0,
+			Validators: []result.ConsensusValidator{{
+				Index:       0,
+				PublicKey:   testchain.PrivateKeyByID(0).PublicKey(),
+				Preparation: true,
+				ChangeView:  &result.ConsensusChangeView{NewView: 2, Reason: "Timeout"},
+				LastSeen:    &result.ConsensusHeightView{Height: 5, View: 1},
+			}},
+			Rounds: []result.ConsensusRound{
+				{Height: 5, Primary: 4, Start: 1000, Duration: 15000, Result: result.ConsensusRoundChangeView, Reason: "Timeout"},
+				{Height: 5, View: 1, Primary: 3, Start: 16000, Duration: 100},
+			},
+		}
+		rpcSrv.SetConsensusHandler(&consensusStub{state: expected})
+		body = doRPCCall(rpc, httpSrv.URL, t)
+		res := checkErrGetResult(t, body, false, 0)
+		actual := new(result.ConsensusState)
+		require.NoError(t, json.Unmarshal(res, actual))
+		require.Equal(t, expected, actual)
+	})
+
+	t.Run("generateblocks", func(t *testing.T) {
+		rpc := `{"jsonrpc": "2.0", "id": 1, "method": "generateblocks", "params": [%s]}`
+		body := doRPCCall(fmt.Sprintf(rpc, ""), httpSrv.URL, t)
+		checkErrGetResult(t, body, true, neorpc.ErrConsensusDisabledCode)
+
+		t.Cleanup(func() { rpcSrv.SetConsensusHandler(nil) })
+		rpcSrv.SetConsensusHandler(&consensusStub{})
+		body = doRPCCall(fmt.Sprintf(rpc, ""), httpSrv.URL, t)
+		checkErrGetResult(t, body, true, neorpc.ErrInstantMiningDisabledCode)
+
+		gen := &generatorStub{hashes: []util.Uint256{random.Uint256(), random.Uint256()}}
+		rpcSrv.SetConsensusHandler(gen)
+		for _, p := range []string{"0", "-1", "1001", `"abc"`} {
+			body = doRPCCall(fmt.Sprintf(rpc, p), httpSrv.URL, t)
+			checkErrGetResult(t, body, true, neorpc.InvalidParamsCode)
+		}
+
+		body = doRPCCall(fmt.Sprintf(rpc, ""), httpSrv.URL, t)
+		res := checkErrGetResult(t, body, false, 0)
+		var actual []util.Uint256
+		require.NoError(t, json.Unmarshal(res, &actual))
+		require.Equal(t, gen.hashes[:1], actual)
+
+		body = doRPCCall(fmt.Sprintf(rpc, "2"), httpSrv.URL, t)
+		res = checkErrGetResult(t, body, false, 0)
+		require.NoError(t, json.Unmarshal(res, &actual))
+		require.Equal(t, gen.hashes, actual)
+
+		gen.err = errors.New("not enough keys")
+		body = doRPCCall(fmt.Sprintf(rpc, "1"), httpSrv.URL, t)
+		checkErrGetResult(t, body, true, neorpc.InternalServerErrorCode, "not enough keys")
+	})
+
+	t.Run("extension", func(t *testing.T) {
+		rpc := `{"jsonrpc": "2.0", "id": 1, "method": "%s", "params": [1, "two"]}`
+		body := doRPCCall(fmt.Sprintf(rpc, "echo"), httpSrv.URL, t)
+		checkErrGetResult(t, body, true, neorpc.MethodNotFoundCode)
+
+		t.Cleanup(func() { rpcSrv.SetExtensionHandler(nil) })
+		rpcSrv.SetExtensionHandler(extensionStub{})
+		body = doRPCCall(fmt.Sprintf(rpc, "echo"), httpSrv.URL, t)
+		res := checkErrGetResult(t, body, false, 0)
+		require.JSONEq(t, `[1, "two"]`, string(res))
+
+		body = doRPCCall(fmt.Sprintf(rpc, "fail"), httpSrv.URL, t)
+		checkErrGetResult(t, body, true, neorpc.InternalServerErrorCode, "failed")
+
+		body = doRPCCall(fmt.Sprintf(rpc, "unknown"), httpSrv.URL, t)
+		checkErrGetResult(t, body, true, neorpc.MethodNotFoundCode)
+	})
+
+	t.Run("getstatediff", func(t *testing.T) {
+		rpc := `{"jsonrpc": "2.0", "id": 1, "method": "getstatediff", "params": [%s]}`
+		body := doRPCCall(fmt.Sprintf(rpc, ""), httpSrv.URL, t)
+		checkErrGetResult(t, body, true, neorpc.InvalidParamsCode)
+		body = doRPCCall(fmt.Sprintf(rpc, `"`+random.Uint256().StringLE()+`"`), httpSrv.URL, t)
+		checkErrGetResult(t, body, true, neorpc.ErrUnknownBlockCode)
+		body = doRPCCall(fmt.Sprintf(rpc, strconv.Itoa(int(chain.BlockHeight()+1))), httpSrv.URL, t)
+		checkErrGetResult(t, body, true, neorpc.ErrUnknownHeightCode)
+
+		h, err := util.Uint256DecodeStringLE(deploymentTxHash)
+		require.NoError(t, err)
+		_, height, err := chain.GetTransaction(h)
+		require.NoError(t, err)
+		expected, err := chain.GetStateDiff(height)
+		require.NoError(t, err)
+		require.NotEqual(t, 0, len(expected.Contracts))
+		for _, p := range []string{strconv.Itoa(int(height)), `"` + chain.GetHeaderHash(height).StringLE() + `"`} {
+			body = doRPCCall(fmt.Sprintf(rpc, p), httpSrv.URL, t)
+			res := checkErrGetResult(t, body, false, 0)
+			var actual state.StateDiff
+			require.NoError(t, json.Unmarshal(res, &actual))
+			require.Equal(t, *expected, actual)
+		}
+	})
+
+	t.Run("tracetransaction", func(t *testing.T) {
+		rpc := `{"jsonrpc": "2.0", "id": 1, "method": "tracetransaction", "params": [%s]}`
+		for _, p := range []string{"", `"abc"`, `"` + deploymentTxHash + `", {}`} {
+			body := doRPCCall(fmt.Sprintf(rpc, p), httpSrv.URL, t)
+			checkErrGetResult(t, body, true, neorpc.InvalidParamsCode)
+		}
+		body := doRPCCall(fmt.Sprintf(rpc, `"`+random.Uint256().StringLE()+`"`), httpSrv.URL, t)
+		checkErrGetResult(t, body, true, neorpc.ErrUnknownTransactionCode)
+
+		// Trace results must match application logs for all transactions of
+		// the test chain (other tests add some more blocks).
+		var nested, changed bool
+		for i := uint32(1); i <= testChainHeight; i++ {
+			b, err := chain.GetBlock(chain.GetHeaderHash(i))
+			require.NoError(t, err)
+			for _, tx := range b.Transactions {
+				body = doRPCCall(fmt.Sprintf(rpc, `"`+tx.Hash().StringLE()+`"`), httpSrv.URL, t)
+				res := checkErrGetResult(t, body, false, 0)
+				var actual result.TransactionTrace
+				require.NoError(t, json.Unmarshal(res, &actual))
+
+				aer, err := chain.GetAppExecResults(tx.Hash(), trigger.Application)
+				require.NoError(t, err)
+				require.Equal(t, tx.Hash(), actual.TxHash)
+				require.Equal(t, i, actual.BlockIndex)
+				require.Equal(t, aer[0].VMState.String(), actual.VMState, tx.Hash().StringLE())
+				require.Equal(t, aer[0].GasConsumed, actual.GasConsumed, tx.Hash().StringLE())
+				require.Equal(t, aer[0].FaultException, actual.FaultException)
+				require.Nil(t, actual.Steps)
+				require.Equal(t, actual.VMState, actual.Call.State)
+				require.Equal(t, actual.GasConsumed, actual.Call.GasConsumed)
+				for _, c := range actual.Call.Calls {
+					require.True(t, c.GasConsumed <= actual.GasConsumed)
+					nested = nested || len(c.Calls) != 0
+				}
+				if aer[0].VMState == vmstate.Fault {
+					require.Equal(t, 0, len(actual.StorageChanges))
+				}
+				changed = changed || len(actual.StorageChanges) != 0
+			}
+		}
+		require.True(t, nested)
+		require.True(t, changed)
+
+		t.Run("deployment", func(t *testing.T) {
+			body := doRPCCall(fmt.Sprintf(rpc, `"`+deploymentTxHash+`", true`), httpSrv.URL, t)
+			res := checkErrGetResult(t, body, false, 0)
+			var actual result.TransactionTrace
+			require.NoError(t, json.Unmarshal(res, &actual))
+			require.Equal(t, vmstate.Halt.String(), actual.VMState)
+			require.Equal(t, 1, len(actual.Call.Calls))
+			deploy := actual.Call.Calls[0]
+			require.Equal(t, nativehashes.ContractManagement, deploy.Contract)
+			require.Equal(t, "deploy", deploy.Method)
+			require.Equal(t, 3, len(deploy.Arguments))
+			require.Equal(t, vmstate.Halt.String(), deploy.State)
+			require.NotNil(t, deploy.Result)
+
+			cs := new(state.Contract)
+			require.NoError(t, cs.FromStackItem(deploy.Result))
+			require.NotNil(t, chain.GetContractState(cs.Hash))
+			var found bool
+			for _, ch := range actual.StorageChanges {
+				if ch.ID == native.ManagementContractID && bytes.Equal(ch.Key, native.MakeContractKey(cs.Hash)) {
+					found = true
+					require.Nil(t, ch.OldValue)
+					require.NotNil(t, ch.NewValue)
+				}
+			}
+			require.True(t, found)
+
+			require.NotEqual(t, 0, len(actual.Steps))
+			require.False(t, actual.StepsTruncated)
+			require.Equal(t, actual.Call.Contract, actual.Steps[0].Contract)
+			require.Equal(t, 0, actual.Steps[0].Offset)
+			require.Equal(t, 1, actual.Steps[0].Depth)
+			require.Equal(t, int64(0), actual.Steps[0].GasConsumed)
+		})
+	})
+
 	t.Run("getnep17transfers", func(t *testing.T) {
 		testNEP17T := func(t *testing.T, start, stop, limit, page int, sent, rcvd []int) {
 			ps := []string{`"` + testchain.PrivateKeyByID(0).Address() + `"`}
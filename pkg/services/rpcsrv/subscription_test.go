@@ -12,6 +12,7 @@ import (
 	"github.com/nspcc-dev/neo-go/internal/testchain"
 	"github.com/nspcc-dev/neo-go/pkg/config"
 	"github.com/nspcc-dev/neo-go/pkg/core"
+	"github.com/nspcc-dev/neo-go/pkg/core/transaction"
 	"github.com/nspcc-dev/neo-go/pkg/encoding/address"
 	"github.com/nspcc-dev/neo-go/pkg/neorpc"
 	"github.com/nspcc-dev/neo-go/pkg/util"
@@ -129,7 +130,7 @@ func callUnsubscribe(t *testing.T, ws *websocket.Conn, msgs <-chan []byte, id st
 
 func TestSubscriptions(t *testing.T) {
 	var subIDs = make([]string, 0)
-	var subFeeds = []string{"block_added", "transaction_added", "notification_from_execution", "transaction_executed", "notary_request_event", "header_of_added_block"}
+	var subFeeds = []string{"block_added", "transaction_added", "notification_from_execution", "transaction_executed", "notary_request_event", "header_of_added_block", "mempool_event"}
 
 	chain, rpcSrv, c, respMsgs := initCleanServerAndWSClient(t, true)
 
@@ -451,6 +452,66 @@ func TestFilteredNotaryRequestSubscriptions(t *testing.T) {
 	}
 }
 
+func TestMempoolEventSubscriptions(t *testing.T) {
+	chain, _, c, respMsgs := initCleanServerAndWSClient(t)
+
+	getEvent := func(t *testing.T) map[string]any {
+		var resp = new(neorpc.Notification)
+		select {
+		case body := <-respMsgs:
+			require.NoError(t, json.Unmarshal(body, resp))
+		case <-time.After(time.Second):
+			t.Fatal("timeout waiting for event")
+		}
+		require.Equal(t, neorpc.MempoolEventID, resp.Event)
+		return resp.Payload[0].(map[string]any)
+	}
+	newTx := func(t *testing.T, nonce uint32, vub uint32) *transaction.Transaction {
+		tx, err := testchain.NewTransferFromOwner(chain, chain.UtilityTokenHash(), util.Uint160{1, 2, 3}, 1, nonce, vub)
+		require.NoError(t, err)
+		return tx
+	}
+
+	allID := callSubscribe(t, c, respMsgs, `["mempool_event"]`)
+	resp := callWSGetRaw(t, c, `{"jsonrpc": "2.0","method": "subscribe","params": ["mempool_event", {"type":"added", "reason":"evicted"}],"id": 1}`, respMsgs)
+	require.NotNil(t, resp.Error)
+
+	tx1 := newTx(t, 1, chain.BlockHeight()+10)
+	tx2 := newTx(t, 2, chain.BlockHeight()+1)
+	require.NoError(t, chain.PoolTx(tx1))
+	ev := getEvent(t)
+	require.Equal(t, "added", ev["type"])
+	require.Nil(t, ev["reason"])
+	require.Equal(t, "0x"+tx1.Hash().StringLE(), ev["transaction"].(map[string]any)["hash"])
+	require.NoError(t, chain.PoolTx(tx2))
+	require.Equal(t, "added", getEvent(t)["type"])
+
+	// tx1 is included and tx2 expires with the next block.
+	require.NoError(t, chain.AddBlock(testchain.NewBlock(t, chain, 1, 0, tx1)))
+	var reasons = make(map[string]string)
+	for i := 0; i < 2; i++ {
+		ev := getEvent(t)
+		require.Equal(t, "removed", ev["type"])
+		reasons[ev["transaction"].(map[string]any)["hash"].(string)] = ev["reason"].(string)
+	}
+	require.Equal(t, map[string]string{
+		"0x" + tx1.Hash().StringLE(): "included",
+		"0x" + tx2.Hash().StringLE(): "expired",
+	}, reasons)
+	callUnsubscribe(t, c, respMsgs, allID)
+
+	// Filtered subscription.
+	includedID := callSubscribe(t, c, respMsgs, `["mempool_event", {"sender":"`+testchain.MultisigScriptHash().StringLE()+`", "type":"removed", "reason":"included"}]`)
+	tx3 := newTx(t, 3, chain.BlockHeight()+10)
+	require.NoError(t, chain.PoolTx(tx3))
+	require.NoError(t, chain.AddBlock(testchain.NewBlock(t, chain, 1, 0, tx3)))
+	ev = getEvent(t)
+	require.Equal(t, "removed", ev["type"])
+	require.Equal(t, "included", ev["reason"])
+	require.Equal(t, "0x"+tx3.Hash().StringLE(), ev["transaction"].(map[string]any)["hash"])
+	callUnsubscribe(t, c, respMsgs, includedID)
+}
+
 func TestFilteredBlockSubscriptions(t *testing.T) {
 	// We can't fit this into TestFilteredSubscriptions, because it uses
 	// blocks as EOF events to wait for.
@@ -0,0 +1,228 @@
+package rpcsrv
+
+import (
+	"bytes"
+	"encoding/binary"
+	"sort"
+
+	"github.com/nspcc-dev/neo-go/pkg/core/interop"
+	"github.com/nspcc-dev/neo-go/pkg/core/interop/interopnames"
+	istorage "github.com/nspcc-dev/neo-go/pkg/core/interop/storage"
+	"github.com/nspcc-dev/neo-go/pkg/neorpc/result"
+	"github.com/nspcc-dev/neo-go/pkg/vm"
+	"github.com/nspcc-dev/neo-go/pkg/vm/opcode"
+	"github.com/nspcc-dev/neo-go/pkg/vm/vmstate"
+)
+
+// maxTraceSteps is the maximum number of instructions returned by
+// tracetransaction.
+const maxTraceSteps = 100000
+
+// tracer collects the execution trace using VM hooks.
+type tracer struct {
+	ic        *interop.Context
+	withSteps bool
+	trace     *result.TransactionTrace
+	// frames is the stack of currently executed calls.
+	frames []*traceFrame
+}
+
+// traceFrame is a call being executed.
+type traceFrame struct {
+	call     *result.TraceCall
+	gasStart int64
+	// params is the number of arguments to be collected before the first
+	// instruction of the call, it's -1 when they're collected.
+	params int
+}
+
+// newTracer attaches a new tracer to the interop context which VM has the
+// transaction script loaded.
+func newTracer(ic *interop.Context, withSteps bool) *tracer {
+	t := &tracer{
+		ic:        ic,
+		withSteps: withSteps,
+		trace:     new(result.TransactionTrace),
+	}
+	v := ic.VM
+	t.trace.Call = &result.TraceCall{Contract: v.Context().ScriptHash()}
+	t.frames = []*traceFrame{{call: t.trace.Call, params: -1}}
+	v.OnLoad = t.onLoad
+	v.OnUnload = t.onUnload
+	v.SyscallHandler = t.syscallHandler
+	v.SetPriceGetter(t.getPrice)
+	return t
+}
+
+// run executes the script and returns the trace.
+func (t *tracer) run() *result.TransactionTrace {
+	var (
+		v    = t.ic.VM
+		base = t.ic.DAO
+	)
+	t.ic.DAO = base.GetPrivate()
+	err := t.ic.Exec()
+	t.trace.VMState = v.State().String()
+	t.trace.GasConsumed = v.GasConsumed()
+	t.trace.Stack = v.Estack().ToArray()
+	if err != nil {
+		t.trace.FaultException = err.Error()
+	}
+	// Frames are left on the stack if VM has failed.
+	for len(t.frames) > 0 {
+		f := t.frames[len(t.frames)-1]
+		f.call.State = vmstate.Fault.String()
+		f.call.GasConsumed = v.GasConsumed() - f.gasStart
+		t.frames = t.frames[:len(t.frames)-1]
+	}
+	t.trace.StorageChanges = []result.TraceStorageChange{}
+	if !v.HasFailed() {
+		for k, val := range t.ic.DAO.Store.GetStorageChanges() {
+			var (
+				id  = int32(binary.LittleEndian.Uint32([]byte(k[1:5])))
+				key = []byte(k[5:])
+			)
+			t.trace.StorageChanges = append(t.trace.StorageChanges, result.TraceStorageChange{
+				ID:       id,
+				Key:      key,
+				OldValue: base.GetStorageItem(id, key),
+				NewValue: val,
+			})
+		}
+		sort.Slice(t.trace.StorageChanges, func(i, j int) bool {
+			a, b := t.trace.StorageChanges[i], t.trace.StorageChanges[j]
+			if a.ID != b.ID {
+				return a.ID < b.ID
+			}
+			return bytes.Compare(a.Key, b.Key) < 0
+		})
+	}
+	return t.trace
+}
+
+func (t *tracer) top() *traceFrame {
+	return t.frames[len(t.frames)-1]
+}
+
+func (t *tracer) onLoad(ctx *vm.Context) {
+	var (
+		call = &result.TraceCall{Contract: ctx.ScriptHash()}
+		f    = &traceFrame{call: call, gasStart: t.ic.VM.GasConsumed(), params: -1}
+	)
+	if m := ctx.GetManifest(); m != nil {
+		for i := range m.ABI.Methods {
+			if m.ABI.Methods[i].Offset == ctx.NextIP() {
+				call.Method = m.ABI.Methods[i].Name
+				f.params = len(m.ABI.Methods[i].Parameters)
+				break
+			}
+		}
+	}
+	parent := t.top().call
+	parent.Calls = append(parent.Calls, call)
+	t.frames = append(t.frames, f)
+}
+
+func (t *tracer) onUnload(ctx *vm.Context, commit bool) {
+	f := t.top()
+	f.call.GasConsumed = t.ic.VM.GasConsumed() - f.gasStart
+	if commit {
+		f.call.State = vmstate.Halt.String()
+		if es := ctx.Estack(); es.Len() > 0 {
+			f.call.Result = es.Peek(0).Item()
+		}
+	} else {
+		f.call.State = vmstate.Fault.String()
+	}
+	t.frames = t.frames[:len(t.frames)-1]
+}
+
+// getPrice is called before every instruction execution.
+func (t *tracer) getPrice(op opcode.Opcode, param []byte) int64 {
+	var (
+		v   = t.ic.VM
+		ctx = v.Context()
+		f   = t.top()
+	)
+	if f.params >= 0 {
+		// Arguments are pushed after the call context is loaded, so they're
+		// collected before the first instruction.
+		es := ctx.Estack()
+		for i := 0; i < f.params && i < es.Len(); i++ {
+			f.call.Arguments = append(f.call.Arguments, es.Peek(i).Item())
+		}
+		f.params = -1
+	}
+	if t.withSteps {
+		if len(t.trace.Steps) < maxTraceSteps {
+			t.trace.Steps = append(t.trace.Steps, result.TraceStep{
+				Contract:    ctx.ScriptHash(),
+				Offset:      ctx.IP(),
+				Opcode:      op.String(),
+				Depth:       len(v.Istack()),
+				GasConsumed: v.GasConsumed(),
+			})
+		} else {
+			t.trace.StepsTruncated = true
+		}
+	}
+	return t.ic.GetPrice(op, param)
+}
+
+func (t *tracer) syscallHandler(v *vm.VM, id uint32) error {
+	name, err := interopnames.FromID(id)
+	if err != nil {
+		return t.ic.SyscallHandler(v, id)
+	}
+	var (
+		call = t.top().call
+		op   *result.TraceStorageOp
+	)
+	call.Syscalls = append(call.Syscalls, name)
+	switch name {
+	case interopnames.SystemStorageGet:
+		op = t.storageOp(result.TraceStorageGet)
+	case interopnames.SystemStoragePut:
+		if op = t.storageOp(result.TraceStoragePut); op != nil && v.Estack().Len() > 2 {
+			op.Value, _ = v.Estack().Peek(2).Item().TryBytes()
+		}
+	case interopnames.SystemStorageDelete:
+		op = t.storageOp(result.TraceStorageDelete)
+	case interopnames.SystemStorageFind:
+		op = t.storageOp(result.TraceStorageFind)
+	}
+	if err = t.ic.SyscallHandler(v, id); err != nil || op == nil {
+		return err
+	}
+	if op.Type == result.TraceStorageGet && v.Estack().Len() > 0 {
+		op.Value, _ = v.Estack().Peek(0).Item().TryBytes()
+	}
+	call.Storage = append(call.Storage, *op)
+	return nil
+}
+
+// storageOp creates a storage operation from storage syscall arguments, it
+// returns nil if they're invalid (syscall fails then).
+func (t *tracer) storageOp(typ string) *result.TraceStorageOp {
+	es := t.ic.VM.Estack()
+	if es.Len() < 2 {
+		return nil
+	}
+	sc, ok := es.Peek(0).Value().(*istorage.Context)
+	if !ok {
+		return nil
+	}
+	key, err := es.Peek(1).Item().TryBytes()
+	if err != nil {
+		return nil
+	}
+	op := &result.TraceStorageOp{
+		Type: typ,
+		ID:   sc.ID,
+		Key:  bytes.Clone(key),
+	}
+	if typ == result.TraceStoragePut || typ == result.TraceStorageDelete {
+		op.OldValue = t.ic.DAO.GetStorageItem(sc.ID, key)
+	}
+	return op
+}
@@ -0,0 +1,200 @@
+package signer
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/nspcc-dev/neo-go/pkg/config"
+	"github.com/nspcc-dev/neo-go/pkg/config/netmode"
+	"github.com/nspcc-dev/neo-go/pkg/core/block"
+	"github.com/nspcc-dev/neo-go/pkg/core/state"
+	"github.com/nspcc-dev/neo-go/pkg/crypto/hash"
+	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
+	nio "github.com/nspcc-dev/neo-go/pkg/io"
+	"github.com/nspcc-dev/neo-go/pkg/network/payload"
+)
+
+const (
+	// unixPrefix is the address prefix used for Unix domain sockets.
+	unixPrefix = "unix:"
+	// defaultTimeout is the request timeout used if it's not configured.
+	defaultTimeout = 5 * time.Second
+	// maxResponseSize is the maximum size of the response accepted from the
+	// signer.
+	maxResponseSize = 1 << 16
+)
+
+// Client is a Signer implementation talking to the remote signer via HTTP
+// (either over TCP with optional TLS or over Unix domain socket).
+type Client struct {
+	cli      http.Client
+	endpoint string
+	token    string
+}
+
+// signable is an item that can be sent to the signer.
+type signable interface {
+	hash.Hashable
+	nio.Serializable
+}
+
+var _ Signer = (*Client)(nil)
+
+// NewClient returns a new remote signer client for the given configuration.
+// No connection is made until the first request.
+func NewClient(cfg config.RemoteSigner) (*Client, error) {
+	var c = &Client{
+		cli:   http.Client{Timeout: cfg.Timeout},
+		token: cfg.Token,
+	}
+	if c.cli.Timeout <= 0 {
+		c.cli.Timeout = defaultTimeout
+	}
+	switch {
+	case strings.HasPrefix(cfg.Address, unixPrefix):
+		path := socketPath(cfg.Address)
+		if len(path) == 0 {
+			return nil, errors.New("empty socket path")
+		}
+		var d net.Dialer
+		c.cli.Transport = &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				return d.DialContext(ctx, "unix", path)
+			},
+		}
+		c.endpoint = "http://signer"
+	case strings.HasPrefix(cfg.Address, "http://"):
+		if len(cfg.Token) != 0 {
+			return nil, errors.New("token can't be sent over plain HTTP, use https signer address")
+		}
+		c.endpoint = strings.TrimRight(cfg.Address, "/")
+	case strings.HasPrefix(cfg.Address, "https://"):
+		if len(cfg.CAFile) != 0 {
+			pem, err := os.ReadFile(cfg.CAFile)
+			if err != nil {
+				return nil, fmt.Errorf("can't read CA file: %w", err)
+			}
+			roots := x509.NewCertPool()
+			if !roots.AppendCertsFromPEM(pem) {
+				return nil, fmt.Errorf("no certificates in CA file %s", cfg.CAFile)
+			}
+			c.cli.Transport = &http.Transport{
+				TLSClientConfig: &tls.Config{RootCAs: roots, MinVersion: tls.VersionTLS12},
+			}
+		}
+		c.endpoint = strings.TrimRight(cfg.Address, "/")
+	default:
+		return nil, fmt.Errorf("unsupported signer address %q", cfg.Address)
+	}
+	return c, nil
+}
+
+// socketPath returns the Unix socket path from the given address.
+func socketPath(addr string) string {
+	path := strings.TrimPrefix(addr, unixPrefix)
+	return strings.TrimPrefix(path, "//")
+}
+
+// PublicKeys implements the Signer interface.
+func (c *Client) PublicKeys() (keys.PublicKeys, error) {
+	var res keysResponse
+	if err := c.do(http.MethodGet, keysPath, nil, &res); err != nil {
+		return nil, err
+	}
+	return res.Keys, nil
+}
+
+// SignBlock implements the Signer interface.
+func (c *Client) SignBlock(pub *keys.PublicKey, net netmode.Magic, h *block.Header) ([]byte, error) {
+	return c.sign(pub, KindBlock, net, h)
+}
+
+// SignStateRoot implements the Signer interface.
+func (c *Client) SignStateRoot(pub *keys.PublicKey, net netmode.Magic, r *state.MPTRoot) ([]byte, error) {
+	return c.sign(pub, KindStateRoot, net, r)
+}
+
+// SignExtensible implements the Signer interface.
+func (c *Client) SignExtensible(pub *keys.PublicKey, net netmode.Magic, p *payload.Extensible) ([]byte, error) {
+	return c.sign(pub, KindPayload, net, p)
+}
+
+// sign sends the serialized item to the signer and checks the signature
+// returned.
+func (c *Client) sign(pub *keys.PublicKey, kind Kind, net netmode.Magic, item signable) ([]byte, error) {
+	w := nio.NewBufBinWriter()
+	item.EncodeBinary(w.BinWriter)
+	if w.Err != nil {
+		return nil, fmt.Errorf("can't encode %s: %w", kind, w.Err)
+	}
+	var res signResponse
+	err := c.do(http.MethodPost, signPath, &signRequest{
+		Key:     pub,
+		Kind:    kind,
+		Network: net,
+		Data:    w.Bytes(),
+	}, &res)
+	if err != nil {
+		return nil, err
+	}
+	if !pub.VerifyHashable(res.Signature, uint32(net), item) {
+		return nil, errors.New("invalid signature returned by signer")
+	}
+	return res.Signature, nil
+}
+
+func (c *Client) do(method string, path string, req any, res any) error {
+	var body io.Reader
+	if req != nil {
+		data, err := json.Marshal(req)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(data)
+	}
+	r, err := http.NewRequest(method, c.endpoint+path, body)
+	if err != nil {
+		return err
+	}
+	r.Header.Set("Content-Type", "application/json")
+	if len(c.token) != 0 {
+		r.Header.Set("Authorization", authScheme+c.token)
+	}
+	resp, err := c.cli.Do(r)
+	if err != nil {
+		return fmt.Errorf("signer request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+	if err != nil {
+		return fmt.Errorf("can't read signer response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		var e errorResponse
+		if err := json.Unmarshal(data, &e); err != nil || len(e.Error) == 0 {
+			e.Error = resp.Status
+		}
+		switch resp.StatusCode {
+		case http.StatusConflict:
+			return fmt.Errorf("%w: %s", ErrDoubleSign, strings.TrimPrefix(e.Error, ErrDoubleSign.Error()+": "))
+		case http.StatusNotFound:
+			return fmt.Errorf("%w: %s", ErrUnknownKey, strings.TrimPrefix(e.Error, ErrUnknownKey.Error()+": "))
+		case http.StatusBadRequest:
+			return fmt.Errorf("%w: %s", ErrInvalidData, strings.TrimPrefix(e.Error, ErrInvalidData.Error()+": "))
+		default:
+			return fmt.Errorf("signer error: %s", e.Error)
+		}
+	}
+	return json.Unmarshal(data, res)
+}
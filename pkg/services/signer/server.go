@@ -0,0 +1,275 @@
+package signer
+
+import (
+	"bytes"
+	"crypto/subtle"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	gio "io"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/nspcc-dev/neo-go/pkg/config/netmode"
+	"github.com/nspcc-dev/neo-go/pkg/core/block"
+	"github.com/nspcc-dev/neo-go/pkg/core/state"
+	"github.com/nspcc-dev/neo-go/pkg/core/storage"
+	"github.com/nspcc-dev/neo-go/pkg/crypto/hash"
+	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
+	"github.com/nspcc-dev/neo-go/pkg/io"
+	"github.com/nspcc-dev/neo-go/pkg/network/payload"
+	"go.uber.org/zap"
+)
+
+// maxRequestSize is the maximum size of the signing request, it's big enough
+// for the base64-encoded extensible payload of the maximum size.
+const maxRequestSize = 2 * payload.MaxSize
+
+// stateServiceCategory is the extensible payload category used by the state
+// root service, it can't be imported from the stateroot package because of
+// the dependency cycle.
+const stateServiceCategory = "StateService"
+
+// ServerConfig contains signer server settings.
+type ServerConfig struct {
+	// Network is the magic of the network the signer works for, requests
+	// for any other network are refused.
+	Network netmode.Magic
+	// StateRootInHeader must match the same protocol setting of the network,
+	// it's needed to decode block headers.
+	StateRootInHeader bool
+	// Token is the secret clients must present in the Authorization header
+	// (as "Bearer <token>"). Requests are not authenticated if it's empty.
+	Token string
+}
+
+// Server is the reference signer implementation serving signing requests via
+// HTTP. It keeps the double-signing protection data in the given store, every
+// protected signature is recorded there before it's returned to the client.
+type Server struct {
+	cfg  ServerConfig
+	log  *zap.Logger
+	keys map[string]*keys.PrivateKey
+	pubs keys.PublicKeys
+
+	// lock serializes signing requests, so that protection data is always
+	// consistent.
+	lock sync.Mutex
+	db   storage.Store
+}
+
+var _ http.Handler = (*Server)(nil)
+
+// NewServer returns a new signer server for the given keys using the given
+// store for the double-signing protection data.
+func NewServer(cfg ServerConfig, privs []*keys.PrivateKey, db storage.Store, log *zap.Logger) *Server {
+	s := &Server{
+		cfg:  cfg,
+		log:  log,
+		keys: make(map[string]*keys.PrivateKey, len(privs)),
+		db:   db,
+	}
+	for _, p := range privs {
+		pub := p.PublicKey()
+		if _, ok := s.keys[string(pub.Bytes())]; ok {
+			continue
+		}
+		s.keys[string(pub.Bytes())] = p
+		s.pubs = append(s.pubs, pub)
+	}
+	return s
+}
+
+// Listen creates a listener for the given signer address that can either be
+// "unix:/path/to/socket" or "http(s)://host:port". Unix socket is only
+// accessible by the owner, TLS is to be handled by the caller for https
+// addresses.
+func Listen(addr string) (net.Listener, error) {
+	switch {
+	case strings.HasPrefix(addr, unixPrefix):
+		path := socketPath(addr)
+		if len(path) == 0 {
+			return nil, errors.New("empty socket path")
+		}
+		l, err := net.Listen("unix", path)
+		if err != nil {
+			return nil, err
+		}
+		if err = os.Chmod(path, 0o600); err != nil {
+			l.Close()
+			return nil, fmt.Errorf("can't restrict socket permissions: %w", err)
+		}
+		return l, nil
+	case strings.HasPrefix(addr, "http://"):
+		return net.Listen("tcp", strings.TrimRight(strings.TrimPrefix(addr, "http://"), "/"))
+	case strings.HasPrefix(addr, "https://"):
+		return net.Listen("tcp", strings.TrimRight(strings.TrimPrefix(addr, "https://"), "/"))
+	default:
+		return nil, fmt.Errorf("unsupported signer address %q", addr)
+	}
+}
+
+// ServeHTTP implements the http.Handler interface.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !s.authorized(r) {
+		s.writeResponse(w, http.StatusUnauthorized, errorResponse{Error: "unauthorized"})
+		return
+	}
+	switch {
+	case r.URL.Path == keysPath && r.Method == http.MethodGet:
+		s.writeResponse(w, http.StatusOK, keysResponse{Keys: s.pubs})
+	case r.URL.Path == signPath && r.Method == http.MethodPost:
+		s.handleSign(w, r)
+	default:
+		s.writeResponse(w, http.StatusNotFound, errorResponse{Error: "not found"})
+	}
+}
+
+// authorized checks the request token if it's required.
+func (s *Server) authorized(r *http.Request) bool {
+	if len(s.cfg.Token) == 0 {
+		return true
+	}
+	return subtle.ConstantTimeCompare([]byte(r.Header.Get("Authorization")), []byte(authScheme+s.cfg.Token)) == 1
+}
+
+func (s *Server) handleSign(w http.ResponseWriter, r *http.Request) {
+	var req signRequest
+	data, err := gio.ReadAll(gio.LimitReader(r.Body, maxRequestSize))
+	if err == nil {
+		err = json.Unmarshal(data, &req)
+	}
+	if err == nil && req.Key == nil {
+		err = errors.New("no key")
+	}
+	if err != nil {
+		s.writeResponse(w, http.StatusBadRequest, errorResponse{Error: fmt.Sprintf("bad request: %s", err)})
+		return
+	}
+	sig, err := s.sign(&req)
+	if err != nil {
+		status := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, ErrDoubleSign):
+			status = http.StatusConflict
+			s.log.Warn("refusing to sign", zap.Stringer("kind", req.Kind),
+				zap.String("key", req.Key.StringCompressed()),
+				zap.Error(err))
+		case errors.Is(err, ErrInvalidData):
+			status = http.StatusBadRequest
+			s.log.Warn("refusing to sign", zap.Stringer("kind", req.Kind),
+				zap.String("key", req.Key.StringCompressed()),
+				zap.Error(err))
+		case errors.Is(err, ErrUnknownKey):
+			status = http.StatusNotFound
+		default:
+			s.log.Error("signing failed", zap.Error(err))
+		}
+		s.writeResponse(w, status, errorResponse{Error: err.Error()})
+		return
+	}
+	s.writeResponse(w, http.StatusOK, signResponse{Signature: sig})
+}
+
+// sign decodes the data to be signed, checks it against the double-signing
+// protection data and signs it.
+func (s *Server) sign(req *signRequest) ([]byte, error) {
+	pub := req.Key.Bytes()
+	priv, ok := s.keys[string(pub)]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownKey, req.Key.StringCompressed())
+	}
+	if req.Network != s.cfg.Network {
+		return nil, fmt.Errorf("%w: network %d is not supported", ErrInvalidData, req.Network)
+	}
+	item, height, err := s.decode(req)
+	if err != nil {
+		return nil, fmt.Errorf("%w: can't decode %s: %s", ErrInvalidData, req.Kind, err)
+	}
+	digest := hash.NetSha256(uint32(s.cfg.Network), item)
+	if req.Kind.protected() {
+		s.lock.Lock()
+		defer s.lock.Unlock()
+
+		key := protectionKey(req.Kind, pub, height)
+		old, err := s.db.Get(key)
+		switch {
+		case err == nil:
+			if !bytes.Equal(old, digest.BytesLE()) {
+				return nil, fmt.Errorf("%w: different %s was signed for height %d", ErrDoubleSign, req.Kind, height)
+			}
+		case errors.Is(err, storage.ErrKeyNotFound):
+			err = s.db.PutChangeSet(map[string][]byte{string(key): digest.BytesLE()}, nil)
+			if err != nil {
+				return nil, fmt.Errorf("can't save protection data: %w", err)
+			}
+		default:
+			return nil, fmt.Errorf("can't get protection data: %w", err)
+		}
+	}
+	return priv.SignHash(digest), nil
+}
+
+// decode decodes the request data according to its kind and returns the item
+// to be signed along with the height it belongs to.
+func (s *Server) decode(req *signRequest) (hash.Hashable, uint32, error) {
+	var (
+		item   hash.Hashable
+		height uint32
+		r      = io.NewBinReaderFromBuf(req.Data)
+	)
+	switch req.Kind {
+	case KindBlock:
+		h := &block.Header{StateRootEnabled: s.cfg.StateRootInHeader}
+		h.DecodeBinary(r)
+		item, height = h, h.Index
+	case KindStateRoot:
+		sr := new(state.MPTRoot)
+		sr.DecodeBinary(r)
+		item, height = sr, sr.Index
+	case KindPayload:
+		p := new(payload.Extensible)
+		p.DecodeBinary(r)
+		if r.Err != nil {
+			break
+		}
+		if p.Category != payload.ConsensusCategory && p.Category != stateServiceCategory {
+			return nil, 0, fmt.Errorf("unsupported category %q", p.Category)
+		}
+		if !p.Sender.Equals(req.Key.GetScriptHash()) {
+			return nil, 0, errors.New("payload sender doesn't match the key")
+		}
+		item, height = p, p.ValidBlockStart
+	default:
+		return nil, 0, errors.New("unknown kind")
+	}
+	if r.Err != nil {
+		return nil, 0, r.Err
+	}
+	if r.Len() != 0 {
+		return nil, 0, errors.New("unexpected trailing data")
+	}
+	return item, height, nil
+}
+
+// protectionKey returns the protection data key for the given kind, public key
+// and height.
+func protectionKey(kind Kind, pub []byte, height uint32) []byte {
+	key := make([]byte, 1+len(pub)+4)
+	key[0] = byte(kind)
+	copy(key[1:], pub)
+	binary.BigEndian.PutUint32(key[1+len(pub):], height)
+	return key
+}
+
+func (s *Server) writeResponse(w http.ResponseWriter, status int, res any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(res); err != nil {
+		s.log.Debug("can't write response", zap.Error(err))
+	}
+}
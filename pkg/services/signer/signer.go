@@ -0,0 +1,149 @@
+/*
+Package signer provides an interface for external signers holding private keys
+of consensus and state validator nodes along with the HTTP client for it and
+the reference signer server implementation.
+
+The signer is a separate process (potentially running on a separate machine)
+that keeps private keys and signs data requested by the node. It never signs
+bare hashes, the node sends the data itself (block header, state root or
+extensible network payload), the signer decodes it, computes the hash to sign
+and refuses anything it can't decode, so it can't be used to sign
+transactions or any other arbitrary data. Extensible payloads are only signed
+if they're sent by the account of the key used. Block and state root
+signatures are tracked per key and height, so the signer never signs two
+different blocks (or state roots) for the same height even if the node is
+compromised or misbehaves.
+*/
+package signer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/nspcc-dev/neo-go/pkg/config/netmode"
+	"github.com/nspcc-dev/neo-go/pkg/core/block"
+	"github.com/nspcc-dev/neo-go/pkg/core/state"
+	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
+	"github.com/nspcc-dev/neo-go/pkg/network/payload"
+)
+
+// Kind is the kind of data being signed.
+type Kind byte
+
+const (
+	// KindPayload is used for extensible network payloads (consensus
+	// messages, state root votes), such signatures are not protected from
+	// double-signing, but the payload sender must match the key used.
+	KindPayload Kind = iota
+	// KindBlock is used for block signatures made by consensus nodes.
+	KindBlock
+	// KindStateRoot is used for state root signatures made by state
+	// validators.
+	KindStateRoot
+)
+
+var (
+	// ErrDoubleSign is returned when the signer refuses to sign data that
+	// conflicts with the one already signed for the same height.
+	ErrDoubleSign = errors.New("double signing attempt")
+	// ErrUnknownKey is returned when the signer doesn't have the requested key.
+	ErrUnknownKey = errors.New("unknown key")
+	// ErrInvalidData is returned when the signer refuses to sign data it
+	// can't decode or that is not allowed to be signed with the key given.
+	ErrInvalidData = errors.New("invalid data")
+)
+
+// Signer is an external signer holding private keys.
+type Signer interface {
+	// PublicKeys returns the list of keys the signer can sign with.
+	PublicKeys() (keys.PublicKeys, error)
+	// SignBlock signs the block header for the given network with the
+	// private key corresponding to the given public key. ErrDoubleSign is
+	// returned if some other block was already signed by this key for the
+	// same height.
+	SignBlock(pub *keys.PublicKey, net netmode.Magic, h *block.Header) ([]byte, error)
+	// SignStateRoot signs the state root for the given network with the
+	// private key corresponding to the given public key. ErrDoubleSign is
+	// returned if some other state root was already signed by this key for
+	// the same height.
+	SignStateRoot(pub *keys.PublicKey, net netmode.Magic, r *state.MPTRoot) ([]byte, error)
+	// SignExtensible signs the extensible payload for the given network with
+	// the private key corresponding to the given public key. Payload sender
+	// must be the account of this key.
+	SignExtensible(pub *keys.PublicKey, net netmode.Magic, p *payload.Extensible) ([]byte, error)
+}
+
+// String implements the fmt.Stringer interface.
+func (k Kind) String() string {
+	switch k {
+	case KindPayload:
+		return "payload"
+	case KindBlock:
+		return "block"
+	case KindStateRoot:
+		return "stateroot"
+	default:
+		return fmt.Sprintf("unknown(%d)", byte(k))
+	}
+}
+
+// protected returns true if signatures of this kind are protected from
+// double-signing.
+func (k Kind) protected() bool {
+	return k == KindBlock || k == KindStateRoot
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (k Kind) MarshalJSON() ([]byte, error) {
+	return json.Marshal(k.String())
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (k *Kind) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	for _, kind := range []Kind{KindPayload, KindBlock, KindStateRoot} {
+		if kind.String() == s {
+			*k = kind
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown signature kind %q", s)
+}
+
+type (
+	// keysResponse is the response to the keys request.
+	keysResponse struct {
+		Keys keys.PublicKeys `json:"keys"`
+	}
+
+	// signRequest is the signing request, Data is the serialized item of
+	// the given Kind.
+	signRequest struct {
+		Key     *keys.PublicKey `json:"key"`
+		Kind    Kind            `json:"kind"`
+		Network netmode.Magic   `json:"network"`
+		Data    []byte          `json:"data"`
+	}
+
+	// signResponse is the response to the signing request.
+	signResponse struct {
+		Signature []byte `json:"signature"`
+	}
+
+	// errorResponse is returned by the server in case of any error.
+	errorResponse struct {
+		Error string `json:"error"`
+	}
+)
+
+const (
+	keysPath = "/keys"
+	signPath = "/sign"
+
+	// authScheme is the Authorization header prefix used for the token.
+	authScheme = "Bearer "
+)
@@ -0,0 +1,207 @@
+package signer
+
+import (
+	"encoding/json"
+	"encoding/pem"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/nspcc-dev/neo-go/internal/random"
+	"github.com/nspcc-dev/neo-go/pkg/config"
+	"github.com/nspcc-dev/neo-go/pkg/config/netmode"
+	"github.com/nspcc-dev/neo-go/pkg/core/block"
+	"github.com/nspcc-dev/neo-go/pkg/core/state"
+	"github.com/nspcc-dev/neo-go/pkg/core/storage"
+	"github.com/nspcc-dev/neo-go/pkg/core/transaction"
+	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
+	"github.com/nspcc-dev/neo-go/pkg/network/payload"
+	"github.com/nspcc-dev/neo-go/pkg/vm/opcode"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestKindJSON(t *testing.T) {
+	for _, k := range []Kind{KindPayload, KindBlock, KindStateRoot} {
+		data, err := json.Marshal(k)
+		require.NoError(t, err)
+		var actual Kind
+		require.NoError(t, json.Unmarshal(data, &actual))
+		require.Equal(t, k, actual)
+	}
+	var k Kind
+	require.Error(t, json.Unmarshal([]byte(`"unknown"`), &k))
+	require.Error(t, json.Unmarshal([]byte(`1`), &k))
+}
+
+func TestNewClient(t *testing.T) {
+	for _, addr := range []string{"", "localhost:1234", "tcp://localhost:1234", "unix:"} {
+		_, err := NewClient(config.RemoteSigner{Address: addr})
+		require.Error(t, err, addr)
+	}
+	_, err := NewClient(config.RemoteSigner{Address: "http://localhost:1234", Token: "secret"})
+	require.ErrorContains(t, err, "plain HTTP")
+	_, err = NewClient(config.RemoteSigner{Address: "https://localhost:1234", CAFile: "/nonexistent"})
+	require.Error(t, err)
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	require.NoError(t, os.WriteFile(caFile, []byte("not a certificate"), 0o600))
+	_, err = NewClient(config.RemoteSigner{Address: "https://localhost:1234", CAFile: caFile})
+	require.ErrorContains(t, err, "no certificates")
+}
+
+func TestClientServer(t *testing.T) {
+	privs := []*keys.PrivateKey{newKey(t), newKey(t)}
+	newServer := func(t *testing.T, token string) *Server {
+		return NewServer(ServerConfig{Network: netmode.UnitTestNet, Token: token},
+			append(privs, privs[0]), storage.NewMemoryStore(), zaptest.NewLogger(t))
+	}
+
+	t.Run("https", func(t *testing.T) {
+		srv := newServer(t, "secret")
+		ts := httptest.NewTLSServer(srv)
+		t.Cleanup(ts.Close)
+		caFile := filepath.Join(t.TempDir(), "ca.pem")
+		require.NoError(t, os.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ts.Certificate().Raw}), 0o600))
+		testClient(t, config.RemoteSigner{Address: ts.URL, Token: "secret", CAFile: caFile}, privs)
+
+		// Signer certificate is not trusted without CA file.
+		c, err := NewClient(config.RemoteSigner{Address: ts.URL, Token: "secret"})
+		require.NoError(t, err)
+		_, err = c.PublicKeys()
+		require.Error(t, err)
+
+		for _, token := range []string{"", "wrong"} {
+			c, err := NewClient(config.RemoteSigner{Address: ts.URL, Token: token, CAFile: caFile})
+			require.NoError(t, err)
+			_, err = c.PublicKeys()
+			require.Error(t, err)
+			_, err = c.SignExtensible(privs[0].PublicKey(), netmode.UnitTestNet, newExtensible(privs[0].PublicKey()))
+			require.Error(t, err)
+		}
+	})
+	t.Run("unix", func(t *testing.T) {
+		addr := "unix:" + filepath.Join(t.TempDir(), "signer.sock")
+		srv := newServer(t, "")
+		l, err := Listen(addr)
+		require.NoError(t, err)
+		hs := &http.Server{Handler: srv, ReadHeaderTimeout: time.Second}
+		go func() { _ = hs.Serve(l) }()
+		t.Cleanup(func() { _ = hs.Close() })
+		testClient(t, config.RemoteSigner{Address: addr}, privs)
+	})
+}
+
+func testClient(t *testing.T, cfg config.RemoteSigner, privs []*keys.PrivateKey) {
+	const net = netmode.UnitTestNet
+
+	c, err := NewClient(cfg)
+	require.NoError(t, err)
+
+	pubs, err := c.PublicKeys()
+	require.NoError(t, err)
+	require.Equal(t, keys.PublicKeys{privs[0].PublicKey(), privs[1].PublicKey()}, pubs)
+
+	t.Run("unknown key", func(t *testing.T) {
+		pub := newKey(t).PublicKey()
+		_, err := c.SignExtensible(pub, net, newExtensible(pub))
+		require.ErrorIs(t, err, ErrUnknownKey)
+	})
+	t.Run("wrong network", func(t *testing.T) {
+		_, err := c.SignExtensible(pubs[0], netmode.MainNet, newExtensible(pubs[0]))
+		require.ErrorIs(t, err, ErrInvalidData)
+	})
+	t.Run("payload", func(t *testing.T) {
+		for i := 0; i < 2; i++ {
+			p := newExtensible(pubs[0])
+			sig, err := c.SignExtensible(pubs[0], net, p)
+			require.NoError(t, err)
+			require.True(t, pubs[0].VerifyHashable(sig, uint32(net), p))
+		}
+
+		p := newExtensible(pubs[1])
+		_, err := c.SignExtensible(pubs[0], net, p)
+		require.ErrorIs(t, err, ErrInvalidData)
+
+		p = newExtensible(pubs[0])
+		p.Category = "unknown"
+		_, err = c.SignExtensible(pubs[0], net, p)
+		require.ErrorIs(t, err, ErrInvalidData)
+	})
+	t.Run("block", func(t *testing.T) {
+		newHeader := func(height uint32) *block.Header {
+			return &block.Header{Index: height, Nonce: rand.Uint64(), PrevHash: random.Uint256()}
+		}
+		h := newHeader(100)
+		sig, err := c.SignBlock(pubs[0], net, h)
+		require.NoError(t, err)
+		require.True(t, pubs[0].VerifyHashable(sig, uint32(net), h))
+
+		// The same block can be signed again.
+		_, err = c.SignBlock(pubs[0], net, h)
+		require.NoError(t, err)
+
+		// But not a different one.
+		_, err = c.SignBlock(pubs[0], net, newHeader(100))
+		require.ErrorIs(t, err, ErrDoubleSign)
+
+		// Other heights and keys are not affected.
+		_, err = c.SignBlock(pubs[0], net, newHeader(101))
+		require.NoError(t, err)
+		_, err = c.SignBlock(pubs[1], net, newHeader(100))
+		require.NoError(t, err)
+	})
+	t.Run("state root", func(t *testing.T) {
+		newRoot := func(height uint32) *state.MPTRoot {
+			return &state.MPTRoot{Index: height, Root: random.Uint256()}
+		}
+		r := newRoot(100)
+		sig, err := c.SignStateRoot(pubs[0], net, r)
+		require.NoError(t, err)
+		require.True(t, pubs[0].VerifyHashable(sig, uint32(net), r))
+
+		_, err = c.SignStateRoot(pubs[0], net, r)
+		require.NoError(t, err)
+		_, err = c.SignStateRoot(pubs[0], net, newRoot(100))
+		require.ErrorIs(t, err, ErrDoubleSign)
+		_, err = c.SignStateRoot(pubs[0], net, newRoot(101))
+		require.NoError(t, err)
+		_, err = c.SignStateRoot(pubs[1], net, newRoot(100))
+		require.NoError(t, err)
+	})
+	t.Run("invalid data", func(t *testing.T) {
+		tx := transaction.New([]byte{byte(opcode.PUSH1)}, 0)
+		tx.Signers = []transaction.Signer{{Account: pubs[0].GetScriptHash()}}
+		tx.Scripts = []transaction.Witness{{}}
+		for _, k := range []Kind{KindPayload, KindBlock, KindStateRoot, Kind(0xff)} {
+			var res signResponse
+			err := c.do(http.MethodPost, signPath, &signRequest{
+				Key:     pubs[0],
+				Kind:    k,
+				Network: net,
+				Data:    tx.Bytes(),
+			}, &res)
+			require.ErrorIs(t, err, ErrInvalidData, k)
+		}
+	})
+}
+
+func newExtensible(pub *keys.PublicKey) *payload.Extensible {
+	return &payload.Extensible{
+		Category:        payload.ConsensusCategory,
+		ValidBlockStart: 1,
+		ValidBlockEnd:   2,
+		Sender:          pub.GetScriptHash(),
+		Data:            random.Bytes(10),
+		Witness:         transaction.Witness{VerificationScript: pub.GetVerificationScript()},
+	}
+}
+
+func newKey(t *testing.T) *keys.PrivateKey {
+	k, err := keys.NewPrivateKey()
+	require.NoError(t, err)
+	return k
+}
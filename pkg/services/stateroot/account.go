@@ -0,0 +1,59 @@
+package stateroot
+
+import (
+	"github.com/nspcc-dev/neo-go/pkg/config/netmode"
+	"github.com/nspcc-dev/neo-go/pkg/core/state"
+	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
+	"github.com/nspcc-dev/neo-go/pkg/network/payload"
+	"github.com/nspcc-dev/neo-go/pkg/services/signer"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/nspcc-dev/neo-go/pkg/wallet"
+)
+
+// account is a state validator account used by the service. Its private key
+// is either held by the unlocked wallet account or by the external signer.
+type account struct {
+	acc    *wallet.Account
+	signer signer.Signer
+	pub    *keys.PublicKey
+}
+
+// PublicKey returns the account public key.
+func (a *account) PublicKey() *keys.PublicKey {
+	if a.signer != nil {
+		return a.pub
+	}
+	return a.acc.PublicKey()
+}
+
+// ScriptHash returns the account script hash.
+func (a *account) ScriptHash() util.Uint160 {
+	if a.signer != nil {
+		return a.pub.GetScriptHash()
+	}
+	return a.acc.ScriptHash()
+}
+
+// GetVerificationScript returns the account verification script.
+func (a *account) GetVerificationScript() []byte {
+	if a.signer != nil {
+		return a.pub.GetVerificationScript()
+	}
+	return a.acc.GetVerificationScript()
+}
+
+// signStateRoot signs the state root for the given network.
+func (a *account) signStateRoot(net netmode.Magic, r *state.MPTRoot) ([]byte, error) {
+	if a.signer != nil {
+		return a.signer.SignStateRoot(a.pub, net, r)
+	}
+	return a.acc.SignHashable(net, r), nil
+}
+
+// signExtensible signs the extensible payload for the given network.
+func (a *account) signExtensible(net netmode.Magic, p *payload.Extensible) ([]byte, error) {
+	if a.signer != nil {
+		return a.signer.SignExtensible(a.pub, net, p)
+	}
+	return a.acc.SignHashable(net, p), nil
+}
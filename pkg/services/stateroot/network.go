@@ -10,7 +10,6 @@ import (
 	"github.com/nspcc-dev/neo-go/pkg/io"
 	"github.com/nspcc-dev/neo-go/pkg/network/payload"
 	"github.com/nspcc-dev/neo-go/pkg/vm/emit"
-	"github.com/nspcc-dev/neo-go/pkg/wallet"
 	"go.uber.org/zap"
 )
 
@@ -74,7 +73,7 @@ func (s *service) getIncompleteRoot(height uint32, myIndex byte) *incompleteRoot
 }
 
 // trySendRoot attempts to finalize and send MPTRoot, it must be called with the ir locked.
-func (s *service) trySendRoot(ir *incompleteRoot, acc *wallet.Account) {
+func (s *service) trySendRoot(ir *incompleteRoot, acc *account) {
 	if !ir.isSenderNow() {
 		return
 	}
@@ -89,7 +88,7 @@ func (s *service) trySendRoot(ir *incompleteRoot, acc *wallet.Account) {
 	}
 }
 
-func (s *service) sendValidatedRoot(r *state.MPTRoot, acc *wallet.Account) {
+func (s *service) sendValidatedRoot(r *state.MPTRoot, acc *account) {
 	w := io.NewBufBinWriter()
 	m := NewMessage(RootT, r)
 	m.EncodeBinary(w.BinWriter)
@@ -103,7 +102,11 @@ func (s *service) sendValidatedRoot(r *state.MPTRoot, acc *wallet.Account) {
 			VerificationScript: acc.GetVerificationScript(),
 		},
 	}
-	sig := acc.SignHashable(s.Network, ep)
+	sig, err := acc.signExtensible(s.Network, ep)
+	if err != nil {
+		s.log.Error("can't sign validated state root", zap.Uint32("height", r.Index), zap.Error(err))
+		return
+	}
 	buf := io.NewBufBinWriter()
 	emit.Bytes(buf.BinWriter, sig)
 	ep.Witness.InvocationScript = buf.Bytes()
@@ -16,6 +16,7 @@ import (
 	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
 	"github.com/nspcc-dev/neo-go/pkg/io"
 	"github.com/nspcc-dev/neo-go/pkg/network/payload"
+	"github.com/nspcc-dev/neo-go/pkg/services/signer"
 	"github.com/nspcc-dev/neo-go/pkg/wallet"
 	"go.uber.org/zap"
 )
@@ -62,7 +63,11 @@ type (
 		accHeight uint32
 		myIndex   byte
 		wallet    *wallet.Wallet
-		acc       *wallet.Account
+		acc       *account
+		// signer is an external signer holding state validator keys, it's
+		// used instead of the wallet if configured.
+		signer     signer.Signer
+		signerKeys keys.PublicKeys
 
 		srMtx           sync.Mutex
 		incompleteRoots map[uint32]*incompleteRoot
@@ -107,20 +112,29 @@ func New(cfg config.StateRoot, sm *stateroot.Module, log *zap.Logger, bc Ledger,
 			return nil, errors.New("`StateRootInHeader` should be disabled when state service is enabled")
 		}
 		var err error
-		w := cfg.UnlockWallet
-		if s.wallet, err = wallet.NewWalletFromFile(w.Path); err != nil {
-			return nil, err
-		}
+		if len(cfg.RemoteSigner.Address) > 0 {
+			if s.signer, err = signer.NewClient(cfg.RemoteSigner); err != nil {
+				return nil, fmt.Errorf("can't create remote signer client: %w", err)
+			}
+			if s.signerKeys, err = s.signer.PublicKeys(); err != nil {
+				return nil, fmt.Errorf("can't get keys from remote signer: %w", err)
+			}
+		} else {
+			w := cfg.UnlockWallet
+			if s.wallet, err = wallet.NewWalletFromFile(w.Path); err != nil {
+				return nil, err
+			}
 
-		haveAccount := false
-		for _, acc := range s.wallet.Accounts {
-			if err := acc.Decrypt(w.Password, s.wallet.Scrypt); err == nil {
-				haveAccount = true
-				break
+			haveAccount := false
+			for _, acc := range s.wallet.Accounts {
+				if err := acc.Decrypt(w.Password, s.wallet.Scrypt); err == nil {
+					haveAccount = true
+					break
+				}
+			}
+			if !haveAccount {
+				return nil, errors.New("no wallet account could be unlocked")
 			}
-		}
-		if !haveAccount {
-			return nil, errors.New("no wallet account could be unlocked")
 		}
 
 		keys, h, err := bc.GetDesignatedByRole(noderoles.StateValidator)
@@ -174,11 +188,24 @@ func (s *service) updateValidators(height uint32, pubs keys.PublicKeys) {
 	defer s.accMtx.Unlock()
 
 	s.acc = nil
+	if s.signer != nil {
+		for i := range pubs {
+			for _, k := range s.signerKeys {
+				if k.Equal(pubs[i]) {
+					s.acc = &account{signer: s.signer, pub: k}
+					s.accHeight = height
+					s.myIndex = byte(i)
+					return
+				}
+			}
+		}
+		return
+	}
 	for i := range pubs {
 		if acc := s.wallet.GetAccount(pubs[i].GetScriptHash()); acc != nil {
 			err := acc.Decrypt(s.MainCfg.UnlockWallet.Password, s.wallet.Scrypt)
 			if err == nil {
-				s.acc = acc
+				s.acc = &account{acc: acc}
 				s.accHeight = height
 				s.myIndex = byte(i)
 				break
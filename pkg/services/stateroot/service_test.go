@@ -2,6 +2,7 @@ package stateroot_test
 
 import (
 	"crypto/elliptic"
+	"net/http/httptest"
 	"path/filepath"
 	"sort"
 	"sync/atomic"
@@ -26,6 +27,7 @@ import (
 	"github.com/nspcc-dev/neo-go/pkg/neotest"
 	"github.com/nspcc-dev/neo-go/pkg/neotest/chain"
 	"github.com/nspcc-dev/neo-go/pkg/network/payload"
+	"github.com/nspcc-dev/neo-go/pkg/services/signer"
 	"github.com/nspcc-dev/neo-go/pkg/services/stateroot"
 	"github.com/nspcc-dev/neo-go/pkg/smartcontract"
 	"github.com/nspcc-dev/neo-go/pkg/util"
@@ -220,10 +222,12 @@ func createAndWriteWallet(t *testing.T, acc *wallet.Account, path, password stri
 
 func createStateRootConfig(walletPath, password string) config.StateRoot {
 	return config.StateRoot{
-		Enabled: true,
-		UnlockWallet: config.Wallet{
-			Path:     walletPath,
-			Password: password,
+		InternalService: config.InternalService{
+			Enabled: true,
+			UnlockWallet: config.Wallet{
+				Path:     walletPath,
+				Password: password,
+			},
 		},
 	}
 }
@@ -296,6 +300,61 @@ func TestStateRootFull(t *testing.T) {
 	require.Equal(t, r.Root, actual.Root)
 }
 
+func TestStateRootRemoteSigner(t *testing.T) {
+	bc, validator, committee := chain.NewMulti(t)
+	e := neotest.NewExecutor(t, bc, validator, committee)
+	designationSuperInvoker := e.NewInvoker(e.NativeHash(t, nativenames.Designation), validator, committee)
+	gasValidatorInvoker := e.ValidatorInvoker(e.NativeHash(t, nativenames.Gas))
+
+	h, pubs, accs := newMajorityMultisigWithGAS(t, 2)
+	ts := httptest.NewServer(signer.NewServer(signer.ServerConfig{
+		Network:           netmode.UnitTestNet,
+		StateRootInHeader: bc.GetConfig().StateRootInHeader,
+	}, []*keys.PrivateKey{accs[1].PrivateKey()}, storage.NewMemoryStore(), zaptest.NewLogger(t)))
+	t.Cleanup(ts.Close)
+	cfg := config.StateRoot{
+		InternalService: config.InternalService{Enabled: true},
+		RemoteSigner:    config.RemoteSigner{Address: ts.URL},
+	}
+
+	var lastValidated atomic.Value
+	var lastHeight atomic.Uint32
+	srMod := bc.GetStateModule().(*corestate.Module) // Take full responsibility here.
+	srv, err := stateroot.New(cfg, srMod, zaptest.NewLogger(t), bc, func(ep *payload.Extensible) {
+		lastHeight.Store(ep.ValidBlockStart)
+		lastValidated.Store(ep)
+	})
+	require.NoError(t, err)
+	require.False(t, srv.IsAuthorized())
+	srv.Start()
+	t.Cleanup(srv.Shutdown)
+
+	validatorNodes := []any{pubs[0].Bytes(), pubs[1].Bytes()}
+	designationSuperInvoker.Invoke(t, stackitem.Null{}, "designateAsRole",
+		int64(roles.StateValidator), validatorNodes)
+	require.True(t, srv.IsAuthorized())
+	gasValidatorInvoker.Invoke(t, true, "transfer", validator.ScriptHash(), h, 1_0000_0000, nil)
+	// The service subscribes to blocks asynchronously, so it can miss the
+	// first ones.
+	require.Eventually(t, func() bool {
+		if lastHeight.Load() >= 2 {
+			return true
+		}
+		e.AddNewBlock(t)
+		return false
+	}, time.Second, 10*time.Millisecond)
+
+	p := lastValidated.Load().(*payload.Extensible)
+	require.Equal(t, accs[1].ScriptHash(), p.Sender)
+	require.True(t, pubs[1].VerifyHashable(p.Witness.InvocationScript[2:], uint32(netmode.UnitTestNet), p))
+	m := new(stateroot.Message)
+	require.NoError(t, testserdes.DecodeBinary(p.Data, m))
+	require.Equal(t, stateroot.VoteT, m.Type)
+	r, err := bc.GetStateModule().GetStateRoot(m.Payload.(*stateroot.Vote).Height)
+	require.NoError(t, err)
+	require.True(t, pubs[1].VerifyHashable(m.Payload.(*stateroot.Vote).Signature, uint32(netmode.UnitTestNet), r))
+}
+
 func checkVoteBroadcasted(t *testing.T, bc *core.Blockchain, p *payload.Extensible,
 	height uint32, valIndex byte, getDesignatedByRole func(t *testing.T, h uint32) keys.PublicKeys) {
 	require.NotNil(t, p)
@@ -1,6 +1,7 @@
 package stateroot
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/nspcc-dev/neo-go/pkg/core/state"
@@ -8,7 +9,6 @@ import (
 	"github.com/nspcc-dev/neo-go/pkg/io"
 	"github.com/nspcc-dev/neo-go/pkg/network/payload"
 	"github.com/nspcc-dev/neo-go/pkg/vm/emit"
-	"github.com/nspcc-dev/neo-go/pkg/wallet"
 	"go.uber.org/zap"
 )
 
@@ -90,7 +90,10 @@ func (s *service) signAndSend(r *state.MPTRoot) error {
 		return nil
 	}
 
-	sig := acc.SignHashable(s.Network, r)
+	sig, err := acc.signStateRoot(s.Network, r)
+	if err != nil {
+		return fmt.Errorf("can't sign state root: %w", err)
+	}
 	incRoot := s.getIncompleteRoot(r.Index, myIndex)
 	incRoot.Lock()
 	defer incRoot.Unlock()
@@ -120,7 +123,10 @@ func (s *service) signAndSend(r *state.MPTRoot) error {
 			VerificationScript: acc.GetVerificationScript(),
 		},
 	}
-	sig = acc.SignHashable(s.Network, e)
+	sig, err = acc.signExtensible(s.Network, e)
+	if err != nil {
+		return fmt.Errorf("can't sign vote: %w", err)
+	}
 	buf := io.NewBufBinWriter()
 	emit.Bytes(buf.BinWriter, sig)
 	e.Witness.InvocationScript = buf.Bytes()
@@ -151,7 +157,7 @@ func (s *service) sendVote(ir *incompleteRoot) {
 }
 
 // getAccount returns the current index and account for the node running this service.
-func (s *service) getAccount() (byte, *wallet.Account) {
+func (s *service) getAccount() (byte, *account) {
 	s.accMtx.RLock()
 	defer s.accMtx.RUnlock()
 	return s.myIndex, s.acc
@@ -87,6 +87,13 @@ type VM struct {
 	// LoadToken handles CALLT opcode.
 	LoadToken func(id int32) error
 
+	// OnLoad is an optional hook called after a new script (contract) is
+	// loaded into the VM, it's not called for internal CALL* invocations.
+	OnLoad func(ctx *Context)
+	// OnUnload is an optional hook called after the script loaded earlier is
+	// unloaded, commit is false if it's unloaded because of an exception.
+	OnUnload func(ctx *Context, commit bool)
+
 	trigger trigger.Type
 
 	// invTree is a top-level invocation tree (if enabled).
@@ -137,6 +144,8 @@ func (v *VM) Reset(t trigger.Type) {
 	v.GasLimit = 0
 	v.SyscallHandler = nil
 	v.LoadToken = nil
+	v.OnLoad = nil
+	v.OnUnload = nil
 	v.trigger = t
 	v.invTree = nil
 }
@@ -376,6 +385,9 @@ func (v *VM) loadScriptWithCallingHash(b []byte, exe *nef.File, manifest *manife
 	}
 	ctx.sc.onUnload = onContextUnload
 	v.istack = append(v.istack, ctx)
+	if v.OnLoad != nil {
+		v.OnLoad(ctx)
+	}
 }
 
 // Context returns the current executed context. Nil if there is no context,
@@ -1680,6 +1692,9 @@ func (v *VM) unloadContext(ctx *Context) {
 				panic(errors.New(errMessage))
 			}
 		}
+		if v.OnUnload != nil {
+			v.OnUnload(ctx, v.uncaughtException == nil)
+		}
 	}
 }
 
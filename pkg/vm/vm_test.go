@@ -18,6 +18,7 @@ import (
 	"github.com/nspcc-dev/neo-go/pkg/io"
 	"github.com/nspcc-dev/neo-go/pkg/smartcontract/callflag"
 	"github.com/nspcc-dev/neo-go/pkg/smartcontract/trigger"
+	"github.com/nspcc-dev/neo-go/pkg/util"
 	"github.com/nspcc-dev/neo-go/pkg/vm/emit"
 	"github.com/nspcc-dev/neo-go/pkg/vm/opcode"
 	"github.com/nspcc-dev/neo-go/pkg/vm/stackitem"
@@ -631,6 +632,43 @@ func TestCALL(t *testing.T) {
 	runWithArgs(t, prog, 3)
 }
 
+func TestLoadUnloadHooks(t *testing.T) {
+	var (
+		inner = util.Uint160{1, 2, 3}
+		// TRY, SYSCALL, ENDTRY, catch: DROP, ENDTRY, RET.
+		prog = []byte{byte(opcode.TRY), 10, 0,
+			byte(opcode.SYSCALL), 0, 0, 0, 0,
+			byte(opcode.ENDTRY), 5,
+			byte(opcode.DROP), byte(opcode.ENDTRY), 2,
+			byte(opcode.RET)}
+	)
+	check := func(t *testing.T, innerScript []byte, commit bool) {
+		var (
+			loaded   []util.Uint160
+			unloaded []bool
+		)
+		v := newTestVM()
+		v.SyscallHandler = func(v *VM, _ uint32) error {
+			v.LoadScriptWithHash(innerScript, inner, callflag.All)
+			return nil
+		}
+		v.LoadScript(prog)
+		// Hooks are set after the entry script is loaded.
+		v.OnLoad = func(ctx *Context) { loaded = append(loaded, ctx.ScriptHash()) }
+		v.OnUnload = func(_ *Context, ok bool) { unloaded = append(unloaded, ok) }
+		runVM(t, v)
+		require.Equal(t, []util.Uint160{inner}, loaded)
+		require.Equal(t, []bool{commit, true}, unloaded)
+	}
+	t.Run("halt", func(t *testing.T) {
+		// Internal CALL doesn't trigger the hooks.
+		check(t, makeProgram(opcode.CALL, 3, opcode.RET, opcode.PUSH1), true)
+	})
+	t.Run("exception", func(t *testing.T) {
+		check(t, makeProgram(opcode.PUSH1, opcode.THROW), false)
+	})
+}
+
 func TestNOT(t *testing.T) {
 	prog := makeProgram(opcode.NOT)
 	t.Run("Bool", getTestFuncForVM(prog, true, false))